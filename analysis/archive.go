@@ -0,0 +1,173 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/carloberd/db-reader/diff"
+	"github.com/carloberd/db-reader/export"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Thresholds of the archive advisor. Sizes are compared in bytes when the
+// database reports them, in rows otherwise.
+const (
+	partitionBytes = 10 << 30 // Size worth partitioning, now or within a year
+	partitionRows  = 100_000_000
+	archiveBytes   = 1 << 30 // Size from which archiving old rows is worth it
+	archiveRows    = 10_000_000
+	minGrowth      = 0.05           // Monthly growth from which a table is growing
+	minHistory     = 24 * time.Hour // Time the sizes must span to tell the growth
+	month          = 30 * 24 * time.Hour
+	year           = 365 * 24 * time.Hour
+)
+
+// timeColumnNames are names of columns recording when rows were created, the
+// best ones to partition or archive by, in order of preference
+var timeColumnNames = []string{
+	"created_at", "created_on", "created", "inserted_at", "occurred_at", "event_time", "logged_at", "timestamp",
+}
+
+// sizePoint is the size of a table in a snapshot
+type sizePoint struct {
+	taken time.Time
+	size  int64 // Bytes, or rows if the bytes are unknown
+}
+
+// ArchiveCandidates suggests tables to partition or archive from the sizes recorded
+// in snapshots given oldest first, and the tables of the latest snapshot. A table
+// grows when its size increases by at least 5% a month over a day or more of
+// snapshots. Growing tables with a timestamp column are suggested for range
+// partitioning on it when they reach 10 GiB (100 million rows) or will within a
+// year, and for archiving old rows when they reach 1 GiB (10 million rows) and
+// never had rows removed. Large growing tables without a timestamp column are
+// reported too, as they have nothing to partition or archive by.
+func ArchiveCandidates(snapshots []*diff.Schema) []t.Finding {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	var findings []t.Finding
+	for _, table := range snapshots[len(snapshots)-1].Tables {
+		points, bytes, removed := sizeHistory(snapshots, table)
+		if len(points) < 2 {
+			continue
+		}
+		first, last := points[0], points[len(points)-1]
+		span := last.taken.Sub(first.taken)
+		if span < minHistory || last.size <= first.size {
+			continue
+		}
+
+		growth := float64(last.size-first.size) / float64(max(first.size, 1)) * float64(month) / float64(span)
+		if growth < minGrowth {
+			continue
+		}
+		projected := last.size + int64(float64(last.size-first.size)*float64(year)/float64(span))
+
+		partitionSize, archiveSize, format := int64(partitionRows), int64(archiveRows), formatRows
+		if bytes {
+			partitionSize, archiveSize, format = partitionBytes, archiveBytes, export.FormatBytes
+		}
+		trend := fmt.Sprintf("%s, growing %.0f%% a month over %.0f days", format(last.size), growth*100, span.Hours()/24)
+
+		column := timeColumn(table)
+		if column == "" {
+			if last.size >= archiveSize {
+				findings = append(findings, t.Finding{
+					Check:   "growing-table",
+					Object:  table.Name,
+					Message: fmt.Sprintf("Table is %s, but has no timestamp column to partition or archive by", trend),
+				})
+			}
+			continue
+		}
+
+		if projected >= partitionSize {
+			findings = append(findings, t.Finding{
+				Check:  "partition-candidate",
+				Object: table.Name,
+				Message: fmt.Sprintf("Table is %s (%s in a year); range partitioning on %s would keep its indexes small "+
+					"and let old partitions be detached instead of deleted", trend, format(projected), column),
+			})
+		}
+		if last.size >= archiveSize && !removed {
+			findings = append(findings, t.Finding{
+				Check:  "archive-candidate",
+				Object: table.Name,
+				Message: fmt.Sprintf("Table is %s and no rows were removed; rows older than a retention period "+
+					"could be moved to an archive by %s", trend, column),
+			})
+		}
+	}
+
+	return findings
+}
+
+// sizeHistory returns the sizes of a table recorded in the snapshots, in bytes
+// if every snapshot knows them and in rows otherwise, and whether its rows ever
+// decreased from a snapshot to the next
+func sizeHistory(snapshots []*diff.Schema, table *t.Table) (points []sizePoint, bytes, removed bool) {
+	var recorded []diff.TableStats
+	var taken []time.Time
+	for _, snapshot := range snapshots {
+		for _, stats := range snapshot.Stats {
+			if stats.Schema == table.Schema && stats.Table == table.Name {
+				recorded = append(recorded, stats)
+				taken = append(taken, snapshot.Taken)
+				break
+			}
+		}
+	}
+
+	bytes = !slices.ContainsFunc(recorded, func(stats diff.TableStats) bool { return stats.Bytes <= 0 })
+	for i, stats := range recorded {
+		if i > 0 && stats.Rows >= 0 && recorded[i-1].Rows >= 0 && stats.Rows < recorded[i-1].Rows {
+			removed = true
+		}
+
+		size := stats.Bytes
+		if !bytes {
+			if stats.Rows < 0 {
+				continue
+			}
+			size = stats.Rows
+		}
+		points = append(points, sizePoint{taken: taken[i], size: size})
+	}
+	return points, bytes, removed
+}
+
+// timeColumn returns the column of a table best recording when its rows were
+// created: a date or timestamp column with a usual name, or else the first one
+// that is not nullable, "" if there is none
+func timeColumn(table *t.Table) string {
+	var candidates []t.Column
+	for _, col := range table.Columns {
+		typ := strings.ToLower(col.Type)
+		if strings.Contains(typ, "timestamp") || strings.Contains(typ, "date") {
+			candidates = append(candidates, col)
+		}
+	}
+
+	for _, name := range timeColumnNames {
+		for _, col := range candidates {
+			if strings.EqualFold(col.Name, name) {
+				return col.Name
+			}
+		}
+	}
+	for _, col := range candidates {
+		if !col.Nullable {
+			return col.Name
+		}
+	}
+	return ""
+}
+
+// formatRows formats a row count, e.g. "12840 rows"
+func formatRows(rows int64) string {
+	return fmt.Sprintf("%d rows", rows)
+}
@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// RedundantIndexes finds indexes that duplicate another index, or whose columns are a
+// leading prefix of another index with the same method and predicate. Unique indexes
+// are only reported as exact duplicates, since they also enforce a constraint.
+// Indexes with expression keys are skipped as their full key is not known, and an
+// index is only redundant when the other one also stores its INCLUDE columns.
+func RedundantIndexes(tables []*t.Table) []t.Finding {
+	var findings []t.Finding
+
+	for _, table := range tables {
+		reported := make(map[string]bool)
+
+		for i, a := range table.Indexes {
+			for j, b := range table.Indexes {
+				if i == j || a.Expression || b.Expression || reported[a.Name] {
+					continue
+				}
+				if a.Method != b.Method || a.Predicate != b.Predicate {
+					continue
+				}
+
+				switch {
+				case sameColumns(a.Columns, b.Columns) && sameSet(a.Include, b.Include):
+					// Keep the constraint-backing index, or the first by name
+					if keepFirst(b, a) {
+						findings = append(findings, t.Finding{
+							Check:   "duplicate-index",
+							Object:  table.Name + "." + a.Name,
+							Message: fmt.Sprintf("Index duplicates %s on (%s)", b.Name, strings.Join(a.Columns, ", ")),
+							Bytes:   a.Size,
+						})
+						reported[a.Name] = true
+					}
+
+				case !a.Unique && !a.PrimaryKey && isPrefix(a.Columns, b.Columns) && covers(b, a.Include):
+					findings = append(findings, t.Finding{
+						Check:  "redundant-index",
+						Object: table.Name + "." + a.Name,
+						Message: fmt.Sprintf("Index on (%s) is a prefix of %s on (%s)",
+							strings.Join(a.Columns, ", "), b.Name, strings.Join(b.Columns, ", ")),
+						Bytes: a.Size,
+					})
+					reported[a.Name] = true
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// keepFirst reports whether index a should be kept over its duplicate b
+func keepFirst(a, b t.Index) bool {
+	if a.PrimaryKey != b.PrimaryKey {
+		return a.PrimaryKey
+	}
+	if a.Unique != b.Unique {
+		return a.Unique
+	}
+	return a.Name < b.Name
+}
+
+// sameColumns reports whether two indexes have the same key columns in the same order
+func sameColumns(a, b []string) bool {
+	return len(a) == len(b) && isPrefix(a, b)
+}
+
+// sameSet reports whether two lists hold the same names in any order
+func sameSet(a, b []string) bool {
+	return len(a) == len(b) && !slices.ContainsFunc(a, func(name string) bool { return !slices.Contains(b, name) })
+}
+
+// covers reports whether an index stores all the given columns, as keys or INCLUDE columns
+func covers(idx t.Index, columns []string) bool {
+	for _, col := range columns {
+		if !slices.Contains(idx.Columns, col) && !slices.Contains(idx.Include, col) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPrefix reports whether prefix is a leading part of columns
+func isPrefix(prefix, columns []string) bool {
+	if len(prefix) > len(columns) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != columns[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Object kinds that naming rules can apply to
+const (
+	NamingTable      = "table"
+	NamingColumn     = "column"
+	NamingIndex      = "index" // Indexes that do not back a constraint
+	NamingPrimaryKey = "primary_key"
+	NamingForeignKey = "foreign_key"
+	NamingUnique     = "unique"
+	NamingCheck      = "check"
+	NamingConstraint = "constraint" // Any constraint
+)
+
+// NamingRule requires the names of one kind of object to match a regular expression
+type NamingRule struct {
+	Object  string `json:"object"`            // Object kind, e.g. "index" or "foreign_key"
+	Pattern string `json:"pattern"`           // Regular expression the whole name must match
+	Message string `json:"message,omitempty"` // Optional explanation shown with violations
+}
+
+// constraintKinds maps naming rule kinds to the constraint type they apply to
+var constraintKinds = map[string]string{
+	NamingPrimaryKey: t.PrimaryKeyConstraint,
+	NamingForeignKey: t.ForeignKeyConstraint,
+	NamingUnique:     t.UniqueConstraint,
+	NamingCheck:      t.CheckConstraint,
+}
+
+// CheckNaming reports every object name that violates one of the rules
+func CheckNaming(tables []*t.Table, rules []NamingRule) ([]t.Finding, error) {
+	var findings []t.Finding
+
+	for _, rule := range rules {
+		// Anchor the pattern so that it has to match the whole name
+		re, err := regexp.Compile("^(?:" + rule.Pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid naming rule pattern '%s': %v", rule.Pattern, err)
+		}
+
+		message := rule.Message
+		if message == "" {
+			message = fmt.Sprintf("Name does not match %s naming rule %s", rule.Object, rule.Pattern)
+		}
+
+		check := func(object, name string) {
+			if !re.MatchString(name) {
+				findings = append(findings, t.Finding{
+					Check:   "naming-" + rule.Object,
+					Object:  object,
+					Message: message,
+				})
+			}
+		}
+
+		for _, table := range tables {
+			switch rule.Object {
+			case NamingTable:
+				check(table.Name, table.Name)
+
+			case NamingColumn:
+				for _, col := range table.Columns {
+					check(table.Name+"."+col.Name, col.Name)
+				}
+
+			case NamingIndex:
+				for _, idx := range table.Indexes {
+					if !backsConstraint(table, idx.Name) {
+						check(table.Name+"."+idx.Name, idx.Name)
+					}
+				}
+
+			case NamingConstraint:
+				for _, con := range table.Constraints {
+					check(table.Name+"."+con.Name, con.Name)
+				}
+
+			default:
+				kind, ok := constraintKinds[rule.Object]
+				if !ok {
+					return nil, fmt.Errorf("unknown naming rule object '%s'", rule.Object)
+				}
+				for _, con := range table.Constraints {
+					if con.Type == kind {
+						check(table.Name+"."+con.Name, con.Name)
+					}
+				}
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// backsConstraint reports whether an index was created for a constraint of the table
+func backsConstraint(table *t.Table, indexName string) bool {
+	for _, con := range table.Constraints {
+		if con.Name == indexName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// IndexHints cross-references the sequential scans of a plan with the indexes of
+// the scanned tables. Scans of tables with fewer than minRows estimated rows are
+// left out, as reading them whole is usually the cheapest plan. Filter columns
+// compared for equality come first in a suggested index, then range columns.
+func IndexHints(plan *t.PlanNode, minRows int64, load func(schema, table string) (*t.Table, error)) ([]t.Finding, error) {
+	var findings []t.Finding
+
+	var walk func(node *t.PlanNode) error
+	walk = func(node *t.PlanNode) error {
+		for _, child := range node.Children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+
+		if node.NodeType != "Seq Scan" || node.RelationName == "" || node.TableRows < minRows {
+			return nil
+		}
+
+		if node.Filter == "" {
+			findings = append(findings, t.Finding{
+				Check:   "seq-scan",
+				Object:  node.RelationName,
+				Message: fmt.Sprintf("Sequential scan on %s reads all ~%d rows without a filter an index could serve", node.RelationName, node.TableRows),
+			})
+			return nil
+		}
+
+		table, err := load(node.Schema, node.RelationName)
+		if err != nil {
+			return err
+		}
+
+		columns := filterColumns(node.Filter, table)
+		if len(columns) == 0 {
+			findings = append(findings, t.Finding{
+				Check:   "seq-scan",
+				Object:  node.RelationName,
+				Message: fmt.Sprintf("Sequential scan on %s (~%d rows) filters on expressions no plain index covers: %s", node.RelationName, node.TableRows, node.Filter),
+			})
+			return nil
+		}
+
+		if idx, ok := leadingIndex(table, columns); ok {
+			findings = append(findings, t.Finding{
+				Check:  "seq-scan-unused-index",
+				Object: node.RelationName,
+				Message: fmt.Sprintf("Sequential scan on %s (~%d rows) although index %s on (%s) exists; the filter may not be selective or statistics may be stale",
+					node.RelationName, node.TableRows, idx.Name, strings.Join(idx.Columns, ", ")),
+			})
+			return nil
+		}
+
+		findings = append(findings, t.Finding{
+			Check:  "seq-scan-missing-index",
+			Object: node.RelationName,
+			Message: fmt.Sprintf("Sequential scan on %s (~%d rows) — consider an index on (%s)",
+				node.RelationName, node.TableRows, strings.Join(columns, ", ")),
+		})
+		return nil
+	}
+
+	if err := walk(plan); err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// rangeOperators compare a column against a bound
+var rangeOperators = []string{"<", ">", "<=", ">="}
+
+// filterColumns returns the columns of a table that a filter compares against
+// values, equality comparisons first
+func filterColumns(filter string, table *t.Table) []string {
+	tokens := sqlutil.Tokenize(filter)
+
+	var equality, ranges []string
+	for i, tok := range tokens {
+		if !tok.IsName() || i+1 < len(tokens) && tokens[i+1].IsSymbol(".") {
+			continue
+		}
+		name := tok.Name()
+		if !hasColumn(table, name) || slices.Contains(equality, name) || slices.Contains(ranges, name) {
+			continue
+		}
+
+		// Columns passed to functions, as in lower(email), need an expression index
+		open := 0
+		k := i - 1
+		for k >= 0 && tokens[k].IsSymbol("(") {
+			open++
+			k--
+		}
+		if open > 0 && k >= 0 && tokens[k].IsName() && !tokens[k].Is("AND") && !tokens[k].Is("OR") && !tokens[k].Is("NOT") {
+			continue
+		}
+
+		// Skip the parentheses around the column and casts such as "(status)::text"
+		j := i + 1
+		for j < len(tokens) {
+			if tokens[j].IsSymbol(")") && open > 0 {
+				open--
+				j++
+			} else if tokens[j].IsSymbol("::") {
+				j++
+				for j < len(tokens) && tokens[j].IsName() {
+					j++
+				}
+			} else {
+				break
+			}
+		}
+		if j >= len(tokens) {
+			continue
+		}
+
+		switch op := tokens[j]; {
+		case op.IsSymbol("="):
+			equality = append(equality, name)
+		case op.Kind == sqlutil.Symbol && slices.Contains(rangeOperators, op.Text):
+			ranges = append(ranges, name)
+		}
+	}
+
+	return append(equality, ranges...)
+}
+
+// hasColumn reports whether a table has a column with the given name
+func hasColumn(table *t.Table, name string) bool {
+	for _, col := range table.Columns {
+		if col.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// leadingIndex returns an index whose first column is one of the given columns
+func leadingIndex(table *t.Table, columns []string) (t.Index, bool) {
+	for _, idx := range table.Indexes {
+		if !idx.Expression && idx.Predicate == "" && len(idx.Columns) > 0 && slices.Contains(columns, idx.Columns[0]) {
+			return idx, true
+		}
+	}
+	return t.Index{}, false
+}
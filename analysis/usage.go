@@ -0,0 +1,45 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Scores from which tables are rated hot, and under which they are rated cold
+const (
+	hotScore  = 0.8
+	coldScore = 0.4
+)
+
+// RateUsage scores every table by its accesses relative to the busiest table and
+// sorts them busiest first. Scores are logarithmic, as a few tables usually take
+// most of the traffic: tables scoring at least 0.8 are hot, tables never accessed
+// or scoring under 0.4 are cold, and the others are warm.
+func RateUsage(usage *t.SchemaUsage) {
+	var busiest int64
+	for _, table := range usage.Tables {
+		busiest = max(busiest, table.Accesses())
+	}
+
+	for i := range usage.Tables {
+		table := &usage.Tables[i]
+		if accesses := table.Accesses(); accesses > 0 {
+			table.Score = math.Log1p(float64(accesses)) / math.Log1p(float64(busiest))
+		}
+
+		switch {
+		case table.Score < coldScore:
+			table.Heat = t.HeatCold
+		case table.Score >= hotScore:
+			table.Heat = t.HeatHot
+		default:
+			table.Heat = t.HeatWarm
+		}
+	}
+
+	sort.SliceStable(usage.Tables, func(i, j int) bool {
+		return usage.Tables[i].Accesses() > usage.Tables[j].Accesses()
+	})
+}
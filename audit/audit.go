@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionConnect = "connect"
+	ActionQuery   = "query"
+	ActionExport  = "export"
+	ActionAPI     = "api"
+	ActionWrite   = "write"
+)
+
+// Settings selects where audit events are written. Both destinations may be used at once.
+type Settings struct {
+	File   string `json:"file,omitempty"`   // Append JSON lines to this file
+	Syslog bool   `json:"syslog,omitempty"` // Send events to the local syslog daemon
+}
+
+// Event is a single audited action
+type Event struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`               // Operating system user, or API token name in server mode
+	Database string    `json:"database,omitempty"` // user@host:port/database of the connection
+	Action   string    `json:"action"`
+	Detail   string    `json:"detail"` // Query text, export target and the like
+}
+
+// Target describes the database of a connection for audit events
+func Target(params t.ConnectionParams) string {
+	return fmt.Sprintf("%s@%s:%s/%s", params.User, params.Host, params.Port, params.Database)
+}
+
+// Logger writes audit events. A nil Logger discards all events, so callers
+// need not check whether auditing is enabled.
+type Logger struct {
+	mu      sync.Mutex
+	writers []io.Writer
+	closers []io.Closer
+	user    string
+}
+
+// Open creates a logger for the settings. It returns nil when auditing is disabled.
+func Open(settings *Settings) (*Logger, error) {
+	if settings == nil || (settings.File == "" && !settings.Syslog) {
+		return nil, nil
+	}
+
+	l := &Logger{user: currentUser()}
+
+	if settings.File != "" {
+		f, err := os.OpenFile(settings.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("error opening audit log: %v", err)
+		}
+		l.writers = append(l.writers, f)
+		l.closers = append(l.closers, f)
+	}
+
+	if settings.Syslog {
+		w, err := openSyslog()
+		if err != nil {
+			l.Close()
+			return nil, fmt.Errorf("error connecting to syslog: %v", err)
+		}
+		l.writers = append(l.writers, w)
+		l.closers = append(l.closers, w)
+	}
+
+	return l, nil
+}
+
+// currentUser returns the name of the operating system user
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// Log records an action by the current operating system user
+func (l *Logger) Log(database, action, detail string) {
+	if l == nil {
+		return
+	}
+	l.LogAs(l.user, database, action, detail)
+}
+
+// LogAs records an action on behalf of another user, such as an API token
+func (l *Logger) LogAs(user, database, action, detail string) {
+	if l == nil {
+		return
+	}
+
+	data, err := json.Marshal(Event{
+		Time:     time.Now().UTC(),
+		User:     user,
+		Database: database,
+		Action:   action,
+		Detail:   detail,
+	})
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, w := range l.writers {
+		// Auditing must not break the action itself, so write errors are reported on stderr only
+		if _, err := w.Write(data); err != nil {
+			fmt.Fprintf(os.Stderr, "audit log: %v\n", err)
+		}
+	}
+}
+
+// Close closes the audit destinations
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	var firstErr error
+	for _, c := range l.closers {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,373 @@
+package bigquery
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// BigQueryConnector implements the DatabaseConnector interface for Google BigQuery.
+// The database is the project and schemas are its datasets.
+type BigQueryConnector struct {
+	sqlutil.QueryLog
+
+	client  *bigquery.Client
+	project string // Project connected to, which qualifies the datasets
+}
+
+// Connect creates a client for a BigQuery project, authenticating with the
+// service account key file if given and the application default credentials otherwise
+func (bc *BigQueryConnector) Connect(params t.ConnectionParams) error {
+	if params.Database == "" {
+		return fmt.Errorf("a BigQuery project is required")
+	}
+
+	var opts []option.ClientOption
+	if params.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(params.CredentialsFile))
+	}
+
+	ctx := context.Background()
+	client, err := bigquery.NewClient(ctx, params.Database, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+	if location, ok := params.Settings["location"]; ok {
+		client.Location = location
+	}
+
+	// Test the connection by listing a dataset, which also checks the credentials
+	if _, err := client.Datasets(ctx).Next(); err != nil && err != iterator.Done {
+		client.Close()
+		return fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	bc.client = client
+	bc.project = params.Database
+	return nil
+}
+
+// Disconnect closes the client
+func (bc *BigQueryConnector) Disconnect() error {
+	if bc.client != nil {
+		err := bc.client.Close()
+		bc.client = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// catalog returns the qualified name of an INFORMATION_SCHEMA view of a dataset
+func (bc *BigQueryConnector) catalog(dataset, view string) string {
+	return quoteIdentifier(bc.project+"."+dataset) + ".INFORMATION_SCHEMA." + view
+}
+
+// GetSchemas returns the datasets of the project
+func (bc *BigQueryConnector) GetSchemas() ([]string, error) {
+	if bc.client == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var datasets []string
+	it := bc.client.Datasets(context.Background())
+	for {
+		dataset, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error querying schemas: %v", err)
+		}
+		datasets = append(datasets, dataset.DatasetID)
+	}
+
+	sort.Strings(datasets)
+	return datasets, nil
+}
+
+// GetTables returns a list of tables in the specified dataset
+func (bc *BigQueryConnector) GetTables(schema string) ([]string, error) {
+	if bc.client == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			table_name
+		FROM
+			` + bc.catalog(schema, "TABLES") + `
+		WHERE
+			table_type = 'BASE TABLE'
+		ORDER BY
+			table_name
+	`
+
+	it, err := bc.query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+
+	var tables []string
+	for {
+		var row struct {
+			TableName string `bigquery:"table_name"`
+		}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, row.TableName)
+	}
+
+	return tables, nil
+}
+
+// GetTableStructure returns the structure of the specified table. BigQuery has
+// no indexes; partitioning and clustering are reported as table properties.
+func (bc *BigQueryConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if bc.client == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Check if the table exists, reading its description at the same time
+	it, err := bc.query(`
+		SELECT
+			t.table_name,
+			o.option_value AS description
+		FROM
+			`+bc.catalog(schema, "TABLES")+` t
+		LEFT JOIN
+			`+bc.catalog(schema, "TABLE_OPTIONS")+` o
+			ON o.table_name = t.table_name AND o.option_name = 'description'
+		WHERE
+			t.table_name = @table
+	`, bigquery.QueryParameter{Name: "table", Value: tableName})
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	var info struct {
+		TableName   string              `bigquery:"table_name"`
+		Description bigquery.NullString `bigquery:"description"`
+	}
+	if err := it.Next(&info); err == iterator.Done {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	} else if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+
+	// Options are stored as literals, e.g. "a \"quoted\" description"
+	table := &t.Table{Name: tableName, Schema: schema, Comment: unquoteOption(info.Description.StringVal)}
+
+	var partitioning string
+	var clustering []string
+	if table.Columns, partitioning, clustering, err = bc.getColumns(schema, tableName); err != nil {
+		return nil, err
+	}
+	if partitioning != "" {
+		table.Properties = append(table.Properties, t.Property{Name: "Partitioned by", Value: partitioning})
+	}
+	if len(clustering) > 0 {
+		table.Properties = append(table.Properties, t.Property{Name: "Clustered by", Value: strings.Join(clustering, ", ")})
+	}
+
+	if err := bc.addConstraints(table); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// getColumns returns the columns of a table with their descriptions, and the
+// partitioning column and clustering columns in clustering order
+func (bc *BigQueryConnector) getColumns(schema, tableName string) ([]t.Column, string, []string, error) {
+	query := `
+		SELECT
+			c.column_name,
+			c.data_type,
+			c.is_nullable,
+			c.column_default,
+			c.is_partitioning_column,
+			c.clustering_ordinal_position,
+			p.description
+		FROM
+			` + bc.catalog(schema, "COLUMNS") + ` c
+		LEFT JOIN
+			` + bc.catalog(schema, "COLUMN_FIELD_PATHS") + ` p
+			ON p.table_name = c.table_name AND p.field_path = c.column_name
+		WHERE
+			c.table_name = @table
+		ORDER BY
+			c.ordinal_position
+	`
+
+	it, err := bc.query(query, bigquery.QueryParameter{Name: "table", Value: tableName})
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("error querying columns: %v", err)
+	}
+
+	var columns []t.Column
+	var partitioning string
+	clustering := make(map[int64]string)
+	for {
+		var row struct {
+			ColumnName   string              `bigquery:"column_name"`
+			DataType     string              `bigquery:"data_type"`
+			IsNullable   string              `bigquery:"is_nullable"`
+			Default      bigquery.NullString `bigquery:"column_default"`
+			Partitioning string              `bigquery:"is_partitioning_column"`
+			Clustering   bigquery.NullInt64  `bigquery:"clustering_ordinal_position"`
+			Description  bigquery.NullString `bigquery:"description"`
+		}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+
+		col := t.Column{
+			Name:     row.ColumnName,
+			Type:     row.DataType,
+			Nullable: row.IsNullable == "YES",
+			Comment:  row.Description.StringVal,
+		}
+		// Columns without a default report the string NULL
+		if row.Default.Valid && row.Default.StringVal != "NULL" {
+			col.DefaultValue = sql.NullString{String: row.Default.StringVal, Valid: true}
+		}
+		if row.Partitioning == "YES" {
+			partitioning = row.ColumnName
+		}
+		if row.Clustering.Valid {
+			clustering[row.Clustering.Int64] = row.ColumnName
+		}
+		columns = append(columns, col)
+	}
+
+	clusteredBy := make([]string, 0, len(clustering))
+	for pos := int64(1); pos <= int64(len(clustering)); pos++ {
+		clusteredBy = append(clusteredBy, clustering[pos])
+	}
+
+	return columns, partitioning, clusteredBy, nil
+}
+
+// addConstraints adds the primary and foreign keys of a table, which BigQuery
+// declares but does not enforce. They come from the table metadata, as
+// INFORMATION_SCHEMA does not pair the columns of multi-column foreign keys.
+func (bc *BigQueryConnector) addConstraints(table *t.Table) error {
+	meta, err := bc.client.Dataset(table.Schema).Table(table.Name).Metadata(context.Background())
+	if err != nil {
+		return fmt.Errorf("error querying constraints: %v", err)
+	}
+	if meta.TableConstraints == nil {
+		return nil
+	}
+
+	if pk := meta.TableConstraints.PrimaryKey; pk != nil {
+		table.Constraints = append(table.Constraints, t.Constraint{
+			Name:       "PRIMARY KEY",
+			Type:       t.PrimaryKeyConstraint,
+			Columns:    pk.Columns,
+			Definition: fmt.Sprintf("PRIMARY KEY (%s) NOT ENFORCED", strings.Join(pk.Columns, ", ")),
+		})
+		for i := range table.Columns {
+			if indexOf(pk.Columns, table.Columns[i].Name) >= 0 {
+				table.Columns[i].IsPrimaryKey = true
+			}
+		}
+	}
+
+	for _, fk := range meta.TableConstraints.ForeignKeys {
+		target := fk.ReferencedTable.TableID
+		if fk.ReferencedTable.DatasetID != table.Schema {
+			target = fk.ReferencedTable.DatasetID + "." + target
+		}
+
+		var columns, refColumns []string
+		for _, ref := range fk.ColumnReferences {
+			columns = append(columns, ref.ReferencingColumn)
+			refColumns = append(refColumns, ref.ReferencedColumn)
+
+			pos := indexOf(columnNames(table.Columns), ref.ReferencingColumn)
+			if pos >= 0 && !table.Columns[pos].ForeignKey.Valid {
+				table.Columns[pos].ForeignKey = sql.NullString{
+					String: fmt.Sprintf("%s (%s)", target, ref.ReferencedColumn),
+					Valid:  true,
+				}
+			}
+		}
+
+		table.Constraints = append(table.Constraints, t.Constraint{
+			Name:    fk.Name,
+			Type:    t.ForeignKeyConstraint,
+			Columns: columns,
+			Definition: fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s) NOT ENFORCED",
+				strings.Join(columns, ", "), target, strings.Join(refColumns, ", ")),
+		})
+	}
+
+	return nil
+}
+
+// unquoteOption returns the value of a string table option, stored as a quoted literal
+func unquoteOption(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+		value = strings.NewReplacer(`\"`, `"`, `\n`, "\n", `\\`, `\`).Replace(value)
+	}
+	return value
+}
+
+// quoteIdentifier quotes a name with backticks for use in queries
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+}
+
+// columnNames returns the names of columns
+func columnNames(columns []t.Column) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// indexOf returns the position of a name in a list, or -1
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// NewBigQueryConnector creates a connector for BigQuery projects
+func NewBigQueryConnector() t.DatabaseConnector {
+	return &BigQueryConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverBigQuery, NewBigQueryConnector, t.ConnectorInfo{
+		Title: "BigQuery",
+		// The location of the jobs is a session setting
+		Fields: []string{t.FieldDatabase, t.FieldSchema, t.FieldCredentials, t.FieldSettings},
+		Labels: map[string]string{t.FieldDatabase: "Project", t.FieldSchema: "Dataset"},
+	})
+}
@@ -0,0 +1,20 @@
+package bigquery
+
+import (
+	"context"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// query runs a catalog query with named parameters, recording it in the query log
+func (bc *BigQueryConnector) query(query string, params ...bigquery.QueryParameter) (*bigquery.RowIterator, error) {
+	args := make([]any, len(params))
+	for i, param := range params {
+		args[i] = param.Value
+	}
+	bc.LogQuery(query, args)
+
+	q := bc.client.Query(query)
+	q.Parameters = params
+	return q.Read(context.Background())
+}
@@ -0,0 +1,71 @@
+package bigquery
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	"google.golang.org/api/iterator"
+)
+
+// GetViews returns the views and materialized views of the dataset, with their
+// columns and SELECT statements
+func (bc *BigQueryConnector) GetViews(schema string) ([]t.View, error) {
+	if bc.client == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			t.table_name,
+			t.table_type,
+			t.ddl,
+			o.option_value AS description
+		FROM
+			` + bc.catalog(schema, "TABLES") + ` t
+		LEFT JOIN
+			` + bc.catalog(schema, "TABLE_OPTIONS") + ` o
+			ON o.table_name = t.table_name AND o.option_name = 'description'
+		WHERE
+			t.table_type IN ('VIEW', 'MATERIALIZED VIEW')
+		ORDER BY
+			t.table_name
+	`
+
+	it, err := bc.query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+
+	var views []t.View
+	for {
+		var row struct {
+			TableName   string              `bigquery:"table_name"`
+			TableType   string              `bigquery:"table_type"`
+			DDL         string              `bigquery:"ddl"`
+			Description bigquery.NullString `bigquery:"description"`
+		}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		views = append(views, t.View{
+			Name:         row.TableName,
+			Schema:       schema,
+			Definition:   sqlutil.ViewQuery(row.DDL),
+			Materialized: row.TableType == "MATERIALIZED VIEW",
+			Comment:      unquoteOption(row.Description.StringVal),
+		})
+	}
+
+	for i := range views {
+		if views[i].Columns, _, _, err = bc.getColumns(schema, views[i].Name); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
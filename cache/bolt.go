@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltBucket is the bucket holding the cached values
+var boltBucket = []byte("metadata")
+
+// Bolt is a cache in a bbolt file on disk. Every value is stored after the
+// Unix time in nanoseconds it expires at, 0 if it never does.
+type Bolt struct {
+	db *bolt.DB
+}
+
+// OpenBolt opens or creates a bbolt cache file. The file is locked while
+// open, so processes cannot share it.
+func OpenBolt(path string) (*Bolt, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening cache file %s: %v", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error preparing cache file %s: %v", path, err)
+	}
+	return &Bolt{db: db}, nil
+}
+
+// Get returns the value stored under a key, dropping it if it expired
+func (b *Bolt) Get(key string) ([]byte, bool, error) {
+	var value []byte
+	expired := false
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucket).Get([]byte(key))
+		if len(data) < 8 {
+			return nil
+		}
+		if expires := int64(binary.BigEndian.Uint64(data)); expires != 0 && time.Now().UnixNano() > expires {
+			expired = true
+			return nil
+		}
+		// The data is only valid during the transaction
+		value = append([]byte{}, data[8:]...)
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading cache: %v", err)
+	}
+	if expired {
+		err := b.db.Update(func(tx *bolt.Tx) error {
+			return tx.Bucket(boltBucket).Delete([]byte(key))
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("error dropping expired cache entry: %v", err)
+		}
+	}
+	return value, value != nil, nil
+}
+
+// Set stores a value under a key
+func (b *Bolt) Set(key string, value []byte, ttl time.Duration) error {
+	data := make([]byte, 8, 8+len(value))
+	if ttl > 0 {
+		binary.BigEndian.PutUint64(data, uint64(time.Now().Add(ttl).UnixNano()))
+	}
+	data = append(data, value...)
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), data)
+	})
+	if err != nil {
+		return fmt.Errorf("error writing cache: %v", err)
+	}
+	return nil
+}
+
+// Close closes the cache file
+func (b *Bolt) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cache stores introspection results, serialized, for a limited time. The
+// memory backend serves a single process, while the Redis one is shared by
+// every replica of the service pointed at it.
+type Cache interface {
+	// Get returns the value stored under a key, and false if there is none or it expired
+	Get(key string) ([]byte, bool, error)
+	// Set stores a value under a key, expiring after ttl, or never if ttl is 0
+	Set(key string, value []byte, ttl time.Duration) error
+	Close() error
+}
+
+// Open opens the cache backend at a location:
+//
+//	memory               in the memory of the process
+//	bolt:/path/cache.db  in a bbolt file on disk, kept across restarts
+//	redis://host:6379/0  in Redis, shared by the processes using it
+func Open(location string) (Cache, error) {
+	switch {
+	case location == "memory":
+		return NewMemory(), nil
+	case strings.HasPrefix(location, "bolt:"):
+		return OpenBolt(strings.TrimPrefix(location, "bolt:"))
+	case strings.HasPrefix(location, "redis://"), strings.HasPrefix(location, "rediss://"):
+		return OpenRedis(location)
+	}
+	return nil, fmt.Errorf("unknown cache '%s', expected memory, bolt:FILE or redis://HOST:PORT/DB", location)
+}
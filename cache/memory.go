@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Memory is a cache in the memory of the process
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+// memoryEntry is a cached value with its expiry, zero if it never expires
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemory creates an empty in-memory cache
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]memoryEntry)}
+}
+
+// Get returns the value stored under a key, dropping it if it expired
+func (m *Memory) Get(key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set stores a value under a key
+func (m *Memory) Set(key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	m.entries[key] = entry
+	return nil
+}
+
+// Close forgets every entry
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]memoryEntry)
+	return nil
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds every Redis command, so an unreachable server delays
+// requests only briefly before they read from the database
+const redisTimeout = 2 * time.Second
+
+// Redis is a cache in a Redis server, whose entries expire with Redis TTLs
+type Redis struct {
+	client *redis.Client
+}
+
+// OpenRedis connects to the Redis server of a redis:// or rediss:// URL, e.g.
+// redis://:password@host:6379/0
+func OpenRedis(url string) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %v", err)
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error connecting to Redis at %s: %v", opts.Addr, err)
+	}
+	return &Redis{client: client}, nil
+}
+
+// Get returns the value stored under a key
+func (r *Redis) Get(key string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading cache: %v", err)
+	}
+	return value, true, nil
+}
+
+// Set stores a value under a key
+func (r *Redis) Set(key string, value []byte, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := r.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("error writing cache: %v", err)
+	}
+	return nil
+}
+
+// Close disconnects from the Redis server
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/carloberd/db-reader/analysis"
+	"github.com/carloberd/db-reader/diff"
+	"github.com/carloberd/db-reader/export"
+)
+
+// runArchive suggests tables to partition or archive from how their size grew
+// across snapshots taken with recorded stats
+func runArchive(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("archive", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: db-reader archive [flags] SNAPSHOT...")
+		fmt.Fprintln(stderr, "\nThe snapshots must be taken with 'db-reader snapshot -stats', at least a day apart.")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "text", "output format: text or md")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "md" {
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+	if fs.NArg() < 2 {
+		return fmt.Errorf("at least two snapshots are needed to tell how tables grow")
+	}
+
+	var snapshots []*diff.Schema
+	for _, path := range fs.Args() {
+		snapshot, err := diff.ReadSnapshot(path)
+		if err != nil {
+			return err
+		}
+		if len(snapshot.Stats) == 0 {
+			return fmt.Errorf("snapshot %s has no table sizes; take it with 'db-reader snapshot -stats'", path)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Taken.Before(snapshots[j].Taken) })
+
+	findings := analysis.ArchiveCandidates(snapshots)
+	if *format == "md" {
+		return export.WriteFindingsMarkdown(stdout, snapshots[len(snapshots)-1].Name, findings)
+	}
+
+	if len(findings) == 0 {
+		fmt.Fprintln(stdout, "No tables to partition or archive.")
+	}
+	for i, finding := range findings {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintf(stdout, "%s (%s)\n  %s\n", finding.Object, finding.Check, finding.Message)
+	}
+	return nil
+}
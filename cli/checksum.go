@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/carloberd/db-reader/audit"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// tableSide is a connected database taking part in a data comparison
+type tableSide struct {
+	name      string
+	connector t.DatabaseConnector
+	params    *t.ConnectionParams
+	tables    []string
+}
+
+// runChecksum compares row counts and content hashes of tables between two profiles
+func runChecksum(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("checksum", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	var selection selectionFlags
+	conn.registerConfig(fs)
+	selection.register(fs)
+	from := fs.String("from", "", "profile of the source database, e.g. the primary")
+	to := fs.String("to", "", "profile of the database expected to hold the same data, e.g. the replica")
+	rowsOnly := fs.Bool("rows-only", false, "compare row counts only, skipping the slower content hashes")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" || *to == "" {
+		return fmt.Errorf("both --from and --to profiles are required")
+	}
+
+	logger, err := conn.openAuditLog()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+
+	var sides [2]*tableSide
+	for i, profile := range []string{*from, *to} {
+		connector, params, err := conn.connectProfile(profile, stderr)
+		if err != nil {
+			return fmt.Errorf("%s: %v", profile, err)
+		}
+		defer connector.Disconnect()
+
+		tables, err := selection.selectTables(connector, params.Schema)
+		if err != nil {
+			return fmt.Errorf("%s: %v", profile, err)
+		}
+		sides[i] = &tableSide{name: profile, connector: connector, params: params, tables: tables}
+	}
+
+	// Tables missing on one side are reported, the others compared
+	names := append(slices.Clone(sides[0].tables), sides[1].tables...)
+	sort.Strings(names)
+	names = slices.Compact(names)
+
+	for _, side := range sides {
+		logger.Log(audit.Target(*side.params), audit.ActionQuery,
+			fmt.Sprintf("data checksum of %d tables", len(side.tables)))
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "TABLE\t%s ROWS\t%s ROWS\tRESULT\n", *from, *to)
+
+	differing := 0
+	for _, name := range names {
+		missing := ""
+		for _, side := range sides {
+			if !slices.Contains(side.tables, name) {
+				missing = side.name
+			}
+		}
+		if missing != "" {
+			differing++
+			fmt.Fprintf(tw, "%s\t-\t-\tmissing in %s\n", name, missing)
+			continue
+		}
+
+		var rows [2]int64
+		var hashes [2]string
+		for i, side := range sides {
+			if rows[i], hashes[i], err = checksumTable(side, name, *rowsOnly); err != nil {
+				return fmt.Errorf("%s: %v", side.name, err)
+			}
+		}
+
+		result := "match"
+		switch {
+		case rows[0] != rows[1]:
+			result = "row counts differ"
+		case hashes[0] != hashes[1]:
+			result = "contents differ"
+		}
+		if result != "match" {
+			differing++
+		}
+
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", name, rows[0], rows[1], result)
+	}
+	tw.Flush()
+
+	if differing > 0 {
+		return fmt.Errorf("%d of %d tables differ", differing, len(names))
+	}
+	return nil
+}
+
+// checksumTable returns the row count and, unless only rows are compared, the content hash of a table
+func checksumTable(side *tableSide, table string, rowsOnly bool) (int64, string, error) {
+	ctx := context.Background()
+
+	if !rowsOnly {
+		checksummer, ok := side.connector.(t.TableChecksummer)
+		if !ok {
+			return 0, "", fmt.Errorf("content checksums are not supported for this database; use --rows-only")
+		}
+		return checksummer.ChecksumTable(ctx, side.params.Schema, table)
+	}
+
+	counter, ok := side.connector.(t.RowCounter)
+	if !ok {
+		return 0, "", fmt.Errorf("row counts are not supported for this database")
+	}
+	rows, err := counter.CountRows(ctx, side.params.Schema, table)
+	return rows, "", err
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// command is a subcommand of the command line interface
+type command struct {
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) error
+}
+
+// commands lists the available subcommands by name
+var commands = map[string]command{
+	"archive":   {"Suggest tables to partition or archive from their growth across snapshots", runArchive},
+	"checksum":  {"Compare row counts and content hashes of tables between two profiles", runChecksum},
+	"describe":  {"Print the columns, indexes, constraints and triggers of tables", runDescribe},
+	"diff":      {"Compare the schemas of two connection profiles or snapshots", runDiff},
+	"export":    {"Export tables as a Mermaid or DOT diagram, baseline migration, Markdown or AsciiDoc docs and dictionary, JSON Schemas, HTML or PDF report, column inventory, Excel workbook, SQL DDL, OpenAPI document, GraphQL SDL, Prisma schema, JSON, JSON Lines, YAML or through a custom template", runExport},
+	"init":      {"Create a connection profile step by step", runInit},
+	"render":    {"Render the statistics dashboard and ER diagram to PNG files", runRender},
+	"sequences": {"List the sequences of a schema with their settings and owning columns", runSequences},
+	"serve":     {"Serve schema metadata over a read-only REST API and linkable web pages", runServe},
+	"snapshot":  {"Save the schema as a JSON snapshot to diff against later", runSnapshot},
+	"usage":     {"List tables from the most to the least used since statistics were reset, rated hot, warm or cold", runUsage},
+	"validate":  {"Check a database against an expected schema declaration", runValidate},
+	"views":     {"List the views of a schema with their columns and definitions", runViews},
+}
+
+// Run executes a command line invocation and returns the process exit code
+func Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return 0
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command '%s'\n\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+
+	err := cmd.run(args[1:], stdout, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// printUsage lists the available commands
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: db-reader [command] [flags]")
+	fmt.Fprintln(w, "\nWithout a command the graphical interface is started.")
+	fmt.Fprintln(w, "\nCommands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintln(w, "\nRun 'db-reader [command] -h' for the flags of a command.")
+}
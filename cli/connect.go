@@ -0,0 +1,301 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/filter"
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	"golang.org/x/term"
+)
+
+// connectionFlags holds the flags selecting the database to inspect
+type connectionFlags struct {
+	params     t.ConnectionParams
+	profile    string
+	configPath string
+	debugSQL   bool
+}
+
+// register adds the connection flags to a flag set
+func (cf *connectionFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&cf.params.Driver, "driver", t.DriverPostgres, "database driver: "+strings.Join(t.Drivers(), ", "))
+	fs.StringVar(&cf.params.Host, "host", "localhost", "database server host")
+	fs.StringVar(&cf.params.Port, "port", "", "database server port (default 5432, 3306 for mysql)")
+	fs.StringVar(&cf.params.User, "user", "", "database user (default postgres, root for mysql)")
+	fs.StringVar(&cf.params.Password, "password", "", "database password (default $PGPASSWORD for postgres, $MYSQL_PWD for mysql)")
+	fs.StringVar(&cf.params.Database, "database", "", "database name (default postgres for postgres), file path for sqlite, duckdb, pgdump and snapshot, bigquery project, odbc data source or trino catalog")
+	fs.StringVar(&cf.params.Schema, "schema", "", "schema, bigquery dataset or trino catalog.schema to inspect (default public, the database for mysql)")
+	fs.StringVar(&cf.params.Account, "account", "", "snowflake account identifier")
+	fs.StringVar(&cf.params.Warehouse, "warehouse", "", "snowflake warehouse")
+	fs.StringVar(&cf.params.Role, "role", "", "snowflake role")
+	fs.StringVar(&cf.params.CredentialsFile, "credentials", "", "bigquery service account key file (default application credentials)")
+	fs.StringVar(&cf.params.PgBouncer, "pgbouncer", "", "postgres through pgbouncer in transaction pooling mode: on or off (default detected)")
+	fs.StringVar(&cf.params.Secret, "secret", "", "user and password from a secret store: vault:path#field or aws:secret-id")
+	fs.StringVar(&cf.profile, "profile", "", "saved connection profile to use instead of the connection flags")
+	cf.registerConfig(fs)
+}
+
+// registerConfig adds only the flags locating the profiles, for commands that
+// connect by profile name
+func (cf *connectionFlags) registerConfig(fs *flag.FlagSet) {
+	fs.StringVar(&cf.configPath, "config", "", "config or workspace file holding the profiles (default user config)")
+	fs.BoolVar(&cf.debugSQL, "debug-sql", false, "print the catalog queries to stderr as they run")
+}
+
+// connect opens a connection using either the selected profile or the flags.
+// Flags given explicitly on the command line override the profile.
+func (cf *connectionFlags) connect(fs *flag.FlagSet, stderr io.Writer) (t.DatabaseConnector, *t.ConnectionParams, error) {
+	params := cf.params
+
+	// Rather than trying the default server, set up a profile on first run
+	if cf.firstRun(fs) {
+		path, err := cf.path()
+		if err != nil {
+			return nil, nil, err
+		}
+		profile, password, err := newWizard(os.Stdin, stderr).run(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		params = profile.Params
+		params.Password = password
+		cf.profile = profile.Name
+		return cf.open(params, stderr)
+	}
+
+	if cf.profile != "" {
+		cfg, err := cf.loadConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		profile, ok := cfg.Profile(cf.profile)
+		if !ok {
+			return nil, nil, fmt.Errorf("profile '%s' not found", cf.profile)
+		}
+
+		explicit := params
+		params = profile.Params
+		if params.Password, err = profilePassword(cfg, profile, stderr); err != nil {
+			return nil, nil, err
+		}
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "driver":
+				params.Driver = explicit.Driver
+			case "host":
+				params.Host = explicit.Host
+			case "port":
+				params.Port = explicit.Port
+			case "user":
+				params.User = explicit.User
+			case "password":
+				params.Password = explicit.Password
+			case "database":
+				params.Database = explicit.Database
+			case "schema":
+				params.Schema = explicit.Schema
+			case "account":
+				params.Account = explicit.Account
+			case "warehouse":
+				params.Warehouse = explicit.Warehouse
+			case "role":
+				params.Role = explicit.Role
+			case "credentials":
+				params.CredentialsFile = explicit.CredentialsFile
+			case "secret":
+				params.Secret = explicit.Secret
+			case "pgbouncer":
+				params.PgBouncer = explicit.PgBouncer
+			}
+		})
+	}
+
+	// Read the password from the environment rather than as a flag default,
+	// so that it does not show up in the usage text
+	if env := passwordEnv(params.Driver); params.Password == "" && env != "" {
+		params.Password = os.Getenv(env)
+	}
+	params.ApplyDefaults()
+
+	return cf.open(params, stderr)
+}
+
+// passwordEnv returns the environment variable the usual client of a driver reads
+// the password from, "" if there is none
+func passwordEnv(driver string) string {
+	switch driver {
+	case t.DriverPostgres:
+		return "PGPASSWORD"
+	case t.DriverMySQL:
+		return "MYSQL_PWD"
+	default:
+		return ""
+	}
+}
+
+// connectProfile opens a connection using a saved profile as is, for commands
+// working on several databases at once
+func (cf *connectionFlags) connectProfile(name string, stderr io.Writer) (t.DatabaseConnector, *t.ConnectionParams, error) {
+	cfg, err := cf.loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	profile, ok := cfg.Profile(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("profile '%s' not found", name)
+	}
+
+	params := profile.Params
+	if params.Password, err = profilePassword(cfg, profile, stderr); err != nil {
+		return nil, nil, err
+	}
+	return cf.open(params, stderr)
+}
+
+// open connects to the database, printing catalog queries if requested.
+// References to environment variables and to secret stores are resolved first.
+func (cf *connectionFlags) open(params t.ConnectionParams, stderr io.Writer) (t.DatabaseConnector, *t.ConnectionParams, error) {
+	params, err := config.ExpandEnv(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if params, err = config.ResolveSecret(context.Background(), params); err != nil {
+		return nil, nil, err
+	}
+	connector, err := t.NewConnector(params.Driver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if logger, ok := connector.(t.QueryLogger); ok && cf.debugSQL {
+		logger.SetQueryLog(func(query string, args []any) {
+			fmt.Fprintln(stderr, sqlutil.FormatLogged(query, args))
+		})
+	}
+	if err := connector.Connect(params); err != nil {
+		return nil, nil, err
+	}
+
+	return connector, &params, nil
+}
+
+// passphraseEnv names the environment variable holding the master passphrase for scripts
+const passphraseEnv = "DB_READER_PASSPHRASE"
+
+// profilePassword returns the password of a profile, asking for the master
+// passphrase on the terminal if the password is encrypted
+func profilePassword(cfg *config.Config, profile *config.Profile, stderr io.Writer) (string, error) {
+	if profile.EncryptedPassword == "" || cfg.Encryption == nil {
+		return cfg.ProfilePassword(profile, nil)
+	}
+
+	passphrase, ok := os.LookupEnv(passphraseEnv)
+	if !ok {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return "", fmt.Errorf("the password of profile '%s' is encrypted; set %s", profile.Name, passphraseEnv)
+		}
+		fmt.Fprint(stderr, "Master passphrase: ")
+		input, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(stderr)
+		if err != nil {
+			return "", fmt.Errorf("error reading passphrase: %v", err)
+		}
+		passphrase = string(input)
+	}
+
+	sealer, err := cfg.Encryption.Unlock(passphrase)
+	if err != nil {
+		return "", err
+	}
+	return cfg.ProfilePassword(profile, sealer)
+}
+
+// path returns the config file given by the flags or the default one
+func (cf *connectionFlags) path() (string, error) {
+	if cf.configPath != "" {
+		return cf.configPath, nil
+	}
+	return config.DefaultPath()
+}
+
+// loadConfig reads the config file given by the flags or the default one
+func (cf *connectionFlags) loadConfig() (*config.Config, error) {
+	path, err := cf.path()
+	if err != nil {
+		return nil, err
+	}
+	return config.Load(path)
+}
+
+// openAuditLog starts audit logging if the config file enables it. A missing
+// config file disables auditing.
+func (cf *connectionFlags) openAuditLog() (*audit.Logger, error) {
+	cfg, err := cf.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return audit.Open(cfg.Audit)
+}
+
+// selectionFlags holds the flags choosing which tables a command works on
+type selectionFlags struct {
+	tables string
+	match  string
+	system bool
+}
+
+// register adds the table selection flags to a flag set
+func (sf *selectionFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&sf.tables, "tables", "", "comma separated list of tables (default all)")
+	fs.StringVar(&sf.match, "match", "", "comma separated table name patterns, e.g. 'order_*,invoice*'")
+	fs.BoolVar(&sf.system, "system-tables", false, "include extension and migration tool tables")
+}
+
+// selectTables returns the names of the selected tables of a schema
+func (sf *selectionFlags) selectTables(connector t.DatabaseConnector, schema string) ([]string, error) {
+	names, err := connector.GetTables(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sf.system {
+		var extensionTables []string
+		if inspector, ok := connector.(t.ExtensionInspector); ok {
+			if extensionTables, err = inspector.GetExtensionTables(schema); err != nil {
+				return nil, err
+			}
+		}
+		names = filter.Tables(names, extensionTables)
+	}
+
+	selection := filter.Selection{
+		Tables: filter.ParseList(sf.tables),
+		Match:  filter.ParseList(sf.match),
+	}
+	return selection.Apply(names)
+}
+
+// loadTables returns the structure of the selected tables of a schema
+func (sf *selectionFlags) loadTables(connector t.DatabaseConnector, schema string) ([]*t.Table, error) {
+	names, err := sf.selectTables(connector, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []*t.Table
+	for _, name := range names {
+		table, err := connector.GetTableStructure(schema, name)
+		if err != nil {
+			return nil, fmt.Errorf("error loading table %s: %v", name, err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
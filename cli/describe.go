@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/carloberd/db-reader/export"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// runDescribe prints the structure of tables: columns, indexes, constraints and
+// triggers as plain text, or in another export format written as one document
+func runDescribe(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("describe", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: db-reader describe [flags] TABLE...")
+		fs.PrintDefaults()
+	}
+
+	var conn connectionFlags
+	conn.register(fs)
+	formatName := fs.String("format", "text", "export format of the description, one written as a single document")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("no table given")
+	}
+	format, ok := export.LookupFormat(*formatName)
+	if !ok || format.Exporter == nil {
+		return fmt.Errorf("unknown export format '%s'", *formatName)
+	}
+
+	connector, params, err := conn.connect(fs, stderr)
+	if err != nil {
+		return err
+	}
+	defer connector.Disconnect()
+
+	var tables []*t.Table
+	for _, name := range fs.Args() {
+		table, err := connector.GetTableStructure(params.Schema, name)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, table)
+	}
+	schema := &export.Schema{Name: params.Schema, Driver: params.Driver, Tables: tables}
+	return format.Exporter.Export(stdout, schema, export.Options{Connector: connector})
+}
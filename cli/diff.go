@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/carloberd/db-reader/diff"
+	"github.com/carloberd/db-reader/filter"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Diff report formats
+const (
+	diffFormatText = "text"
+	diffFormatHTML = "html"
+)
+
+// runDiff compares the schemas of two saved connection profiles or snapshots
+func runDiff(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	var selection selectionFlags
+	conn.registerConfig(fs)
+	selection.register(fs)
+	from := fs.String("from", "", "profile or snapshot file to compare from, e.g. prod")
+	to := fs.String("to", "", "profile or snapshot file to compare to, e.g. dev")
+	baseline := fs.String("baseline", "", "snapshot file both sides are compared against, reporting which side diverged")
+	schemas := fs.String("schemas", "", "comma separated schemas to compare (default the schema of each profile)")
+	format := fs.String("format", diffFormatText, "report format: text or html")
+	output := fs.String("output", "", "output file (default stdout)")
+
+	var ignore diff.Ignore
+	ignoreFile := fs.String("ignore-file", "", "JSON file with ignore rules, combined with the ignore flags")
+	fs.BoolVar(&ignore.Comments, "ignore-comments", false, "ignore table and column comments")
+	fs.BoolVar(&ignore.IndexNames, "ignore-index-names", false, "match indexes by definition instead of by name")
+	ignoreSchemas := fs.String("ignore-schemas", "", "comma separated schema name patterns to leave out")
+	ignoreTables := fs.String("ignore-tables", "", "comma separated table name patterns to leave out, e.g. 'tmp_*,audit.*'")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" || *to == "" {
+		return fmt.Errorf("both --from and --to are required")
+	}
+	if *format != diffFormatText && *format != diffFormatHTML {
+		return fmt.Errorf("unknown report format '%s'", *format)
+	}
+	if *format == diffFormatHTML && *baseline != "" {
+		return fmt.Errorf("HTML reports compare two schemas and cannot be used with --baseline")
+	}
+
+	if *ignoreFile != "" {
+		rules, err := loadIgnoreRules(*ignoreFile)
+		if err != nil {
+			return err
+		}
+		ignore.Comments = ignore.Comments || rules.Comments
+		ignore.IndexNames = ignore.IndexNames || rules.IndexNames
+		ignore.Schemas = append(ignore.Schemas, rules.Schemas...)
+		ignore.Tables = append(ignore.Tables, rules.Tables...)
+	}
+	ignore.Schemas = append(ignore.Schemas, filter.ParseList(*ignoreSchemas)...)
+	ignore.Tables = append(ignore.Tables, filter.ParseList(*ignoreTables)...)
+
+	before, err := loadSchema(&conn, &selection, *from, filter.ParseList(*schemas), stderr)
+	if err != nil {
+		return err
+	}
+	after, err := loadSchema(&conn, &selection, *to, filter.ParseList(*schemas), stderr)
+	if err != nil {
+		return err
+	}
+
+	w := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *baseline != "" {
+		base, err := diff.ReadSnapshot(*baseline)
+		if err != nil {
+			return err
+		}
+		printDivergences(w, diff.CompareBaseline(base, before, after, ignore), before.Name, after.Name)
+		return nil
+	}
+
+	changes := diff.Compare(before, after, ignore)
+	if *format == diffFormatHTML {
+		return diff.WriteHTML(w, before, after, changes)
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No differences found.")
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Fprintln(w, change)
+	}
+	return nil
+}
+
+// printDivergences lists the changes from a baseline by the side that made them
+func printDivergences(w io.Writer, divergences []diff.Divergence, nameA, nameB string) {
+	if len(divergences) == 0 {
+		fmt.Fprintln(w, "Both sides match the baseline.")
+		return
+	}
+
+	var onlyA, onlyB, same, conflicts int
+	for _, d := range divergences {
+		switch {
+		case d.Same():
+			same++
+			fmt.Fprintf(w, "[both] %s\n", d.A)
+		case d.Conflict():
+			conflicts++
+			fmt.Fprintf(w, "[conflict] %s: %s\n", nameA, d.A)
+			fmt.Fprintf(w, "[conflict] %s: %s\n", nameB, d.B)
+		case d.A != nil:
+			onlyA++
+			fmt.Fprintf(w, "[%s] %s\n", nameA, d.A)
+		default:
+			onlyB++
+			fmt.Fprintf(w, "[%s] %s\n", nameB, d.B)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d changes only in %s, %d only in %s, %d in both, %d conflicting\n",
+		onlyA, nameA, onlyB, nameB, same, conflicts)
+}
+
+// loadSchema reads the tables to compare from a snapshot file or, if no such
+// file exists, from the database of a saved profile
+func loadSchema(conn *connectionFlags, selection *selectionFlags, source string, schemas []string, stderr io.Writer) (*diff.Schema, error) {
+	if _, err := os.Stat(source); err == nil {
+		return diff.ReadSnapshot(source)
+	}
+
+	connector, params, err := conn.connectProfile(source, stderr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+	defer connector.Disconnect()
+
+	tables, err := selection.loadSchemas(connector, schemas, params.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+	return &diff.Schema{Name: source, Tables: tables}, nil
+}
+
+// loadSchemas returns the selected tables of several schemas, or of the
+// default schema if none are given
+func (sf *selectionFlags) loadSchemas(connector t.DatabaseConnector, schemas []string, defaultSchema string) ([]*t.Table, error) {
+	if len(schemas) == 0 {
+		schemas = []string{defaultSchema}
+	}
+
+	var tables []*t.Table
+	for _, schema := range schemas {
+		schemaTables, err := sf.loadTables(connector, schema)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, schemaTables...)
+	}
+	return tables, nil
+}
+
+// loadIgnoreRules reads diff ignore rules from a JSON file
+func loadIgnoreRules(path string) (*diff.Ignore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading ignore file: %v", err)
+	}
+
+	var rules diff.Ignore
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("error parsing ignore file: %v", err)
+	}
+	return &rules, nil
+}
@@ -0,0 +1,290 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/export"
+	"github.com/carloberd/db-reader/filter"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Formats the command refers to besides the registered ones
+const (
+	formatMermaid  = "mermaid"
+	formatTemplate = "template"
+)
+
+// settingAliases are the flags kept as shorthands for format settings, by flag name
+var settingAliases = map[string]struct{ format, setting string }{
+	"style":         {"baseline", "style"},
+	"dot-clusters":  {"dot", "clusters"},
+	"sample-rows":   {"docs", "sample-rows"},
+	"docs-format":   {"docs", "pages"},
+	"sample-unmask": {"docs", "unmask"},
+}
+
+// formatHelp describes the export formats for the -format flag
+func formatHelp() string {
+	var names []string
+	for _, format := range export.Formats() {
+		name := format.Name
+		if format.Description != "" {
+			name += " (" + format.Description + ")"
+		}
+		names = append(names, name)
+	}
+	return "comma separated export formats: " + strings.Join(names, ", ") + " or template"
+}
+
+// settingHelp describes the settings of the export formats for the -set flag
+func settingHelp() string {
+	var settings []string
+	for _, format := range export.Formats() {
+		names := slices.Sorted(maps.Keys(format.Settings))
+		for _, name := range names {
+			settings = append(settings, fmt.Sprintf("%s.%s (%s)", format.Name, name, format.Settings[name]))
+		}
+	}
+	return "setting of a format as format.name=value, repeatable: " + strings.Join(settings, "; ")
+}
+
+// templateFormat returns the export format rendering a custom template,
+// written to a file named after the template without its .tmpl extension
+func templateFormat(path string) (export.Format, error) {
+	tmpl, err := export.ParseTemplate(path)
+	if err != nil {
+		return export.Format{}, err
+	}
+	name := filepath.Base(path)
+	file, ok := strings.CutSuffix(name, ".tmpl")
+	if !ok {
+		// Never overwrite the template itself
+		file = name + ".out"
+	}
+	return export.Format{
+		Name: formatTemplate,
+		File: func(string) string { return file },
+		Exporter: export.ExporterFunc(func(w io.Writer, schema *export.Schema, _ export.Options) error {
+			return export.WriteTemplate(w, tmpl, schema.Name, schema.Tables)
+		}),
+	}, nil
+}
+
+// runExport writes the selected tables in one or more of the export formats.
+// Tables are loaded and written by a pipeline running several catalog queries at once.
+func runExport(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	var selection selectionFlags
+	conn.register(fs)
+	selection.register(fs)
+	formatList := fs.String("format", formatMermaid, formatHelp())
+	output := fs.String("output", "", "output file for the export of a single format written as one document, otherwise folder (default stdout / current folder)")
+	groupSpec := fs.String("group", "", "group tables into modules in the dot graph and data dictionaries: comma separated name=patterns, with patterns separated by '|', and prefix to group the other tables by name prefix, e.g. 'auth=users|sessions,prefix'")
+	workers := fs.Int("workers", 4, "tables loaded and files written concurrently")
+	templatePath := fs.String("template", "", "Go text/template file rendered with the schema and its tables, the only format unless -format is given")
+	settings := make(map[string]map[string]string)
+	fs.Func("set", settingHelp(), func(value string) error {
+		key, setting, ok := strings.Cut(value, "=")
+		format, name, qualified := strings.Cut(key, ".")
+		if !ok || !qualified {
+			return fmt.Errorf("expected format.name=value, got '%s'", value)
+		}
+		if settings[format] == nil {
+			settings[format] = make(map[string]string)
+		}
+		settings[format][name] = setting
+		return nil
+	})
+	fs.String("style", string(export.GolangMigrate), "baseline migration tool: golang-migrate or flyway, as -set baseline.style")
+	fs.Bool("dot-clusters", false, "group the tables of the dot graph by schema, as -set dot.clusters=true")
+	fs.Int("sample-rows", 0, "rows sampled into a CSV file next to each docs page (default none), as -set docs.sample-rows")
+	fs.String("docs-format", "md", "format of the docs pages: md (Markdown) or adoc (AsciiDoc), as -set docs.pages")
+	fs.String("sample-unmask", "", "comma separated patterns of the sampled columns to show as they are; all others but keys, numbers and booleans are masked, as -set docs.unmask")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Shorthand flags given on the command line set their format setting, unless -set does
+	fs.Visit(func(f *flag.Flag) {
+		alias, ok := settingAliases[f.Name]
+		if !ok {
+			return
+		}
+		if settings[alias.format] == nil {
+			settings[alias.format] = make(map[string]string)
+		}
+		if _, set := settings[alias.format][alias.setting]; !set {
+			settings[alias.format][alias.setting] = f.Value.String()
+		}
+	})
+
+	formats := filter.ParseList(*formatList)
+	chosen := make(map[string]export.Format, len(formats))
+	if *templatePath != "" {
+		custom, err := templateFormat(*templatePath)
+		if err != nil {
+			return err
+		}
+		chosen[formatTemplate] = custom
+		formatGiven := false
+		fs.Visit(func(f *flag.Flag) { formatGiven = formatGiven || f.Name == "format" })
+		if !formatGiven {
+			formats = []string{formatTemplate}
+		} else if !slices.Contains(formats, formatTemplate) {
+			formats = append(formats, formatTemplate)
+		}
+	} else if slices.Contains(formats, formatTemplate) {
+		return fmt.Errorf("the template format needs a -template file")
+	}
+	for _, name := range formats {
+		if name == formatTemplate {
+			continue
+		}
+		format, ok := export.LookupFormat(name)
+		if !ok {
+			return fmt.Errorf("unknown export format '%s'", name)
+		}
+		chosen[name] = format
+	}
+	if len(formats) == 0 {
+		return fmt.Errorf("no export format given")
+	}
+	for name, values := range settings {
+		format, ok := export.LookupFormat(name)
+		if !ok {
+			return fmt.Errorf("unknown export format '%s' in -set", name)
+		}
+		for setting := range values {
+			if _, ok := format.Settings[setting]; !ok {
+				return fmt.Errorf("the %s format has no setting '%s'", name, setting)
+			}
+		}
+	}
+	grouping, err := export.ParseGrouping(*groupSpec)
+	if err != nil {
+		return err
+	}
+
+	connector, params, err := conn.connect(fs, stderr)
+	if err != nil {
+		return err
+	}
+	defer connector.Disconnect()
+	optsOf := func(format string) export.Options {
+		return export.Options{Connector: connector, Grouping: grouping, Settings: settings[format]}
+	}
+
+	names, err := selection.selectTables(connector, params.Schema)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no tables selected")
+	}
+
+	logger, err := conn.openAuditLog()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	logger.Log(audit.Target(*params), audit.ActionExport,
+		fmt.Sprintf("%s export of %d tables to %s", strings.Join(formats, ", "), len(names), describeOutput(*output)))
+
+	pipeline := export.Pipeline{
+		Workers: *workers,
+		Load: func(name string) (*t.Table, error) {
+			return connector.GetTableStructure(params.Schema, name)
+		},
+	}
+	schemaOf := func(tables []*t.Table) *export.Schema {
+		return &export.Schema{Name: params.Schema, Driver: params.Driver, Tables: tables}
+	}
+
+	// A single diagram or schema document keeps going to standard output or the output file
+	if doc := chosen[formats[0]]; len(formats) == 1 && !doc.WritesFiles() {
+		tables, _, err := pipeline.Run(names)
+		if err != nil {
+			return err
+		}
+		w := stdout
+		if *output != "" {
+			f, err := os.Create(*output)
+			if err != nil {
+				return fmt.Errorf("error creating output file: %v", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		return doc.Exporter.Export(w, schemaOf(tables), optsOf(formats[0]))
+	}
+
+	dir := *output
+	if dir == "" {
+		dir = "."
+	}
+	for _, name := range formats {
+		format, opts := chosen[name], optsOf(name)
+		switch {
+		case format.TableFiles != nil:
+			pipeline.TableOutputs = append(pipeline.TableOutputs, func(table *t.Table) ([]string, error) {
+				return format.TableFiles(dir, table, opts)
+			})
+		case format.Files != nil:
+			pipeline.SchemaOutputs = append(pipeline.SchemaOutputs, func(tables []*t.Table) ([]string, error) {
+				return format.Files(dir, schemaOf(tables), opts)
+			})
+		default:
+			pipeline.SchemaOutputs = append(pipeline.SchemaOutputs, func(tables []*t.Table) ([]string, error) {
+				path := filepath.Join(dir, export.FileName(format.File(params.Schema)))
+				return []string{path}, writeFile(path, func(w io.Writer) error {
+					return format.Exporter.Export(w, schemaOf(tables), opts)
+				})
+			})
+		}
+	}
+
+	_, files, err := pipeline.Run(names)
+	for _, file := range files {
+		fmt.Fprintln(stdout, file)
+	}
+	return err
+}
+
+// writeFile creates a file and writes it with the given function
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", path, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return f.Close()
+}
+
+// describeOutput names the output destination for the audit log
+func describeOutput(output string) string {
+	if output == "" {
+		return "standard output"
+	}
+	return output
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/render"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// runRender draws the statistics dashboard and ER diagram to PNG files without opening the GUI
+func runRender(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	var selection selectionFlags
+	conn.register(fs)
+	selection.register(fs)
+	dashboardPath := fs.String("dashboard", "", "PNG file for the schema statistics dashboard")
+	diagramPath := fs.String("diagram", "", "PNG file for the ER diagram of the selected tables")
+	width := fs.Int("width", 900, "minimum width of the dashboard image in pixels")
+	dark := fs.Bool("dark", false, "use the dark theme")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dashboardPath == "" && *diagramPath == "" {
+		return fmt.Errorf("nothing to render; give --dashboard and/or --diagram")
+	}
+
+	connector, params, err := conn.connect(fs, stderr)
+	if err != nil {
+		return err
+	}
+	defer connector.Disconnect()
+
+	logger, err := conn.openAuditLog()
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+
+	if *dashboardPath != "" {
+		inspector, ok := connector.(t.StatsProvider)
+		if !ok {
+			return fmt.Errorf("schema statistics are not supported for this database")
+		}
+		stats, err := inspector.GetSchemaStats(params.Schema)
+		if err != nil {
+			return err
+		}
+
+		logger.Log(audit.Target(*params), audit.ActionExport, fmt.Sprintf("dashboard image to %s", *dashboardPath))
+		if err := writePNG(*dashboardPath, render.Dashboard(stats, float32(*width), *dark)); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, *dashboardPath)
+	}
+
+	if *diagramPath != "" {
+		tables, err := selection.loadTables(connector, params.Schema)
+		if err != nil {
+			return err
+		}
+		if len(tables) == 0 {
+			return fmt.Errorf("no tables selected")
+		}
+
+		// Reuse the layout arranged by hand in the GUI, if any
+		var positions map[string]config.Position
+		if cfg, err := conn.loadConfig(); err == nil {
+			positions = cfg.Layout(config.SchemaKey(*params))
+		}
+
+		logger.Log(audit.Target(*params), audit.ActionExport,
+			fmt.Sprintf("diagram image of %d tables to %s", len(tables), *diagramPath))
+		if err := writePNG(*diagramPath, render.Diagram(tables, positions, *dark)); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, *diagramPath)
+	}
+
+	return nil
+}
+
+// writePNG saves an image as a PNG file
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating image file: %v", err)
+	}
+
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing image: %v", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/carloberd/db-reader/filter"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// runSequences lists the sequences of a schema with their settings and owning columns
+func runSequences(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("sequences", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	conn.register(fs)
+	match := fs.String("match", "", "comma separated sequence name patterns, e.g. 'order*' (default all)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	connector, params, err := conn.connect(fs, stderr)
+	if err != nil {
+		return err
+	}
+	defer connector.Disconnect()
+
+	lister, ok := connector.(t.SequenceLister)
+	if !ok {
+		return fmt.Errorf("listing sequences is not supported for %s databases", params.Driver)
+	}
+	sequences, err := lister.GetSequences(params.Schema)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(sequences))
+	for i, seq := range sequences {
+		names[i] = seq.Name
+	}
+	selected, err := filter.Selection{Match: filter.ParseList(*match)}.Apply(names)
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]bool, len(selected))
+	for _, name := range selected {
+		wanted[name] = true
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tCURRENT\tINCREMENT\tMIN\tMAX\tCYCLE\tOWNED BY")
+	for _, seq := range sequences {
+		if wanted[seq.Name] {
+			fmt.Fprintln(tw, formatSequence(seq))
+		}
+	}
+	return tw.Flush()
+}
+
+// formatSequence returns a sequence as a tab separated line
+func formatSequence(seq t.Sequence) string {
+	current := "-"
+	if seq.Current.Valid {
+		current = strconv.FormatInt(seq.Current.Int64, 10)
+	}
+	cycle := "no"
+	if seq.Cycle {
+		cycle = "yes"
+	}
+	owner := seq.OwnedBy
+	if owner == "" {
+		owner = "-"
+	}
+	return fmt.Sprintf("%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s", seq.Name, seq.DataType, current, seq.Increment, seq.Min, seq.Max, cycle, owner)
+}
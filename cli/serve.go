@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/cache"
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/server"
+	"github.com/carloberd/db-reader/tracing"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// runServe exposes the schema metadata over a read-only REST API, and as web
+// pages whose links can be shared, e.g. /schema/public/table/users#indexes
+func runServe(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	conn.register(fs)
+	listen := fs.String("listen", "localhost:8080", "address to listen on")
+	noAuth := fs.Bool("no-auth", false, "serve without API tokens, giving everyone access to all metadata")
+	cacheAt := fs.String("cache", "", "cache table metadata in memory, in a file (bolt:FILE) or in Redis shared by replicas (redis://HOST:PORT/DB) (default no cache)")
+	logFormat := fs.String("log-format", "text", "log format: text, or json with one object per line for log collectors")
+	logLevel := fs.String("log-level", "info", "minimum level logged: debug, info, warn or error; debug logs the timing of every query")
+	traceExporter := fs.String("trace", "", "trace requests and metadata queries with OpenTelemetry, exporting spans over OTLP/HTTP (otlp) or to stderr (stdout) (default no tracing)")
+	traceEndpoint := fs.String("trace-endpoint", "", "OTLP/HTTP endpoint of the collector, e.g. http://localhost:4318/v1/traces (default from the OTEL_EXPORTER_OTLP_* environment variables)")
+	cacheTTL := fs.Duration("cache-ttl", 5*time.Minute, "how long cached metadata is served before it is read again")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := setupLogging(stderr, *logFormat, *logLevel); err != nil {
+		return err
+	}
+
+	if *traceExporter != "" {
+		shutdown, err := tracing.Setup(*traceExporter, *traceEndpoint, stderr)
+		if err != nil {
+			return err
+		}
+		defer shutdown(context.Background())
+	}
+
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		return err
+	}
+	if len(cfg.APITokens) == 0 && !*noAuth {
+		return fmt.Errorf("no API tokens configured; add api_tokens to the config file or use -no-auth")
+	}
+	for _, token := range cfg.APITokens {
+		if token.Token == "" {
+			return fmt.Errorf("API token '%s' has an empty secret", token.Name)
+		}
+	}
+
+	connector, params, err := conn.connect(fs, stderr)
+	if err != nil {
+		return err
+	}
+	defer connector.Disconnect()
+
+	logger, err := audit.Open(cfg.Audit)
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+
+	srv := server.New(connector, cfg.APITokens, *noAuth)
+	srv.SetAuditLog(logger, audit.Target(*params))
+
+	if *cacheAt != "" {
+		metadata, err := cache.Open(*cacheAt)
+		if err != nil {
+			return err
+		}
+		defer metadata.Close()
+		srv.SetCache(metadata, *cacheTTL, audit.Target(*params))
+	}
+
+	if params.Secret != "" {
+		go config.WatchSecret(*params, config.SecretCheckInterval, nil, func(rotated t.ConnectionParams) {
+			reconnected, err := t.NewConnector(rotated.Driver)
+			if err == nil {
+				err = reconnected.Connect(rotated)
+			}
+			if err != nil {
+				slog.Error("error reconnecting with rotated credentials", "error", err)
+				return
+			}
+			srv.SetConnector(reconnected).Disconnect()
+			slog.Info("reconnected with rotated credentials")
+		})
+	}
+	slog.Info("serving", "api", "http://"+*listen, "tokens", len(cfg.APITokens),
+		"pages", "http://"+*listen+server.SchemaURL(params.Schema))
+	httpServer := &http.Server{
+		Addr:              *listen,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: serveHeaderTimeout,
+		ReadTimeout:       serveReadTimeout,
+		WriteTimeout:      serveWriteTimeout,
+	}
+	return httpServer.ListenAndServe()
+}
+
+// Timeouts of the API server, so slow clients cannot hold connections open.
+// Writing allows for the schema pages, which load every table.
+const (
+	serveHeaderTimeout = 10 * time.Second
+	serveReadTimeout   = 30 * time.Second
+	serveWriteTimeout  = 2 * time.Minute
+)
+
+// setupLogging sends the logs to stderr in the given format, adding the ID of
+// the request being served to the records logged while serving it
+func setupLogging(stderr io.Writer, format, level string) error {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("unknown log level '%s', expected debug, info, warn or error", level)
+	}
+	opts := &slog.HandlerOptions{Level: minLevel}
+
+	var handler slog.Handler
+	switch format {
+	case "text":
+		handler = slog.NewTextHandler(stderr, opts)
+	case "json":
+		handler = slog.NewJSONHandler(stderr, opts)
+	default:
+		return fmt.Errorf("unknown log format '%s', expected text or json", format)
+	}
+	slog.SetDefault(slog.New(server.LogHandler(handler)))
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/carloberd/db-reader/diff"
+	"github.com/carloberd/db-reader/filter"
+)
+
+// runSnapshot saves the selected tables as a JSON snapshot usable as a diff baseline
+func runSnapshot(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	var selection selectionFlags
+	conn.register(fs)
+	selection.register(fs)
+	schemas := fs.String("schemas", "", "comma separated schemas to include (default --schema)")
+	name := fs.String("name", "", "name recorded in the snapshot (default the profile or database name)")
+	output := fs.String("output", "", "output file (default stdout)")
+	stats := fs.Bool("stats", false, "record the rows and storage of the tables, for the archive advisor")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	connector, params, err := conn.connect(fs, stderr)
+	if err != nil {
+		return err
+	}
+	defer connector.Disconnect()
+
+	tables, err := selection.loadSchemas(connector, filter.ParseList(*schemas), params.Schema)
+	if err != nil {
+		return err
+	}
+
+	schema := &diff.Schema{Name: *name, Tables: tables}
+	if schema.Name == "" {
+		schema.Name = conn.profile
+	}
+	if schema.Name == "" {
+		schema.Name = params.Database
+	}
+	if *stats {
+		if err := schema.RecordStats(connector, params.Schema); err != nil {
+			return err
+		}
+	}
+
+	w := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	return diff.WriteSnapshot(w, schema)
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/carloberd/db-reader/analysis"
+	"github.com/carloberd/db-reader/export"
+	"github.com/carloberd/db-reader/filter"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// runUsage lists the tables of a schema from the most to the least used since
+// statistics were reset, rated hot, warm or cold
+func runUsage(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("usage", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	conn.register(fs)
+	match := fs.String("match", "", "comma separated table name patterns, e.g. 'order*' (default all)")
+	heat := fs.String("heat", "", "comma separated heat ratings to list: hot, warm, cold (default all)")
+	format := fs.String("format", "text", "output format: text, csv or md")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !slices.Contains([]string{"text", "csv", "md"}, *format) {
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+	heats := filter.ParseList(*heat)
+	for _, h := range heats {
+		if h != t.HeatHot && h != t.HeatWarm && h != t.HeatCold {
+			return fmt.Errorf("unknown heat rating '%s'", h)
+		}
+	}
+
+	connector, params, err := conn.connect(fs, stderr)
+	if err != nil {
+		return err
+	}
+	defer connector.Disconnect()
+
+	inspector, ok := connector.(t.UsageInspector)
+	if !ok {
+		return fmt.Errorf("table usage statistics are not supported for %s databases", params.Driver)
+	}
+	usage, err := inspector.GetTableUsage(params.Schema)
+	if err != nil {
+		return err
+	}
+	// Rate against every table before filtering, so the ratings do not depend on the selection
+	analysis.RateUsage(usage)
+
+	names := make([]string, len(usage.Tables))
+	for i, table := range usage.Tables {
+		names[i] = table.Name
+	}
+	selected, err := filter.Selection{Match: filter.ParseList(*match)}.Apply(names)
+	if err != nil {
+		return err
+	}
+	usage.Tables = slices.DeleteFunc(usage.Tables, func(table t.TableUsage) bool {
+		return !slices.Contains(selected, table.Name) || (len(heats) > 0 && !slices.Contains(heats, table.Heat))
+	})
+
+	switch *format {
+	case "csv":
+		return export.WriteUsageCSV(stdout, usage)
+	case "md":
+		return export.WriteUsageMarkdown(stdout, usage)
+	}
+
+	days := export.UsageWindowDays(usage)
+	if days > 0 {
+		fmt.Fprintf(stdout, "Accesses since %s (%.0f days)\n\n", usage.Since.Format("2006-01-02 15:04"), days)
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TABLE\tHEAT\tACCESSES\tPER DAY\tSEQ SCANS\tIDX SCANS\tROW CHANGES\tROWS")
+	for _, table := range usage.Tables {
+		perDay := "-"
+		if days > 0 {
+			perDay = fmt.Sprintf("%.1f", float64(table.Accesses())/days)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%d\t%d\t%d\t%d\n", table.Name, table.Heat, table.Accesses(), perDay,
+			table.SeqScans, table.IndexScans, table.Inserts+table.Updates+table.Deletes, table.Rows)
+	}
+	return tw.Flush()
+}
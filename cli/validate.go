@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/carloberd/db-reader/validate"
+)
+
+// runValidate checks a database against an expectation file and fails if any expectation is not met
+func runValidate(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	conn.register(fs)
+	expectPath := fs.String("expect", "", "JSON file declaring the tables, columns and indexes that must exist")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *expectPath == "" {
+		return fmt.Errorf("an expectation file is required (--expect)")
+	}
+
+	exp, err := validate.Load(*expectPath)
+	if err != nil {
+		return err
+	}
+
+	connector, params, err := conn.connect(fs, stderr)
+	if err != nil {
+		return err
+	}
+	defer connector.Disconnect()
+
+	violations, err := validate.Check(connector, params.Schema, exp)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		fmt.Fprintf(stdout, "All expectations met (%d tables checked).\n", len(exp.Tables))
+		return nil
+	}
+
+	for _, v := range violations {
+		fmt.Fprintln(stdout, v)
+	}
+	return fmt.Errorf("%d expectations not met", len(violations))
+}
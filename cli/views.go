@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/carloberd/db-reader/filter"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// runViews lists the views of a schema with their columns and definitions
+func runViews(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("views", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	conn.register(fs)
+	match := fs.String("match", "", "comma separated view name patterns, e.g. 'report_*' (default all)")
+	definitions := fs.Bool("sql", true, "show the SELECT statement of each view")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	connector, params, err := conn.connect(fs, stderr)
+	if err != nil {
+		return err
+	}
+	defer connector.Disconnect()
+
+	views, err := connector.GetViews(params.Schema)
+	if errors.Is(err, t.ErrViewsNotSupported) {
+		return fmt.Errorf("listing views is not supported for %s databases", params.Driver)
+	}
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(views))
+	for i, view := range views {
+		names[i] = view.Name
+	}
+	selected, err := filter.Selection{Match: filter.ParseList(*match)}.Apply(names)
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]bool, len(selected))
+	for _, name := range selected {
+		wanted[name] = true
+	}
+
+	first := true
+	for _, view := range views {
+		if !wanted[view.Name] {
+			continue
+		}
+		if !first {
+			fmt.Fprintln(stdout)
+		}
+		first = false
+		writeView(stdout, view, *definitions)
+	}
+	return nil
+}
+
+// writeView prints a view with its columns and, if asked, its definition
+func writeView(w io.Writer, view t.View, definition bool) {
+	kind := "view"
+	if view.Materialized {
+		kind = "materialized view"
+	}
+	fmt.Fprintf(w, "%s (%s)\n", view.Name, kind)
+	if view.Comment != "" {
+		fmt.Fprintf(w, "  %s\n", view.Comment)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, col := range view.Columns {
+		line := "  " + col.Name + "\t" + col.Type
+		if col.Comment != "" {
+			line += "\t" + col.Comment
+		}
+		fmt.Fprintln(tw, line)
+	}
+	tw.Flush()
+
+	if definition && view.Definition != "" {
+		fmt.Fprintf(w, "\n  %s\n", strings.ReplaceAll(strings.TrimSpace(view.Definition), "\n", "\n  "))
+	}
+}
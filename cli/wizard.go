@@ -0,0 +1,348 @@
+package cli
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/carloberd/db-reader/config"
+	t "github.com/carloberd/db-reader/types"
+	"golang.org/x/term"
+)
+
+// connectionFlagNames are the flags that describe a connection, as opposed to
+// the flags choosing what a command does
+var connectionFlagNames = []string{"driver", "host", "port", "user", "password", "database", "schema",
+	"account", "warehouse", "role", "credentials", "secret", "pgbouncer", "profile"}
+
+// runInit creates a connection profile by asking for its settings on the terminal
+func runInit(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var conn connectionFlags
+	conn.registerConfig(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path, err := conn.path()
+	if err != nil {
+		return err
+	}
+	w := newWizard(os.Stdin, stderr)
+	profile, _, err := w.run(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Saved profile '%s' to %s\n", profile.Name, path)
+	return nil
+}
+
+// firstRun reports whether a command should start the setup wizard: no connection
+// flag was given, no config file exists yet and a user is at the terminal
+func (cf *connectionFlags) firstRun(fs *flag.FlagSet) bool {
+	given := false
+	fs.Visit(func(f *flag.Flag) {
+		given = given || slices.Contains(connectionFlagNames, f.Name)
+	})
+	if given || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false
+	}
+
+	path, err := cf.path()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// wizard asks for the settings of a connection profile, one step at a time
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+	fd  int // Terminal the answers are read from, or -1
+}
+
+// newWizard creates a wizard reading answers from in and writing prompts to out
+func newWizard(in io.Reader, out io.Writer) *wizard {
+	fd := -1
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fd = int(f.Fd())
+	}
+	return &wizard{in: bufio.NewReader(in), out: out, fd: fd}
+}
+
+// run walks through creating a profile: the connection settings, tested before
+// going on, the default schema, the profile name and where to keep the password.
+// The profile is saved to the config file at path, and returned with its password.
+func (w *wizard) run(path string) (*config.Profile, string, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fmt.Fprintln(w.out, "Let's set up a connection profile. Press Enter to accept the [default].")
+
+	var params t.ConnectionParams
+	var connector t.DatabaseConnector
+	for {
+		params, err = w.askConnection()
+		if err != nil {
+			return nil, "", err
+		}
+
+		fmt.Fprintln(w.out, "Testing the connection...")
+		connector, err = t.NewConnector(params.Driver)
+		if err == nil {
+			err = connector.Connect(params)
+		}
+		if err == nil {
+			fmt.Fprintln(w.out, "Connected.")
+			break
+		}
+		fmt.Fprintf(w.out, "Connection failed: %v\n", err)
+		if connector != nil {
+			connector.Disconnect()
+		}
+		retry, err := w.confirm("Change the settings and try again?", true)
+		if err != nil {
+			return nil, "", err
+		}
+		if !retry {
+			return nil, "", fmt.Errorf("setup cancelled")
+		}
+	}
+	defer connector.Disconnect()
+
+	if params.Schema, err = w.askSchema(connector, params.Schema); err != nil {
+		return nil, "", err
+	}
+
+	name, err := w.ask("Profile name", cmp.Or(params.Database, params.Driver))
+	if err != nil {
+		return nil, "", err
+	}
+	profile := config.Profile{Name: name, Params: params}
+
+	password := params.Password
+	if password != "" {
+		save, err := w.confirm("Save the password in the OS keyring?", true)
+		if err != nil {
+			return nil, "", err
+		}
+		if save {
+			if err := w.storePassword(cfg, &profile, password); err != nil {
+				return nil, "", err
+			}
+		} else {
+			profile.Params.Password = ""
+			if env := passwordEnv(params.Driver); env != "" {
+				fmt.Fprintf(w.out, "The password is not saved; set %s when using the profile.\n", env)
+			} else {
+				fmt.Fprintln(w.out, "The password is not saved; give it with -password when using the profile.")
+			}
+		}
+	}
+
+	cfg.SetProfile(profile)
+	if err := cfg.Save(path); err != nil {
+		return nil, "", err
+	}
+	return &profile, password, nil
+}
+
+// askConnection asks for the driver and the settings it uses
+func (w *wizard) askConnection() (t.ConnectionParams, error) {
+	drivers := t.Drivers()
+	fmt.Fprintln(w.out, "\nDatabase drivers:")
+	for i, driver := range drivers {
+		fmt.Fprintf(w.out, "  %2d. %s\n", i+1, driver)
+	}
+	def := t.DriverPostgres
+	if !slices.Contains(drivers, def) {
+		def = drivers[0]
+	}
+	driver, err := w.choose("Driver", drivers, def)
+	if err != nil {
+		return t.ConnectionParams{}, err
+	}
+
+	params := t.ConnectionParams{Driver: driver}
+	defaults := params
+	defaults.ApplyDefaults()
+
+	// Each step fills a field, stopping at the first error
+	step := func(field *string, prompt, def string) {
+		if err == nil {
+			*field, err = w.ask(prompt, def)
+		}
+	}
+	secret := func(field *string, prompt string) {
+		if err == nil {
+			*field, err = w.askPassword(prompt)
+		}
+	}
+
+	switch driver {
+	case t.DriverDemo:
+	case t.DriverSQLite, t.DriverDuckDB, t.DriverPgDump, t.DriverSnapshot:
+		step(&params.Database, "Database file", "")
+	case t.DriverBigQuery:
+		step(&params.Database, "Project", "")
+		step(&params.CredentialsFile, "Service account key file (empty for application credentials)", "")
+	case t.DriverODBC:
+		step(&params.Database, "Data source name or connection string", "")
+		step(&params.User, "User", "")
+		secret(&params.Password, "Password")
+	case t.DriverSnowflake:
+		step(&params.Account, "Account", "")
+		step(&params.User, "User", "")
+		secret(&params.Password, "Password")
+		step(&params.Database, "Database", "")
+		step(&params.Warehouse, "Warehouse (empty for the user default)", "")
+	default:
+		step(&params.Host, "Host", defaults.Host)
+		step(&params.Port, "Port", defaults.Port)
+		step(&params.User, "User", defaults.User)
+		secret(&params.Password, "Password")
+		step(&params.Database, "Database", "")
+	}
+	if err != nil {
+		return params, err
+	}
+
+	params.ApplyDefaults()
+	return params, nil
+}
+
+// askSchema lets the user pick the default schema among the ones of the database
+func (w *wizard) askSchema(connector t.DatabaseConnector, fallback string) (string, error) {
+	lister, ok := connector.(t.SchemaLister)
+	if !ok {
+		return w.ask("Default schema", fallback)
+	}
+	schemas, err := lister.GetSchemas()
+	if err != nil || len(schemas) == 0 {
+		return w.ask("Default schema", fallback)
+	}
+
+	fmt.Fprintln(w.out, "\nSchemas:")
+	for i, schema := range schemas {
+		fmt.Fprintf(w.out, "  %2d. %s\n", i+1, schema)
+	}
+	if !slices.Contains(schemas, fallback) {
+		fallback = schemas[0]
+	}
+	return w.choose("Default schema", schemas, fallback)
+}
+
+// storePassword keeps the password in the OS keyring, or encrypted with a
+// master passphrase when there is no keyring
+func (w *wizard) storePassword(cfg *config.Config, profile *config.Profile, password string) error {
+	err := cfg.StorePassword(profile, password, nil)
+	if !errors.Is(err, config.ErrPassphraseRequired) {
+		return err
+	}
+
+	fmt.Fprintln(w.out, "The OS keyring is unavailable, so the password is encrypted with a master passphrase.")
+	passphrase, err := w.askPassword("Master passphrase")
+	if err != nil {
+		return err
+	}
+	if cfg.Encryption == nil {
+		again, err := w.askPassword("Repeat the passphrase")
+		if err != nil {
+			return err
+		}
+		if again != passphrase {
+			return fmt.Errorf("the passphrases do not match")
+		}
+	}
+	sealer, err := cfg.UnlockSecrets(passphrase)
+	if err != nil {
+		return err
+	}
+	return cfg.StorePassword(profile, password, sealer)
+}
+
+// ask reads a line, returning the default when it is empty
+func (w *wizard) ask(prompt, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", prompt, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", prompt)
+	}
+
+	line, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("setup cancelled")
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// askPassword reads a secret without echoing it when a user is at the terminal.
+// Answers that are piped or already typed ahead are read like any other.
+func (w *wizard) askPassword(prompt string) (string, error) {
+	if w.fd < 0 || w.in.Buffered() > 0 {
+		return w.ask(prompt, "")
+	}
+
+	fmt.Fprintf(w.out, "%s: ", prompt)
+	input, err := term.ReadPassword(w.fd)
+	fmt.Fprintln(w.out)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %v", strings.ToLower(prompt), err)
+	}
+	return string(input), nil
+}
+
+// choose reads one of the options, by number or by name
+func (w *wizard) choose(prompt string, options []string, def string) (string, error) {
+	for {
+		answer, err := w.ask(prompt, def)
+		if err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+		if slices.Contains(options, answer) {
+			return answer, nil
+		}
+		fmt.Fprintf(w.out, "Please choose one of the %d options.\n", len(options))
+	}
+}
+
+// confirm reads a yes or no answer
+func (w *wizard) confirm(prompt string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		answer, err := w.ask(fmt.Sprintf("%s (%s)", prompt, hint), "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
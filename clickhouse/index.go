@@ -0,0 +1,373 @@
+package clickhouse
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2" // ClickHouse driver
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// ClickHouseConnector implements the DatabaseConnector interface for ClickHouse.
+// ClickHouse has no schemas within a database, so the schema names a database on the server.
+type ClickHouseConnector struct {
+	sqlutil.QueryLog
+
+	db       *sql.DB
+	database string // Database connected to, used when no schema is given
+}
+
+// Connect establishes a connection to the ClickHouse server over the native protocol.
+// Session settings are passed as ClickHouse settings, e.g. max_execution_time=60.
+func (cc *ClickHouseConnector) Connect(params t.ConnectionParams) error {
+	settings := make(clickhouse.Settings, len(params.Settings))
+	for key, value := range params.Settings {
+		settings[key] = value
+	}
+
+	cc.db = clickhouse.OpenDB(&clickhouse.Options{
+		Addr: []string{net.JoinHostPort(params.Host, params.Port)},
+		Auth: clickhouse.Auth{
+			Database: params.Database,
+			Username: params.User,
+			Password: params.Password,
+		},
+		Settings: settings,
+	})
+
+	// Test the connection
+	if err := cc.db.Ping(); err != nil {
+		cc.db.Close()
+		cc.db = nil
+		return fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	cc.database = params.Database
+	return nil
+}
+
+// Disconnect closes the database connection
+func (cc *ClickHouseConnector) Disconnect() error {
+	if cc.db != nil {
+		err := cc.db.Close()
+		cc.db = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// schemaName returns the database to inspect, defaulting to the connected one
+func (cc *ClickHouseConnector) schemaName(schema string) string {
+	if schema == "" {
+		return cc.database
+	}
+	return schema
+}
+
+// GetTables returns a list of tables in the specified database. Views and
+// dictionaries hold no data of their own and are left out.
+func (cc *ClickHouseConnector) GetTables(schema string) ([]string, error) {
+	if cc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			name
+		FROM
+			system.tables
+		WHERE
+			database = ?
+		AND
+			NOT is_temporary
+		AND
+			engine NOT IN ('View', 'MaterializedView', 'LiveView', 'WindowView', 'Dictionary')
+		ORDER BY
+			name
+	`
+
+	rows, err := cc.Query(cc.db, query, cc.schemaName(schema))
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	return tables, rows.Err()
+}
+
+// tableInfo holds the engine and keys of a table from system.tables
+type tableInfo struct {
+	engine, partitionKey, sortingKey, primaryKey, samplingKey, comment string
+}
+
+// GetTableStructure returns the structure of the specified table. The engine,
+// keys and partitions are reported as table properties.
+func (cc *ClickHouseConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if cc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = cc.schemaName(schema)
+
+	// Check if the table exists, reading its engine and keys at the same time
+	rows, err := cc.Query(cc.db, `
+		SELECT engine, partition_key, sorting_key, primary_key, sampling_key, comment
+		FROM system.tables
+		WHERE database = ? AND name = ?
+	`, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	var info tableInfo
+	exists := rows.Next()
+	if exists {
+		err = rows.Scan(&info.engine, &info.partitionKey, &info.sortingKey, &info.primaryKey, &info.samplingKey, &info.comment)
+	}
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	table := &t.Table{Name: tableName, Schema: schema, Comment: info.comment}
+	if table.Columns, err = cc.getColumns(schema, tableName); err != nil {
+		return nil, err
+	}
+	if table.Indexes, err = cc.getIndexes(schema, tableName, info.primaryKey); err != nil {
+		return nil, err
+	}
+	if table.Properties, err = cc.getProperties(schema, tableName, info); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// getColumns returns the columns of a table. Nullable(T) types are reported as
+// nullable columns of type T, as in other databases.
+func (cc *ClickHouseConnector) getColumns(schema, tableName string) ([]t.Column, error) {
+	query := `
+		SELECT
+			name,
+			type,
+			default_kind,
+			default_expression,
+			is_in_primary_key,
+			comment
+		FROM
+			system.columns
+		WHERE
+			database = ? AND table = ?
+		ORDER BY
+			position
+	`
+
+	rows, err := cc.Query(cc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []t.Column
+	for rows.Next() {
+		var col t.Column
+		var defaultKind, defaultExpression string
+
+		err := rows.Scan(&col.Name, &col.Type, &defaultKind, &defaultExpression, &col.IsPrimaryKey, &col.Comment)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+
+		if inner, ok := strings.CutPrefix(col.Type, "Nullable("); ok {
+			col.Type = strings.TrimSuffix(inner, ")")
+			col.Nullable = true
+		}
+
+		// Materialized and alias columns are computed, so the kind is part of the default
+		switch defaultKind {
+		case "":
+		case "DEFAULT":
+			col.DefaultValue = sql.NullString{String: defaultExpression, Valid: true}
+		default:
+			col.DefaultValue = sql.NullString{String: defaultKind + " " + defaultExpression, Valid: true}
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, rows.Err()
+}
+
+// getIndexes returns the sparse primary index and the data skipping indexes of a table
+func (cc *ClickHouseConnector) getIndexes(schema, tableName, primaryKey string) ([]t.Index, error) {
+	var indexes []t.Index
+	if primaryKey != "" {
+		indexes = append(indexes, t.Index{
+			Name:       "PRIMARY",
+			Columns:    splitKey(primaryKey),
+			PrimaryKey: true,
+			Method:     "sparse",
+		})
+	}
+
+	query := `
+		SELECT
+			name,
+			type,
+			expr
+		FROM
+			system.data_skipping_indices
+		WHERE
+			database = ? AND table = ?
+		ORDER BY
+			name
+	`
+
+	rows, err := cc.Query(cc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying indexes: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var idx t.Index
+		var expr string
+
+		if err := rows.Scan(&idx.Name, &idx.Method, &expr); err != nil {
+			return nil, fmt.Errorf("error scanning index results: %v", err)
+		}
+
+		idx.Columns = splitKey(expr)
+		for _, col := range idx.Columns {
+			if !isIdentifier(col) {
+				idx.Expression = true
+			}
+		}
+		indexes = append(indexes, idx)
+	}
+
+	return indexes, rows.Err()
+}
+
+// getProperties describes the engine, keys and active partitions of a table
+func (cc *ClickHouseConnector) getProperties(schema, tableName string, info tableInfo) ([]t.Property, error) {
+	props := []t.Property{{Name: "Engine", Value: info.engine}}
+	if info.partitionKey != "" {
+		props = append(props, t.Property{Name: "Partition key", Value: info.partitionKey})
+	}
+	if info.sortingKey != "" {
+		props = append(props, t.Property{Name: "Sorting key", Value: info.sortingKey})
+	}
+	if info.primaryKey != "" && info.primaryKey != info.sortingKey {
+		props = append(props, t.Property{Name: "Primary key", Value: info.primaryKey})
+	}
+	if info.samplingKey != "" {
+		props = append(props, t.Property{Name: "Sampling key", Value: info.samplingKey})
+	}
+
+	// Only MergeTree family tables store their data in parts
+	if !strings.Contains(info.engine, "MergeTree") {
+		return props, nil
+	}
+
+	rows, err := cc.Query(cc.db, `
+		SELECT
+			toInt64(uniqExact(partition)),
+			toInt64(count()),
+			toInt64(sum(rows)),
+			formatReadableSize(sum(bytes_on_disk))
+		FROM
+			system.parts
+		WHERE
+			database = ? AND table = ? AND active
+	`, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying partitions: %v", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var partitions, parts, partRows int64
+		var size string
+		if err := rows.Scan(&partitions, &parts, &partRows, &size); err != nil {
+			return nil, fmt.Errorf("error scanning partition results: %v", err)
+		}
+		props = append(props, t.Property{
+			Name:  "Partitions",
+			Value: fmt.Sprintf("%d (%d active parts, %d rows, %s)", partitions, parts, partRows, size),
+		})
+	}
+
+	return props, rows.Err()
+}
+
+// splitKey splits a key expression such as "(tenant_id, toDate(created_at))" into its parts
+func splitKey(key string) []string {
+	key = strings.TrimSpace(key)
+	if strings.HasPrefix(key, "(") && strings.HasSuffix(key, ")") {
+		key = key[1 : len(key)-1]
+	}
+
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range key {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(key[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if part := strings.TrimSpace(key[start:]); part != "" {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// isIdentifier reports whether a key part is a plain column name
+func isIdentifier(part string) bool {
+	for _, r := range part {
+		if r != '_' && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') && !('0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return part != ""
+}
+
+// NewClickHouseConnector creates a connector for ClickHouse servers
+func NewClickHouseConnector() t.DatabaseConnector {
+	return &ClickHouseConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverClickHouse, NewClickHouseConnector, t.ConnectorInfo{
+		Title:  "ClickHouse",
+		Fields: t.ServerFields,
+		Defaults: func(p *t.ConnectionParams) {
+			// ClickHouse has no schemas, so the schema is the database
+			p.DefaultServer("9000", "default")
+			p.Schema = cmp.Or(p.Schema, p.Database)
+		},
+	})
+}
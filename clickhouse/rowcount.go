@@ -0,0 +1,67 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// quoteIdentifier quotes a ClickHouse identifier with backticks
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteQualified returns a quoted database-qualified name for use in queries
+func quoteQualified(schema, name string) string {
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// EstimateRowCount returns the row count ClickHouse keeps for a table. Engines
+// that do not track it, such as Log or Distributed, report -1.
+func (cc *ClickHouseConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if cc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := cc.Query(cc.db, `
+		SELECT toNullable(toInt64(total_rows))
+		FROM system.tables
+		WHERE database = ? AND name = ?
+	`, cc.schemaName(schema), tableName)
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	var estimate sql.NullInt64
+	if err := rows.Scan(&estimate); err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	if !estimate.Valid {
+		return -1, nil
+	}
+	return estimate.Int64, nil
+}
+
+// CountRows returns the exact number of rows in a table
+func (cc *ClickHouseConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if cc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := "SELECT toInt64(count()) FROM " + quoteQualified(cc.schemaName(schema), tableName)
+	cc.LogQuery(query, nil)
+
+	var count int64
+	if err := cc.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
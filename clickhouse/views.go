@@ -0,0 +1,58 @@
+package clickhouse
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViews returns the views and materialized views of the database, with
+// their columns and SELECT statements
+func (cc *ClickHouseConnector) GetViews(schema string) ([]t.View, error) {
+	if cc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = cc.schemaName(schema)
+
+	query := `
+		SELECT
+			name,
+			engine = 'MaterializedView',
+			as_select,
+			comment
+		FROM
+			system.tables
+		WHERE
+			database = ?
+		AND
+			engine IN ('View', 'MaterializedView')
+		ORDER BY
+			name
+	`
+
+	rows, err := cc.Query(cc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+	defer rows.Close()
+
+	var views []t.View
+	for rows.Next() {
+		view := t.View{Schema: schema}
+		if err := rows.Scan(&view.Name, &view.Materialized, &view.Definition, &view.Comment); err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading views: %v", err)
+	}
+	rows.Close()
+
+	for i := range views {
+		if views[i].Columns, err = cc.getColumns(schema, views[i].Name); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
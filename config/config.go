@@ -0,0 +1,239 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/carloberd/db-reader/analysis"
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/registry"
+	"github.com/carloberd/db-reader/secrets"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Profile is a named set of saved connection parameters
+type Profile struct {
+	ID      string             `json:"id,omitempty"` // Random identifier keying the saved password
+	Name    string             `json:"name"`
+	Params  t.ConnectionParams `json:"params"`
+	Replica bool               `json:"replica,omitempty"` // Profile points at a read replica
+	Primary string             `json:"primary,omitempty"` // Host and port of the primary a replica profile follows, e.g. "db1:5432"
+
+	// Environment tags the profile as dev, staging or prod, if set
+	Environment string `json:"environment,omitempty"`
+
+	// The password is kept out of Params, either in the OS keyring or encrypted
+	Keyring           bool   `json:"keyring,omitempty"`
+	EncryptedPassword string `json:"encrypted_password,omitempty"`
+}
+
+// Environments a profile can be tagged with
+const (
+	EnvironmentDev     = "dev"
+	EnvironmentStaging = "staging"
+	EnvironmentProd    = "prod"
+)
+
+// Environments lists the environment tags, from the least to the most sensitive
+var Environments = []string{EnvironmentDev, EnvironmentStaging, EnvironmentProd}
+
+// CustomSection is a user-defined catalog query shown as an extra section of the
+// table details. The query may reference the schema as $1 and the table as $2.
+type CustomSection struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// SnapshotSettings controls the schema snapshots the GUI takes of connected databases
+type SnapshotSettings struct {
+	Directory string `json:"directory,omitempty"` // Where snapshots are stored, next to the config file if empty
+	Interval  string `json:"interval"`            // Minimum time between snapshots of a schema, e.g. "24h"
+}
+
+// Config holds the persistent settings of the application. A config file is also
+// a workspace: besides profiles it keeps favorites, notes, saved queries and diagram
+// layouts, so separate files can be kept per client and opened as needed.
+type Config struct {
+	// ID is a random identifier of the workspace, keying the saved passwords of its profiles
+	ID string `json:"id,omitempty"`
+
+	Profiles []Profile `json:"profiles"`
+
+	// EnableWrites allows the features that modify the database, such as editing comments
+	EnableWrites bool `json:"enable_writes,omitempty"`
+
+	// ShowSystemTables includes extension-owned and migration tool tables in lists and exports
+	ShowSystemTables bool `json:"show_system_tables,omitempty"`
+
+	// PrefetchStructures loads the structure of every table in the background after connecting
+	PrefetchStructures bool `json:"prefetch_structures,omitempty"`
+
+	// NamingRules are the naming conventions checked by the analysis
+	NamingRules []analysis.NamingRule `json:"naming_rules,omitempty"`
+
+	// Layouts holds manually arranged ER diagram positions, by schema key
+	Layouts map[string]map[string]Position `json:"layouts,omitempty"`
+
+	// Favorites are the starred tables, by schema key
+	Favorites map[string][]string `json:"favorites,omitempty"`
+
+	// Notes are free-form table notes, by schema key and table
+	Notes map[string]map[string]string `json:"notes,omitempty"`
+
+	// SavedQueries are named scripts for the query editor
+	SavedQueries []SavedQuery `json:"saved_queries,omitempty"`
+
+	// CustomSections are extra table details sections backed by custom catalog queries
+	CustomSections []CustomSection `json:"custom_sections,omitempty"`
+
+	// APITokens are the tokens accepted in server mode, each limited to its schemas and tables
+	APITokens []t.APIToken `json:"api_tokens,omitempty"`
+
+	// Snapshots enables scheduled schema snapshots in the GUI, if set
+	Snapshots *SnapshotSettings `json:"snapshots,omitempty"`
+
+	// Audit enables logging of queries and exports, if set
+	Audit *audit.Settings `json:"audit,omitempty"`
+
+	// Registry is the data catalog whose descriptions and tags are shown with the tables, if set
+	Registry *registry.Settings `json:"registry,omitempty"`
+
+	// Encryption verifies the master passphrase of encrypted profile passwords
+	Encryption *secrets.Encryption `json:"encryption,omitempty"`
+}
+
+// DefaultPath returns the location of the configuration file in the user's config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating config directory: %v", err)
+	}
+	return filepath.Join(dir, "db-reader", "config.json"), nil
+}
+
+// Load reads the configuration from path. A missing file yields an empty configuration.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %v", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %v", path, err)
+	}
+
+	return &cfg, nil
+}
+
+// Save writes the configuration to path, creating its directory if needed
+func (c *Config) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding config: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("error creating config directory: %v", err)
+	}
+
+	// The file contains passwords, so keep it private to the user
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing config file: %v", err)
+	}
+
+	return nil
+}
+
+// Profile returns the profile with the given name
+func (c *Config) Profile(name string) (*Profile, bool) {
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			return &c.Profiles[i], true
+		}
+	}
+	return nil, false
+}
+
+// SetProfile adds a profile, replacing any existing profile with the same name
+func (c *Config) SetProfile(profile Profile) {
+	if existing, ok := c.Profile(profile.Name); ok {
+		*existing = profile
+		return
+	}
+
+	c.Profiles = append(c.Profiles, profile)
+	sort.Slice(c.Profiles, func(i, j int) bool {
+		return c.Profiles[i].Name < c.Profiles[j].Name
+	})
+}
+
+// ReplicaProfiles returns the profiles of the read replicas of a primary, given
+// by its resolved params: replica profiles of the same database whose primary is
+// the host and port of the params. Environment references in the profiles are
+// expanded first. Profiles saved without a primary match on the database alone.
+func (c *Config) ReplicaProfiles(primary t.ConnectionParams) []Profile {
+	var replicas []Profile
+	for _, p := range c.Profiles {
+		if !p.Replica {
+			continue
+		}
+		var missing string
+		database, follows := expandEnv(p.Params.Database, &missing), expandEnv(p.Primary, &missing)
+		if missing != "" || database != primary.Database {
+			continue
+		}
+		if follows == "" {
+			replicas = append(replicas, p)
+			continue
+		}
+		host, port, err := net.SplitHostPort(follows)
+		if err != nil {
+			host, port = follows, expandEnv(p.Params.Port, &missing)
+		}
+		if strings.EqualFold(host, primary.Host) && port == primary.Port {
+			replicas = append(replicas, p)
+		}
+	}
+	return replicas
+}
+
+// ProfileNames returns the names of all profiles
+func (c *Config) ProfileNames() []string {
+	names := make([]string, len(c.Profiles))
+	for i, p := range c.Profiles {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// ProfilesByEnvironment returns the profiles grouped by environment tag, in the
+// order of Environments; untagged profiles come last under an empty tag
+func (c *Config) ProfilesByEnvironment() ([]string, map[string][]Profile) {
+	groups := make(map[string][]Profile)
+	for _, p := range c.Profiles {
+		env := p.Environment
+		if !slices.Contains(Environments, env) {
+			env = ""
+		}
+		groups[env] = append(groups[env], p)
+	}
+
+	var order []string
+	for _, env := range append(slices.Clone(Environments), "") {
+		if len(groups[env]) > 0 {
+			order = append(order, env)
+		}
+	}
+	return order, groups
+}
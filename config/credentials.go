@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/carloberd/db-reader/secrets"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// SecretCheckInterval is how often a long-running connection checks whether
+// the password of its secret was rotated
+const SecretCheckInterval = 5 * time.Minute
+
+// ResolveSecret fetches the password, and the user if the secret has one, from
+// the secret store the connection references. Connections without a secret
+// reference are returned unchanged.
+func ResolveSecret(ctx context.Context, params t.ConnectionParams) (t.ConnectionParams, error) {
+	if params.Secret == "" {
+		return params, nil
+	}
+	creds, err := secrets.Fetch(ctx, params.Secret)
+	if err != nil {
+		return params, err
+	}
+	if creds.User != "" {
+		params.User = creds.User
+	}
+	params.Password = creds.Password
+	return params, nil
+}
+
+// WatchSecret checks every interval whether the secret of a connection was
+// rotated, calling rotated with the parameters holding the new credentials,
+// until stop is closed. Failed checks are retried at the next interval.
+func WatchSecret(params t.ConnectionParams, interval time.Duration, stop <-chan struct{}, rotated func(t.ConnectionParams)) {
+	if params.Secret == "" {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		fresh, err := ResolveSecret(context.Background(), params)
+		if err != nil || (fresh.User == params.User && fresh.Password == params.Password) {
+			continue
+		}
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		params = fresh
+		rotated(fresh)
+	}
+}
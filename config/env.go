@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// envReference matches a ${NAME} or ${NAME:-default} reference to an environment variable
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// ExpandEnv resolves references to environment variables in the connection
+// fields and session settings, e.g. a password given as ${PROD_DB_PASS}, so
+// profiles can be shared while CI injects the secrets. ${NAME:-default} falls
+// back to the default when the variable is unset or empty, as in the shell; an
+// unset variable without a default is an error, while a variable set to an empty
+// value expands to it.
+func ExpandEnv(params t.ConnectionParams) (t.ConnectionParams, error) {
+	var missing string
+	for _, field := range []*string{
+		&params.Host, &params.Port, &params.User, &params.Password, &params.Database, &params.Schema,
+		&params.Account, &params.Warehouse, &params.Role, &params.CredentialsFile, &params.Secret,
+	} {
+		*field = expandEnv(*field, &missing)
+	}
+	if params.Settings != nil {
+		settings := make(map[string]string, len(params.Settings))
+		for key, value := range params.Settings {
+			settings[key] = expandEnv(value, &missing)
+		}
+		params.Settings = settings
+	}
+
+	if missing != "" {
+		return params, fmt.Errorf("environment variable %s is not set", missing)
+	}
+	return params, nil
+}
+
+// expandEnv resolves the references to environment variables in a value. The
+// first unset variable without a default is recorded in missing.
+func expandEnv(value string, missing *string) string {
+	return envReference.ReplaceAllStringFunc(value, func(ref string) string {
+		match := envReference.FindStringSubmatch(ref)
+		value, set := os.LookupEnv(match[1])
+		if set && (value != "" || match[2] == "") {
+			return value
+		}
+		if match[2] != "" {
+			return match[3]
+		}
+		if *missing == "" {
+			*missing = match[1]
+		}
+		return ""
+	})
+}
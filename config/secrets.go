@@ -0,0 +1,123 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/carloberd/db-reader/secrets"
+)
+
+// ErrPassphraseRequired is returned when a password must be encrypted but no
+// master passphrase has been entered
+var ErrPassphraseRequired = errors.New("a master passphrase is required to store passwords")
+
+// UnlockSecrets returns the sealer for the master passphrase, setting up
+// encryption with this passphrase if the config has none yet
+func (c *Config) UnlockSecrets(passphrase string) (*secrets.Sealer, error) {
+	if c.Encryption != nil {
+		return c.Encryption.Unlock(passphrase)
+	}
+
+	encryption, sealer, err := secrets.NewEncryption(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	c.Encryption = encryption
+	return sealer, nil
+}
+
+// StorePassword keeps the password of a profile in the OS keyring or, when the
+// keyring is unavailable, encrypted with the master passphrase. The password is
+// never written to the config file in plain text. It is keyed by the IDs of the
+// workspace and the profile, assigned here if missing, so that profiles with the
+// same name in other workspaces keep their own password and an encrypted password
+// copied to another profile cannot be decrypted. The sealer may be nil when no
+// passphrase has been entered, in which case ErrPassphraseRequired is returned
+// if the keyring cannot be used.
+func (c *Config) StorePassword(p *Profile, password string, sealer *secrets.Sealer) error {
+	p.Params.Password = ""
+	p.Keyring = false
+	p.EncryptedPassword = ""
+
+	if password == "" {
+		return nil
+	}
+
+	if c.ID == "" {
+		c.ID = newID()
+	}
+	if p.ID == "" {
+		// A profile saved again under its name keeps its keyring entry
+		if existing, ok := c.Profile(p.Name); ok && existing.ID != "" {
+			p.ID = existing.ID
+		} else {
+			p.ID = newID()
+		}
+	}
+	account, additional := c.passwordKey(p)
+
+	if secrets.KeyringAvailable() {
+		if err := secrets.KeyringSet(account, password); err == nil {
+			p.Keyring = true
+			return nil
+		}
+	}
+
+	if sealer == nil {
+		return ErrPassphraseRequired
+	}
+
+	sealed, err := sealer.Seal(password, additional)
+	if err != nil {
+		return err
+	}
+	p.EncryptedPassword = sealed
+	return nil
+}
+
+// ProfilePassword returns the password of a profile from wherever it is stored.
+// The sealer is only needed for passwords encrypted with the master passphrase.
+func (c *Config) ProfilePassword(p *Profile, sealer *secrets.Sealer) (string, error) {
+	account, additional := c.passwordKey(p)
+	switch {
+	case p.Keyring:
+		password, err := secrets.KeyringGet(account)
+		if err != nil {
+			return "", fmt.Errorf("profile '%s': %v", p.Name, err)
+		}
+		return password, nil
+	case p.EncryptedPassword != "":
+		if sealer == nil {
+			return "", fmt.Errorf("the master passphrase is needed for the password of profile '%s'", p.Name)
+		}
+		password, err := sealer.Open(p.EncryptedPassword, additional)
+		if err != nil {
+			return "", fmt.Errorf("profile '%s': %v", p.Name, err)
+		}
+		return password, nil
+	default:
+		// Plain text password saved by older versions
+		return p.Params.Password, nil
+	}
+}
+
+// passwordKey returns the keyring account of the password of a profile and the
+// additional data its encrypted form is bound to, both the workspace and profile
+// IDs. Passwords saved before profiles had IDs are keyed by the profile name and
+// bound to nothing.
+func (c *Config) passwordKey(p *Profile) (account, additional string) {
+	if p.ID == "" {
+		return p.Name, ""
+	}
+	key := c.ID + "/" + p.ID
+	return key, key
+}
+
+// newID returns a random identifier for a workspace or profile
+func newID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Position is the location of a table node in the ER diagram
+type Position struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+}
+
+// SavedQuery is a named SQL script kept for the query editor
+type SavedQuery struct {
+	Name string `json:"name"`
+	SQL  string `json:"sql"`
+}
+
+// SchemaKey identifies a connection and schema, for workspace data kept per schema
+func SchemaKey(params t.ConnectionParams) string {
+	return fmt.Sprintf("%s:%s/%s/%s", params.Host, params.Port, params.Database, params.Schema)
+}
+
+// Layout returns the saved diagram node positions of a schema
+func (c *Config) Layout(key string) map[string]Position {
+	return c.Layouts[key]
+}
+
+// SetPosition records the position of a table node in a diagram layout
+func (c *Config) SetPosition(key, table string, pos Position) {
+	if c.Layouts == nil {
+		c.Layouts = make(map[string]map[string]Position)
+	}
+	if c.Layouts[key] == nil {
+		c.Layouts[key] = make(map[string]Position)
+	}
+	c.Layouts[key][table] = pos
+}
+
+// ResetLayout forgets all saved node positions of a diagram layout
+func (c *Config) ResetLayout(key string) {
+	delete(c.Layouts, key)
+}
+
+// IsFavorite reports whether a table is starred
+func (c *Config) IsFavorite(key, table string) bool {
+	return slices.Contains(c.Favorites[key], table)
+}
+
+// SetFavorite stars or unstars a table
+func (c *Config) SetFavorite(key, table string, favorite bool) {
+	favorites := slices.DeleteFunc(c.Favorites[key], func(name string) bool {
+		return name == table
+	})
+	if favorite {
+		favorites = append(favorites, table)
+		sort.Strings(favorites)
+	}
+
+	if c.Favorites == nil {
+		c.Favorites = make(map[string][]string)
+	}
+	if len(favorites) == 0 {
+		delete(c.Favorites, key)
+	} else {
+		c.Favorites[key] = favorites
+	}
+}
+
+// Note returns the note attached to a table
+func (c *Config) Note(key, table string) string {
+	return c.Notes[key][table]
+}
+
+// SetNote attaches a note to a table. An empty note removes it.
+func (c *Config) SetNote(key, table, note string) {
+	if note == "" {
+		delete(c.Notes[key], table)
+		if len(c.Notes[key]) == 0 {
+			delete(c.Notes, key)
+		}
+		return
+	}
+
+	if c.Notes == nil {
+		c.Notes = make(map[string]map[string]string)
+	}
+	if c.Notes[key] == nil {
+		c.Notes[key] = make(map[string]string)
+	}
+	c.Notes[key][table] = note
+}
+
+// SavedQuery returns the saved query with the given name
+func (c *Config) SavedQuery(name string) (*SavedQuery, bool) {
+	for i := range c.SavedQueries {
+		if c.SavedQueries[i].Name == name {
+			return &c.SavedQueries[i], true
+		}
+	}
+	return nil, false
+}
+
+// SetSavedQuery adds a saved query, replacing any existing query with the same name
+func (c *Config) SetSavedQuery(query SavedQuery) {
+	if existing, ok := c.SavedQuery(query.Name); ok {
+		*existing = query
+		return
+	}
+
+	c.SavedQueries = append(c.SavedQueries, query)
+	sort.Slice(c.SavedQueries, func(i, j int) bool {
+		return c.SavedQueries[i].Name < c.SavedQueries[j].Name
+	})
+}
+
+// SavedQueryNames returns the names of all saved queries
+func (c *Config) SavedQueryNames() []string {
+	names := make([]string, len(c.SavedQueries))
+	for i, q := range c.SavedQueries {
+		names[i] = q.Name
+	}
+	return names
+}
@@ -0,0 +1,27 @@
+//go:build db2
+
+package db2
+
+import (
+	"cmp"
+	"strings"
+
+	_ "github.com/ibmdb/go_ibm_db" // Db2 driver
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// init registers the connector under its driver name. The Db2 driver links
+// against the IBM Data Server Driver (clidriver), so the connector is only
+// built with the db2 build tag.
+func init() {
+	t.RegisterConnector(t.DriverDb2, NewDb2Connector, t.ConnectorInfo{
+		Title:  "IBM Db2",
+		Fields: t.ServerFields,
+		Defaults: func(p *t.ConnectionParams) {
+			// The default schema is the user, whose unquoted name is stored in upper case
+			p.DefaultServer("50000", "db2inst1")
+			p.Schema = cmp.Or(p.Schema, strings.ToUpper(p.User))
+		},
+	})
+}
@@ -0,0 +1,434 @@
+package db2
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Db2Connector implements the DatabaseConnector interface for IBM Db2 for Linux,
+// UNIX and Windows, introspected through the SYSCAT catalog views
+type Db2Connector struct {
+	sqlutil.QueryLog
+
+	db *sql.DB
+}
+
+// Connect establishes a connection to the Db2 database. Session settings are
+// added to the connection string as keywords, e.g. Security=SSL.
+func (dc *Db2Connector) Connect(params t.ConnectionParams) error {
+	var err error
+	dc.db, err = sql.Open("go_ibm_db", connectionString(params))
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	// Test the connection
+	if err := dc.db.Ping(); err != nil {
+		dc.db.Close()
+		dc.db = nil
+		return fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	return nil
+}
+
+// connectionString returns the CLI connection string for the connection parameters
+func connectionString(params t.ConnectionParams) string {
+	attributes := [][2]string{
+		{"HOSTNAME", params.Host},
+		{"PORT", params.Port},
+		{"DATABASE", params.Database},
+		{"UID", params.User},
+		{"PWD", params.Password},
+	}
+	keys := make([]string, 0, len(params.Settings))
+	for key := range params.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		attributes = append(attributes, [2]string{key, params.Settings[key]})
+	}
+
+	var parts []string
+	for _, attr := range attributes {
+		if attr[1] != "" {
+			parts = append(parts, attr[0]+"="+attr[1])
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// Disconnect closes the database connection
+func (dc *Db2Connector) Disconnect() error {
+	if dc.db != nil {
+		err := dc.db.Close()
+		dc.db = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetTables returns a list of tables in the specified schema
+func (dc *Db2Connector) GetTables(schema string) ([]string, error) {
+	if dc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			TABNAME
+		FROM
+			SYSCAT.TABLES
+		WHERE
+			TABSCHEMA = ?
+			AND TYPE = 'T'
+		ORDER BY
+			TABNAME
+	`
+
+	rows, err := dc.Query(dc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	return tables, rows.Err()
+}
+
+// GetTableStructure returns the structure of the specified table. The
+// tablespace and organization are reported as table properties.
+func (dc *Db2Connector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if dc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Check if the table exists, reading its comment and storage at the same time
+	rows, err := dc.Query(dc.db, `
+		SELECT REMARKS, TBSPACE, TABLEORG
+		FROM SYSCAT.TABLES
+		WHERE TABSCHEMA = ? AND TABNAME = ? AND TYPE = 'T'
+	`, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	var comment, tablespace, organization sql.NullString
+	exists := rows.Next()
+	if exists {
+		err = rows.Scan(&comment, &tablespace, &organization)
+	}
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	table := &t.Table{Name: tableName, Schema: schema, Comment: comment.String}
+	if tablespace.Valid {
+		table.Properties = append(table.Properties, t.Property{Name: "Tablespace", Value: tablespace.String})
+	}
+	if organization.String == "C" {
+		table.Properties = append(table.Properties, t.Property{Name: "Organization", Value: "column"})
+	}
+
+	if table.Constraints, err = dc.getConstraints(schema, tableName); err != nil {
+		return nil, err
+	}
+	if table.Columns, err = dc.getColumns(schema, tableName, table.Constraints); err != nil {
+		return nil, err
+	}
+	if table.Indexes, err = dc.getIndexes(schema, tableName); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// getColumns returns the columns of a table, with the foreign keys taken from its constraints
+func (dc *Db2Connector) getColumns(schema, tableName string, constraints []t.Constraint) ([]t.Column, error) {
+	query := `
+		SELECT
+			COLNAME,
+			TYPENAME,
+			LENGTH,
+			SCALE,
+			NULLS,
+			DEFAULT,
+			KEYSEQ,
+			REMARKS
+		FROM
+			SYSCAT.COLUMNS
+		WHERE
+			TABSCHEMA = ? AND TABNAME = ?
+		ORDER BY
+			COLNO
+	`
+
+	rows, err := dc.Query(dc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []t.Column
+	for rows.Next() {
+		var col t.Column
+		var typeName, nulls string
+		var length, scale int64
+		var keySeq sql.NullInt64
+		var comment sql.NullString
+
+		err := rows.Scan(&col.Name, &typeName, &length, &scale, &nulls, &col.DefaultValue, &keySeq, &comment)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+
+		col.Type = formatDataType(strings.TrimSpace(typeName), length, scale)
+		col.Nullable = nulls == "Y"
+		col.IsPrimaryKey = keySeq.Valid
+		col.Comment = comment.String
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range columns {
+		col := &columns[i]
+		for _, con := range constraints {
+			if con.Type != t.ForeignKeyConstraint {
+				continue
+			}
+			if ref, ok := foreignKeyRef(con, col.Name); ok && !col.ForeignKey.Valid {
+				col.ForeignKey = sql.NullString{String: ref, Valid: true}
+			}
+		}
+	}
+
+	return columns, nil
+}
+
+// formatDataType adds the length, or precision and scale, to the types that have them
+func formatDataType(typeName string, length, scale int64) string {
+	switch typeName {
+	case "CHARACTER":
+		return fmt.Sprintf("CHAR(%d)", length)
+	case "VARCHAR", "GRAPHIC", "VARGRAPHIC", "BINARY", "VARBINARY":
+		return fmt.Sprintf("%s(%d)", typeName, length)
+	case "DECIMAL":
+		return fmt.Sprintf("DECIMAL(%d,%d)", length, scale)
+	}
+	return typeName
+}
+
+// indexKinds names the Db2 index types that are not regular indexes
+var indexKinds = map[string]string{
+	"CLUS": "clustering",
+	"DIM":  "dimension block",
+	"BLOK": "block",
+	"XPTH": "xml path",
+	"XRGN": "xml region",
+	"XVIL": "xml values",
+	"XVIP": "xml values",
+}
+
+// getIndexes returns the indexes of a table, with key columns in index order
+func (dc *Db2Connector) getIndexes(schema, tableName string) ([]t.Index, error) {
+	query := `
+		SELECT
+			i.INDNAME,
+			c.COLNAME,
+			i.UNIQUERULE,
+			i.INDEXTYPE
+		FROM
+			SYSCAT.INDEXES i
+		JOIN
+			SYSCAT.INDEXCOLUSE c ON c.INDSCHEMA = i.INDSCHEMA AND c.INDNAME = i.INDNAME
+		WHERE
+			i.TABSCHEMA = ? AND i.TABNAME = ?
+			AND c.COLORDER <> 'I'
+		ORDER BY
+			i.INDNAME, c.COLSEQ
+	`
+
+	rows, err := dc.Query(dc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying indexes: %v", err)
+	}
+	defer rows.Close()
+
+	var indexes []t.Index
+	for rows.Next() {
+		var indexName, columnName, uniqueRule, indexType string
+
+		if err := rows.Scan(&indexName, &columnName, &uniqueRule, &indexType); err != nil {
+			return nil, fmt.Errorf("error scanning index results: %v", err)
+		}
+
+		// Rows come one per key column; a new name starts the next index
+		if n := len(indexes); n == 0 || indexes[n-1].Name != indexName {
+			indexes = append(indexes, t.Index{
+				Name:       indexName,
+				Unique:     uniqueRule != "D",
+				PrimaryKey: uniqueRule == "P",
+				Method:     indexKinds[strings.TrimSpace(indexType)],
+			})
+		}
+		idx := &indexes[len(indexes)-1]
+		idx.Columns = append(idx.Columns, columnName)
+	}
+
+	return indexes, rows.Err()
+}
+
+// constraintTypes maps SYSCAT.TABCONST types to constraint kinds
+var constraintTypes = map[string]string{
+	"P": t.PrimaryKeyConstraint,
+	"U": t.UniqueConstraint,
+	"F": t.ForeignKeyConstraint,
+	"K": t.CheckConstraint,
+}
+
+// getConstraints returns the constraints of a table. The columns of foreign keys
+// are matched to the referenced key columns by their position in the keys.
+func (dc *Db2Connector) getConstraints(schema, tableName string) ([]t.Constraint, error) {
+	query := `
+		SELECT
+			tc.CONSTNAME,
+			tc.TYPE,
+			k.COLNAME,
+			r.REFTABSCHEMA,
+			r.REFTABNAME,
+			rk.COLNAME,
+			ch.TEXT
+		FROM
+			SYSCAT.TABCONST tc
+		LEFT JOIN
+			SYSCAT.KEYCOLUSE k ON k.CONSTNAME = tc.CONSTNAME
+			AND k.TABSCHEMA = tc.TABSCHEMA AND k.TABNAME = tc.TABNAME
+		LEFT JOIN
+			SYSCAT.REFERENCES r ON r.CONSTNAME = tc.CONSTNAME
+			AND r.TABSCHEMA = tc.TABSCHEMA AND r.TABNAME = tc.TABNAME
+		LEFT JOIN
+			SYSCAT.KEYCOLUSE rk ON rk.CONSTNAME = r.REFKEYNAME
+			AND rk.TABSCHEMA = r.REFTABSCHEMA AND rk.TABNAME = r.REFTABNAME
+			AND rk.COLSEQ = k.COLSEQ
+		LEFT JOIN
+			SYSCAT.CHECKS ch ON ch.CONSTNAME = tc.CONSTNAME
+			AND ch.TABSCHEMA = tc.TABSCHEMA AND ch.TABNAME = tc.TABNAME
+		WHERE
+			tc.TABSCHEMA = ? AND tc.TABNAME = ?
+			AND tc.TYPE IN ('P', 'U', 'F', 'K')
+		ORDER BY
+			tc.CONSTNAME, k.COLSEQ
+	`
+
+	rows, err := dc.Query(dc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying constraints: %v", err)
+	}
+	defer rows.Close()
+
+	var constraints []t.Constraint
+	var refTable string
+	var refColumns []string
+	for rows.Next() {
+		var name, conType string
+		var column, referencedSchema, referencedTable, referencedColumn, check sql.NullString
+
+		err := rows.Scan(&name, &conType, &column, &referencedSchema, &referencedTable, &referencedColumn, &check)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning constraint results: %v", err)
+		}
+
+		// Rows come one per column; a new name starts the next constraint
+		if n := len(constraints); n == 0 || constraints[n-1].Name != name {
+			if n > 0 {
+				constraints[n-1].Definition = constraintDefinition(constraints[n-1], refTable, refColumns)
+			}
+			constraints = append(constraints, t.Constraint{Name: name, Type: constraintTypes[conType]})
+			refTable, refColumns = referencedTable.String, nil
+			if referencedSchema.Valid && strings.TrimSpace(referencedSchema.String) != schema {
+				refTable = strings.TrimSpace(referencedSchema.String) + "." + refTable
+			}
+		}
+		con := &constraints[len(constraints)-1]
+		if check.Valid {
+			con.Definition = "CHECK (" + check.String + ")"
+		}
+		if column.Valid {
+			con.Columns = append(con.Columns, column.String)
+		}
+		if referencedColumn.Valid {
+			refColumns = append(refColumns, referencedColumn.String)
+		}
+	}
+	if n := len(constraints); n > 0 {
+		constraints[n-1].Definition = constraintDefinition(constraints[n-1], refTable, refColumns)
+	}
+
+	return constraints, rows.Err()
+}
+
+// constraintDefinition builds the SQL definition of a key constraint. Check
+// constraints keep the text read from the catalog.
+func constraintDefinition(con t.Constraint, refTable string, refColumns []string) string {
+	switch con.Type {
+	case t.CheckConstraint:
+		return con.Definition
+	case t.ForeignKeyConstraint:
+		return fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)",
+			strings.Join(con.Columns, ", "), refTable, strings.Join(refColumns, ", "))
+	}
+	return fmt.Sprintf("%s (%s)", con.Type, strings.Join(con.Columns, ", "))
+}
+
+// foreignKeyRef returns the "table (column)" reference of a column from a foreign key definition
+func foreignKeyRef(con t.Constraint, column string) (string, bool) {
+	pos := -1
+	for i, name := range con.Columns {
+		if name == column {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return "", false
+	}
+	_, target, ok := strings.Cut(con.Definition, " REFERENCES ")
+	if !ok {
+		return "", false
+	}
+	open := strings.Index(target, "(")
+	if open < 0 || !strings.HasSuffix(target, ")") {
+		return "", false
+	}
+	refColumns := strings.Split(target[open+1:len(target)-1], ", ")
+	if pos >= len(refColumns) {
+		return "", false
+	}
+	return fmt.Sprintf("%s (%s)", target[:open], refColumns[pos]), true
+}
+
+// NewDb2Connector creates a connector for IBM Db2 databases
+func NewDb2Connector() t.DatabaseConnector {
+	return &Db2Connector{}
+}
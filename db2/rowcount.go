@@ -0,0 +1,58 @@
+package db2
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// quoteIdentifier returns a quoted identifier for use in queries
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteQualified returns a quoted schema-qualified name for use in queries
+func quoteQualified(schema, name string) string {
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// EstimateRowCount returns the row count recorded by RUNSTATS, or -1 if
+// statistics have never been collected for the table
+func (dc *Db2Connector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if dc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	var estimate int64
+	err := dc.QueryRow(dc.db, `
+		SELECT CARD FROM SYSCAT.TABLES WHERE TABSCHEMA = ? AND TABNAME = ?
+	`, schema, tableName).Scan(&estimate)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+
+	return estimate, nil
+}
+
+// CountRows returns the exact number of rows in a table
+func (dc *Db2Connector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if dc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := "SELECT COUNT_BIG(*) FROM " + quoteQualified(schema, tableName)
+	dc.LogQuery(query, nil)
+
+	var count int64
+	if err := dc.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,66 @@
+package db2
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViews returns the views and materialized query tables of the schema, with
+// their columns and SELECT statements
+func (dc *Db2Connector) GetViews(schema string) ([]t.View, error) {
+	if dc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			t.TABNAME,
+			CASE WHEN t.TYPE = 'S' THEN 1 ELSE 0 END,
+			v.TEXT,
+			t.REMARKS
+		FROM
+			SYSCAT.TABLES t
+		LEFT JOIN
+			SYSCAT.VIEWS v ON v.VIEWSCHEMA = t.TABSCHEMA AND v.VIEWNAME = t.TABNAME
+		WHERE
+			t.TABSCHEMA = ?
+			AND t.TYPE IN ('V', 'S')
+		ORDER BY
+			t.TABNAME
+	`
+
+	rows, err := dc.Query(dc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+	defer rows.Close()
+
+	var views []t.View
+	for rows.Next() {
+		view := t.View{Schema: schema}
+		var materialized int
+		var definition, comment sql.NullString
+		if err := rows.Scan(&view.Name, &materialized, &definition, &comment); err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		// Db2 keeps the CREATE VIEW statement
+		view.Definition = sqlutil.ViewQuery(definition.String)
+		view.Materialized = materialized == 1
+		view.Comment = comment.String
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading views: %v", err)
+	}
+	rows.Close()
+
+	for i := range views {
+		if views[i].Columns, err = dc.getColumns(schema, views[i].Name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
@@ -0,0 +1,317 @@
+package demo
+
+import (
+	"database/sql"
+	"time"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// schema is the only schema of the demo database
+const schema = "public"
+
+// fixture is a small online shop, as the PostgreSQL catalogs would describe it.
+// It exercises everything the inspector shows: comments, defaults, foreign keys,
+// unique, partial and expression indexes, check constraints and a view.
+var fixture = []demoTable{
+	{
+		table: t.Table{
+			Name:    "categories",
+			Comment: "Product categories, nested through parent_id",
+			Columns: []t.Column{
+				serial("categories"),
+				column("parent_id", "integer", true, "", references("categories (id)")),
+				column("name", "character varying(100)", false, ""),
+				column("slug", "character varying(100)", false, ""),
+			},
+			Indexes: []t.Index{
+				primaryKey("categories_pkey", "id"),
+				unique("categories_slug_key", "slug"),
+			},
+			Constraints: []t.Constraint{
+				{Name: "categories_pkey", Type: t.PrimaryKeyConstraint, Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+				{Name: "categories_slug_key", Type: t.UniqueConstraint, Columns: []string{"slug"}, Definition: "UNIQUE (slug)"},
+				foreignKey("categories_parent_id_fkey", "parent_id", "categories(id)"),
+			},
+		},
+		rows: 42,
+	},
+	{
+		table: t.Table{
+			Name:    "customers",
+			Comment: "Registered customers",
+			Columns: []t.Column{
+				serial("customers"),
+				column("email", "character varying(255)", false, "", comment("Login, stored lower case")),
+				column("full_name", "character varying(200)", false, ""),
+				column("phone", "character varying(30)", true, ""),
+				column("marketing_opt_in", "boolean", false, "false"),
+				column("created_at", "timestamp with time zone", false, "now()"),
+			},
+			Indexes: []t.Index{
+				primaryKey("customers_pkey", "id"),
+				{Name: "customers_email_lower_idx", Unique: true, Method: "btree", Expression: true},
+			},
+			Constraints: []t.Constraint{
+				{Name: "customers_pkey", Type: t.PrimaryKeyConstraint, Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+			},
+		},
+		rows: 12840,
+	},
+	{
+		table: t.Table{
+			Name:    "products",
+			Comment: "Products on sale",
+			Columns: []t.Column{
+				serial("products"),
+				column("category_id", "integer", false, "", references("categories (id)")),
+				column("sku", "character varying(32)", false, "", comment("Stock keeping unit")),
+				column("name", "character varying(200)", false, ""),
+				column("description", "text", true, ""),
+				column("price", "numeric(10,2)", false, "", comment("Unit price in EUR, VAT included")),
+				column("tags", "text[]", false, "'{}'::text[]"),
+				column("discontinued", "boolean", false, "false"),
+			},
+			Indexes: []t.Index{
+				primaryKey("products_pkey", "id"),
+				unique("products_sku_key", "sku"),
+				{Name: "products_category_id_idx", Columns: []string{"category_id"}, Method: "btree", Predicate: "NOT discontinued"},
+				{Name: "products_tags_idx", Columns: []string{"tags"}, Method: "gin"},
+			},
+			Constraints: []t.Constraint{
+				{Name: "products_pkey", Type: t.PrimaryKeyConstraint, Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+				{Name: "products_sku_key", Type: t.UniqueConstraint, Columns: []string{"sku"}, Definition: "UNIQUE (sku)"},
+				foreignKey("products_category_id_fkey", "category_id", "categories(id)"),
+				{Name: "products_price_check", Type: t.CheckConstraint, Columns: []string{"price"}, Definition: "CHECK (price >= 0)"},
+			},
+		},
+		rows: 3150,
+	},
+	{
+		table: t.Table{
+			Name:    "orders",
+			Comment: "Customer orders",
+			Columns: []t.Column{
+				serial("orders"),
+				column("customer_id", "integer", false, "", references("customers (id)")),
+				column("status", "character varying(20)", false, "'pending'::character varying"),
+				column("placed_at", "timestamp with time zone", false, "now()"),
+				column("shipped_at", "timestamp with time zone", true, ""),
+				column("shipping_address", "jsonb", false, ""),
+			},
+			Indexes: []t.Index{
+				primaryKey("orders_pkey", "id"),
+				{Name: "orders_customer_id_idx", Columns: []string{"customer_id"}, Method: "btree"},
+				{Name: "orders_pending_idx", Columns: []string{"placed_at"}, Method: "btree", Predicate: "status = 'pending'"},
+			},
+			Constraints: []t.Constraint{
+				{Name: "orders_pkey", Type: t.PrimaryKeyConstraint, Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+				foreignKey("orders_customer_id_fkey", "customer_id", "customers(id)"),
+				{Name: "orders_status_check", Type: t.CheckConstraint, Columns: []string{"status"},
+					Definition: "CHECK (status IN ('pending', 'paid', 'shipped', 'cancelled'))"},
+			},
+			Triggers: []t.Trigger{
+				{
+					Name:       "orders_set_shipped_at",
+					Timing:     "BEFORE",
+					Events:     []string{"UPDATE"},
+					ForEach:    "ROW",
+					Function:   "set_shipped_at()",
+					Enabled:    true,
+					Definition: "CREATE TRIGGER orders_set_shipped_at BEFORE UPDATE OF status ON orders FOR EACH ROW EXECUTE FUNCTION set_shipped_at()",
+				},
+			},
+		},
+		rows: 48210,
+	},
+	{
+		table: t.Table{
+			Name:    "order_items",
+			Comment: "Lines of an order",
+			Columns: []t.Column{
+				column("order_id", "integer", false, "", inPrimaryKey, references("orders (id)")),
+				column("product_id", "integer", false, "", inPrimaryKey, references("products (id)")),
+				column("quantity", "integer", false, "1"),
+				column("unit_price", "numeric(10,2)", false, "", comment("Price when the order was placed")),
+			},
+			Indexes: []t.Index{
+				primaryKey("order_items_pkey", "order_id", "product_id"),
+				{Name: "order_items_product_id_idx", Columns: []string{"product_id"}, Method: "btree"},
+			},
+			Constraints: []t.Constraint{
+				{Name: "order_items_pkey", Type: t.PrimaryKeyConstraint, Columns: []string{"order_id", "product_id"},
+					Definition: "PRIMARY KEY (order_id, product_id)"},
+				foreignKey("order_items_order_id_fkey", "order_id", "orders(id) ON DELETE CASCADE"),
+				foreignKey("order_items_product_id_fkey", "product_id", "products(id)"),
+				{Name: "order_items_quantity_check", Type: t.CheckConstraint, Columns: []string{"quantity"}, Definition: "CHECK (quantity > 0)"},
+			},
+		},
+		rows: 131904,
+	},
+	{
+		table: t.Table{
+			Name: "reviews",
+			Columns: []t.Column{
+				serial("reviews"),
+				column("product_id", "integer", false, "", references("products (id)")),
+				column("customer_id", "integer", true, "", references("customers (id)"), comment("NULL once the customer is deleted")),
+				column("rating", "smallint", false, ""),
+				column("body", "text", true, ""),
+				column("created_at", "timestamp with time zone", false, "now()"),
+			},
+			Indexes: []t.Index{
+				primaryKey("reviews_pkey", "id"),
+			},
+			Constraints: []t.Constraint{
+				{Name: "reviews_pkey", Type: t.PrimaryKeyConstraint, Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+				foreignKey("reviews_product_id_fkey", "product_id", "products(id)"),
+				foreignKey("reviews_customer_id_fkey", "customer_id", "customers(id) ON DELETE SET NULL"),
+				{Name: "reviews_rating_check", Type: t.CheckConstraint, Columns: []string{"rating"}, Definition: "CHECK (rating BETWEEN 1 AND 5)"},
+			},
+		},
+		rows: 9377,
+	},
+}
+
+// views are the views of the demo schema, sorted by name
+var views = []t.View{
+	{
+		Name:   "order_totals",
+		Schema: schema,
+		Columns: []t.Column{
+			column("order_id", "integer", true, ""),
+			column("customer_id", "integer", true, ""),
+			column("total", "numeric", true, ""),
+		},
+		Definition: ` SELECT o.id AS order_id,
+    o.customer_id,
+    sum(i.quantity::numeric * i.unit_price) AS total
+   FROM orders o
+     JOIN order_items i ON i.order_id = o.id
+  GROUP BY o.id, o.customer_id;`,
+	},
+}
+
+// routines are the functions and procedures of the demo schema, sorted by name
+var routines = []t.Routine{
+	{
+		Name:      "cancel_stale_orders",
+		Schema:    schema,
+		Kind:      "PROCEDURE",
+		Arguments: "before timestamp with time zone",
+		Language:  "plpgsql",
+		Comment:   "Cancels the orders placed before a date and still pending",
+		Source: `
+BEGIN
+  UPDATE orders SET status = 'cancelled'
+   WHERE status = 'pending' AND placed_at < before;
+END;
+`,
+	},
+	{
+		Name:       "order_total",
+		Schema:     schema,
+		Kind:       "FUNCTION",
+		Arguments:  "order_id integer",
+		ReturnType: "numeric",
+		Language:   "sql",
+		Source: `
+  SELECT COALESCE(sum(quantity * unit_price), 0)
+    FROM order_items
+   WHERE order_items.order_id = order_total.order_id
+`,
+	},
+	{
+		Name:       "set_shipped_at",
+		Schema:     schema,
+		Kind:       "FUNCTION",
+		ReturnType: "trigger",
+		Language:   "plpgsql",
+		Comment:    "Stamps orders with the time they are shipped",
+		Source: `
+BEGIN
+  IF NEW.status = 'shipped' AND OLD.status <> 'shipped' THEN
+    NEW.shipped_at := now();
+  END IF;
+  RETURN NEW;
+END;
+`,
+	},
+}
+
+// usage is the scans and row changes of the demo tables over the last month, sorted by name
+var usage = []t.TableUsage{
+	{Name: "categories", SeqScans: 18320, IndexScans: 4110, Inserts: 3},
+	{Name: "customers", SeqScans: 12, IndexScans: 391204, Inserts: 1840, Updates: 5522},
+	{Name: "order_items", SeqScans: 4, IndexScans: 1288431, Inserts: 20118},
+	{Name: "orders", SeqScans: 31, IndexScans: 904377, Inserts: 7104, Updates: 15960, Deletes: 12},
+	{Name: "products", SeqScans: 2250, IndexScans: 611890, Inserts: 120, Updates: 834},
+	{Name: "reviews", SeqScans: 2, IndexScans: 37},
+}
+
+// usageWindow is how long before now the demo usage statistics were reset
+const usageWindow = 30 * 24 * time.Hour
+
+// demoTable is a table of the fixture with its row count
+type demoTable struct {
+	table t.Table
+	rows  int64
+}
+
+// column returns a column with an optional default, modified by the options
+func column(name, typ string, nullable bool, defaultValue string, options ...func(*t.Column)) t.Column {
+	col := t.Column{
+		Name:         name,
+		Type:         typ,
+		Nullable:     nullable,
+		DefaultValue: sql.NullString{String: defaultValue, Valid: defaultValue != ""},
+	}
+	for _, option := range options {
+		option(&col)
+	}
+	return col
+}
+
+// serial returns the id column of a table, filled from a sequence
+func serial(table string) t.Column {
+	return column("id", "integer", false, "nextval('"+table+"_id_seq'::regclass)", inPrimaryKey)
+}
+
+// inPrimaryKey marks a column as part of the primary key
+func inPrimaryKey(col *t.Column) {
+	col.IsPrimaryKey = true
+}
+
+// references sets the foreign key reference of a column, as "table (column)"
+func references(ref string) func(*t.Column) {
+	return func(col *t.Column) {
+		col.ForeignKey = sql.NullString{String: ref, Valid: true}
+	}
+}
+
+// comment sets the comment of a column
+func comment(text string) func(*t.Column) {
+	return func(col *t.Column) {
+		col.Comment = text
+	}
+}
+
+// primaryKey returns the index backing a primary key
+func primaryKey(name string, columns ...string) t.Index {
+	return t.Index{Name: name, Columns: columns, Unique: true, PrimaryKey: true, Method: "btree"}
+}
+
+// unique returns a unique btree index
+func unique(name string, columns ...string) t.Index {
+	return t.Index{Name: name, Columns: columns, Unique: true, Method: "btree"}
+}
+
+// foreignKey returns a single-column foreign key constraint
+func foreignKey(name, column, target string) t.Constraint {
+	return t.Constraint{
+		Name:       name,
+		Type:       t.ForeignKeyConstraint,
+		Columns:    []string{column},
+		Definition: "FOREIGN KEY (" + column + ") REFERENCES " + target,
+	}
+}
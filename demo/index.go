@@ -0,0 +1,239 @@
+package demo
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// DemoConnector implements the DatabaseConnector interface over a built-in sample
+// schema, so the inspector can be explored without a database server
+type DemoConnector struct {
+	connected bool
+}
+
+// Connect opens the demo database; connection parameters other than the schema are ignored
+func (dc *DemoConnector) Connect(params t.ConnectionParams) error {
+	if params.Schema != "" && params.Schema != schema {
+		return fmt.Errorf("the demo database has no schema '%s', only '%s'", params.Schema, schema)
+	}
+	dc.connected = true
+	return nil
+}
+
+// Disconnect closes the demo database
+func (dc *DemoConnector) Disconnect() error {
+	dc.connected = false
+	return nil
+}
+
+// findTable returns a table of the fixture
+func (dc *DemoConnector) findTable(schemaName, tableName string) (*demoTable, error) {
+	if !dc.connected {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if schemaName == schema {
+		for i := range fixture {
+			if fixture[i].table.Name == tableName {
+				return &fixture[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("table '%s.%s' does not exist", schemaName, tableName)
+}
+
+// GetTables returns the tables of the demo schema
+func (dc *DemoConnector) GetTables(schemaName string) ([]string, error) {
+	if !dc.connected {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if schemaName != schema {
+		return nil, nil
+	}
+
+	tables := make([]string, len(fixture))
+	for i, dt := range fixture {
+		tables[i] = dt.table.Name
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// GetTableStructure returns a copy of a table of the demo schema, which callers may modify
+func (dc *DemoConnector) GetTableStructure(schemaName, tableName string) (*t.Table, error) {
+	dt, err := dc.findTable(schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := dt.table
+	table.Schema = schema
+	table.Columns = append([]t.Column(nil), table.Columns...)
+	table.Indexes = make([]t.Index, len(dt.table.Indexes))
+	for i, idx := range dt.table.Indexes {
+		idx.Columns = append([]string(nil), idx.Columns...)
+		table.Indexes[i] = idx
+	}
+	table.Constraints = make([]t.Constraint, len(dt.table.Constraints))
+	for i, con := range dt.table.Constraints {
+		con.Columns = append([]string(nil), con.Columns...)
+		table.Constraints[i] = con
+	}
+	table.Triggers = make([]t.Trigger, len(dt.table.Triggers))
+	for i, trigger := range dt.table.Triggers {
+		trigger.Events = append([]string(nil), trigger.Events...)
+		table.Triggers[i] = trigger
+	}
+	return &table, nil
+}
+
+// EstimateRowCount returns the row count of a table of the demo schema
+func (dc *DemoConnector) EstimateRowCount(schemaName, tableName string) (int64, error) {
+	dt, err := dc.findTable(schemaName, tableName)
+	if err != nil {
+		return 0, err
+	}
+	return dt.rows, nil
+}
+
+// GetTableSizes returns the row counts of the tables of the demo schema, whose size is unknown
+func (dc *DemoConnector) GetTableSizes(schemaName string) ([]t.TableSize, error) {
+	if !dc.connected {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if schemaName != schema {
+		return nil, nil
+	}
+
+	sizes := make([]t.TableSize, len(fixture))
+	for i, dt := range fixture {
+		sizes[i] = t.TableSize{Name: dt.table.Name, Rows: dt.rows}
+	}
+	return sizes, nil
+}
+
+// CountRows returns the row count of a table of the demo schema
+func (dc *DemoConnector) CountRows(ctx context.Context, schemaName, tableName string) (int64, error) {
+	return dc.EstimateRowCount(schemaName, tableName)
+}
+
+// GetViewDefinitions returns the views of the demo schema
+func (dc *DemoConnector) GetViewDefinitions(schemaName string) (map[string]string, error) {
+	if !dc.connected {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if schemaName != schema {
+		return nil, nil
+	}
+
+	definitions := make(map[string]string, len(views))
+	for _, view := range views {
+		definitions[view.Name] = view.Definition
+	}
+	return definitions, nil
+}
+
+// GetViews returns the views of the demo schema with their columns
+func (dc *DemoConnector) GetViews(schemaName string) ([]t.View, error) {
+	if !dc.connected {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if schemaName != schema {
+		return nil, nil
+	}
+
+	list := make([]t.View, len(views))
+	for i, view := range views {
+		list[i] = view
+		list[i].Columns = append([]t.Column(nil), view.Columns...)
+	}
+	return list, nil
+}
+
+// GetRoutines returns the functions and procedures of the demo schema
+func (dc *DemoConnector) GetRoutines(schemaName string) ([]t.Routine, error) {
+	if !dc.connected {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if schemaName != schema {
+		return nil, nil
+	}
+	return append([]t.Routine(nil), routines...), nil
+}
+
+// GetSequences returns the sequences filling the id columns of the demo schema
+func (dc *DemoConnector) GetSequences(schemaName string) ([]t.Sequence, error) {
+	if !dc.connected {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if schemaName != schema {
+		return nil, nil
+	}
+
+	var sequences []t.Sequence
+	for _, demo := range fixture {
+		for _, col := range demo.table.Columns {
+			name, ok := strings.CutPrefix(col.DefaultValue.String, "nextval('")
+			if !ok {
+				continue
+			}
+			sequences = append(sequences, t.Sequence{
+				Name:      strings.TrimSuffix(name, "'::regclass)"),
+				Schema:    schema,
+				DataType:  col.Type,
+				Current:   sql.NullInt64{Int64: demo.rows, Valid: true},
+				Increment: 1,
+				Min:       "1",
+				Max:       "2147483647",
+				OwnedBy:   demo.table.Name + "." + col.Name,
+			})
+		}
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i].Name < sequences[j].Name })
+	return sequences, nil
+}
+
+// GetTableUsage returns made up access statistics of the tables of the demo schema
+func (dc *DemoConnector) GetTableUsage(schemaName string) (*t.SchemaUsage, error) {
+	if !dc.connected {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if schemaName != schema {
+		return &t.SchemaUsage{Schema: schemaName}, nil
+	}
+
+	tables := append([]t.TableUsage(nil), usage...)
+	for i := range tables {
+		if dt, err := dc.findTable(schemaName, tables[i].Name); err == nil {
+			tables[i].Rows = dt.rows
+		}
+	}
+	return &t.SchemaUsage{
+		Schema: schema,
+		Since:  time.Now().Add(-usageWindow).Truncate(time.Hour),
+		Tables: tables,
+	}, nil
+}
+
+// NewDemoConnector creates a connector for the built-in demo database
+func NewDemoConnector() t.DatabaseConnector {
+	return &DemoConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverDemo, NewDemoConnector, t.ConnectorInfo{
+		Title:  "Demo database",
+		Fields: []string{t.FieldSchema},
+		Defaults: func(p *t.ConnectionParams) {
+			p.Database = cmp.Or(p.Database, "demo")
+			p.Schema = cmp.Or(p.Schema, "public")
+		},
+	})
+}
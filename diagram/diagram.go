@@ -0,0 +1,208 @@
+package diagram
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"github.com/carloberd/db-reader/export"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Edge is a foreign key relationship from a column of one table to another table
+type Edge struct {
+	From       string
+	FromColumn string
+	To         string
+	ToColumn   string
+}
+
+// Edges returns the foreign key relationships between the given tables.
+// References to tables outside the list are left out.
+func Edges(tables []*t.Table) []Edge {
+	present := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		present[table.Name] = true
+	}
+
+	var edges []Edge
+	for _, table := range tables {
+		for _, col := range table.Columns {
+			target, targetColumn, ok := col.ForeignKeyTarget()
+			if !ok || !present[target] {
+				continue
+			}
+			edges = append(edges, Edge{
+				From:       table.Name,
+				FromColumn: col.Name,
+				To:         target,
+				ToColumn:   targetColumn,
+			})
+		}
+	}
+
+	return edges
+}
+
+// Scope restricts which tables a diagram shows. An empty scope shows all tables.
+type Scope struct {
+	Tables  []string // Only show these tables, if set
+	Focus   string   // Only show this table and its neighbours, if set
+	Depth   int      // Number of foreign key levels around Focus to include
+	Exclude []string // Name patterns (path.Match syntax) of tables to leave out
+}
+
+// Apply returns the tables within the scope, in their original order
+func (s Scope) Apply(tables []*t.Table) ([]*t.Table, error) {
+	for _, pattern := range s.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern '%s': %v", pattern, err)
+		}
+	}
+
+	// Exclusions apply first, so that excluded tables also break neighbour chains
+	var candidates []*t.Table
+	for _, table := range tables {
+		if !s.excluded(table.Name) {
+			candidates = append(candidates, table)
+		}
+	}
+
+	include := make(map[string]bool)
+	switch {
+	case s.Focus != "":
+		for name := range neighbours(candidates, s.Focus, s.Depth) {
+			include[name] = true
+		}
+	case len(s.Tables) > 0:
+		for _, name := range s.Tables {
+			include[name] = true
+		}
+	default:
+		return candidates, nil
+	}
+
+	var scoped []*t.Table
+	for _, table := range candidates {
+		if include[table.Name] {
+			scoped = append(scoped, table)
+		}
+	}
+
+	if s.Focus != "" && len(scoped) == 0 {
+		return nil, fmt.Errorf("table '%s' is not part of the diagram", s.Focus)
+	}
+
+	return scoped, nil
+}
+
+// excluded reports whether a table name matches one of the exclude patterns
+func (s Scope) excluded(name string) bool {
+	for _, pattern := range s.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// neighbours returns the tables reachable from focus within depth foreign keys,
+// following references in both directions, mapped to their distance
+func neighbours(tables []*t.Table, focus string, depth int) map[string]int {
+	adjacent := make(map[string][]string)
+	for _, edge := range Edges(tables) {
+		adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+		adjacent[edge.To] = append(adjacent[edge.To], edge.From)
+	}
+
+	found := false
+	for _, table := range tables {
+		if table.Name == focus {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	distance := map[string]int{focus: 0}
+	queue := []string{focus}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if distance[name] >= depth {
+			continue
+		}
+		for _, next := range adjacent[name] {
+			if _, seen := distance[next]; !seen {
+				distance[next] = distance[name] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return distance
+}
+
+// Levels groups tables by their foreign key distance from focus, for layered layouts.
+// Without a focus, all tables are on a single level.
+func Levels(tables []*t.Table, focus string) [][]string {
+	if focus == "" {
+		names := make([]string, len(tables))
+		for i, table := range tables {
+			names[i] = table.Name
+		}
+		return [][]string{names}
+	}
+
+	distance := neighbours(tables, focus, len(tables))
+
+	var levels [][]string
+	var unreachable []string
+	for _, table := range tables {
+		d, ok := distance[table.Name]
+		if !ok {
+			unreachable = append(unreachable, table.Name)
+			continue
+		}
+		for len(levels) <= d {
+			levels = append(levels, nil)
+		}
+		levels[d] = append(levels[d], table.Name)
+	}
+	if len(unreachable) > 0 {
+		levels = append(levels, unreachable)
+	}
+
+	for _, level := range levels {
+		sort.Strings(level)
+	}
+	return levels
+}
+
+// init registers the diagrams as export formats
+func init() {
+	export.RegisterFormat(export.Format{
+		Name:        "mermaid",
+		Description: "Mermaid diagram",
+		File:        func(string) string { return "diagram.mmd" },
+		Exporter: export.ExporterFunc(func(w io.Writer, schema *export.Schema, _ export.Options) error {
+			return WriteMermaid(w, schema.Tables)
+		}),
+	})
+	export.RegisterFormat(export.Format{
+		Name:        "dot",
+		Description: "Graphviz diagram",
+		File:        func(string) string { return "diagram.dot" },
+		Exporter: export.ExporterFunc(func(w io.Writer, schema *export.Schema, opts export.Options) error {
+			clusters, err := opts.Bool("clusters")
+			if err != nil {
+				return err
+			}
+			return WriteDOT(w, schema.Tables, DOTOptions{ClusterBySchema: clusters, Grouping: opts.Grouping})
+		}),
+		Settings: map[string]string{"clusters": "true to group the tables by schema"},
+	})
+}
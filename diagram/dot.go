@@ -0,0 +1,149 @@
+package diagram
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/carloberd/db-reader/export"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// DOTOptions controls how WriteDOT lays out the graph
+type DOTOptions struct {
+	// ClusterBySchema draws the tables of each schema in a box of their own, with
+	// the tables of other schemas that foreign keys point to drawn dashed
+	ClusterBySchema bool
+	// Grouping draws the tables of each module in a box of their own, inside
+	// the box of their schema if clustered by schema too
+	Grouping export.Grouping
+}
+
+// dotNode is a table of the graph, identified by its schema and name
+type dotNode struct {
+	schema, name string
+	external     bool // Referenced by a foreign key but not among the exported tables
+}
+
+// WriteDOT writes the tables as the nodes of a Graphviz graph and their foreign
+// keys as edges from the referencing to the referenced table, labelled with the
+// referencing column
+func WriteDOT(w io.Writer, tables []*t.Table, opts DOTOptions) error {
+	var sb strings.Builder
+
+	sb.WriteString("digraph schema {\n")
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    node [shape=box, fontname=\"Helvetica\"];\n")
+	sb.WriteString("    edge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	if !opts.ClusterBySchema && opts.Grouping.IsZero() {
+		for _, table := range tables {
+			sb.WriteString(fmt.Sprintf("    %s;\n", dotID(table.Name)))
+		}
+		for _, edge := range Edges(tables) {
+			sb.WriteString(fmt.Sprintf("    %s -> %s [label=%s];\n",
+				dotID(edge.From), dotID(edge.To), dotID(edge.FromColumn)))
+		}
+		sb.WriteString("}\n")
+		_, err := io.WriteString(w, sb.String())
+		return err
+	}
+
+	// Nodes are identified by their qualified name, as schemas may share table names
+	var nodes []dotNode
+	present := make(map[dotNode]bool)
+	for _, table := range tables {
+		node := dotNode{schema: table.Schema, name: table.Name}
+		nodes = append(nodes, node)
+		present[node] = true
+	}
+
+	var edges []string
+	for _, table := range tables {
+		for _, col := range table.Columns {
+			target, _, ok := col.ForeignKeyTarget()
+			if !ok {
+				continue
+			}
+			node := dotNode{schema: col.ForeignKeySchema(), name: target}
+			if node.schema == "" {
+				node.schema = table.Schema
+			}
+			if !present[node] {
+				present[node] = true
+				external := node
+				external.external = true
+				nodes = append(nodes, external)
+			}
+			edges = append(edges, fmt.Sprintf("    %s -> %s [label=%s];\n",
+				dotID(table.Schema+"."+table.Name), dotID(node.schema+"."+node.name), dotID(col.Name)))
+		}
+	}
+
+	// The box of a node is its schema and its module, nodes in neither are drawn outside boxes
+	stubs := make(map[*t.Table]dotNode, len(nodes))
+	tables = make([]*t.Table, len(nodes))
+	for i, node := range nodes {
+		tables[i] = &t.Table{Name: node.name, Schema: node.schema}
+		stubs[tables[i]] = node
+	}
+	box := make(map[dotNode]string, len(nodes))
+	for _, group := range opts.Grouping.Group(tables) {
+		for _, table := range group.Tables {
+			node := stubs[table]
+			var labels []string
+			if opts.ClusterBySchema {
+				labels = append(labels, node.schema)
+			}
+			if group.Module != "" {
+				labels = append(labels, group.Module)
+			}
+			box[node] = strings.Join(labels, " / ")
+		}
+	}
+
+	var boxes []string
+	for _, node := range nodes {
+		if !slices.Contains(boxes, box[node]) {
+			boxes = append(boxes, box[node])
+		}
+	}
+	i := 0
+	for _, label := range boxes {
+		indent := "        "
+		if label == "" {
+			indent = "    "
+		} else {
+			sb.WriteString(fmt.Sprintf("    subgraph cluster_%d {\n", i))
+			sb.WriteString(fmt.Sprintf("        label=%s;\n", dotID(label)))
+			i++
+		}
+		for _, node := range nodes {
+			if box[node] != label {
+				continue
+			}
+			style := ""
+			if node.external {
+				style = ", style=dashed"
+			}
+			sb.WriteString(fmt.Sprintf("%s%s [label=%s%s];\n",
+				indent, dotID(node.schema+"."+node.name), dotID(node.name), style))
+		}
+		if label != "" {
+			sb.WriteString("    }\n")
+		}
+	}
+	for _, edge := range edges {
+		sb.WriteString(edge)
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// dotID quotes a name as a DOT identifier
+func dotID(name string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name) + `"`
+}
@@ -0,0 +1,87 @@
+package diagram
+
+import (
+	"math"
+
+	"github.com/carloberd/db-reader/config"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Spacing between the boxes of a laid out diagram
+const (
+	GapX = 80
+	GapY = 30
+)
+
+// Point is a position in a diagram
+type Point struct {
+	X, Y float32
+}
+
+// Box is the area a table takes in a diagram
+type Box struct {
+	X, Y, Width, Height float32
+}
+
+// Layout positions the boxes of tables of the given sizes, starting at the margin.
+// Focused diagrams are laid out in columns by distance, others in a grid; saved
+// positions take precedence over the automatic layout.
+func Layout(tables []*t.Table, focus string, sizes map[string]Point, positions map[string]config.Position, margin float32) map[string]Box {
+	var columns [][]string
+	if focus != "" {
+		columns = Levels(tables, focus)
+	} else {
+		perColumn := int(math.Ceil(math.Sqrt(float64(len(tables)))))
+		for i, table := range tables {
+			if i%max(perColumn, 1) == 0 {
+				columns = append(columns, nil)
+			}
+			columns[len(columns)-1] = append(columns[len(columns)-1], table.Name)
+		}
+	}
+
+	boxes := make(map[string]Box, len(tables))
+	x := margin
+	for _, column := range columns {
+		y := margin
+		var width float32
+		for _, name := range column {
+			size := sizes[name]
+			boxes[name] = Box{X: x, Y: y, Width: size.X, Height: size.Y}
+			y += size.Y + GapY
+			width = max(width, size.X)
+		}
+		x += width + GapX
+	}
+
+	for name, pos := range positions {
+		if box, ok := boxes[name]; ok {
+			box.X, box.Y = pos.X, pos.Y
+			boxes[name] = box
+		}
+	}
+
+	return boxes
+}
+
+// Endpoints returns the points connecting the facing sides of two boxes
+func Endpoints(from, to Box) (Point, Point) {
+	start := Point{X: from.X + from.Width, Y: from.Y + from.Height/2}
+	end := Point{X: to.X, Y: to.Y + to.Height/2}
+	if to.X < from.X {
+		start.X = from.X
+		end.X = to.X + to.Width
+	}
+
+	return start, end
+}
+
+// Extent returns the size needed to show all boxes with a gap around them
+func Extent(boxes []Box) Point {
+	var total Point
+	for _, box := range boxes {
+		total.X = max(total.X, box.X+box.Width+GapX)
+		total.Y = max(total.Y, box.Y+box.Height+GapY)
+	}
+	return total
+}
@@ -0,0 +1,49 @@
+package diagram
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// mermaidUnsafe matches characters that are not allowed in Mermaid entity and attribute names
+var mermaidUnsafe = regexp.MustCompile(`[^A-Za-z0-9_\-\[\]()]+`)
+
+// WriteMermaid writes the tables and their foreign keys as a Mermaid erDiagram
+func WriteMermaid(w io.Writer, tables []*t.Table) error {
+	var sb strings.Builder
+
+	sb.WriteString("erDiagram\n")
+	for _, table := range tables {
+		sb.WriteString(fmt.Sprintf("    %s {\n", mermaidName(table.Name)))
+		for _, col := range table.Columns {
+			var keys []string
+			if col.IsPrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if col.ForeignKey.Valid {
+				keys = append(keys, "FK")
+			}
+			line := fmt.Sprintf("        %s %s %s", mermaidName(col.Type), mermaidName(col.Name), strings.Join(keys, ","))
+			sb.WriteString(strings.TrimRight(line, " ") + "\n")
+		}
+		sb.WriteString("    }\n")
+	}
+
+	for _, edge := range Edges(tables) {
+		// Many rows of the referencing table point at one row of the referenced table
+		sb.WriteString(fmt.Sprintf("    %s }o--|| %s : \"%s\"\n",
+			mermaidName(edge.From), mermaidName(edge.To), edge.FromColumn))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// mermaidName replaces characters Mermaid does not accept in names
+func mermaidName(name string) string {
+	return mermaidUnsafe.ReplaceAllString(name, "_")
+}
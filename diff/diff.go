@@ -0,0 +1,283 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/carloberd/db-reader/filter"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Schema is a set of tables to compare, typically one or more schemas of a database
+type Schema struct {
+	Name   string    // Describes where the tables come from, e.g. a profile name
+	Taken  time.Time // When the snapshot was taken, zero for live schemas
+	Tables []*t.Table
+	Stats  []TableStats // Sizes of the tables when the snapshot was taken, if recorded
+}
+
+// ChangeKind tells how an object differs between the compared schemas
+type ChangeKind string
+
+// Change kinds
+const (
+	Added    ChangeKind = "added"    // Only in the second schema
+	Removed  ChangeKind = "removed"  // Only in the first schema
+	Modified ChangeKind = "modified" // In both, with a different definition
+)
+
+// Object types
+const (
+	TableObject      = "table"
+	ColumnObject     = "column"
+	IndexObject      = "index"
+	ConstraintObject = "constraint"
+)
+
+// Change is a single difference between two schemas
+type Change struct {
+	Kind   ChangeKind
+	Object string // One of the object types
+	Table  string // Table the object belongs to, schema-qualified when comparing several schemas
+	Name   string // Column, index or constraint name; empty for tables
+	Field  string // Attribute that differs for modified objects, e.g. "type"
+	Old    string
+	New    string
+}
+
+// Path returns the changed object as table or table.name
+func (c Change) Path() string {
+	if c.Name == "" {
+		return c.Table
+	}
+	return c.Table + "." + c.Name
+}
+
+// String describes the change on a single line
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s %s", c.Object, c.Path())
+	case Removed:
+		return fmt.Sprintf("- %s %s", c.Object, c.Path())
+	default:
+		return fmt.Sprintf("~ %s %s: %s %s -> %s", c.Object, c.Path(), c.Field, describe(c.Old), describe(c.New))
+	}
+}
+
+// describe quotes a value for display, showing empty values as "none"
+func describe(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// Ignore lists the differences left out of a comparison, so that expected
+// differences between environments do not hide real drift
+type Ignore struct {
+	Comments   bool     `json:"comments,omitempty"`    // Table and column comments
+	IndexNames bool     `json:"index_names,omitempty"` // Match indexes by definition instead of by name
+	Schemas    []string `json:"schemas,omitempty"`     // Schema name patterns
+	Tables     []string `json:"tables,omitempty"`      // Table name patterns, matched against both the bare and qualified name
+}
+
+// skipTable reports whether a table is left out of the comparison
+func (ig Ignore) skipTable(table *t.Table) bool {
+	return filter.MatchesAny(ig.Schemas, table.Schema) ||
+		filter.MatchesAny(ig.Tables, table.Name) ||
+		filter.MatchesAny(ig.Tables, table.Schema+"."+table.Name)
+}
+
+// Compare returns the differences between two schemas, ordered by table.
+// Tables are matched by name, qualified with their schema when either side
+// spans several schemas.
+func Compare(a, b *Schema, ignore Ignore) []Change {
+	qualify := multiSchema(a) || multiSchema(b)
+	before := tablesByName(a, ignore, qualify)
+	after := tablesByName(b, ignore, qualify)
+
+	var changes []Change
+	for _, name := range unionKeys(before, after) {
+		oldTable, inBefore := before[name]
+		newTable, inAfter := after[name]
+		switch {
+		case !inAfter:
+			changes = append(changes, Change{Kind: Removed, Object: TableObject, Table: name})
+		case !inBefore:
+			changes = append(changes, Change{Kind: Added, Object: TableObject, Table: name})
+		default:
+			changes = append(changes, compareTables(name, oldTable, newTable, ignore)...)
+		}
+	}
+
+	return changes
+}
+
+// multiSchema reports whether the tables of a schema set come from several schemas
+func multiSchema(s *Schema) bool {
+	for _, table := range s.Tables {
+		if table.Schema != s.Tables[0].Schema {
+			return true
+		}
+	}
+	return false
+}
+
+// tablesByName maps the compared tables of a schema set by name
+func tablesByName(s *Schema, ignore Ignore, qualify bool) map[string]*t.Table {
+	tables := make(map[string]*t.Table, len(s.Tables))
+	for _, table := range s.Tables {
+		if ignore.skipTable(table) {
+			continue
+		}
+		name := table.Name
+		if qualify {
+			name = table.Schema + "." + table.Name
+		}
+		tables[name] = table
+	}
+	return tables
+}
+
+// unionKeys returns the keys of both maps, sorted
+func unionKeys[V any](a, b map[string]V) []string {
+	var keys []string
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// compareTables returns the differences between two versions of a table
+func compareTables(name string, a, b *t.Table, ignore Ignore) []Change {
+	var changes []Change
+	modified := func(object, objName, field, old, new string) {
+		if old != new {
+			changes = append(changes, Change{Kind: Modified, Object: object, Table: name,
+				Name: objName, Field: field, Old: old, New: new})
+		}
+	}
+
+	if !ignore.Comments {
+		modified(TableObject, "", "comment", a.Comment, b.Comment)
+	}
+
+	// Columns
+	oldColumns, newColumns := columnsByName(a), columnsByName(b)
+	for _, col := range unionKeys(oldColumns, newColumns) {
+		oldCol, inOld := oldColumns[col]
+		newCol, inNew := newColumns[col]
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Kind: Removed, Object: ColumnObject, Table: name, Name: col})
+		case !inOld:
+			changes = append(changes, Change{Kind: Added, Object: ColumnObject, Table: name, Name: col})
+		default:
+			modified(ColumnObject, col, "type", oldCol.Type, newCol.Type)
+			modified(ColumnObject, col, "nullable", fmt.Sprint(oldCol.Nullable), fmt.Sprint(newCol.Nullable))
+			modified(ColumnObject, col, "default", oldCol.DefaultValue.String, newCol.DefaultValue.String)
+			modified(ColumnObject, col, "primary key", fmt.Sprint(oldCol.IsPrimaryKey), fmt.Sprint(newCol.IsPrimaryKey))
+			modified(ColumnObject, col, "foreign key", foreignKey(oldCol), foreignKey(newCol))
+			if !ignore.Comments {
+				modified(ColumnObject, col, "comment", oldCol.Comment, newCol.Comment)
+			}
+		}
+	}
+
+	// Indexes, keyed by name or, when names are ignored, by definition
+	oldIndexes, newIndexes := indexesByKey(a, ignore.IndexNames), indexesByKey(b, ignore.IndexNames)
+	for _, key := range unionKeys(oldIndexes, newIndexes) {
+		oldIdx, inOld := oldIndexes[key]
+		newIdx, inNew := newIndexes[key]
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Kind: Removed, Object: IndexObject, Table: name, Name: oldIdx.Name})
+		case !inOld:
+			changes = append(changes, Change{Kind: Added, Object: IndexObject, Table: name, Name: newIdx.Name})
+		default:
+			modified(IndexObject, newIdx.Name, "definition", indexDefinition(oldIdx), indexDefinition(newIdx))
+		}
+	}
+
+	// Constraints
+	oldConstraints, newConstraints := constraintsByName(a), constraintsByName(b)
+	for _, con := range unionKeys(oldConstraints, newConstraints) {
+		oldCon, inOld := oldConstraints[con]
+		newCon, inNew := newConstraints[con]
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Kind: Removed, Object: ConstraintObject, Table: name, Name: con})
+		case !inOld:
+			changes = append(changes, Change{Kind: Added, Object: ConstraintObject, Table: name, Name: con})
+		default:
+			modified(ConstraintObject, con, "definition", oldCon.Definition, newCon.Definition)
+		}
+	}
+
+	return changes
+}
+
+// foreignKey describes the column a foreign key references, without the schema
+// qualifier, which depends on the search_path of the inspecting session
+func foreignKey(col t.Column) string {
+	if table, column, ok := col.ForeignKeyTarget(); ok {
+		return fmt.Sprintf("%s (%s)", table, column)
+	}
+	return ""
+}
+
+// columnsByName maps the columns of a table by name
+func columnsByName(table *t.Table) map[string]t.Column {
+	columns := make(map[string]t.Column, len(table.Columns))
+	for _, col := range table.Columns {
+		columns[col.Name] = col
+	}
+	return columns
+}
+
+// constraintsByName maps the constraints of a table by name
+func constraintsByName(table *t.Table) map[string]t.Constraint {
+	constraints := make(map[string]t.Constraint, len(table.Constraints))
+	for _, con := range table.Constraints {
+		constraints[con.Name] = con
+	}
+	return constraints
+}
+
+// indexesByKey maps the indexes of a table by name or by definition
+func indexesByKey(table *t.Table, byDefinition bool) map[string]t.Index {
+	indexes := make(map[string]t.Index, len(table.Indexes))
+	for _, idx := range table.Indexes {
+		key := idx.Name
+		if byDefinition {
+			key = indexDefinition(idx)
+		}
+		indexes[key] = idx
+	}
+	return indexes
+}
+
+// indexDefinition describes what an index covers, independently of its name
+func indexDefinition(idx t.Index) string {
+	var sb strings.Builder
+	if idx.Unique {
+		sb.WriteString("UNIQUE ")
+	}
+	sb.WriteString(fmt.Sprintf("%s (%s)", idx.Method, strings.Join(idx.Columns, ", ")))
+	if idx.Expression {
+		sb.WriteString(" with expressions")
+	}
+	if idx.Predicate != "" {
+		sb.WriteString(" WHERE " + idx.Predicate)
+	}
+	return sb.String()
+}
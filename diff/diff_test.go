@@ -0,0 +1,204 @@
+package diff
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/carloberd/db-reader/types"
+)
+
+// users returns a table to derive the compared versions from
+func users() *types.Table {
+	return &types.Table{
+		Name:   "users",
+		Schema: "public",
+		Columns: []types.Column{
+			{Name: "id", Type: "integer", IsPrimaryKey: true},
+			{Name: "email", Type: "text", Nullable: true},
+		},
+		Indexes: []types.Index{
+			{Name: "users_pkey", Columns: []string{"id"}, Unique: true, PrimaryKey: true, Method: "btree"},
+		},
+		Constraints: []types.Constraint{
+			{Name: "users_pkey", Type: "PRIMARY KEY", Definition: "PRIMARY KEY (id)"},
+		},
+	}
+}
+
+// schema wraps tables in a schema to compare
+func schema(tables ...*types.Table) *Schema {
+	return &Schema{Tables: tables}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*types.Table)
+		ignore Ignore
+		want   []string
+	}{
+		{
+			name:   "no changes",
+			change: func(*types.Table) {},
+		},
+		{
+			name: "column added and removed",
+			change: func(table *types.Table) {
+				table.Columns[1].Name = "mail"
+			},
+			want: []string{"- column users.email", "+ column users.mail"},
+		},
+		{
+			name: "column redefined",
+			change: func(table *types.Table) {
+				table.Columns[1].Type = "varchar(255)"
+				table.Columns[1].Nullable = false
+				table.Columns[1].DefaultValue = sql.NullString{String: "''", Valid: true}
+			},
+			want: []string{
+				`~ column users.email: type "text" -> "varchar(255)"`,
+				`~ column users.email: nullable "true" -> "false"`,
+				`~ column users.email: default none -> "''"`,
+			},
+		},
+		{
+			name: "foreign key ignores the schema qualifier",
+			change: func(table *types.Table) {
+				table.Columns[1].ForeignKey = sql.NullString{String: "public.emails (address)", Valid: true}
+			},
+			want: []string{`~ column users.email: foreign key none -> "emails (address)"`},
+		},
+		{
+			name: "comments",
+			change: func(table *types.Table) {
+				table.Comment = "People"
+				table.Columns[0].Comment = "Key"
+			},
+			want: []string{`~ table users: comment none -> "People"`, `~ column users.id: comment none -> "Key"`},
+		},
+		{
+			name: "comments ignored",
+			change: func(table *types.Table) {
+				table.Comment = "People"
+			},
+			ignore: Ignore{Comments: true},
+		},
+		{
+			name: "index renamed",
+			change: func(table *types.Table) {
+				table.Indexes[0].Name = "users_id_key"
+			},
+			want: []string{"+ index users.users_id_key", "- index users.users_pkey"},
+		},
+		{
+			name: "index renamed with names ignored",
+			change: func(table *types.Table) {
+				table.Indexes[0].Name = "users_id_key"
+			},
+			ignore: Ignore{IndexNames: true},
+		},
+		{
+			name: "constraint redefined",
+			change: func(table *types.Table) {
+				table.Constraints[0].Definition = "PRIMARY KEY (id, email)"
+			},
+			want: []string{`~ constraint users.users_pkey: definition "PRIMARY KEY (id)" -> "PRIMARY KEY (id, email)"`},
+		},
+		{
+			name: "ignored table",
+			change: func(table *types.Table) {
+				table.Columns = nil
+			},
+			ignore: Ignore{Tables: []string{"public.user*"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := users()
+			tt.change(after)
+
+			var got []string
+			for _, change := range Compare(schema(users()), schema(after), tt.ignore) {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes are\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareTables(t *testing.T) {
+	orders := &types.Table{Name: "orders", Schema: "public"}
+	audit := &types.Table{Name: "users", Schema: "audit"}
+
+	// One side spanning several schemas qualifies the table names
+	var got []string
+	for _, change := range Compare(schema(users(), orders), schema(users(), audit), Ignore{}) {
+		got = append(got, change.String())
+	}
+	want := []string{"+ table audit.users", "- table public.orders"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes are %q, want %q", got, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	after := users()
+	after.Columns[1].Type = "varchar(255)"
+	after.Columns[1].Nullable = false
+	after.Columns = append(after.Columns, types.Column{Name: "created_at", Type: "timestamp"})
+
+	result := Diff(schema(users()), schema(after), Ignore{})
+	if result.Empty() {
+		t.Fatal("result is empty")
+	}
+	if len(result.Columns) != 2 {
+		t.Fatalf("column changes are %+v, want 2", result.Columns)
+	}
+
+	added, modified := result.Columns[0], result.Columns[1]
+	if added.Kind != Added || added.Column != "created_at" || added.Before != nil || added.After == nil {
+		t.Errorf("added column is %+v", added)
+	}
+	if modified.Kind != Modified || !reflect.DeepEqual(modified.Fields, []string{"type", "nullable"}) {
+		t.Errorf("modified column is %+v, want type and nullable changed", modified)
+	}
+	if modified.Before == nil || modified.Before.Type != "text" || modified.After.Type != "varchar(255)" {
+		t.Errorf("modified column points to %+v and %+v", modified.Before, modified.After)
+	}
+
+	if !Diff(schema(users()), schema(users()), Ignore{}).Empty() {
+		t.Error("equal schemas differ")
+	}
+}
+
+func TestCompareBaseline(t *testing.T) {
+	dev, prod := users(), users()
+	dev.Columns[1].Type = "varchar(255)"
+	prod.Columns[1].Type = "varchar(100)"
+	dev.Comment, prod.Comment = "People", "People"
+	prod.Indexes = nil
+
+	divergences := CompareBaseline(schema(users()), schema(dev), schema(prod), Ignore{})
+	if len(divergences) != 3 {
+		t.Fatalf("divergences are %d, want 3", len(divergences))
+	}
+
+	var same, conflicts, prodOnly int
+	for _, d := range divergences {
+		switch {
+		case d.Same():
+			same++
+		case d.Conflict():
+			conflicts++
+		case d.A == nil:
+			prodOnly++
+		}
+	}
+	if same != 1 || conflicts != 1 || prodOnly != 1 {
+		t.Errorf("same %d, conflicts %d, only in prod %d; want one of each", same, conflicts, prodOnly)
+	}
+}
@@ -0,0 +1,216 @@
+package diff
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// htmlReport is the data of the HTML diff report
+type htmlReport struct {
+	From, To  string
+	Generated string
+	Added     int
+	Removed   int
+	Modified  int
+	Tables    []htmlTable
+}
+
+// htmlTable groups the changed objects of a table
+type htmlTable struct {
+	Name    string
+	Objects []htmlObject
+}
+
+// htmlObject is a changed object with its definitions on both sides
+type htmlObject struct {
+	Kind     ChangeKind
+	Object   string
+	Name     string
+	Fields   []string // Changed attributes of modified objects
+	Old, New string   // Definitions, empty where the object does not exist
+}
+
+// WriteHTML writes a standalone HTML report of the changes between two schemas,
+// showing the definitions of the changed objects side by side
+func WriteHTML(w io.Writer, a, b *Schema, changes []Change) error {
+	qualify := multiSchema(a) || multiSchema(b)
+	before := tablesByName(a, Ignore{}, qualify)
+	after := tablesByName(b, Ignore{}, qualify)
+
+	report := htmlReport{
+		From:      a.Name,
+		To:        b.Name,
+		Generated: time.Now().Format("2006-01-02 15:04"),
+	}
+
+	// Changes come ordered by table; several changes to the same object make one row
+	var rows map[string]int
+	for _, change := range changes {
+		switch change.Kind {
+		case Added:
+			report.Added++
+		case Removed:
+			report.Removed++
+		}
+
+		if len(report.Tables) == 0 || report.Tables[len(report.Tables)-1].Name != change.Table {
+			report.Tables = append(report.Tables, htmlTable{Name: change.Table})
+			rows = make(map[string]int)
+		}
+		table := &report.Tables[len(report.Tables)-1]
+
+		key := change.Object + " " + change.Name
+		if row, ok := rows[key]; ok {
+			table.Objects[row].Fields = append(table.Objects[row].Fields, change.Field)
+			continue
+		}
+
+		obj := htmlObject{
+			Kind:   change.Kind,
+			Object: change.Object,
+			Name:   change.Name,
+			Old:    definition(before[change.Table], change),
+			New:    definition(after[change.Table], change),
+		}
+		if change.Kind == Modified {
+			report.Modified++
+			obj.Fields = []string{change.Field}
+		}
+		rows[key] = len(table.Objects)
+		table.Objects = append(table.Objects, obj)
+	}
+
+	if err := htmlTemplate.Execute(w, report); err != nil {
+		return fmt.Errorf("error writing HTML report: %v", err)
+	}
+	return nil
+}
+
+// definition describes the object a change applies to as it is defined in a table,
+// or returns an empty string if the object does not exist there
+func definition(table *t.Table, change Change) string {
+	if table == nil {
+		return ""
+	}
+
+	switch change.Object {
+	case TableObject:
+		// The comment is the only attribute of a table itself
+		if change.Kind == Modified {
+			return table.Comment
+		}
+
+		var lines []string
+		if table.Comment != "" {
+			lines = append(lines, "-- "+table.Comment)
+		}
+		for _, col := range table.Columns {
+			lines = append(lines, col.Name+" "+columnDefinition(col))
+		}
+		for _, idx := range table.Indexes {
+			lines = append(lines, "INDEX "+idx.Name+" "+indexDefinition(idx))
+		}
+		for _, con := range table.Constraints {
+			lines = append(lines, "CONSTRAINT "+con.Name+" "+con.Definition)
+		}
+		return strings.Join(lines, "\n")
+
+	case ColumnObject:
+		for _, col := range table.Columns {
+			if col.Name == change.Name {
+				return columnDefinition(col)
+			}
+		}
+
+	case IndexObject:
+		for _, idx := range table.Indexes {
+			if idx.Name == change.Name {
+				return indexDefinition(idx)
+			}
+		}
+
+	case ConstraintObject:
+		for _, con := range table.Constraints {
+			if con.Name == change.Name {
+				return con.Definition
+			}
+		}
+	}
+
+	return ""
+}
+
+// columnDefinition describes a column on a single line
+func columnDefinition(col t.Column) string {
+	def := col.Type
+	if !col.Nullable {
+		def += " NOT NULL"
+	}
+	if col.DefaultValue.Valid {
+		def += " DEFAULT " + col.DefaultValue.String
+	}
+	if col.IsPrimaryKey {
+		def += " PRIMARY KEY"
+	}
+	if ref := foreignKey(col); ref != "" {
+		def += " REFERENCES " + ref
+	}
+	if col.Comment != "" {
+		def += " -- " + col.Comment
+	}
+	return def
+}
+
+// htmlTemplate renders the HTML diff report
+var htmlTemplate = template.Must(template.New("diff").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Schema diff: {{.From}} / {{.To}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; table-layout: fixed; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+th.object { width: 20%; }
+pre { margin: 0; white-space: pre-wrap; font-size: 0.9em; }
+.fields { color: #666; font-size: 0.85em; }
+tr.added td.new { background: #e6ffec; }
+tr.removed td.old { background: #ffebe9; }
+tr.modified td.old { background: #fff8c5; }
+tr.modified td.new { background: #fff8c5; }
+.legend span { padding: 0.2em 0.6em; margin-right: 0.5em; }
+</style>
+</head>
+<body>
+<h1>Schema diff</h1>
+<p>Comparing <strong>{{.From}}</strong> with <strong>{{.To}}</strong>, generated {{.Generated}}.</p>
+<p class="legend">
+<span style="background: #e6ffec">{{.Added}} added</span>
+<span style="background: #ffebe9">{{.Removed}} removed</span>
+<span style="background: #fff8c5">{{.Modified}} modified</span>
+</p>
+{{- if not .Tables}}
+<p>No differences found.</p>
+{{- end}}
+{{- range .Tables}}
+<h2>{{.Name}}</h2>
+<table>
+<tr><th class="object">Object</th><th>{{$.From}}</th><th>{{$.To}}</th></tr>
+{{- range .Objects}}
+<tr class="{{.Kind}}">
+<td>{{.Object}}{{with .Name}} {{.}}{{end}}{{if .Fields}}<div class="fields">{{range $i, $f := .Fields}}{{if $i}}, {{end}}{{$f}}{{end}}</div>{{end}}</td>
+<td class="old"><pre>{{.Old}}</pre></td>
+<td class="new"><pre>{{.New}}</pre></td>
+</tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,145 @@
+package diff
+
+import (
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Result holds the differences between two schemas as typed records per object
+// kind. Before and After point into the compared schemas and are nil on the side
+// where the object does not exist.
+type Result struct {
+	From, To    string // Names of the compared schemas
+	Tables      []TableChange
+	Columns     []ColumnChange
+	Indexes     []IndexChange
+	Constraints []ConstraintChange
+
+	changes []Change
+}
+
+// TableChange is a table added, removed or with a changed comment
+type TableChange struct {
+	Kind          ChangeKind
+	Table         string
+	Fields        []string // Changed attributes of a modified table
+	Before, After *t.Table
+}
+
+// ColumnChange is a column added, removed or redefined
+type ColumnChange struct {
+	Kind          ChangeKind
+	Table         string
+	Column        string
+	Fields        []string // Changed attributes of a modified column, e.g. "type"
+	Before, After *t.Column
+}
+
+// IndexChange is an index added, removed or redefined
+type IndexChange struct {
+	Kind          ChangeKind
+	Table         string
+	Index         string
+	Before, After *t.Index
+}
+
+// ConstraintChange is a constraint added, removed or redefined
+type ConstraintChange struct {
+	Kind          ChangeKind
+	Table         string
+	Constraint    string
+	Before, After *t.Constraint
+}
+
+// Empty reports whether the schemas match
+func (r *Result) Empty() bool {
+	return len(r.changes) == 0
+}
+
+// Changes returns the differences as a flat list, one entry per changed attribute
+func (r *Result) Changes() []Change {
+	return r.changes
+}
+
+// Diff compares two schemas and returns the differences as typed records
+func Diff(a, b *Schema, ignore Ignore) *Result {
+	qualify := multiSchema(a) || multiSchema(b)
+	before := tablesByName(a, Ignore{}, qualify)
+	after := tablesByName(b, Ignore{}, qualify)
+
+	result := &Result{From: a.Name, To: b.Name, changes: Compare(a, b, ignore)}
+	for _, change := range result.changes {
+		oldTable, newTable := before[change.Table], after[change.Table]
+
+		switch change.Object {
+		case TableObject:
+			if n := len(result.Tables); change.Kind == Modified && n > 0 && result.Tables[n-1].Table == change.Table {
+				result.Tables[n-1].Fields = append(result.Tables[n-1].Fields, change.Field)
+				continue
+			}
+			rec := TableChange{Kind: change.Kind, Table: change.Table, Before: oldTable, After: newTable}
+			if change.Kind == Modified {
+				rec.Fields = []string{change.Field}
+			}
+			result.Tables = append(result.Tables, rec)
+
+		case ColumnObject:
+			if n := len(result.Columns); change.Kind == Modified && n > 0 &&
+				result.Columns[n-1].Table == change.Table && result.Columns[n-1].Column == change.Name {
+				result.Columns[n-1].Fields = append(result.Columns[n-1].Fields, change.Field)
+				continue
+			}
+			rec := ColumnChange{Kind: change.Kind, Table: change.Table, Column: change.Name,
+				Before: findColumn(oldTable, change.Name), After: findColumn(newTable, change.Name)}
+			if change.Kind == Modified {
+				rec.Fields = []string{change.Field}
+			}
+			result.Columns = append(result.Columns, rec)
+
+		case IndexObject:
+			result.Indexes = append(result.Indexes, IndexChange{Kind: change.Kind, Table: change.Table, Index: change.Name,
+				Before: findIndex(oldTable, change.Name), After: findIndex(newTable, change.Name)})
+
+		case ConstraintObject:
+			result.Constraints = append(result.Constraints, ConstraintChange{Kind: change.Kind, Table: change.Table,
+				Constraint: change.Name, Before: findConstraint(oldTable, change.Name), After: findConstraint(newTable, change.Name)})
+		}
+	}
+
+	return result
+}
+
+// findColumn returns the named column of a table, or nil
+func findColumn(table *t.Table, name string) *t.Column {
+	if table != nil {
+		for i := range table.Columns {
+			if table.Columns[i].Name == name {
+				return &table.Columns[i]
+			}
+		}
+	}
+	return nil
+}
+
+// findIndex returns the named index of a table, or nil
+func findIndex(table *t.Table, name string) *t.Index {
+	if table != nil {
+		for i := range table.Indexes {
+			if table.Indexes[i].Name == name {
+				return &table.Indexes[i]
+			}
+		}
+	}
+	return nil
+}
+
+// findConstraint returns the named constraint of a table, or nil
+func findConstraint(table *t.Table, name string) *t.Constraint {
+	if table != nil {
+		for i := range table.Constraints {
+			if table.Constraints[i].Name == name {
+				return &table.Constraints[i]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/carloberd/db-reader/export"
+)
+
+// snapshot is the file format of a saved schema, meant to be committed as a baseline
+type snapshot struct {
+	Name   string                 `json:"name"`
+	Taken  time.Time              `json:"taken"`
+	Tables []export.TableDocument `json:"tables"`
+	Stats  []TableStats           `json:"stats,omitempty"`
+}
+
+// WriteSnapshot saves the tables of a schema as JSON
+func WriteSnapshot(w io.Writer, s *Schema) error {
+	snap := snapshot{
+		Name:   s.Name,
+		Taken:  time.Now().UTC().Truncate(time.Second),
+		Tables: []export.TableDocument{},
+		Stats:  s.Stats,
+	}
+	for _, table := range s.Tables {
+		doc := export.NewTableDocument(table)
+		// Sizes change all the time and would make every snapshot differ
+		for i := range doc.Indexes {
+			doc.Indexes[i].SizeBytes = 0
+		}
+		snap.Tables = append(snap.Tables, doc)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snap); err != nil {
+		return fmt.Errorf("error writing snapshot: %v", err)
+	}
+	return nil
+}
+
+// ReadSnapshot loads a schema saved with WriteSnapshot
+func ReadSnapshot(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot: %v", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("error parsing snapshot %s: %v", path, err)
+	}
+
+	s := &Schema{Name: snap.Name, Taken: snap.Taken, Stats: snap.Stats}
+	if s.Name == "" {
+		s.Name = path
+	}
+	for _, doc := range snap.Tables {
+		s.Tables = append(s.Tables, doc.Table())
+	}
+	return s, nil
+}
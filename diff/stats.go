@@ -0,0 +1,49 @@
+package diff
+
+import t "github.com/carloberd/db-reader/types"
+
+// TableStats is the size of a table when a snapshot was taken. Snapshots taken
+// over time record how the tables grow.
+type TableStats struct {
+	Schema string `json:"schema,omitempty"` // Schema of the table as in the snapshot, "" if the table has none
+	Table  string `json:"table"`
+	Rows   int64  `json:"rows"`  // Estimated rows, -1 if unknown
+	Bytes  int64  `json:"bytes"` // Storage used by the table, 0 if unknown
+}
+
+// RecordStats records the current rows and storage of the tables of the schema,
+// if the connector can list table sizes. Tables without a schema are looked up
+// in the default schema.
+func (s *Schema) RecordStats(connector t.DatabaseConnector, defaultSchema string) error {
+	lister, ok := connector.(t.TableSizeLister)
+	if !ok {
+		return nil
+	}
+
+	sizes := make(map[string]map[string]t.TableSize)
+	s.Stats = nil
+	for _, table := range s.Tables {
+		schema := table.Schema
+		if schema == "" {
+			schema = defaultSchema
+		}
+
+		bySchema, ok := sizes[schema]
+		if !ok {
+			list, err := lister.GetTableSizes(schema)
+			if err != nil {
+				return err
+			}
+			bySchema = make(map[string]t.TableSize, len(list))
+			for _, size := range list {
+				bySchema[size.Name] = size
+			}
+			sizes[schema] = bySchema
+		}
+
+		if size, ok := bySchema[table.Name]; ok {
+			s.Stats = append(s.Stats, TableStats{Schema: table.Schema, Table: table.Name, Rows: size.Rows, Bytes: size.Bytes})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package diff
+
+import "sort"
+
+// Divergence is a difference from a baseline found in one or both compared schemas
+type Divergence struct {
+	A *Change // Change in the first schema, nil if it matches the baseline
+	B *Change // Change in the second schema, nil if it matches the baseline
+}
+
+// Same reports whether both schemas made the same change to the baseline
+func (d Divergence) Same() bool {
+	return d.A != nil && d.B != nil && *d.A == *d.B
+}
+
+// Conflict reports whether both schemas changed the same object differently
+func (d Divergence) Conflict() bool {
+	return d.A != nil && d.B != nil && *d.A != *d.B
+}
+
+// changeKey identifies the object and attribute a change applies to
+type changeKey struct {
+	object, table, name, field string
+}
+
+// CompareBaseline compares two schemas against a common baseline, for example dev and
+// prod against the last released snapshot, telling which side diverged from it
+func CompareBaseline(baseline, a, b *Schema, ignore Ignore) []Divergence {
+	var divergences []Divergence
+	index := make(map[changeKey]int)
+
+	for _, change := range Compare(baseline, a, ignore) {
+		index[keyOf(change)] = len(divergences)
+		divergences = append(divergences, Divergence{A: &change})
+	}
+	for _, change := range Compare(baseline, b, ignore) {
+		if i, ok := index[keyOf(change)]; ok {
+			divergences[i].B = &change
+			continue
+		}
+		divergences = append(divergences, Divergence{B: &change})
+	}
+
+	// Keep the changes of each table together, as Compare does
+	sort.SliceStable(divergences, func(i, j int) bool {
+		return divergences[i].table() < divergences[j].table()
+	})
+
+	return divergences
+}
+
+// table returns the table the diverged object belongs to
+func (d Divergence) table() string {
+	if d.A != nil {
+		return d.A.Table
+	}
+	return d.B.Table
+}
+
+// keyOf returns the key of the object and attribute a change applies to
+func keyOf(c Change) changeKey {
+	return changeKey{c.Object, c.Table, c.Name, c.Field}
+}
@@ -0,0 +1,459 @@
+package duckdb
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	_ "github.com/marcboeker/go-duckdb" // DuckDB driver
+)
+
+// defaultSchema is the schema DuckDB creates in every database
+const defaultSchema = "main"
+
+// dataExtensions are the file types attached as views to an in-memory database,
+// with the function reading them
+var dataExtensions = map[string]string{
+	".parquet": "read_parquet",
+	".csv":     "read_csv",
+	".tsv":     "read_csv",
+	".json":    "read_json",
+	".jsonl":   "read_json",
+	".ndjson":  "read_json",
+}
+
+// DuckDBConnector implements the DatabaseConnector interface for DuckDB. The
+// database name is either the path of a .duckdb file, opened read-only, or a
+// semicolon separated list of Parquet, CSV and JSON files, each read through a
+// view of an in-memory database named after the file.
+type DuckDBConnector struct {
+	sqlutil.QueryLog
+
+	db    *sql.DB
+	files []string // Views over the attached data files, if any
+}
+
+// Connect opens the database file, or an in-memory database over the data files.
+// Session settings are passed to DuckDB as configuration options, e.g. threads=4.
+func (dc *DuckDBConnector) Connect(params t.ConnectionParams) error {
+	paths := dataFiles(params.Database)
+
+	options := url.Values{}
+	for key, value := range params.Settings {
+		options.Set(key, value)
+	}
+
+	dsn := ""
+	if paths == nil && params.Database != ":memory:" {
+		// Opening a missing file would silently create an empty database
+		if _, err := os.Stat(params.Database); err != nil {
+			return fmt.Errorf("failed to open database file: %v", err)
+		}
+		dsn = params.Database
+		options.Set("access_mode", "read_only")
+	}
+	if len(options) > 0 {
+		dsn += "?" + options.Encode()
+	}
+
+	var err error
+	dc.db, err = sql.Open("duckdb", dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %v", err)
+	}
+
+	// Test the connection
+	if err := dc.db.Ping(); err != nil {
+		dc.Disconnect()
+		return fmt.Errorf("failed to open database: %v", err)
+	}
+
+	dc.files = nil
+	for _, path := range paths {
+		if err := dc.attachFile(path); err != nil {
+			dc.Disconnect()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dataFiles splits the database name into data files, or returns nil if it names a database file
+func dataFiles(database string) []string {
+	var paths []string
+	for _, path := range strings.Split(database, ";") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if !IsDataFile(path) {
+			return nil
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// IsDataFile reports whether a path, or the last of a list of paths, is read as a data file
+func IsDataFile(path string) bool {
+	if i := strings.LastIndex(path, ";"); i >= 0 {
+		path = path[i+1:]
+	}
+	_, ok := dataExtensions[dataExtension(strings.TrimSpace(path))]
+	return ok
+}
+
+// dataExtension returns the extension of a data file, ignoring gzip compression
+func dataExtension(path string) string {
+	return strings.ToLower(filepath.Ext(strings.TrimSuffix(path, ".gz")))
+}
+
+// attachFile creates a view reading a data file, named after the file. Globs such
+// as data/*.parquet read all matching files through one view.
+func (dc *DuckDBConnector) attachFile(path string) error {
+	base := filepath.Base(strings.TrimSuffix(path, ".gz"))
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	name = strings.NewReplacer("*", "all", "?", "_").Replace(name)
+
+	query := fmt.Sprintf("CREATE VIEW %s AS SELECT * FROM %s(%s)",
+		quoteIdentifier(name), dataExtensions[dataExtension(path)], quoteLiteral(path))
+	dc.LogQuery(query, nil)
+	if _, err := dc.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to read data file %s: %v", path, err)
+	}
+
+	dc.files = append(dc.files, name)
+	return nil
+}
+
+// Disconnect closes the database
+func (dc *DuckDBConnector) Disconnect() error {
+	if dc.db != nil {
+		err := dc.db.Close()
+		dc.db = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// quoteIdentifier quotes a DuckDB identifier
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral quotes a string literal
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// schemaName returns the schema to inspect, defaulting to the main one
+func schemaName(schema string) string {
+	if schema == "" {
+		return defaultSchema
+	}
+	return schema
+}
+
+// GetTables returns a list of tables in the specified schema, including the
+// views over attached data files
+func (dc *DuckDBConnector) GetTables(schema string) ([]string, error) {
+	if dc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = schemaName(schema)
+
+	query := `
+		SELECT
+			table_name
+		FROM
+			duckdb_tables()
+		WHERE
+			schema_name = ?
+			AND NOT internal
+			AND NOT temporary
+	`
+
+	rows, err := dc.Query(dc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, tableName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if schema == defaultSchema {
+		tables = append(tables, dc.files...)
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// GetTableStructure returns the structure of the specified table
+func (dc *DuckDBConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if dc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = schemaName(schema)
+
+	table := &t.Table{Name: tableName, Schema: schema}
+
+	// Check if the table exists, reading its comment at the same time
+	rows, err := dc.Query(dc.db, `
+		SELECT comment FROM duckdb_tables() WHERE schema_name = ? AND table_name = ?
+		UNION ALL
+		SELECT comment FROM duckdb_views() WHERE schema_name = ? AND view_name = ?
+	`, schema, tableName, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	var comment sql.NullString
+	exists := rows.Next()
+	if exists {
+		err = rows.Scan(&comment)
+	}
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	table.Comment = comment.String
+
+	if table.Constraints, err = dc.getConstraints(schema, tableName); err != nil {
+		return nil, err
+	}
+	if table.Columns, err = dc.getColumns(schema, tableName, table.Constraints); err != nil {
+		return nil, err
+	}
+	if table.Indexes, err = dc.getIndexes(schema, tableName); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// getColumns returns the columns of a table, with the primary and foreign keys
+// taken from its constraints
+func (dc *DuckDBConnector) getColumns(schema, tableName string, constraints []t.Constraint) ([]t.Column, error) {
+	query := `
+		SELECT
+			column_name,
+			data_type,
+			is_nullable,
+			column_default,
+			comment
+		FROM
+			duckdb_columns()
+		WHERE
+			schema_name = ? AND table_name = ?
+		ORDER BY
+			column_index
+	`
+
+	rows, err := dc.Query(dc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []t.Column
+	for rows.Next() {
+		var col t.Column
+		var comment sql.NullString
+
+		if err := rows.Scan(&col.Name, &col.Type, &col.Nullable, &col.DefaultValue, &comment); err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+		col.Comment = comment.String
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Views over data files have no constraints, but their columns are listed the same way
+	for i := range columns {
+		col := &columns[i]
+		for _, con := range constraints {
+			pos := indexOf(con.Columns, col.Name)
+			if pos < 0 {
+				continue
+			}
+			switch con.Type {
+			case t.PrimaryKeyConstraint:
+				col.IsPrimaryKey = true
+			case t.ForeignKeyConstraint:
+				if ref, ok := foreignKeyRef(con.Definition, pos); ok && !col.ForeignKey.Valid {
+					col.ForeignKey = sql.NullString{String: ref, Valid: true}
+				}
+			}
+		}
+	}
+
+	return columns, nil
+}
+
+// foreignKeyRef returns the "table (column)" reference of a key column from a
+// definition such as "FOREIGN KEY (a, b) REFERENCES t(x, y)"
+func foreignKeyRef(definition string, pos int) (string, bool) {
+	_, target, ok := strings.Cut(definition, " REFERENCES ")
+	if !ok {
+		return "", false
+	}
+	open := strings.Index(target, "(")
+	if open < 0 || !strings.HasSuffix(target, ")") {
+		return "", false
+	}
+	refColumns := strings.Split(target[open+1:len(target)-1], ",")
+	if pos >= len(refColumns) {
+		return "", false
+	}
+	return fmt.Sprintf("%s (%s)", strings.TrimSpace(target[:open]), strings.TrimSpace(refColumns[pos])), true
+}
+
+// indexOf returns the position of a name in a list, or -1
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// getIndexes returns the indexes of a table. Primary keys and unique
+// constraints are enforced by indexes that DuckDB does not list.
+func (dc *DuckDBConnector) getIndexes(schema, tableName string) ([]t.Index, error) {
+	query := `
+		SELECT
+			index_name,
+			is_unique,
+			is_primary,
+			expressions
+		FROM
+			duckdb_indexes()
+		WHERE
+			schema_name = ? AND table_name = ?
+		ORDER BY
+			index_name
+	`
+
+	rows, err := dc.Query(dc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying indexes: %v", err)
+	}
+	defer rows.Close()
+
+	var indexes []t.Index
+	for rows.Next() {
+		var idx t.Index
+		var expressions string
+
+		if err := rows.Scan(&idx.Name, &idx.Unique, &idx.PrimaryKey, &expressions); err != nil {
+			return nil, fmt.Errorf("error scanning index results: %v", err)
+		}
+
+		// Expressions are listed as "[a, b]", with parentheses around computed keys
+		idx.Method = "art"
+		for _, key := range strings.Split(strings.Trim(expressions, "[]"), ", ") {
+			if strings.HasPrefix(key, "(") {
+				idx.Expression = true
+			} else if key != "" {
+				idx.Columns = append(idx.Columns, strings.Trim(key, `"`))
+			}
+		}
+		indexes = append(indexes, idx)
+	}
+
+	return indexes, rows.Err()
+}
+
+// getConstraints returns the constraints of a table, leaving out NOT NULL
+// constraints, which are shown on the columns
+func (dc *DuckDBConnector) getConstraints(schema, tableName string) ([]t.Constraint, error) {
+	query := `
+		SELECT
+			constraint_name,
+			constraint_type,
+			constraint_text,
+			constraint_column_names
+		FROM
+			duckdb_constraints()
+		WHERE
+			schema_name = ? AND table_name = ?
+			AND constraint_type <> 'NOT NULL'
+		ORDER BY
+			constraint_index
+	`
+
+	rows, err := dc.Query(dc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying constraints: %v", err)
+	}
+	defer rows.Close()
+
+	var constraints []t.Constraint
+	for rows.Next() {
+		var con t.Constraint
+		var columns []any
+
+		if err := rows.Scan(&con.Name, &con.Type, &con.Definition, &columns); err != nil {
+			return nil, fmt.Errorf("error scanning constraint results: %v", err)
+		}
+		for _, col := range columns {
+			con.Columns = append(con.Columns, fmt.Sprint(col))
+		}
+		constraints = append(constraints, con)
+	}
+
+	return constraints, rows.Err()
+}
+
+// NewDuckDBConnector creates a connector for DuckDB databases and data files
+func NewDuckDBConnector() t.DatabaseConnector {
+	return &DuckDBConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverDuckDB, NewDuckDBConnector, t.ConnectorInfo{
+		Title:      "DuckDB file",
+		Fields:     []string{t.FieldDatabase, t.FieldSchema},
+		Hint:       "file.duckdb, or data files separated by ;",
+		File:       true,
+		Extensions: append([]string{".duckdb", ".db", ".gz"}, slices.Sorted(maps.Keys(dataExtensions))...),
+		// Data files picked one after the other are all read
+		AddFile: func(database, path string) string {
+			if IsDataFile(path) && IsDataFile(database) {
+				return database + ";" + path
+			}
+			return path
+		},
+		Defaults: func(p *t.ConnectionParams) {
+			p.Schema = cmp.Or(p.Schema, "main")
+		},
+	})
+}
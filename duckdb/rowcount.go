@@ -0,0 +1,58 @@
+package duckdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// quoteQualified returns a quoted schema-qualified name for use in queries
+func quoteQualified(schema, name string) string {
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// EstimateRowCount returns the row count DuckDB keeps for a table. Views over
+// data files have no estimate and report -1.
+func (dc *DuckDBConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if dc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+	schema = schemaName(schema)
+
+	rows, err := dc.Query(dc.db, `
+		SELECT estimated_size FROM duckdb_tables() WHERE schema_name = ? AND table_name = ?
+		UNION ALL
+		SELECT -1 FROM duckdb_views() WHERE schema_name = ? AND view_name = ?
+	`, schema, tableName, schema, tableName)
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	var estimate int64
+	if err := rows.Scan(&estimate); err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	return estimate, nil
+}
+
+// CountRows returns the exact number of rows in a table, reading the whole file for data files
+func (dc *DuckDBConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if dc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := "SELECT count(*) FROM " + quoteQualified(schemaName(schema), tableName)
+	dc.LogQuery(query, nil)
+
+	var count int64
+	if err := dc.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
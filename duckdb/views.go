@@ -0,0 +1,67 @@
+package duckdb
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViews returns the views of the schema, with their columns and SELECT
+// statements. The views over attached data files are listed as tables instead.
+func (dc *DuckDBConnector) GetViews(schema string) ([]t.View, error) {
+	if dc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = schemaName(schema)
+
+	query := `
+		SELECT
+			view_name,
+			sql,
+			comment
+		FROM
+			duckdb_views()
+		WHERE
+			schema_name = ?
+			AND NOT internal
+			AND NOT temporary
+		ORDER BY
+			view_name
+	`
+
+	rows, err := dc.Query(dc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+	defer rows.Close()
+
+	var views []t.View
+	for rows.Next() {
+		view := t.View{Schema: schema}
+		var definition, comment sql.NullString
+		if err := rows.Scan(&view.Name, &definition, &comment); err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		if schema == defaultSchema && slices.Contains(dc.files, view.Name) {
+			continue
+		}
+		// DuckDB keeps the CREATE VIEW statement
+		view.Definition = sqlutil.ViewQuery(definition.String)
+		view.Comment = comment.String
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading views: %v", err)
+	}
+	rows.Close()
+
+	for i := range views {
+		if views[i].Columns, err = dc.getColumns(schema, views[i].Name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
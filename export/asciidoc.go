@@ -0,0 +1,211 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// WriteColumnsAsciiDoc writes the columns of a table as an AsciiDoc table
+func WriteColumnsAsciiDoc(w io.Writer, table *t.Table) error {
+	_, err := io.WriteString(w, columnsAsciiDoc(table))
+	return err
+}
+
+// columnsAsciiDoc returns the AsciiDoc table of the columns of a table
+func columnsAsciiDoc(table *t.Table) string {
+	var sb strings.Builder
+
+	sb.WriteString("[cols=\"3,3,1,3,1,3\",options=\"header\"]\n|===\n")
+	sb.WriteString("|Column |Type |Nullable |Default |Primary key |Foreign key\n")
+
+	for _, col := range table.Columns {
+		defaultVal := ""
+		if col.DefaultValue.Valid {
+			defaultVal = asciidocLiteral(col.DefaultValue.String)
+		}
+
+		primaryKey := ""
+		if col.IsPrimaryKey {
+			primaryKey = "yes"
+		}
+
+		nullable := "no"
+		if col.Nullable {
+			nullable = "yes"
+		}
+
+		sb.WriteString(fmt.Sprintf("\n|%s |%s |%s |%s |%s |%s\n",
+			asciidocCell(col.Name), asciidocCell(col.Type), nullable,
+			defaultVal, primaryKey, asciidocCell(col.ForeignKey.String)))
+	}
+	sb.WriteString("|===\n")
+	return sb.String()
+}
+
+// asciidocCell escapes a value for use in an AsciiDoc table cell
+func asciidocCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// asciidocLiteral formats a value as monospace text without AsciiDoc markup,
+// e.g. a default expression or a constraint definition
+func asciidocLiteral(s string) string {
+	return "`+" + asciidocCell(s) + "+`"
+}
+
+// WriteTableAsciiDoc writes a documentation page of a table in AsciiDoc, as
+// WriteTableMarkdown does in Markdown
+func WriteTableAsciiDoc(w io.Writer, table *t.Table) error {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("= %s.%s\n\n", table.Schema, table.Name))
+	writeTableSectionsAsciiDoc(&sb, table, "==")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeTableSectionsAsciiDoc writes the comment, properties, columns, indexes
+// and constraints of a table, with the section titles at the given level
+func writeTableSectionsAsciiDoc(sb *strings.Builder, table *t.Table, level string) {
+	if table.Comment != "" {
+		sb.WriteString(table.Comment + "\n\n")
+	}
+	for _, prop := range table.Properties {
+		sb.WriteString(fmt.Sprintf("* *%s:* %s\n", prop.Name, prop.Value))
+	}
+	if len(table.Properties) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(level + " Columns\n\n")
+	sb.WriteString(columnsAsciiDoc(table))
+
+	if len(table.Indexes) > 0 {
+		sb.WriteString("\n" + level + " Indexes\n\n")
+		for _, idx := range table.Indexes {
+			keys := strings.Join(idx.Columns, ", ")
+			if idx.Expression {
+				keys += " (with expressions)"
+			}
+			if len(idx.Include) > 0 {
+				keys += ", including " + strings.Join(idx.Include, ", ")
+			}
+			kind := ""
+			if idx.PrimaryKey {
+				kind = " primary key"
+			} else if idx.Unique {
+				kind = " unique"
+			}
+			sb.WriteString(fmt.Sprintf("* %s%s on %s\n", asciidocLiteral(idx.Name), kind, keys))
+		}
+	}
+
+	if len(table.Constraints) > 0 {
+		sb.WriteString("\n" + level + " Constraints\n\n")
+		for _, con := range table.Constraints {
+			sb.WriteString(fmt.Sprintf("* %s: %s\n", asciidocLiteral(con.Name), asciidocLiteral(con.Definition)))
+		}
+	}
+}
+
+// WriteSampleSectionAsciiDoc writes the AsciiDoc section of a documentation
+// page linking to the sample CSV file of the table
+func WriteSampleSectionAsciiDoc(w io.Writer, file string, sample *t.QueryResult, masked []string) error {
+	var sb strings.Builder
+	sb.WriteString("\n== Sample data\n\n")
+	sb.WriteString(fmt.Sprintf("link:%s[%d example rows]", file, len(sample.Rows)))
+	if len(masked) > 0 {
+		sb.WriteString(fmt.Sprintf(", with the values of %s masked", strings.Join(masked, ", ")))
+	}
+	sb.WriteString(".\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteDataDictionaryAsciiDoc writes every table of a schema into a single
+// AsciiDoc document, as WriteDataDictionary does in Markdown. The table of
+// contents is left to the toc attribute.
+func WriteDataDictionaryAsciiDoc(w io.Writer, schema string, tables []*t.Table) error {
+	return WriteGroupedDataDictionaryAsciiDoc(w, schema, tables, Grouping{})
+}
+
+// WriteGroupedDataDictionaryAsciiDoc writes an AsciiDoc data dictionary with
+// the tables of each module of the grouping under a section of their own
+func WriteGroupedDataDictionaryAsciiDoc(w io.Writer, schema string, tables []*t.Table, grouping Grouping) error {
+	var sb strings.Builder
+
+	referencedBy := make(map[string][]string)
+	for _, table := range tables {
+		for _, ref := range referencedTables(table) {
+			if ref != table.Name && !slices.Contains(referencedBy[ref], table.Name) {
+				referencedBy[ref] = append(referencedBy[ref], table.Name)
+			}
+		}
+	}
+
+	groups := []TableGroup{{Tables: tables}}
+	level := "=="
+	if !grouping.IsZero() {
+		groups = grouping.Group(tables)
+		level = "==="
+	}
+
+	if level == "==" {
+		sb.WriteString(fmt.Sprintf("= Data dictionary: %s\n:toc:\n:toclevels: 1\n", schema))
+	} else {
+		sb.WriteString(fmt.Sprintf("= Data dictionary: %s\n:toc:\n:toclevels: 2\n", schema))
+	}
+
+	for _, group := range groups {
+		if level != "==" {
+			sb.WriteString(fmt.Sprintf("\n== %s\n", moduleTitle(group.Module)))
+		}
+		for _, table := range group.Tables {
+			sb.WriteString(fmt.Sprintf("\n[#%s]\n%s %s\n\n", asciidocID(table.Name), level, table.Name))
+			writeTableSectionsAsciiDoc(&sb, table, level+"=")
+
+			references := referencedTables(table)
+			if len(references) > 0 || len(referencedBy[table.Name]) > 0 {
+				sb.WriteString("\n" + level + "= Related tables\n\n")
+				if len(references) > 0 {
+					sb.WriteString("* References: " + asciidocXrefs(references) + "\n")
+				}
+				if len(referencedBy[table.Name]) > 0 {
+					sb.WriteString("* Referenced by: " + asciidocXrefs(referencedBy[table.Name]) + "\n")
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// asciidocXrefs returns cross references to the sections of tables
+func asciidocXrefs(names []string) string {
+	refs := make([]string, len(names))
+	for i, name := range names {
+		refs[i] = fmt.Sprintf("<<%s,%s>>", asciidocID(name), name)
+	}
+	return strings.Join(refs, ", ")
+}
+
+// asciidocID returns the section ID of a table, which AsciiDoc limits to
+// letters, digits, dashes and underscores
+func asciidocID(table string) string {
+	return "table-" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, table)
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// MigrationStyle selects the file naming convention of a baseline migration
+type MigrationStyle string
+
+const (
+	// GolangMigrate writes 000001_baseline.up.sql and 000001_baseline.down.sql
+	GolangMigrate MigrationStyle = "golang-migrate"
+
+	// Flyway writes V1__baseline.sql
+	Flyway MigrationStyle = "flyway"
+)
+
+// MigrationStyles lists the supported baseline migration styles
+var MigrationStyles = []MigrationStyle{GolangMigrate, Flyway}
+
+// WriteBaseline writes an initial migration creating the given tables into dir,
+// named after the conventions of the migration tool. The tables are created by
+// the DDL of the provider, if any, so every constraint and index the database
+// knows is kept. It returns the written files.
+func WriteBaseline(dir string, style MigrationStyle, schema string, tables []*t.Table, provider t.DDLProvider) ([]string, error) {
+	var up strings.Builder
+	up.WriteString(fmt.Sprintf("-- Baseline of schema %s generated by db-reader on %s\n\n",
+		schema, time.Now().Format("2006-01-02")))
+	if err := WriteDatabaseDDL(&up, tables, provider); err != nil {
+		return nil, err
+	}
+
+	type file struct{ name, content string }
+	var files []file
+	switch style {
+	case GolangMigrate:
+		files = []file{
+			{"000001_baseline.up.sql", up.String()},
+			{"000001_baseline.down.sql", dropStatements(tables)},
+		}
+	case Flyway:
+		files = []file{{"V1__baseline.sql", up.String()}}
+	default:
+		return nil, fmt.Errorf("unknown migration style '%s'", style)
+	}
+
+	// Check every file first, so an existing one leaves nothing half written
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("file %s already exists", path)
+		}
+	}
+
+	var written []string
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, []byte(f.content), 0o644); err != nil {
+			return written, fmt.Errorf("error writing %s: %v", path, err)
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// dropStatements returns DROP TABLE statements undoing a baseline, dependants first
+func dropStatements(tables []*t.Table) string {
+	ordered := sortByDependencies(tables)
+
+	var sb strings.Builder
+	for i := len(ordered) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", QuoteIdentifier(ordered[i].Name)))
+	}
+	return sb.String()
+}
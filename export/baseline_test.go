@@ -0,0 +1,46 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carloberd/db-reader/types"
+)
+
+// fixedDDL is a DDL provider returning the same DDL for every table
+type fixedDDL string
+
+func (d fixedDDL) GetTableDDL(_, _ string) (string, error) { return string(d), nil }
+
+func TestWriteBaseline(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider types.DDLProvider
+		want     string
+	}{
+		{"reconstructed", nil, "CONSTRAINT bookings_room_check CHECK ((room > 0))"},
+		{"from the database", fixedDDL("CREATE TABLE bookings (id integer);"), "CREATE TABLE bookings (id integer);"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files, err := WriteBaseline(dir, Flyway, "public", []*types.Table{constrainedTable()}, tt.provider)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != 1 || files[0] != filepath.Join(dir, "V1__baseline.sql") {
+				t.Fatalf("files are %v, expected V1__baseline.sql", files)
+			}
+			content, err := os.ReadFile(files[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(content), tt.want) {
+				t.Errorf("baseline lacks %q:\n%s", tt.want, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// simpleIdentifier matches identifiers that never need quoting
+var simpleIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// reservedWords lists common SQL keywords that must be quoted when used as identifiers
+var reservedWords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true,
+	"as": true, "asc": true, "check": true, "collate": true, "column": true, "constraint": true,
+	"create": true, "current_date": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "default": true, "desc": true, "distinct": true, "do": true,
+	"else": true, "end": true, "except": true, "false": true, "for": true, "foreign": true,
+	"from": true, "grant": true, "group": true, "having": true, "in": true, "into": true,
+	"limit": true, "not": true, "null": true, "offset": true, "on": true, "only": true,
+	"or": true, "order": true, "primary": true, "references": true, "select": true,
+	"table": true, "then": true, "to": true, "true": true, "union": true, "unique": true,
+	"user": true, "using": true, "when": true, "where": true, "window": true, "with": true,
+}
+
+// serialTypes maps integer types to their auto-incrementing pseudo-type
+var serialTypes = map[string]string{
+	"smallint": "smallserial",
+	"integer":  "serial",
+	"bigint":   "bigserial",
+}
+
+// QuoteIdentifier quotes a SQL identifier if it is not a plain lower-case name
+func QuoteIdentifier(name string) string {
+	if simpleIdentifier.MatchString(name) && !reservedWords[name] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// WriteDDL writes CREATE TABLE and CREATE INDEX statements for the given tables.
+// Tables are ordered so that referenced tables are created first, and foreign keys
+// are added at the end so that circular references still apply. Unique, check and
+// exclusion constraints are written inside CREATE TABLE.
+func WriteDDL(w io.Writer, tables []*t.Table) error {
+	ordered := sortByDependencies(tables)
+
+	var sb strings.Builder
+	for _, table := range ordered {
+		writeCreateTable(&sb, table)
+		writeCreateIndexes(&sb, table)
+	}
+	writeForeignKeys(&sb, ordered)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteDatabaseDDL writes the DDL of the given tables as the database generates
+// it, in dependency order, with the ALTER TABLE statements adding foreign keys
+// after every table is created. Without a provider, or when the database cannot
+// show DDL, all tables are reconstructed by WriteDDL instead so the script stays
+// in a single dialect. Other errors, such as a lost connection, are returned.
+func WriteDatabaseDDL(w io.Writer, tables []*t.Table, provider t.DDLProvider) error {
+	if provider == nil {
+		return WriteDDL(w, tables)
+	}
+
+	var sb strings.Builder
+	var alters []string
+	for i, table := range sortByDependencies(tables) {
+		ddl, err := provider.GetTableDDL(table.Schema, table.Name)
+		if errors.Is(err, t.ErrDDLNotSupported) {
+			return WriteDDL(w, tables)
+		}
+		if err != nil {
+			return fmt.Errorf("error reading the DDL of %s: %v", table.Name, err)
+		}
+
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		var statements []string
+		for _, stmt := range sqlutil.SplitStatements(ddl) {
+			if sqlutil.FirstKeyword(stmt) == "ALTER" {
+				alters = append(alters, stmt+";\n")
+			} else {
+				statements = append(statements, stmt+";\n")
+			}
+		}
+		sb.WriteString(strings.Join(statements, "\n"))
+	}
+	if len(alters) > 0 {
+		sb.WriteString("\n" + strings.Join(alters, "\n"))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeCreateTable writes the CREATE TABLE statement of a table
+func writeCreateTable(sb *strings.Builder, table *t.Table) {
+	sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", QuoteIdentifier(table.Name)))
+
+	var lines []string
+	for _, col := range table.Columns {
+		lines = append(lines, "    "+columnDefinition(col))
+	}
+	if pk := primaryKeyColumns(table); len(pk) > 0 {
+		lines = append(lines, fmt.Sprintf("    PRIMARY KEY (%s)", quoteList(pk)))
+	}
+	for _, con := range table.Constraints {
+		if def, ok := tableConstraint(con); ok {
+			lines = append(lines, "    "+def)
+		}
+	}
+
+	sb.WriteString(strings.Join(lines, ",\n"))
+	sb.WriteString("\n);\n\n")
+}
+
+// tableConstraint returns the definition of a unique, check or exclusion
+// constraint inside CREATE TABLE. Primary and foreign keys are written apart.
+func tableConstraint(con t.Constraint) (string, bool) {
+	switch con.Type {
+	case t.UniqueConstraint, t.CheckConstraint, t.ExcludeConstraint:
+	default:
+		return "", false
+	}
+
+	def := strings.TrimSpace(con.Definition)
+	if def == "" && con.Type == t.UniqueConstraint && len(con.Columns) > 0 {
+		def = fmt.Sprintf("UNIQUE (%s)", quoteList(con.Columns))
+	}
+	if def == "" {
+		return "", false
+	}
+	if con.Name == "" {
+		return def, true
+	}
+	return "CONSTRAINT " + QuoteIdentifier(con.Name) + " " + def, true
+}
+
+// columnDefinition returns the definition of a column inside CREATE TABLE
+func columnDefinition(col t.Column) string {
+	dataType := sqlType(col.Type)
+	defaultValue := col.DefaultValue
+
+	// Sequence defaults refer to sequences that do not exist in a fresh database,
+	// so integer columns backed by a sequence become serial columns instead
+	if defaultValue.Valid && strings.HasPrefix(defaultValue.String, "nextval(") {
+		if serial, ok := serialTypes[dataType]; ok {
+			dataType = serial
+			defaultValue.Valid = false
+		}
+	}
+
+	def := QuoteIdentifier(col.Name) + " " + dataType
+	if !col.Nullable {
+		def += " NOT NULL"
+	}
+	if defaultValue.Valid {
+		def += " DEFAULT " + defaultValue.String
+	}
+
+	return def
+}
+
+// sqlType converts the compact type names used for display back to valid SQL
+func sqlType(displayType string) string {
+	if displayType == "double" || strings.HasPrefix(displayType, "double[") {
+		return strings.Replace(displayType, "double", "double precision", 1)
+	}
+	return displayType
+}
+
+// primaryKeyColumns returns the primary key columns of a table in key order
+func primaryKeyColumns(table *t.Table) []string {
+	for _, idx := range table.Indexes {
+		if idx.PrimaryKey {
+			return idx.Columns
+		}
+	}
+
+	var columns []string
+	for _, col := range table.Columns {
+		if col.IsPrimaryKey {
+			columns = append(columns, col.Name)
+		}
+	}
+	return columns
+}
+
+// writeCreateIndexes writes CREATE INDEX statements for all indexes but those
+// of the primary key and of the constraints CREATE TABLE already creates
+func writeCreateIndexes(sb *strings.Builder, table *t.Table) {
+	indexes := append([]t.Index(nil), table.Indexes...)
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
+
+	constraints := make(map[string]bool)
+	for _, con := range table.Constraints {
+		if _, ok := tableConstraint(con); ok {
+			constraints[con.Name] = true
+		}
+	}
+
+	written := false
+	for _, idx := range indexes {
+		if idx.PrimaryKey || constraints[idx.Name] {
+			continue
+		}
+
+		written = true
+		if idx.Expression {
+			sb.WriteString(fmt.Sprintf("-- Index %s uses expressions and must be recreated manually\n", idx.Name))
+			continue
+		}
+
+		unique := ""
+		if idx.Unique {
+			unique = "UNIQUE "
+		}
+		method := ""
+		if idx.Method != "" && idx.Method != "btree" {
+			method = "USING " + idx.Method + " "
+		}
+		include := ""
+		if len(idx.Include) > 0 {
+			include = fmt.Sprintf(" INCLUDE (%s)", quoteList(idx.Include))
+		}
+		where := ""
+		if idx.Predicate != "" {
+			where = " WHERE " + idx.Predicate
+		}
+		sb.WriteString(fmt.Sprintf("CREATE %sINDEX %s ON %s %s(%s)%s%s;\n",
+			unique, QuoteIdentifier(idx.Name), QuoteIdentifier(table.Name), method, quoteList(idx.Columns), include, where))
+	}
+
+	if written {
+		sb.WriteString("\n")
+	}
+}
+
+// foreignKeyDefinition splits the definition of a foreign key constraint into
+// its referenced table, referenced columns and trailing actions such as ON DELETE
+var foreignKeyDefinition = regexp.MustCompile(`(?is)^FOREIGN KEY\s*\(.*?\)\s*REFERENCES\s+(.+?)\s*\((.*?)\)(.*)$`)
+
+// writeForeignKeys writes ALTER TABLE statements adding the foreign keys of all
+// tables, from their constraints so composite keys stay whole. Column references
+// no constraint covers are added as single-column foreign keys.
+func writeForeignKeys(sb *strings.Builder, tables []*t.Table) {
+	for _, table := range tables {
+		covered := make(map[string]bool)
+		for _, con := range table.Constraints {
+			if con.Type != t.ForeignKeyConstraint {
+				continue
+			}
+			for _, col := range con.Columns {
+				covered[col] = true
+			}
+			sb.WriteString(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;\n",
+				QuoteIdentifier(table.Name), QuoteIdentifier(con.Name), quoteForeignKey(con)))
+		}
+
+		for _, col := range table.Columns {
+			target, targetColumn, ok := col.ForeignKeyTarget()
+			if !ok || covered[col.Name] {
+				continue
+			}
+			if schema := col.ForeignKeySchema(); schema != "" {
+				target = QuoteIdentifier(schema) + "." + QuoteIdentifier(target)
+			} else {
+				target = QuoteIdentifier(target)
+			}
+			sb.WriteString(fmt.Sprintf("ALTER TABLE %s ADD FOREIGN KEY (%s) REFERENCES %s (%s);\n",
+				QuoteIdentifier(table.Name), QuoteIdentifier(col.Name), target, QuoteIdentifier(targetColumn)))
+		}
+	}
+}
+
+// quoteForeignKey returns the definition of a foreign key constraint with its
+// identifiers quoted where needed. Definitions of an unexpected form are kept as is.
+func quoteForeignKey(con t.Constraint) string {
+	match := foreignKeyDefinition.FindStringSubmatch(strings.TrimSpace(con.Definition))
+	if match == nil {
+		return con.Definition
+	}
+
+	var target []string
+	for _, part := range strings.Split(match[1], ".") {
+		target = append(target, quoteName(part))
+	}
+	var refColumns []string
+	for _, col := range strings.Split(match[2], ",") {
+		refColumns = append(refColumns, quoteName(col))
+	}
+	return fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)%s",
+		quoteList(con.Columns), strings.Join(target, "."), strings.Join(refColumns, ", "), match[3])
+}
+
+// quoteName quotes an identifier taken from a definition, unless already quoted
+func quoteName(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.HasPrefix(name, `"`) {
+		return name
+	}
+	return QuoteIdentifier(strings.Trim(name, "`[]"))
+}
+
+// quoteList quotes and joins a list of identifiers
+func quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = QuoteIdentifier(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sortByDependencies orders tables so that each table comes after the tables it references.
+// Tables are otherwise kept in name order, and cycles are broken arbitrarily.
+func sortByDependencies(tables []*t.Table) []*t.Table {
+	byName := make(map[string]*t.Table, len(tables))
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		byName[table.Name] = table
+		names = append(names, table.Name)
+	}
+	sort.Strings(names)
+
+	visited := make(map[string]bool, len(tables))
+	var ordered []*t.Table
+
+	var visit func(name string)
+	visit = func(name string) {
+		table, ok := byName[name]
+		if !ok || visited[name] {
+			return
+		}
+		visited[name] = true
+
+		for _, col := range table.Columns {
+			if target, _, ok := col.ForeignKeyTarget(); ok {
+				visit(target)
+			}
+		}
+		ordered = append(ordered, table)
+	}
+
+	for _, name := range names {
+		visit(name)
+	}
+
+	return ordered
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carloberd/db-reader/types"
+)
+
+// constrainedTable returns a table with every kind of constraint, and the
+// indexes backing its unique and exclusion constraints
+func constrainedTable() *types.Table {
+	return &types.Table{
+		Name: "bookings",
+		Columns: []types.Column{
+			{Name: "id", Type: "integer", IsPrimaryKey: true},
+			{Name: "code", Type: "text"},
+			{Name: "room", Type: "integer"},
+			{Name: "during", Type: "tstzrange"},
+		},
+		Indexes: []types.Index{
+			{Name: "bookings_pkey", Columns: []string{"id"}, Unique: true, PrimaryKey: true},
+			{Name: "bookings_code_key", Columns: []string{"code"}, Unique: true},
+			{Name: "bookings_room_excl", Columns: []string{"room", "during"}, Method: "gist"},
+			{Name: "bookings_room_idx", Columns: []string{"room"}},
+		},
+		Constraints: []types.Constraint{
+			{Name: "bookings_pkey", Type: types.PrimaryKeyConstraint, Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+			{Name: "bookings_code_key", Type: types.UniqueConstraint, Columns: []string{"code"}, Definition: "UNIQUE (code)"},
+			{Name: "bookings_room_check", Type: types.CheckConstraint, Columns: []string{"room"}, Definition: "CHECK ((room > 0))"},
+			{Name: "bookings_room_excl", Type: types.ExcludeConstraint, Columns: []string{"room", "during"},
+				Definition: "EXCLUDE USING gist (room WITH =, during WITH &&)"},
+		},
+	}
+}
+
+func TestWriteDDLConstraints(t *testing.T) {
+	var sb strings.Builder
+	if err := WriteDDL(&sb, []*types.Table{constrainedTable()}); err != nil {
+		t.Fatal(err)
+	}
+	ddl := sb.String()
+
+	for _, want := range []string{
+		"    PRIMARY KEY (id),\n",
+		"    CONSTRAINT bookings_code_key UNIQUE (code),\n",
+		"    CONSTRAINT bookings_room_check CHECK ((room > 0)),\n",
+		"    CONSTRAINT bookings_room_excl EXCLUDE USING gist (room WITH =, during WITH &&)\n);\n",
+		"CREATE INDEX bookings_room_idx ON bookings (room);\n",
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("DDL lacks %q:\n%s", want, ddl)
+		}
+	}
+
+	// The indexes of the constraints are created with them
+	for _, index := range []string{"bookings_pkey", "bookings_code_key", "bookings_room_excl"} {
+		if strings.Contains(ddl, "INDEX "+index) {
+			t.Errorf("DDL creates the index %s of a constraint:\n%s", index, ddl)
+		}
+	}
+}
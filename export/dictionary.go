@@ -0,0 +1,149 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// WriteDataDictionary writes every table of a schema into a single Markdown
+// document, for pasting into a wiki: a table of contents, then a section per
+// table with its columns, indexes and the tables it references or is referenced by
+func WriteDataDictionary(w io.Writer, schema string, tables []*t.Table) error {
+	return WriteGroupedDataDictionary(w, schema, tables, Grouping{})
+}
+
+// WriteGroupedDataDictionary writes a data dictionary like WriteDataDictionary,
+// with the tables of each module of the grouping under a section of their own
+func WriteGroupedDataDictionary(w io.Writer, schema string, tables []*t.Table, grouping Grouping) error {
+	var sb strings.Builder
+
+	// Tables referencing each table, by table name
+	referencedBy := make(map[string][]string)
+	for _, table := range tables {
+		for _, ref := range referencedTables(table) {
+			if ref != table.Name && !slices.Contains(referencedBy[ref], table.Name) {
+				referencedBy[ref] = append(referencedBy[ref], table.Name)
+			}
+		}
+	}
+
+	groups := []TableGroup{{Tables: tables}}
+	heading := "##"
+	if !grouping.IsZero() {
+		groups = grouping.Group(tables)
+		heading = "###"
+	}
+
+	sb.WriteString(fmt.Sprintf("# Data dictionary: %s\n\n", schema))
+	indent := ""
+	for _, group := range groups {
+		if heading != "##" {
+			sb.WriteString(fmt.Sprintf("- **%s**\n", moduleTitle(group.Module)))
+			indent = "  "
+		}
+		for _, table := range group.Tables {
+			sb.WriteString(fmt.Sprintf("%s- [%s](#%s)", indent, table.Name, markdownAnchor(table.Name)))
+			if summary, _, _ := strings.Cut(table.Comment, "\n"); summary != "" {
+				sb.WriteString(" – " + markdownCell(summary))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	for _, group := range groups {
+		if heading != "##" {
+			sb.WriteString(fmt.Sprintf("\n## %s\n", moduleTitle(group.Module)))
+		}
+		for _, table := range group.Tables {
+			sb.WriteString(fmt.Sprintf("\n%s %s\n\n", heading, table.Name))
+			if table.Comment != "" {
+				sb.WriteString(table.Comment + "\n\n")
+			}
+			for _, prop := range table.Properties {
+				sb.WriteString(fmt.Sprintf("- **%s:** %s\n", prop.Name, prop.Value))
+			}
+			if len(table.Properties) > 0 {
+				sb.WriteString("\n")
+			}
+
+			sb.WriteString(heading + "# Columns\n\n")
+			if err := WriteColumnsMarkdown(&sb, table); err != nil {
+				return err
+			}
+
+			if len(table.Indexes) > 0 {
+				sb.WriteString("\n" + heading + "# Indexes\n\n")
+				for _, idx := range table.Indexes {
+					sb.WriteString(indexItem(idx))
+				}
+			}
+
+			references := referencedTables(table)
+			if len(references) > 0 || len(referencedBy[table.Name]) > 0 {
+				sb.WriteString("\n" + heading + "# Related tables\n\n")
+				if len(references) > 0 {
+					sb.WriteString("- References: " + tableLinks(references) + "\n")
+				}
+				if len(referencedBy[table.Name]) > 0 {
+					sb.WriteString("- Referenced by: " + tableLinks(referencedBy[table.Name]) + "\n")
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// moduleTitle returns the heading of the tables of a module, or of the tables in none
+func moduleTitle(module string) string {
+	if module == "" {
+		return "Other tables"
+	}
+	return module
+}
+
+// referencedTables returns the tables the foreign keys of a table point to, in column order
+func referencedTables(table *t.Table) []string {
+	var refs []string
+	for _, col := range table.Columns {
+		if ref, _, ok := col.ForeignKeyTarget(); ok && !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// tableLinks returns links to the sections of tables
+func tableLinks(names []string) string {
+	links := make([]string, len(names))
+	for i, name := range names {
+		links[i] = fmt.Sprintf("[%s](#%s)", name, markdownAnchor(name))
+	}
+	return strings.Join(links, ", ")
+}
+
+// markdownAnchor returns the anchor wikis and GitHub generate for a heading:
+// lower case, with spaces as dashes and other punctuation dropped
+func markdownAnchor(heading string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '-'
+		case r == '-' || r == '_':
+			return r
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		case r > 127:
+			return r
+		default:
+			return -1
+		}
+	}, heading)
+}
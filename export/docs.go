@@ -0,0 +1,116 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/carloberd/db-reader/filter"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// docsPage is a format of the documentation pages of the docs export
+type docsPage struct {
+	extension   string
+	write       func(w io.Writer, table *t.Table) error
+	writeSample func(w io.Writer, file string, sample *t.QueryResult, masked []string) error
+}
+
+// docsPages are the formats of the documentation pages, by name
+var docsPages = map[string]docsPage{
+	"md":   {".md", WriteTableMarkdown, WriteSampleSection},
+	"adoc": {".adoc", WriteTableAsciiDoc, WriteSampleSectionAsciiDoc},
+}
+
+// sampleTimeout bounds the time spent sampling the rows of a table
+const sampleTimeout = 30 * time.Second
+
+// WriteTableDocs writes the documentation page of a table into dir and, when
+// the settings ask for sample rows, a CSV file of masked example rows next to
+// it, linked from the page. The rows are read through the connector of the
+// options. It returns the written files.
+func WriteTableDocs(dir string, table *t.Table, opts Options) ([]string, error) {
+	format, ok := docsPages[opts.Setting("pages", "md")]
+	if !ok {
+		return nil, fmt.Errorf("unknown docs page format '%s', expected md or adoc", opts.Setting("pages", ""))
+	}
+	rows, err := opts.Int("sample-rows")
+	if err != nil {
+		return nil, err
+	}
+
+	page := filepath.Join(dir, FileName(table.Name)+format.extension)
+	if rows <= 0 {
+		return []string{page}, writeFile(page, func(w io.Writer) error {
+			return format.write(w, table)
+		})
+	}
+
+	sampler, ok := opts.Connector.(t.RowSampler)
+	if !ok {
+		return nil, fmt.Errorf("sampling rows is not supported for this database")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sampleTimeout)
+	defer cancel()
+	sample, err := sampler.SampleRows(ctx, table.Schema, table.Name, rows)
+	if err != nil {
+		return nil, fmt.Errorf("error sampling %s: %v", table.Name, err)
+	}
+	masked := MaskedColumns(table, sample.Columns, filter.ParseList(opts.Setting("unmask", "")))
+
+	csvFile := FileName(table.Name) + ".csv"
+	err = writeFile(filepath.Join(dir, csvFile), func(w io.Writer) error {
+		return WriteSampleCSV(w, table, sample, masked)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []string{page, filepath.Join(dir, csvFile)}, writeFile(page, func(w io.Writer) error {
+		if err := format.write(w, table); err != nil {
+			return err
+		}
+		return format.writeSample(w, csvFile, sample, masked)
+	})
+}
+
+// writeTableJSONSchemaFile writes the JSON Schema of a table into a file of its own
+func writeTableJSONSchemaFile(dir string, table *t.Table, _ Options) ([]string, error) {
+	path := filepath.Join(dir, FileName(table.Name)+".schema.json")
+	return []string{path}, writeFile(path, func(w io.Writer) error {
+		return WriteTableJSONSchema(w, table)
+	})
+}
+
+// writeFile creates a file and writes it with the given function
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", path, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return f.Close()
+}
+
+// FileName replaces the characters of a table name that are unsafe in file names
+func FileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, name)
+}
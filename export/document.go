@@ -0,0 +1,178 @@
+package export
+
+import (
+	"database/sql"
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// TableDocument is the JSON and YAML representation of a table
+type TableDocument struct {
+	Name        string               `json:"name" yaml:"name"`
+	Schema      string               `json:"schema" yaml:"schema"`
+	Columns     []ColumnDocument     `json:"columns" yaml:"columns"`
+	Indexes     []IndexDocument      `json:"indexes" yaml:"indexes"`
+	Constraints []ConstraintDocument `json:"constraints" yaml:"constraints"`
+	Comment     string               `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Properties  []PropertyDocument   `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+// PropertyDocument is the JSON representation of database-specific table metadata
+type PropertyDocument struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// ColumnDocument is the JSON representation of a column
+type ColumnDocument struct {
+	Name       string              `json:"name" yaml:"name"`
+	Type       string              `json:"type" yaml:"type"`
+	Nullable   bool                `json:"nullable" yaml:"nullable"`
+	Default    *string             `json:"default" yaml:"default"` // null when the column has no default
+	PrimaryKey bool                `json:"primary_key" yaml:"primary_key"`
+	ForeignKey *ForeignKeyDocument `json:"foreign_key,omitempty" yaml:"foreign_key,omitempty"`
+	Comment    string              `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// ForeignKeyDocument is the JSON representation of the column a foreign key references
+type ForeignKeyDocument struct {
+	Table  string `json:"table" yaml:"table"`
+	Column string `json:"column" yaml:"column"`
+}
+
+// IndexDocument is the JSON representation of an index
+type IndexDocument struct {
+	Name       string   `json:"name" yaml:"name"`
+	Columns    []string `json:"columns" yaml:"columns"`
+	Include    []string `json:"include,omitempty" yaml:"include,omitempty"`
+	Unique     bool     `json:"unique" yaml:"unique"`
+	PrimaryKey bool     `json:"primary_key" yaml:"primary_key"`
+	Method     string   `json:"method,omitempty" yaml:"method,omitempty"`
+	Predicate  string   `json:"predicate,omitempty" yaml:"predicate,omitempty"`
+	Expression bool     `json:"expression,omitempty" yaml:"expression,omitempty"`
+	SizeBytes  int64    `json:"size_bytes,omitempty" yaml:"size_bytes,omitempty"`
+}
+
+// ConstraintDocument is the JSON representation of a constraint
+type ConstraintDocument struct {
+	Name       string   `json:"name" yaml:"name"`
+	Type       string   `json:"type" yaml:"type"`
+	Columns    []string `json:"columns" yaml:"columns"`
+	Definition string   `json:"definition" yaml:"definition"`
+}
+
+// NewTableDocument converts a table to its JSON representation
+func NewTableDocument(table *t.Table) TableDocument {
+	doc := TableDocument{
+		Name:        table.Name,
+		Schema:      table.Schema,
+		Columns:     []ColumnDocument{},
+		Indexes:     []IndexDocument{},
+		Constraints: []ConstraintDocument{},
+		Comment:     table.Comment,
+	}
+
+	for _, prop := range table.Properties {
+		doc.Properties = append(doc.Properties, PropertyDocument{Name: prop.Name, Value: prop.Value})
+	}
+
+	for _, col := range table.Columns {
+		c := ColumnDocument{
+			Name:       col.Name,
+			Type:       col.Type,
+			Nullable:   col.Nullable,
+			PrimaryKey: col.IsPrimaryKey,
+			Comment:    col.Comment,
+		}
+		if col.DefaultValue.Valid {
+			def := col.DefaultValue.String
+			c.Default = &def
+		}
+		if refTable, refColumn, ok := col.ForeignKeyTarget(); ok {
+			c.ForeignKey = &ForeignKeyDocument{Table: refTable, Column: refColumn}
+		}
+		doc.Columns = append(doc.Columns, c)
+	}
+
+	for _, idx := range table.Indexes {
+		doc.Indexes = append(doc.Indexes, IndexDocument{
+			Name:       idx.Name,
+			Columns:    idx.Columns,
+			Include:    idx.Include,
+			Unique:     idx.Unique,
+			PrimaryKey: idx.PrimaryKey,
+			Method:     idx.Method,
+			Predicate:  idx.Predicate,
+			Expression: idx.Expression,
+			SizeBytes:  idx.Size,
+		})
+	}
+
+	for _, con := range table.Constraints {
+		doc.Constraints = append(doc.Constraints, ConstraintDocument{
+			Name:       con.Name,
+			Type:       con.Type,
+			Columns:    con.Columns,
+			Definition: con.Definition,
+		})
+	}
+
+	return doc
+}
+
+// Table converts a JSON table representation back to a table, for example to
+// compare a saved snapshot with a live database
+func (doc TableDocument) Table() *t.Table {
+	table := &t.Table{
+		Name:    doc.Name,
+		Schema:  doc.Schema,
+		Comment: doc.Comment,
+	}
+
+	for _, prop := range doc.Properties {
+		table.Properties = append(table.Properties, t.Property{Name: prop.Name, Value: prop.Value})
+	}
+
+	for _, c := range doc.Columns {
+		col := t.Column{
+			Name:         c.Name,
+			Type:         c.Type,
+			Nullable:     c.Nullable,
+			IsPrimaryKey: c.PrimaryKey,
+			Comment:      c.Comment,
+		}
+		if c.Default != nil {
+			col.DefaultValue = sql.NullString{String: *c.Default, Valid: true}
+		}
+		if c.ForeignKey != nil {
+			col.ForeignKey = sql.NullString{String: fmt.Sprintf("%s (%s)", c.ForeignKey.Table, c.ForeignKey.Column), Valid: true}
+		}
+		table.Columns = append(table.Columns, col)
+	}
+
+	for _, idx := range doc.Indexes {
+		table.Indexes = append(table.Indexes, t.Index{
+			Name:       idx.Name,
+			Columns:    idx.Columns,
+			Include:    idx.Include,
+			Unique:     idx.Unique,
+			PrimaryKey: idx.PrimaryKey,
+			Method:     idx.Method,
+			Predicate:  idx.Predicate,
+			Expression: idx.Expression,
+			Size:       idx.SizeBytes,
+		})
+	}
+
+	for _, con := range doc.Constraints {
+		table.Constraints = append(table.Constraints, t.Constraint{
+			Name:       con.Name,
+			Type:       con.Type,
+			Columns:    con.Columns,
+			Definition: con.Definition,
+		})
+	}
+
+	return table
+}
@@ -0,0 +1,241 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Schema is what an exporter writes: the tables of a schema
+type Schema struct {
+	Name   string
+	Driver string // Driver of the database, for formats depending on its dialect, "" if unknown
+	Tables []*t.Table
+}
+
+// Options are the settings of an export. Formats ignore the ones that do not concern them.
+type Options struct {
+	Connector t.DatabaseConnector // Database the tables come from, for formats reading more of it such as its DDL; nil if none
+	Grouping  Grouping            // Modules grouping the tables of diagrams and data dictionaries
+	Settings  map[string]string   // Settings of the format by name, among those it lists
+}
+
+// Setting returns a setting of the format, or def if it is not given
+func (o Options) Setting(name, def string) string {
+	if value, ok := o.Settings[name]; ok {
+		return value
+	}
+	return def
+}
+
+// Bool returns a setting of the format that is true or false, false if not given
+func (o Options) Bool(name string) (bool, error) {
+	value, err := strconv.ParseBool(o.Setting(name, "false"))
+	if err != nil {
+		return false, fmt.Errorf("setting %s must be true or false", name)
+	}
+	return value, nil
+}
+
+// Int returns a setting of the format that is a number, 0 if not given
+func (o Options) Int(name string) (int, error) {
+	value, err := strconv.Atoi(o.Setting(name, "0"))
+	if err != nil {
+		return 0, fmt.Errorf("setting %s must be a number", name)
+	}
+	return value, nil
+}
+
+// Exporter writes a schema in an export format
+type Exporter interface {
+	Export(w io.Writer, schema *Schema, opts Options) error
+}
+
+// ExporterFunc is a function writing a schema, used as an Exporter
+type ExporterFunc func(w io.Writer, schema *Schema, opts Options) error
+
+// Export calls the function
+func (f ExporterFunc) Export(w io.Writer, schema *Schema, opts Options) error {
+	return f(w, schema, opts)
+}
+
+// Format is an export format, written as a single document by its Exporter or
+// as several files into a folder. A format may offer both, e.g. JSON Schemas
+// as one document or a file per table.
+type Format struct {
+	Name        string                     // Name selecting the format, e.g. "json"
+	Description string                     // Short description for help texts, "" if the name says it all
+	File        func(schema string) string // Name of the document written to an output folder
+	Exporter    Exporter                   // Writes the single document, nil for formats of several files
+
+	// Files writes the files of the schema into a folder, returning their paths
+	Files func(dir string, schema *Schema, opts Options) ([]string, error)
+	// TableFiles writes the files of a table into a folder as soon as the table is
+	// loaded, returning their paths
+	TableFiles func(dir string, table *t.Table, opts Options) ([]string, error)
+
+	Settings map[string]string // Settings the format reads from the options, with their descriptions
+}
+
+// WritesFiles reports whether the format is written as several files into a folder
+func (f Format) WritesFiles() bool {
+	return f.Files != nil || f.TableFiles != nil
+}
+
+// formats holds the registered export formats by name
+var (
+	formatsMu sync.RWMutex
+	formats   = make(map[string]Format)
+)
+
+// RegisterFormat makes an export format available by its name. The built-in
+// formats register themselves, and other packages can add their own. It panics
+// if the name is taken.
+func RegisterFormat(format Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	if (format.Exporter == nil) != (format.File == nil) || format.Exporter == nil && !format.WritesFiles() {
+		panic("export: RegisterFormat needs an exporter and file, or a function writing files")
+	}
+	if _, dup := formats[format.Name]; dup {
+		panic("export: RegisterFormat called twice for format " + format.Name)
+	}
+	formats[format.Name] = format
+}
+
+// LookupFormat returns the registered export format with the given name
+func LookupFormat(name string) (Format, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	format, ok := formats[name]
+	return format, ok
+}
+
+// Formats returns the registered export formats, sorted by name
+func Formats() []Format {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	list := make([]Format, 0, len(formats))
+	for _, format := range formats {
+		list = append(list, format)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// tablesExporter adapts a function writing the tables of a schema by name,
+// such as WriteSchemaJSON, to an Exporter
+func tablesExporter(write func(w io.Writer, schema string, tables []*t.Table) error) Exporter {
+	return ExporterFunc(func(w io.Writer, schema *Schema, _ Options) error {
+		return write(w, schema.Name, schema.Tables)
+	})
+}
+
+// init registers the built-in document formats
+func init() {
+	builtin := []struct {
+		name, description, suffix string
+		write                     func(w io.Writer, schema string, tables []*t.Table) error
+	}{
+		{"json", "", ".json", WriteSchemaJSON},
+		{"jsonl", "JSON Lines", ".jsonl", WriteSchemaJSONL},
+		{"yaml", "", ".yaml", WriteSchemaYAML},
+		{"csv", "column inventory", "-columns.csv", WriteColumnInventoryCSV},
+		{"xlsx", "Excel workbook", ".xlsx", WriteWorkbook},
+		{"pdf", "printable report", ".pdf", WriteSchemaPDF},
+		{"openapi", "", ".openapi.json", WriteOpenAPI},
+		{"graphql", "", ".graphql", WriteGraphQLSchema},
+	}
+	for _, format := range builtin {
+		suffix := format.suffix
+		RegisterFormat(Format{
+			Name:        format.name,
+			Description: format.description,
+			File:        func(schema string) string { return schema + suffix },
+			Exporter:    tablesExporter(format.write),
+		})
+	}
+
+	RegisterFormat(Format{
+		Name:        "jsonschema",
+		Description: "JSON Schema of every table, a file per table in a folder",
+		File:        func(schema string) string { return schema + ".schema.json" },
+		Exporter:    tablesExporter(WriteSchemaJSONSchema),
+		TableFiles:  writeTableJSONSchemaFile,
+	})
+	RegisterFormat(Format{
+		Name:        "md",
+		Description: "Markdown data dictionary",
+		File:        func(schema string) string { return schema + "-dictionary.md" },
+		Exporter: ExporterFunc(func(w io.Writer, schema *Schema, opts Options) error {
+			return WriteGroupedDataDictionary(w, schema.Name, schema.Tables, opts.Grouping)
+		}),
+	})
+	RegisterFormat(Format{
+		Name:        "adoc",
+		Description: "AsciiDoc data dictionary",
+		File:        func(schema string) string { return schema + "-dictionary.adoc" },
+		Exporter: ExporterFunc(func(w io.Writer, schema *Schema, opts Options) error {
+			return WriteGroupedDataDictionaryAsciiDoc(w, schema.Name, schema.Tables, opts.Grouping)
+		}),
+	})
+	RegisterFormat(Format{
+		Name:        "prisma",
+		Description: "Prisma schema",
+		File:        func(string) string { return "schema.prisma" },
+		Exporter: ExporterFunc(func(w io.Writer, schema *Schema, _ Options) error {
+			return WritePrismaSchema(w, schema.Driver, schema.Tables)
+		}),
+	})
+	RegisterFormat(Format{
+		Name:        "sql",
+		Description: "DDL script",
+		File:        func(schema string) string { return schema + ".sql" },
+		Exporter: ExporterFunc(func(w io.Writer, schema *Schema, opts Options) error {
+			provider, _ := opts.Connector.(t.DDLProvider)
+			return WriteDatabaseDDL(w, schema.Tables, provider)
+		}),
+	})
+	RegisterFormat(Format{
+		Name:        "text",
+		Description: "plain text description",
+		File:        func(schema string) string { return schema + ".txt" },
+		Exporter:    tablesExporter(WriteSchemaText),
+	})
+
+	// Formats written as several files
+	RegisterFormat(Format{
+		Name:        "baseline",
+		Description: "baseline migration",
+		Files: func(dir string, schema *Schema, opts Options) ([]string, error) {
+			provider, _ := opts.Connector.(t.DDLProvider)
+			style := MigrationStyle(opts.Setting("style", string(GolangMigrate)))
+			return WriteBaseline(dir, style, schema.Name, schema.Tables, provider)
+		},
+		Settings: map[string]string{"style": "migration tool: golang-migrate (default) or flyway"},
+	})
+	RegisterFormat(Format{
+		Name:        "html",
+		Description: "HTML report",
+		Files: func(dir string, schema *Schema, _ Options) ([]string, error) {
+			return WriteHTMLReport(dir, schema.Name, schema.Tables)
+		},
+	})
+	RegisterFormat(Format{
+		Name:        "docs",
+		Description: "a page per table",
+		TableFiles:  WriteTableDocs,
+		Settings: map[string]string{
+			"pages":       "format of the pages: md (default) or adoc",
+			"sample-rows": "rows sampled into a CSV file next to each page (default none)",
+			"unmask":      "comma separated patterns of the sampled columns to show as they are; all others but keys, numbers and booleans are masked",
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// WriteFindingsMarkdown writes analysis findings as a Markdown report, grouped by check
+func WriteFindingsMarkdown(w io.Writer, schema string, findings []t.Finding) error {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("## Analysis of schema %s\n\n", schema))
+	if len(findings) == 0 {
+		sb.WriteString("No findings.\n")
+	}
+
+	var checks []string
+	byCheck := make(map[string][]t.Finding)
+	for _, f := range findings {
+		if _, ok := byCheck[f.Check]; !ok {
+			checks = append(checks, f.Check)
+		}
+		byCheck[f.Check] = append(byCheck[f.Check], f)
+	}
+
+	for _, check := range checks {
+		sb.WriteString(fmt.Sprintf("### %s (%d)\n\n| Object | Message | Size |\n|---|---|---:|\n", check, len(byCheck[check])))
+		for _, f := range byCheck[check] {
+			size := ""
+			if f.Bytes > 0 {
+				size = FormatBytes(f.Bytes)
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", markdownCell(f.Object), markdownCell(f.Message), size))
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,217 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// graphQLScalars are the custom scalars the generated types may use, in the
+// order they are declared
+var graphQLScalars = []string{"BigInt", "Date", "DateTime", "Time", "UUID", "JSON"}
+
+// graphQLField is a field of a generated GraphQL type
+type graphQLField struct {
+	name string
+	typ  string
+}
+
+// WriteGraphQLSchema writes GraphQL type definitions of the tables: an object
+// type per table with a field per column, a field per foreign key returning
+// the referenced row and, on the referenced table, a field listing the rows
+// referencing it. A Query type lists the rows of each table and looks them up
+// by primary key. Foreign keys to tables left out of the export stay plain columns.
+func WriteGraphQLSchema(w io.Writer, _ string, tables []*t.Table) error {
+	types := make(map[string]string, len(tables)) // Type name by table name
+	for _, table := range tables {
+		types[table.Name] = graphQLTypeName(table.Name)
+	}
+
+	fields := make(map[string][]graphQLField, len(tables))
+	used := make(map[string]bool)
+	for _, table := range tables {
+		for _, col := range table.Columns {
+			typ := graphQLType(col.Type, used)
+			if !col.Nullable {
+				typ += "!"
+			}
+			fields[table.Name] = append(fields[table.Name], graphQLField{graphQLName(col.Name), typ})
+		}
+	}
+
+	// Relationships come after the columns, so their names avoid the column names
+	for _, table := range tables {
+		// Tables referenced by several columns name the rows referencing them
+		// after the column too, e.g. ordersByShippingAddressId
+		references := make(map[string]int)
+		for _, col := range table.Columns {
+			if ref, _, ok := col.ForeignKeyTarget(); ok {
+				references[ref]++
+			}
+		}
+
+		for _, col := range table.Columns {
+			ref, _, ok := col.ForeignKeyTarget()
+			if !ok || types[ref] == "" {
+				continue
+			}
+
+			forward := graphQLName(strings.TrimSuffix(strings.TrimSuffix(col.Name, "_id"), "Id"))
+			if forward == graphQLName(col.Name) {
+				forward += "Ref"
+			}
+			typ := types[ref]
+			if !col.Nullable {
+				typ += "!"
+			}
+			fields[table.Name] = appendField(fields[table.Name], graphQLField{forward, typ})
+
+			reverse := camelCase(types[table.Name])
+			if references[ref] > 1 {
+				reverse += "By" + pascalCase(col.Name)
+			}
+			fields[ref] = appendField(fields[ref], graphQLField{reverse, "[" + types[table.Name] + "!]!"})
+		}
+	}
+
+	var sb strings.Builder
+	for _, scalar := range graphQLScalars {
+		if used[scalar] {
+			sb.WriteString("scalar " + scalar + "\n")
+		}
+	}
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+
+	for _, table := range tables {
+		if table.Comment != "" {
+			sb.WriteString(graphQLDescription(table.Comment, ""))
+		}
+		sb.WriteString(fmt.Sprintf("type %s {\n", types[table.Name]))
+		comments := make(map[string]string, len(table.Columns))
+		for _, col := range table.Columns {
+			comments[graphQLName(col.Name)] = col.Comment
+		}
+		for _, field := range fields[table.Name] {
+			if comment := comments[field.name]; comment != "" {
+				sb.WriteString(graphQLDescription(comment, "  "))
+			}
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", field.name, field.typ))
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	sb.WriteString("type Query {\n")
+	for _, table := range tables {
+		name := camelCase(types[table.Name])
+		sb.WriteString(fmt.Sprintf("  %s(limit: Int, offset: Int): [%s!]!\n", name, types[table.Name]))
+
+		var args []string
+		for _, key := range primaryKeyColumns(table) {
+			args = append(args, fmt.Sprintf("%s: %s!", graphQLName(key), graphQLType(columnType(table, key), used)))
+		}
+		if len(args) > 0 {
+			sb.WriteString(fmt.Sprintf("  %sByPk(%s): %s\n", name, strings.Join(args, ", "), types[table.Name]))
+		}
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteTableGraphQL writes the GraphQL type definitions of a single table
+func WriteTableGraphQL(w io.Writer, table *t.Table) error {
+	return WriteGraphQLSchema(w, table.Schema, []*t.Table{table})
+}
+
+// appendField appends a field, numbering its name if the type already has it
+func appendField(fields []graphQLField, field graphQLField) []graphQLField {
+	taken := func(name string) bool {
+		return slices.ContainsFunc(fields, func(f graphQLField) bool { return f.name == name })
+	}
+	name := field.name
+	for n := 2; taken(name); n++ {
+		name = fmt.Sprintf("%s%d", field.name, n)
+	}
+	field.name = name
+	return append(fields, field)
+}
+
+// graphQLType maps a column type to a GraphQL type, recording the custom
+// scalars it uses
+func graphQLType(columnType string, used map[string]bool) string {
+	schema := columnJSONSchema(columnType)
+	name, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(columnType)), "(")
+
+	var scalar string
+	switch {
+	case schema.Type == "array":
+		return "[" + graphQLType(strings.TrimPrefix(strings.TrimSuffix(columnType, "[]"), "_"), used) + "]"
+	case schema.Type == nil:
+		scalar = "JSON"
+	case schema.Type == "integer":
+		scalar = "Int"
+		// GraphQL's Int has 32 bits
+		if name == "bigint" || name == "int8" || name == "bigserial" || name == "int64" || name == "uint32" || name == "uint64" {
+			scalar = "BigInt"
+		}
+	case schema.Type == "number":
+		scalar = "Float"
+	case schema.Type == "boolean":
+		return "Boolean"
+	case schema.Format == "uuid":
+		scalar = "UUID"
+	case schema.Format == "date":
+		scalar = "Date"
+	case schema.Format == "date-time":
+		scalar = "DateTime"
+	case schema.Format == "time":
+		scalar = "Time"
+	default:
+		return "String"
+	}
+	used[scalar] = true
+	return scalar
+}
+
+// graphQLTypeName returns the type name of a table
+func graphQLTypeName(table string) string {
+	name := pascalCase(table)
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "T" + name
+	}
+	return name
+}
+
+// graphQLName returns a column name as a GraphQL field name, which may only
+// hold letters, digits and underscores and not start with a digit
+func graphQLName(column string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, column)
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// camelCase returns a type name with its first letter in lower case
+func camelCase(typeName string) string {
+	return strings.ToLower(typeName[:1]) + typeName[1:]
+}
+
+// graphQLDescription returns a comment as a GraphQL block description
+func graphQLDescription(comment, indent string) string {
+	comment = strings.ReplaceAll(comment, `"""`, `\"""`)
+	return indent + `"""` + "\n" + indent + strings.ReplaceAll(comment, "\n", "\n"+indent) + "\n" + indent + `"""` + "\n"
+}
@@ -0,0 +1,134 @@
+package export
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// groupByPrefix is the grouping item that groups tables by name prefix
+const groupByPrefix = "prefix"
+
+// Module is a named group of tables, matched by name patterns (path.Match syntax)
+type Module struct {
+	Name     string
+	Patterns []string
+}
+
+// Grouping assigns tables to modules, so that diagrams and docs of large
+// schemas read as groups of related tables. The zero Grouping groups nothing.
+type Grouping struct {
+	Modules  []Module // Checked in order, the first module matching a table gets it
+	ByPrefix bool     // Group the other tables by the part of their name before the first "_", when tables share it
+}
+
+// TableGroup is the tables of a module, Module "" for the tables in none
+type TableGroup struct {
+	Module string
+	Tables []*t.Table
+}
+
+// ParseGrouping parses a comma separated list of modules, as name=patterns
+// with the patterns separated by "|", and "prefix" to group the other tables
+// by name prefix, e.g. "auth=users|sessions,billing=billing_*|invoices,prefix"
+func ParseGrouping(spec string) (Grouping, error) {
+	var g Grouping
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if item == groupByPrefix {
+			g.ByPrefix = true
+			continue
+		}
+
+		name, patterns, ok := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return Grouping{}, fmt.Errorf("invalid module '%s', expected name=patterns or prefix", item)
+		}
+		module := Module{Name: name}
+		for _, pattern := range strings.Split(patterns, "|") {
+			pattern = strings.TrimSpace(pattern)
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return Grouping{}, fmt.Errorf("invalid table pattern '%s' in module %s", pattern, name)
+			}
+			module.Patterns = append(module.Patterns, pattern)
+		}
+		g.Modules = append(g.Modules, module)
+	}
+	return g, nil
+}
+
+// IsZero reports whether the grouping groups nothing
+func (g Grouping) IsZero() bool {
+	return len(g.Modules) == 0 && !g.ByPrefix
+}
+
+// Group splits the tables into their modules: the declared ones in order,
+// then the prefixes, sorted, then the tables in no module. Tables keep their
+// order within a module.
+func (g Grouping) Group(tables []*t.Table) []TableGroup {
+	modules := make([]string, len(tables))
+	prefixCount := make(map[string]int)
+	for i, table := range tables {
+		modules[i] = g.declaredModule(table.Name)
+		if modules[i] == "" && g.ByPrefix {
+			if prefix, _, ok := strings.Cut(table.Name, "_"); ok && prefix != "" {
+				prefixCount[prefix]++
+			}
+		}
+	}
+
+	var order []string
+	for _, module := range g.Modules {
+		order = append(order, module.Name)
+	}
+	var prefixes []string
+	for i, table := range tables {
+		if modules[i] != "" {
+			continue
+		}
+		// A prefix of a single table is not a module
+		if prefix, _, _ := strings.Cut(table.Name, "_"); prefixCount[prefix] > 1 {
+			modules[i] = prefix
+			if !slices.Contains(prefixes, prefix) && !slices.Contains(order, prefix) {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	sort.Strings(prefixes)
+	order = append(order, prefixes...)
+	order = append(order, "")
+
+	var groups []TableGroup
+	for _, module := range order {
+		group := TableGroup{Module: module}
+		for i, table := range tables {
+			if modules[i] == module {
+				group.Tables = append(group.Tables, table)
+			}
+		}
+		if len(group.Tables) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// declaredModule returns the first declared module matching a table name, "" if none
+func (g Grouping) declaredModule(name string) string {
+	for _, module := range g.Modules {
+		for _, pattern := range module.Patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return module.Name
+			}
+		}
+	}
+	return ""
+}
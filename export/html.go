@@ -0,0 +1,284 @@
+package export
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// htmlTemplates holds the pages and style sheet of the HTML report
+//
+//go:embed templates/layout.html templates/index.html templates/table.html templates/style.css
+var htmlTemplates embed.FS
+
+// reportTemplates are the parsed pages of the HTML report
+var reportTemplates = template.Must(template.New("report").
+	Funcs(template.FuncMap{"join": strings.Join}).
+	ParseFS(htmlTemplates, "templates/*.html"))
+
+// HTMLLinks are the URLs the pages of the HTML report link to: the files of a
+// written report, or the routes of a server rendering the pages on request
+type HTMLLinks struct {
+	Index string                   // Page listing the tables
+	Style string                   // Style sheet
+	Table func(name string) string // Page of a table, "" for tables without a page
+}
+
+// htmlPage is the data a page of the HTML report is rendered from
+type htmlPage struct {
+	Title     string
+	Schema    string
+	Generated string
+	Index     string       // URL of the index page
+	Style     string       // URL of the style sheet
+	Tables    []*htmlTable // All tables, for the navigation
+	Current   string       // Name of the table of the page, empty on the index
+	Table     *htmlTable
+}
+
+// htmlTable is a table as shown in the HTML report, with its links resolved
+type htmlTable struct {
+	Name         string
+	File         string
+	Comment      string
+	Summary      string // First line of the comment
+	Properties   []t.Property
+	Columns      []htmlColumn
+	Indexes      []t.Index
+	Constraints  []t.Constraint
+	References   []htmlLink
+	ReferencedBy []htmlLink
+}
+
+// htmlColumn is a column as shown in the HTML report
+type htmlColumn struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	Default    string
+	PrimaryKey bool
+	ForeignKey *htmlLink
+	Comment    string
+}
+
+// htmlLink points to the page of a table; File is empty when the table is not
+// part of the report
+type htmlLink struct {
+	Table  string
+	File   string
+	Column string
+}
+
+// WriteHTMLReport writes a standalone HTML report of a schema into dir: an
+// index.html listing the tables, a page per table with its columns and indexes,
+// and the style sheet. Foreign keys link to the pages of the referenced tables.
+// It returns the written files.
+func WriteHTMLReport(dir, schema string, tables []*t.Table) ([]string, error) {
+	fileNames := htmlFileNames(tables)
+	pages := htmlTables(tables, func(name string) string { return fileNames[name] })
+	generated := time.Now().Format("2006-01-02 15:04")
+
+	files := []string{"index.html", "style.css"}
+	for _, table := range pages {
+		files = append(files, table.File)
+	}
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("file %s already exists", path)
+		}
+	}
+
+	var written []string
+	write := func(name string, content []byte) error {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return fmt.Errorf("error writing %s: %v", path, err)
+		}
+		written = append(written, path)
+		return nil
+	}
+	render := func(name, tmpl string, page htmlPage) error {
+		var sb strings.Builder
+		if err := reportTemplates.ExecuteTemplate(&sb, tmpl, page); err != nil {
+			return fmt.Errorf("error rendering %s: %v", name, err)
+		}
+		return write(name, []byte(sb.String()))
+	}
+
+	style, err := HTMLStyle()
+	if err != nil {
+		return nil, err
+	}
+	if err := write("style.css", style); err != nil {
+		return written, err
+	}
+
+	index := htmlPage{
+		Title:     "Schema " + schema,
+		Schema:    schema,
+		Generated: generated,
+		Index:     "index.html",
+		Style:     "style.css",
+		Tables:    pages,
+	}
+	if err := render("index.html", "index.html", index); err != nil {
+		return written, err
+	}
+	for _, table := range pages {
+		page := index
+		page.Title = table.Name + " – " + schema
+		page.Current = table.Name
+		page.Table = table
+		if err := render(table.File, "table.html", page); err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// HTMLStyle returns the style sheet of the HTML report
+func HTMLStyle() ([]byte, error) {
+	style, err := htmlTemplates.ReadFile("templates/style.css")
+	if err != nil {
+		return nil, fmt.Errorf("error reading style sheet: %v", err)
+	}
+	return style, nil
+}
+
+// WriteHTMLIndex writes the page of the HTML report listing the tables of a schema
+func WriteHTMLIndex(w io.Writer, schema string, tables []*t.Table, links HTMLLinks) error {
+	page := htmlPage{
+		Title:     "Schema " + schema,
+		Schema:    schema,
+		Generated: time.Now().Format("2006-01-02 15:04"),
+		Index:     links.Index,
+		Style:     links.Style,
+		Tables:    htmlTables(tables, links.Table),
+	}
+	if err := reportTemplates.ExecuteTemplate(w, "index.html", page); err != nil {
+		return fmt.Errorf("error rendering index: %v", err)
+	}
+	return nil
+}
+
+// WriteHTMLTable writes the page of the HTML report of a single table, with the
+// named tables in the navigation. Only the table is loaded, so the tables
+// referencing it are not listed.
+func WriteHTMLTable(w io.Writer, schema string, table *t.Table, names []string, links HTMLLinks) error {
+	page := htmlPage{
+		Title:     table.Name + " – " + schema,
+		Schema:    schema,
+		Generated: time.Now().Format("2006-01-02 15:04"),
+		Index:     links.Index,
+		Style:     links.Style,
+		Current:   table.Name,
+		Table:     htmlTables([]*t.Table{table}, links.Table)[0],
+	}
+	for _, name := range names {
+		page.Tables = append(page.Tables, &htmlTable{Name: name, File: links.Table(name)})
+	}
+	if err := reportTemplates.ExecuteTemplate(w, "table.html", page); err != nil {
+		return fmt.Errorf("error rendering %s: %v", table.Name, err)
+	}
+	return nil
+}
+
+// htmlFileNames returns the page file name of each table, by table name
+func htmlFileNames(tables []*t.Table) map[string]string {
+	files := make(map[string]string, len(tables))
+	used := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		file := htmlFileName(table.Name)
+		// Names differing only in case would clash on case-insensitive file systems
+		for n := 2; used[strings.ToLower(file)]; n++ {
+			file = fmt.Sprintf("%s-%d.html", strings.TrimSuffix(htmlFileName(table.Name), ".html"), n)
+		}
+		used[strings.ToLower(file)] = true
+		files[table.Name] = file
+	}
+	return files
+}
+
+// htmlTables converts tables to their report pages, resolving foreign keys to
+// the pages of the referenced tables
+func htmlTables(tables []*t.Table, file func(name string) string) []*htmlTable {
+	link := func(table, column string) htmlLink {
+		return htmlLink{Table: table, File: file(table), Column: column}
+	}
+
+	referencedBy := make(map[string][]htmlLink)
+	for _, table := range tables {
+		for _, ref := range referencedTables(table) {
+			if ref != table.Name {
+				referencedBy[ref] = append(referencedBy[ref], link(table.Name, ""))
+			}
+		}
+	}
+
+	pages := make([]*htmlTable, 0, len(tables))
+	for _, table := range tables {
+		summary, _, _ := strings.Cut(table.Comment, "\n")
+		page := &htmlTable{
+			Name:         table.Name,
+			File:         file(table.Name),
+			Comment:      table.Comment,
+			Summary:      summary,
+			Properties:   table.Properties,
+			Indexes:      table.Indexes,
+			Constraints:  slices.DeleteFunc(slices.Clone(table.Constraints), isKeyConstraint),
+			ReferencedBy: referencedBy[table.Name],
+		}
+		for _, ref := range referencedTables(table) {
+			page.References = append(page.References, link(ref, ""))
+		}
+		for _, col := range table.Columns {
+			column := htmlColumn{
+				Name:       col.Name,
+				Type:       col.Type,
+				Nullable:   col.Nullable,
+				PrimaryKey: col.IsPrimaryKey,
+				Comment:    col.Comment,
+			}
+			if col.DefaultValue.Valid {
+				column.Default = col.DefaultValue.String
+			}
+			if ref, refColumn, ok := col.ForeignKeyTarget(); ok {
+				target := link(ref, refColumn)
+				column.ForeignKey = &target
+			}
+			page.Columns = append(page.Columns, column)
+		}
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// isKeyConstraint reports whether a constraint is already shown by the key
+// columns, leaving checks and unique constraints for the constraints section
+func isKeyConstraint(c t.Constraint) bool {
+	return c.Type == t.PrimaryKeyConstraint || c.Type == t.ForeignKeyConstraint
+}
+
+// htmlFileName returns the page file name of a table, keeping letters, digits,
+// dashes and underscores so the name is safe in a URL on every platform
+func htmlFileName(table string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, table)
+	return "table-" + name + ".html"
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// inventoryHeader is the header row of the column inventory
+var inventoryHeader = []string{"schema", "table", "column", "type", "nullable", "default", "primary_key", "foreign_key"}
+
+// WriteColumnInventoryCSV writes every column of the tables as a row of a CSV
+// file, for filtering the schema in a spreadsheet. Foreign keys are written as
+// table.column, qualified by the schema when the reference is; a NULL default
+// is left empty.
+func WriteColumnInventoryCSV(w io.Writer, schema string, tables []*t.Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(inventoryHeader); err != nil {
+		return fmt.Errorf("error writing column inventory: %v", err)
+	}
+
+	for _, table := range tables {
+		tableSchema := table.Schema
+		if tableSchema == "" {
+			tableSchema = schema
+		}
+		for _, col := range table.Columns {
+			defaultValue := ""
+			if col.DefaultValue.Valid {
+				defaultValue = col.DefaultValue.String
+			}
+			foreignKey := ""
+			if ref, refColumn, ok := col.ForeignKeyTarget(); ok {
+				foreignKey = ref + "." + refColumn
+				if refSchema := col.ForeignKeySchema(); refSchema != "" {
+					foreignKey = refSchema + "." + foreignKey
+				}
+			}
+
+			record := []string{
+				tableSchema, table.Name, col.Name, col.Type, strconv.FormatBool(col.Nullable),
+				defaultValue, strconv.FormatBool(col.IsPrimaryKey), foreignKey,
+			}
+			if err := cw.Write(record); err != nil {
+				return fmt.Errorf("error writing column inventory: %v", err)
+			}
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("error writing column inventory: %v", err)
+	}
+	return nil
+}
+
+// WriteTableInventoryCSV writes the columns of a single table as a column inventory
+func WriteTableInventoryCSV(w io.Writer, table *t.Table) error {
+	return WriteColumnInventoryCSV(w, table.Schema, []*t.Table{table})
+}
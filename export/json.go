@@ -0,0 +1,91 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// JSONVersion is the version of the JSON and YAML export shape. It changes only
+// when fields are removed or change meaning; new optional fields keep the version.
+const JSONVersion = 1
+
+// SchemaDocument is the JSON export of a schema:
+//
+//	{
+//	  "version": 1,
+//	  "schema": "public",
+//	  "tables": [
+//	    {
+//	      "name": "orders", "schema": "public", "comment": "...",
+//	      "columns": [{"name": "id", "type": "integer", "nullable": false, "default": null,
+//	                   "primary_key": true, "foreign_key": {"table": "customers", "column": "id"}}],
+//	      "indexes": [{"name": "orders_pkey", "columns": ["id"], "unique": true, "primary_key": true}],
+//	      "constraints": [{"name": "orders_pkey", "type": "PRIMARY KEY", "columns": ["id"], "definition": "..."}],
+//	      "properties": [{"name": "Engine", "value": "InnoDB"}]
+//	    }
+//	  ]
+//	}
+//
+// Columns, indexes and constraints are always arrays, possibly empty; fields
+// marked omitempty in TableDocument are left out when not set.
+type SchemaDocument struct {
+	Version int             `json:"version" yaml:"version"`
+	Schema  string          `json:"schema" yaml:"schema"`
+	Tables  []TableDocument `json:"tables" yaml:"tables"`
+}
+
+// NewSchemaDocument converts the tables of a schema to their JSON representation
+func NewSchemaDocument(schema string, tables []*t.Table) SchemaDocument {
+	doc := SchemaDocument{Version: JSONVersion, Schema: schema, Tables: []TableDocument{}}
+	for _, table := range tables {
+		doc.Tables = append(doc.Tables, NewTableDocument(table))
+	}
+	return doc
+}
+
+// WriteTableJSON writes a table as an indented TableDocument
+func WriteTableJSON(w io.Writer, table *t.Table) error {
+	return writeJSON(w, NewTableDocument(table))
+}
+
+// WriteSchemaJSON writes the tables of a schema as an indented SchemaDocument
+func WriteSchemaJSON(w io.Writer, schema string, tables []*t.Table) error {
+	return writeJSON(w, NewSchemaDocument(schema, tables))
+}
+
+// writeJSON encodes a document with two-space indentation
+func writeJSON(w io.Writer, doc any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("error writing JSON: %v", err)
+	}
+	return nil
+}
+
+// TableLine is a line of the JSON Lines export: a TableDocument with the
+// version of the export shape, so each line can be ingested on its own
+type TableLine struct {
+	Version int `json:"version"`
+	TableDocument
+}
+
+// WriteSchemaJSONL writes the tables of a schema as JSON Lines, one compact
+// TableLine per line, for search indexes and catalogs ingesting streams
+func WriteSchemaJSONL(w io.Writer, _ string, tables []*t.Table) error {
+	encoder := json.NewEncoder(w)
+	for _, table := range tables {
+		if err := encoder.Encode(TableLine{Version: JSONVersion, TableDocument: NewTableDocument(table)}); err != nil {
+			return fmt.Errorf("error writing JSON Lines: %v", err)
+		}
+	}
+	return nil
+}
+
+// WriteTableJSONL writes a table as a single line of JSON Lines
+func WriteTableJSONL(w io.Writer, table *t.Table) error {
+	return WriteSchemaJSONL(w, table.Schema, []*t.Table{table})
+}
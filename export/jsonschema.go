@@ -0,0 +1,175 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// jsonSchemaDialect is the JSON Schema version of the generated documents
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is a JSON Schema document or subschema
+type jsonSchema struct {
+	Schema               string          `json:"$schema,omitempty"`
+	Title                string          `json:"title,omitempty"`
+	Description          string          `json:"description,omitempty"`
+	Type                 any             `json:"type,omitempty"` // A type name, or a list of them
+	Format               string          `json:"format,omitempty"`
+	MaxLength            int             `json:"maxLength,omitempty"`
+	ContentEncoding      string          `json:"contentEncoding,omitempty"`
+	Items                *jsonSchema     `json:"items,omitempty"`
+	Properties           *jsonSchemaList `json:"properties,omitempty"`
+	Required             []string        `json:"required,omitempty"`
+	AdditionalProperties *bool           `json:"additionalProperties,omitempty"`
+	Defs                 *jsonSchemaList `json:"$defs,omitempty"`
+}
+
+// jsonSchemaList holds named subschemas, written as an object in their order
+// rather than sorted by name, so properties follow the column order
+type jsonSchemaList struct {
+	names   []string
+	schemas []*jsonSchema
+}
+
+// add appends a named subschema
+func (l *jsonSchemaList) add(name string, schema *jsonSchema) {
+	l.names = append(l.names, name)
+	l.schemas = append(l.schemas, schema)
+}
+
+// MarshalJSON writes the subschemas as an object, in order
+func (l *jsonSchemaList) MarshalJSON() ([]byte, error) {
+	return marshalOrdered(l.names, func(i int) any { return l.schemas[i] })
+}
+
+// marshalOrdered writes a JSON object with the given keys in order, taking the
+// value of each key by its position
+func marshalOrdered(keys []string, value func(i int) any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(value(i))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// WriteTableJSONSchema writes a JSON Schema describing a row of a table as an
+// object: a property per column, typed after the column type, with the NOT
+// NULL columns required and no other properties allowed
+func WriteTableJSONSchema(w io.Writer, table *t.Table) error {
+	schema := tableJSONSchema(table)
+	schema.Schema = jsonSchemaDialect
+	return writeJSON(w, schema)
+}
+
+// WriteSchemaJSONSchema writes a single JSON Schema document with the row
+// schema of every table under $defs, to reference as "#/$defs/<table>"
+func WriteSchemaJSONSchema(w io.Writer, schema string, tables []*t.Table) error {
+	doc := &jsonSchema{Schema: jsonSchemaDialect, Title: schema, Defs: &jsonSchemaList{}}
+	for _, table := range tables {
+		doc.Defs.add(table.Name, tableJSONSchema(table))
+	}
+	return writeJSON(w, doc)
+}
+
+// tableJSONSchema returns the schema of a row of a table
+func tableJSONSchema(table *t.Table) *jsonSchema {
+	closed := false
+	schema := &jsonSchema{
+		Title:                table.Name,
+		Description:          table.Comment,
+		Type:                 "object",
+		Properties:           &jsonSchemaList{},
+		Required:             []string{},
+		AdditionalProperties: &closed,
+	}
+	for _, col := range table.Columns {
+		property := columnJSONSchema(col.Type)
+		property.Description = col.Comment
+		if col.Nullable {
+			if name, ok := property.Type.(string); ok {
+				property.Type = []string{name, "null"}
+			}
+		} else {
+			schema.Required = append(schema.Required, col.Name)
+		}
+		schema.Properties.add(col.Name, property)
+	}
+	return schema
+}
+
+// integerTypes are the names of integer column types across databases
+var integerTypes = map[string]bool{
+	"smallint": true, "integer": true, "int": true, "bigint": true, "tinyint": true, "mediumint": true,
+	"int2": true, "int4": true, "int8": true, "int16": true, "int32": true, "int64": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true, "byteint": true,
+}
+
+// typeLength matches the length of character types, e.g. varchar(255)
+var typeLength = regexp.MustCompile(`^(?:character varying|varchar|character|char|nvarchar|nchar|varchar2|nvarchar2)\s*\((\d+)\)`)
+
+// columnJSONSchema maps a column type to the schema of its values. Types with
+// no JSON counterpart, such as json itself, accept any value.
+func columnJSONSchema(columnType string) *jsonSchema {
+	typ := strings.ToLower(strings.TrimSpace(columnType))
+
+	// Arrays, as int4[] or PostgreSQL's internal _int4
+	if base, ok := strings.CutSuffix(typ, "[]"); ok {
+		return &jsonSchema{Type: "array", Items: columnJSONSchema(base)}
+	}
+	if base, ok := strings.CutPrefix(typ, "_"); ok {
+		return &jsonSchema{Type: "array", Items: columnJSONSchema(base)}
+	}
+
+	name, _, _ := strings.Cut(typ, "(")
+	name = strings.TrimSpace(strings.TrimSuffix(name, " unsigned"))
+	switch {
+	case name == "json" || name == "jsonb" || name == "variant":
+		return &jsonSchema{}
+	case name == "boolean" || name == "bool" || typ == "bit(1)":
+		return &jsonSchema{Type: "boolean"}
+	case integerTypes[name] || strings.HasSuffix(name, "serial"):
+		return &jsonSchema{Type: "integer"}
+	case name == "numeric" || name == "decimal" || name == "number" || name == "real" || name == "money" ||
+		strings.HasPrefix(name, "double") || strings.HasPrefix(name, "float"):
+		return &jsonSchema{Type: "number"}
+	case name == "uuid" || name == "uniqueidentifier":
+		return &jsonSchema{Type: "string", Format: "uuid"}
+	case name == "date":
+		return &jsonSchema{Type: "string", Format: "date"}
+	case strings.HasPrefix(name, "timestamp") || strings.HasPrefix(name, "datetime"):
+		return &jsonSchema{Type: "string", Format: "date-time"}
+	case strings.HasPrefix(name, "time"):
+		return &jsonSchema{Type: "string", Format: "time"}
+	case name == "interval":
+		return &jsonSchema{Type: "string", Format: "duration"}
+	case name == "bytea" || strings.HasSuffix(name, "blob") || strings.HasSuffix(name, "binary"):
+		return &jsonSchema{Type: "string", ContentEncoding: "base64"}
+	}
+
+	schema := &jsonSchema{Type: "string"}
+	if match := typeLength.FindStringSubmatch(typ); match != nil {
+		schema.MaxLength, _ = strconv.Atoi(match[1])
+	}
+	return schema
+}
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// WriteColumnsMarkdown writes the columns of a table as a Markdown table
+func WriteColumnsMarkdown(w io.Writer, table *t.Table) error {
+	var sb strings.Builder
+
+	sb.WriteString("| Column | Type | Nullable | Default | Primary key | Foreign key |\n")
+	sb.WriteString("|---|---|---|---|---|---|\n")
+
+	for _, col := range table.Columns {
+		defaultVal := ""
+		if col.DefaultValue.Valid {
+			defaultVal = "`" + col.DefaultValue.String + "`"
+		}
+
+		primaryKey := ""
+		if col.IsPrimaryKey {
+			primaryKey = "yes"
+		}
+
+		nullable := "no"
+		if col.Nullable {
+			nullable = "yes"
+		}
+
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+			markdownCell(col.Name), markdownCell(col.Type), nullable,
+			markdownCell(defaultVal), primaryKey, markdownCell(col.ForeignKey.String)))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// markdownCell escapes a value for use in a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// WriteTableMarkdown writes a documentation page of a table: its comment and
+// properties, columns, indexes and constraints
+func WriteTableMarkdown(w io.Writer, table *t.Table) error {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s.%s\n\n", table.Schema, table.Name))
+	if table.Comment != "" {
+		sb.WriteString(table.Comment + "\n\n")
+	}
+	for _, prop := range table.Properties {
+		sb.WriteString(fmt.Sprintf("- **%s:** %s\n", prop.Name, prop.Value))
+	}
+	if len(table.Properties) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Columns\n\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return err
+	}
+	if err := WriteColumnsMarkdown(w, table); err != nil {
+		return err
+	}
+	sb.Reset()
+
+	if len(table.Indexes) > 0 {
+		sb.WriteString("\n## Indexes\n\n")
+		for _, idx := range table.Indexes {
+			sb.WriteString(indexItem(idx))
+		}
+	}
+
+	if len(table.Constraints) > 0 {
+		sb.WriteString("\n## Constraints\n\n")
+		for _, con := range table.Constraints {
+			sb.WriteString(fmt.Sprintf("- `%s`: `%s`\n", con.Name, con.Definition))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// indexItem formats an index as a Markdown list item
+func indexItem(idx t.Index) string {
+	keys := strings.Join(idx.Columns, ", ")
+	if idx.Expression {
+		keys += " (with expressions)"
+	}
+	if len(idx.Include) > 0 {
+		keys += ", including " + strings.Join(idx.Include, ", ")
+	}
+	kind := ""
+	if idx.PrimaryKey {
+		kind = " primary key"
+	} else if idx.Unique {
+		kind = " unique"
+	}
+	return fmt.Sprintf("- `%s`%s on %s\n", idx.Name, kind, keys)
+}
@@ -0,0 +1,209 @@
+package export
+
+import (
+	"io"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// openAPIVersion is the OpenAPI version of the generated documents, the first
+// whose schemas are plain JSON Schema
+const openAPIVersion = "3.1.0"
+
+// jsonObject is a JSON object written with its keys in order
+type jsonObject struct {
+	keys   []string
+	values []any
+}
+
+// set appends a key
+func (o *jsonObject) set(key string, value any) {
+	o.keys = append(o.keys, key)
+	o.values = append(o.values, value)
+}
+
+// MarshalJSON writes the object with its keys in order
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	return marshalOrdered(o.keys, func(i int) any { return o.values[i] })
+}
+
+// WriteOpenAPI writes an OpenAPI document with create, read, update and delete
+// operations on the tables, as a starting point for an API over them. Each
+// table gets a row schema, and an input schema requiring only the NOT NULL
+// columns without a default. Tables without a primary key only get the list
+// and create operations.
+func WriteOpenAPI(w io.Writer, schema string, tables []*t.Table) error {
+	paths := &jsonObject{}
+	schemas := &jsonObject{}
+	for _, table := range tables {
+		name := pascalCase(table.Name)
+		row := tableJSONSchema(table)
+		schemas.set(name, row)
+		schemas.set(name+"Input", inputJSONSchema(table, row))
+
+		rowRef := map[string]string{"$ref": "#/components/schemas/" + name}
+		inputRef := map[string]string{"$ref": "#/components/schemas/" + name + "Input"}
+		tags := []string{table.Name}
+
+		paths.set("/"+table.Name, map[string]any{
+			"get": map[string]any{
+				"operationId": "list" + name,
+				"summary":     "List " + table.Name,
+				"tags":        tags,
+				"parameters": []any{
+					queryParameter("limit", "Maximum number of rows to return"),
+					queryParameter("offset", "Number of rows to skip"),
+				},
+				"responses": map[string]any{
+					"200": jsonResponse("The rows", map[string]any{"type": "array", "items": rowRef}),
+				},
+			},
+			"post": map[string]any{
+				"operationId": "create" + name,
+				"summary":     "Create a row of " + table.Name,
+				"tags":        tags,
+				"requestBody": jsonBody(inputRef),
+				"responses": map[string]any{
+					"201": jsonResponse("The created row", rowRef),
+				},
+			},
+		})
+
+		keys := primaryKeyColumns(table)
+		if len(keys) == 0 {
+			continue
+		}
+		path := "/" + table.Name
+		var parameters []any
+		for _, key := range keys {
+			path += "/{" + key + "}"
+			parameters = append(parameters, map[string]any{
+				"name":     key,
+				"in":       "path",
+				"required": true,
+				"schema":   columnJSONSchema(columnType(table, key)),
+			})
+		}
+		notFound := map[string]any{"description": "No row has the key"}
+		paths.set(path, map[string]any{
+			"parameters": parameters,
+			"get": map[string]any{
+				"operationId": "get" + name,
+				"summary":     "Get a row of " + table.Name + " by its primary key",
+				"tags":        tags,
+				"responses": map[string]any{
+					"200": jsonResponse("The row", rowRef),
+					"404": notFound,
+				},
+			},
+			"put": map[string]any{
+				"operationId": "update" + name,
+				"summary":     "Replace a row of " + table.Name,
+				"tags":        tags,
+				"requestBody": jsonBody(inputRef),
+				"responses": map[string]any{
+					"200": jsonResponse("The updated row", rowRef),
+					"404": notFound,
+				},
+			},
+			"delete": map[string]any{
+				"operationId": "delete" + name,
+				"summary":     "Delete a row of " + table.Name,
+				"tags":        tags,
+				"responses": map[string]any{
+					"204": map[string]any{"description": "The row was deleted"},
+					"404": notFound,
+				},
+			},
+		})
+	}
+
+	doc := &jsonObject{}
+	doc.set("openapi", openAPIVersion)
+	doc.set("info", map[string]string{"title": schema + " API", "version": "1.0.0"})
+	doc.set("paths", paths)
+	doc.set("components", map[string]any{"schemas": schemas})
+	return writeJSON(w, doc)
+}
+
+// WriteTableOpenAPI writes an OpenAPI document for a single table
+func WriteTableOpenAPI(w io.Writer, table *t.Table) error {
+	return WriteOpenAPI(w, table.Schema, []*t.Table{table})
+}
+
+// inputJSONSchema returns the schema of the body creating or replacing a row:
+// the row schema, requiring only the columns the database does not fill in
+func inputJSONSchema(table *t.Table, row *jsonSchema) *jsonSchema {
+	input := *row
+	input.Title = table.Name + " input"
+	input.Required = []string{}
+	for _, col := range table.Columns {
+		if !col.Nullable && !col.DefaultValue.Valid {
+			input.Required = append(input.Required, col.Name)
+		}
+	}
+	return &input
+}
+
+// columnType returns the type of the named column of a table
+func columnType(table *t.Table, name string) string {
+	for _, col := range table.Columns {
+		if col.Name == name {
+			return col.Type
+		}
+	}
+	return ""
+}
+
+// queryParameter returns an optional integer query parameter
+func queryParameter(name, description string) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"in":          "query",
+		"description": description,
+		"schema":      map[string]any{"type": "integer", "minimum": 0},
+	}
+}
+
+// jsonBody returns a required JSON request body
+func jsonBody(schema any) map[string]any {
+	return map[string]any{
+		"required": true,
+		"content":  map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
+
+// jsonResponse returns a JSON response
+func jsonResponse(description string, schema any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content":     map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
+
+// pascalCase returns a name in Go style, e.g. OrderItems for order_items,
+// keeping only letters and digits as OpenAPI and GraphQL names allow
+func pascalCase(table string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range table {
+		switch {
+		case r >= 'a' && r <= 'z':
+			if upper {
+				r -= 'a' - 'A'
+			}
+			sb.WriteRune(r)
+			upper = false
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	if sb.Len() == 0 {
+		return "Table"
+	}
+	return sb.String()
+}
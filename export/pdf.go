@@ -0,0 +1,249 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Layout of the PDF report, in millimetres on A4 paper
+const (
+	pdfMargin    = 20.0
+	pdfLineH     = 6.0
+	pdfRowH      = 5.5
+	pdfFontSize  = 9.0
+	pdfFontTitle = 18.0
+)
+
+// pdfColumn is a column of a table drawn in the PDF report
+type pdfColumn struct {
+	title string
+	width float64
+}
+
+// Columns of the column and index tables of the PDF report, filling the 170mm
+// between the margins
+var (
+	pdfColumnsTable = []pdfColumn{
+		{"Column", 42}, {"Type", 34}, {"Null", 11}, {"Default", 30}, {"Key", 11}, {"References", 42},
+	}
+	pdfIndexesTable = []pdfColumn{
+		{"Index", 60}, {"Columns", 70}, {"Unique", 20}, {"Primary", 20},
+	}
+)
+
+// WriteSchemaPDF writes a printable report of a schema: a cover page, a table
+// of contents with the page of each table, and a page per table with its
+// columns and indexes. The PDF fonts only cover Western European characters,
+// others are replaced.
+func WriteSchemaPDF(w io.Writer, schema string, tables []*t.Table) error {
+	// The contents need the page of each table, known once the tables are laid
+	// out, so the report is laid out twice. The contents take the same space
+	// both times, so the pages do not move.
+	_, pages := renderPDF(schema, tables, nil)
+	pdf, _ := renderPDF(schema, tables, pages)
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("error writing PDF: %v", err)
+	}
+	return nil
+}
+
+// WriteTablePDF writes the PDF report of a single table
+func WriteTablePDF(w io.Writer, table *t.Table) error {
+	return WriteSchemaPDF(w, table.Schema, []*t.Table{table})
+}
+
+// pdfReport lays out the PDF report
+type pdfReport struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string // Converts text to the encoding of the PDF fonts
+}
+
+// renderPDF lays out the report with the given table pages in the contents,
+// returning it with the pages the tables were actually laid out on
+func renderPDF(schema string, tables []*t.Table, pages []int) (*fpdf.Fpdf, []int) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetTitle("Schema "+schema, true)
+	pdf.SetCreator("db-reader", true)
+	r := &pdfReport{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(-pdfMargin + 5)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.SetTextColor(128, 128, 128)
+		width, _ := pdf.GetPageSize()
+		half := (width - 2*pdfMargin) / 2
+		pdf.CellFormat(half, 5, r.tr("Schema "+schema), "", 0, "L", false, 0, "")
+		pdf.CellFormat(half, 5, strconv.Itoa(pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	r.cover(schema, tables)
+
+	links := make([]int, len(tables))
+	for i := range tables {
+		links[i] = pdf.AddLink()
+	}
+	r.contents(tables, pages, links)
+
+	actual := make([]int, len(tables))
+	for i, table := range tables {
+		pdf.AddPage()
+		pdf.SetLink(links[i], -1, pdf.PageNo())
+		actual[i] = pdf.PageNo()
+		r.table(table)
+	}
+	return pdf, actual
+}
+
+// cover writes the cover page
+func (r *pdfReport) cover(schema string, tables []*t.Table) {
+	pdf := r.pdf
+	pdf.AddPage()
+
+	columns := 0
+	for _, table := range tables {
+		columns += len(table.Columns)
+	}
+
+	pdf.SetY(90)
+	pdf.SetFont("Helvetica", "", 14)
+	pdf.CellFormat(0, 10, "Schema report", "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 28)
+	pdf.MultiCell(0, 14, r.tr(schema), "", "C", false)
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, pdfLineH, fmt.Sprintf("%d tables, %d columns", len(tables), columns), "", 1, "C", false, 0, "")
+	pdf.CellFormat(0, pdfLineH, "Generated by db-reader on "+time.Now().Format("2006-01-02 15:04"), "", 1, "C", false, 0, "")
+}
+
+// contents writes the table of contents, each entry linking to the page of its
+// table. Without the pages, the page numbers are left blank.
+func (r *pdfReport) contents(tables []*t.Table, pages []int, links []int) {
+	pdf := r.pdf
+	pdf.AddPage()
+	pdf.Bookmark("Contents", 0, -1)
+	pdf.SetFont("Helvetica", "B", pdfFontTitle)
+	pdf.CellFormat(0, 12, "Contents", "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "", 10)
+	width, _ := pdf.GetPageSize()
+	for i, table := range tables {
+		page := ""
+		if pages != nil {
+			page = strconv.Itoa(pages[i])
+		}
+		name := r.fit(table.Name, width-2*pdfMargin-20)
+		pdf.CellFormat(width-2*pdfMargin-20, pdfLineH, name, "", 0, "L", false, links[i], "")
+		pdf.CellFormat(20, pdfLineH, page, "", 1, "R", false, links[i], "")
+	}
+}
+
+// table writes the page of a table
+func (r *pdfReport) table(table *t.Table) {
+	pdf := r.pdf
+	pdf.Bookmark(r.tr(table.Name), 0, -1)
+	pdf.SetFont("Helvetica", "B", pdfFontTitle)
+	pdf.MultiCell(0, 10, r.tr(table.Name), "", "L", false)
+
+	pdf.SetFont("Helvetica", "", 10)
+	if table.Comment != "" {
+		pdf.MultiCell(0, 5, r.tr(table.Comment), "", "L", false)
+	}
+	for _, prop := range table.Properties {
+		pdf.MultiCell(0, 5, r.tr(prop.Name+": "+prop.Value), "", "L", false)
+	}
+
+	r.heading("Columns")
+	rows := make([][]string, len(table.Columns))
+	for i, col := range table.Columns {
+		defaultValue := ""
+		if col.DefaultValue.Valid {
+			defaultValue = col.DefaultValue.String
+		}
+		key := ""
+		if col.IsPrimaryKey {
+			key = "PK"
+		}
+		references := ""
+		if ref, refColumn, ok := col.ForeignKeyTarget(); ok {
+			references = ref + "." + refColumn
+		}
+		rows[i] = []string{col.Name, col.Type, yesNo(col.Nullable), defaultValue, key, references}
+	}
+	r.grid(pdfColumnsTable, rows)
+
+	if len(table.Indexes) > 0 {
+		r.heading("Indexes")
+		rows := make([][]string, len(table.Indexes))
+		for i, idx := range table.Indexes {
+			rows[i] = []string{idx.Name, strings.Join(idx.Columns, ", "), yesNo(idx.Unique), yesNo(idx.PrimaryKey)}
+		}
+		r.grid(pdfIndexesTable, rows)
+	}
+}
+
+// heading writes the heading of a section of a table page
+func (r *pdfReport) heading(title string) {
+	r.pdf.Ln(4)
+	r.pdf.SetFont("Helvetica", "B", 12)
+	r.pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+}
+
+// grid writes rows as a table with a shaded header, repeating the header at
+// the top of each page the rows continue on
+func (r *pdfReport) grid(columns []pdfColumn, rows [][]string) {
+	pdf := r.pdf
+	_, pageHeight := pdf.GetPageSize()
+
+	header := func() {
+		pdf.SetFont("Helvetica", "B", pdfFontSize)
+		pdf.SetFillColor(68, 114, 196)
+		pdf.SetTextColor(255, 255, 255)
+		for _, col := range columns {
+			pdf.CellFormat(col.width, pdfRowH+1, col.title, "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFont("Helvetica", "", pdfFontSize)
+	}
+
+	header()
+	for i, row := range rows {
+		if pdf.GetY()+pdfRowH > pageHeight-pdfMargin {
+			pdf.AddPage()
+			header()
+		}
+		// Alternate rows are shaded for reading across wide rows
+		pdf.SetFillColor(242, 242, 242)
+		for j, col := range columns {
+			pdf.CellFormat(col.width, pdfRowH, r.fit(row[j], col.width-2), "1", 0, "L", i%2 == 1, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
+
+// fit converts text for the PDF fonts and shortens it with an ellipsis to the width
+func (r *pdfReport) fit(text string, width float64) string {
+	text = r.tr(text)
+	if r.pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	ellipsis := r.tr("…")
+	for len(text) > 0 && r.pdf.GetStringWidth(text+ellipsis) > width {
+		text = text[:len(text)-1]
+	}
+	return text + ellipsis
+}
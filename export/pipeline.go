@@ -0,0 +1,144 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// TableOutput renders and writes the output of a single table, returning the written files
+type TableOutput func(table *t.Table) ([]string, error)
+
+// SchemaOutput renders and writes an output covering all tables, returning the written files
+type SchemaOutput func(tables []*t.Table) ([]string, error)
+
+// Pipeline exports tables with bounded concurrency. Table structures are loaded
+// by several workers at once, each table output is written as soon as its table
+// is loaded, and the schema outputs run side by side once all tables are in.
+type Pipeline struct {
+	Workers int // Tables loaded and outputs written at the same time, 1 if not positive
+	Load    func(name string) (*t.Table, error)
+
+	TableOutputs  []TableOutput
+	SchemaOutputs []SchemaOutput
+}
+
+// loaded is a table coming out of the load stage
+type loaded struct {
+	index int
+	table *t.Table
+}
+
+// Run loads the named tables and writes all outputs. It returns the tables in
+// the order of the names and the written files, sorted, and stops at the first error.
+func (p Pipeline) Run(names []string) ([]*t.Table, []string, error) {
+	workers := max(p.Workers, 1)
+
+	var mu sync.Mutex
+	var files []string
+	var firstErr error
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+	written := func(paths ...string) {
+		mu.Lock()
+		defer mu.Unlock()
+		files = append(files, paths...)
+	}
+
+	// Load stage: workers take table names until they run out or something failed
+	indexes := make(chan int)
+	results := make(chan loaded, workers)
+	var loaders sync.WaitGroup
+	for range workers {
+		loaders.Add(1)
+		go func() {
+			defer loaders.Done()
+			for i := range indexes {
+				if failed() {
+					continue
+				}
+				table, err := p.Load(names[i])
+				if err != nil {
+					fail(fmt.Errorf("error loading table %s: %v", names[i], err))
+					continue
+				}
+				results <- loaded{index: i, table: table}
+			}
+		}()
+	}
+	go func() {
+		for i := range names {
+			indexes <- i
+		}
+		close(indexes)
+		loaders.Wait()
+		close(results)
+	}()
+
+	// Table stage: each loaded table is written while the next ones load
+	tables := make([]*t.Table, len(names))
+	slots := make(chan struct{}, workers)
+	var writers sync.WaitGroup
+	for result := range results {
+		tables[result.index] = result.table
+		for _, output := range p.TableOutputs {
+			slots <- struct{}{}
+			writers.Add(1)
+			go func() {
+				defer writers.Done()
+				defer func() { <-slots }()
+				if failed() {
+					return
+				}
+				paths, err := output(result.table)
+				if err != nil {
+					fail(err)
+					return
+				}
+				written(paths...)
+			}()
+		}
+	}
+	writers.Wait()
+	if firstErr != nil {
+		return nil, files, firstErr
+	}
+
+	// Schema stage: outputs such as diagrams need every table
+	for _, output := range p.SchemaOutputs {
+		slots <- struct{}{}
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			defer func() { <-slots }()
+			paths, err := output(tables)
+			written(paths...)
+			if err != nil {
+				fail(err)
+			}
+		}()
+	}
+	writers.Wait()
+
+	sort.Strings(files)
+	return tables, files, firstErr
+}
+
+// LoadTables loads the named tables with up to the given number of concurrent
+// catalog queries, keeping the order of the names
+func LoadTables(load func(name string) (*t.Table, error), names []string, workers int) ([]*t.Table, error) {
+	tables, _, err := Pipeline{Workers: workers, Load: load}.Run(names)
+	return tables, err
+}
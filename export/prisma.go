@@ -0,0 +1,346 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// prismaProviders are the Prisma datasource providers of the drivers Prisma
+// supports. Other drivers get the PostgreSQL provider.
+var prismaProviders = map[string]string{
+	t.DriverPostgres: "postgresql",
+	t.DriverMySQL:    "mysql",
+	t.DriverSQLite:   "sqlite",
+	t.DriverMSSQL:    "sqlserver",
+	t.DriverMongoDB:  "mongodb",
+}
+
+// trailingSpaces matches the padding the alignment leaves after fields without attributes
+var trailingSpaces = regexp.MustCompile(`(?m) +$`)
+
+// prismaField is a field of a generated Prisma model
+type prismaField struct {
+	name       string
+	typ        string
+	attributes string
+}
+
+// prismaRelation is a foreign key column of a table, with the field names of
+// both ends of the relation
+type prismaRelation struct {
+	table, column       string
+	ref, refColumn      string
+	name                string // Relation name, needed when two models are related more than once
+	field, reverseField string
+}
+
+// WritePrismaSchema writes a Prisma schema of the tables, as "prisma db pull"
+// would introspect it: a model per table keeping the table and column names, a
+// relation field per foreign key and, on the referenced model, a list of the
+// rows referencing it. Foreign keys to tables left out of the export stay
+// plain columns. Prisma needs a unique key per model, so tables without a
+// primary key are marked @@ignore.
+func WritePrismaSchema(w io.Writer, driver string, tables []*t.Table) error {
+	provider, ok := prismaProviders[driver]
+	if !ok {
+		provider = prismaProviders[t.DriverPostgres]
+	}
+
+	models := make(map[string]string, len(tables)) // Model name by table name
+	for _, table := range tables {
+		models[table.Name] = prismaName(table.Name)
+	}
+
+	fields := make(map[string][]prismaField, len(tables))
+	for _, table := range tables {
+		keys := primaryKeyColumns(table)
+		for _, col := range table.Columns {
+			fields[table.Name] = append(fields[table.Name], prismaColumnField(table, col, keys, provider))
+		}
+	}
+
+	// Relations come after the columns, so their names avoid the column names
+	relations := prismaRelations(tables, models)
+	for i := range relations {
+		rel := &relations[i]
+		forward := strings.TrimSuffix(strings.TrimSuffix(rel.column, "_id"), "Id")
+		if forward == rel.column || forward == "" {
+			forward = rel.ref
+		}
+		rel.field = prismaFieldName(fields[rel.table], prismaName(forward))
+		// The relation is optional as its column is
+		typ := models[rel.ref]
+		if slices.ContainsFunc(fields[rel.table], func(f prismaField) bool {
+			return f.name == prismaName(rel.column) && strings.HasSuffix(f.typ, "?")
+		}) {
+			typ += "?"
+		}
+		fields[rel.table] = append(fields[rel.table], prismaField{rel.field, typ, ""})
+
+		reverse := rel.table
+		if rel.name != "" {
+			reverse += "_" + rel.column
+		}
+		rel.reverseField = prismaFieldName(fields[rel.ref], prismaName(reverse))
+		fields[rel.ref] = append(fields[rel.ref], prismaField{rel.reverseField, models[rel.table] + "[]", ""})
+	}
+
+	var sb strings.Builder
+	sb.WriteString("generator client {\n  provider = \"prisma-client-js\"\n}\n\n")
+	sb.WriteString(fmt.Sprintf("datasource db {\n  provider = %q\n  url      = env(\"DATABASE_URL\")\n}\n", provider))
+
+	for _, table := range tables {
+		sb.WriteString("\n")
+		if table.Comment != "" {
+			sb.WriteString(prismaComment(table.Comment, ""))
+		}
+		sb.WriteString(fmt.Sprintf("model %s {\n", models[table.Name]))
+
+		comments := make(map[string]string, len(table.Columns))
+		for _, col := range table.Columns {
+			comments[prismaName(col.Name)] = col.Comment
+		}
+		for _, rel := range relations {
+			if rel.table == table.Name {
+				setAttributes(fields[table.Name], rel.field, prismaRelationAttribute(rel))
+			}
+			if rel.ref == table.Name && rel.name != "" {
+				setAttributes(fields[table.Name], rel.reverseField, fmt.Sprintf("@relation(%q)", rel.name))
+			}
+		}
+
+		// Fields are aligned in columns, as prisma format does
+		tw := tabwriter.NewWriter(&sb, 0, 0, 1, ' ', 0)
+		for _, field := range fields[table.Name] {
+			if comment := comments[field.name]; comment != "" {
+				tw.Flush()
+				sb.WriteString(prismaComment(comment, "  "))
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", field.name, field.typ, field.attributes)
+		}
+		tw.Flush()
+
+		attributes := prismaModelAttributes(table, models[table.Name])
+		if len(attributes) > 0 {
+			sb.WriteString("\n")
+		}
+		for _, attribute := range attributes {
+			sb.WriteString("  " + attribute + "\n")
+		}
+		sb.WriteString("}\n")
+	}
+
+	_, err := io.WriteString(w, trailingSpaces.ReplaceAllString(sb.String(), ""))
+	return err
+}
+
+// prismaColumnField returns the field of a column
+func prismaColumnField(table *t.Table, col t.Column, keys []string, provider string) prismaField {
+	field := prismaField{name: prismaName(col.Name), typ: prismaType(col.Type)}
+	if provider != "postgresql" && strings.HasSuffix(field.typ, "[]") {
+		// Only PostgreSQL has arrays
+		field.typ = "Json"
+	}
+	// Key columns are never NULL, although SQLite reports them nullable
+	if col.Nullable && !slices.Contains(keys, col.Name) && !strings.HasSuffix(field.typ, "[]") {
+		field.typ += "?"
+	}
+
+	var attributes []string
+	if len(keys) == 1 && keys[0] == col.Name {
+		attributes = append(attributes, "@id")
+	}
+	if col.DefaultValue.Valid {
+		attributes = append(attributes, prismaDefault(col.DefaultValue.String, field.typ))
+	}
+	for _, idx := range table.Indexes {
+		if idx.Unique && !idx.PrimaryKey && !idx.Expression && len(idx.Columns) == 1 && idx.Columns[0] == col.Name {
+			attributes = append(attributes, "@unique")
+			break
+		}
+	}
+	if field.name != col.Name {
+		attributes = append(attributes, fmt.Sprintf("@map(%q)", col.Name))
+	}
+	field.attributes = strings.Join(attributes, " ")
+	return field
+}
+
+// prismaRelations returns the foreign key columns between the tables, naming
+// the relations of models related more than once, or to themselves
+func prismaRelations(tables []*t.Table, models map[string]string) []prismaRelation {
+	var relations []prismaRelation
+	count := make(map[[2]string]int)
+	for _, table := range tables {
+		for _, col := range table.Columns {
+			ref, refColumn, ok := col.ForeignKeyTarget()
+			if !ok || models[ref] == "" {
+				continue
+			}
+			relations = append(relations, prismaRelation{
+				table: table.Name, column: col.Name, ref: ref, refColumn: refColumn,
+			})
+			pair := [2]string{min(table.Name, ref), max(table.Name, ref)}
+			count[pair]++
+		}
+	}
+	for i, rel := range relations {
+		pair := [2]string{min(rel.table, rel.ref), max(rel.table, rel.ref)}
+		if count[pair] > 1 || rel.table == rel.ref {
+			relations[i].name = rel.table + "_" + rel.column + "_fkey"
+		}
+	}
+	return relations
+}
+
+// prismaRelationAttribute returns the @relation attribute of the forward field of a relation
+func prismaRelationAttribute(rel prismaRelation) string {
+	var name string
+	if rel.name != "" {
+		name = strconv.Quote(rel.name) + ", "
+	}
+	return fmt.Sprintf("@relation(%sfields: [%s], references: [%s])", name, prismaName(rel.column), prismaName(rel.refColumn))
+}
+
+// prismaModelAttributes returns the block attributes of the model of a table:
+// composite keys and indexes, and the table name when the model renames it
+func prismaModelAttributes(table *t.Table, model string) []string {
+	var attributes []string
+	keys := primaryKeyColumns(table)
+	switch {
+	case len(keys) > 1:
+		attributes = append(attributes, fmt.Sprintf("@@id([%s])", prismaNames(keys)))
+	case len(keys) == 0:
+		attributes = append(attributes, "@@ignore // No primary key, which Prisma Client needs")
+	}
+	for _, idx := range table.Indexes {
+		if idx.PrimaryKey || idx.Expression || len(idx.Columns) == 0 {
+			continue
+		}
+		switch {
+		case idx.Unique && len(idx.Columns) > 1:
+			attributes = append(attributes, fmt.Sprintf("@@unique([%s], map: %q)", prismaNames(idx.Columns), idx.Name))
+		case !idx.Unique:
+			attributes = append(attributes, fmt.Sprintf("@@index([%s], map: %q)", prismaNames(idx.Columns), idx.Name))
+		}
+	}
+	if model != table.Name {
+		attributes = append(attributes, fmt.Sprintf("@@map(%q)", table.Name))
+	}
+	return attributes
+}
+
+// prismaType maps a column type to a Prisma scalar type
+func prismaType(columnType string) string {
+	schema := columnJSONSchema(columnType)
+	name, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(columnType)), "(")
+	name = strings.TrimSpace(strings.TrimSuffix(name, " unsigned"))
+
+	switch {
+	case schema.Type == "array":
+		return prismaType(strings.TrimPrefix(strings.TrimSuffix(columnType, "[]"), "_")) + "[]"
+	case schema.Type == nil:
+		return "Json"
+	case schema.Type == "boolean":
+		return "Boolean"
+	case schema.Type == "integer":
+		if name == "bigint" || name == "int8" || name == "bigserial" || name == "int64" || name == "uint64" {
+			return "BigInt"
+		}
+		return "Int"
+	case schema.Type == "number":
+		if name == "numeric" || name == "decimal" || name == "number" || name == "money" {
+			return "Decimal"
+		}
+		return "Float"
+	case schema.Format == "date" || schema.Format == "date-time" || schema.Format == "time":
+		return "DateTime"
+	case schema.ContentEncoding == "base64":
+		return "Bytes"
+	default:
+		return "String"
+	}
+}
+
+// prismaLiteral matches default values Prisma takes as they are: numbers and booleans
+var prismaLiteral = regexp.MustCompile(`^(?i:-?\d+(\.\d+)?|true|false)$`)
+
+// prismaDefault returns the @default attribute of a column default
+func prismaDefault(value, typ string) string {
+	value = strings.TrimSpace(value)
+	lower := strings.ToLower(value)
+	switch {
+	case strings.HasPrefix(lower, "nextval("), strings.Contains(lower, "auto_increment"):
+		return "@default(autoincrement())"
+	case lower == "now()" || lower == "current_timestamp" || lower == "current_timestamp()" || lower == "getdate()":
+		return "@default(now())"
+	case prismaLiteral.MatchString(value):
+		return "@default(" + lower + ")"
+	}
+
+	// A string literal, possibly cast as in 'x'::text
+	if literal, _, _ := strings.Cut(value, "::"); len(literal) >= 2 && literal[0] == '\'' && literal[len(literal)-1] == '\'' &&
+		strings.TrimSuffix(typ, "?") == "String" {
+		return "@default(" + strconv.Quote(strings.ReplaceAll(literal[1:len(literal)-1], "''", "'")) + ")"
+	}
+	return fmt.Sprintf("@default(dbgenerated(%q))", value)
+}
+
+// prismaName returns a table or column name as a Prisma identifier, which
+// starts with a letter followed by letters, digits and underscores
+func prismaName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	if name == "" || !(name[0] >= 'a' && name[0] <= 'z' || name[0] >= 'A' && name[0] <= 'Z') {
+		name = "n" + name
+	}
+	return name
+}
+
+// prismaNames returns the field names of columns, separated by commas
+func prismaNames(columns []string) string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = prismaName(col)
+	}
+	return strings.Join(names, ", ")
+}
+
+// prismaFieldName numbers a field name if the model already has it
+func prismaFieldName(fields []prismaField, name string) string {
+	taken := func(name string) bool {
+		return slices.ContainsFunc(fields, func(f prismaField) bool { return f.name == name })
+	}
+	field := name
+	for n := 2; taken(field); n++ {
+		field = fmt.Sprintf("%s%d", name, n)
+	}
+	return field
+}
+
+// setAttributes sets the attributes of the named field
+func setAttributes(fields []prismaField, name, attributes string) {
+	for i := range fields {
+		if fields[i].name == name {
+			fields[i].attributes = attributes
+		}
+	}
+}
+
+// prismaComment returns a comment as Prisma documentation comments
+func prismaComment(comment, indent string) string {
+	return indent + "/// " + strings.ReplaceAll(comment, "\n", "\n"+indent+"/// ") + "\n"
+}
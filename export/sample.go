@@ -0,0 +1,149 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// MaskedColumns returns the sampled columns whose values are masked: all of
+// them but key, numeric and boolean columns, binary columns that are left out
+// anyway, and those whose names match one of the unmasked patterns (path.Match
+// syntax, case-insensitive). Columns missing from the table are masked too, so
+// only columns known to be harmless show.
+func MaskedColumns(table *t.Table, columns []string, unmasked []string) []string {
+	var masked []string
+	for _, name := range columns {
+		if col := findColumn(table, name); col != nil && (!maskable(*col) || binary(*col)) {
+			continue
+		}
+		if !matchesAny(unmasked, name) {
+			masked = append(masked, name)
+		}
+	}
+	return masked
+}
+
+// maskable reports whether the values of a column may be personal or secret.
+// Keys and numbers only identify or measure rows.
+func maskable(col t.Column) bool {
+	if col.IsPrimaryKey || col.ForeignKey.Valid {
+		return false
+	}
+	switch columnJSONSchema(col.Type).Type {
+	case "integer", "number", "boolean":
+		return false
+	}
+	return true
+}
+
+// binary reports whether a column holds binary data, which is not written to samples
+func binary(col t.Column) bool {
+	return columnJSONSchema(col.Type).ContentEncoding != ""
+}
+
+// matchesAny reports whether a column name matches one of the patterns, ignoring case
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteSampleCSV writes sampled rows of a table as CSV with a header row. Values
+// of the masked columns keep their shape but not their content: letters become
+// x and digits 9, so an e-mail address reads xxxx@xxxxxxx.xxx. Values of binary
+// columns are left out as their size. NULL is left empty.
+func WriteSampleCSV(w io.Writer, table *t.Table, sample *t.QueryResult, masked []string) error {
+	mask := make([]bool, len(sample.Columns))
+	drop := make([]bool, len(sample.Columns))
+	for i, name := range sample.Columns {
+		mask[i] = slices.Contains(masked, name)
+		if col := findColumn(table, name); col != nil {
+			drop[i] = binary(*col)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(sample.Columns); err != nil {
+		return fmt.Errorf("error writing sample: %v", err)
+	}
+	for _, row := range sample.Rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			switch {
+			case value == "NULL":
+			case drop[i]:
+				record[i] = fmt.Sprintf("(%d bytes)", binarySize(value))
+			case mask[i]:
+				record[i] = maskValue(value)
+			default:
+				record[i] = value
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("error writing sample: %v", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("error writing sample: %v", err)
+	}
+	return nil
+}
+
+// binarySize returns the size of a binary value, given raw or hex-encoded as \x...
+func binarySize(value string) int {
+	if digits, ok := strings.CutPrefix(value, "\\x"); ok {
+		return len(digits) / 2
+	}
+	return len(value)
+}
+
+// findColumn returns the named column of a table, or nil
+func findColumn(table *t.Table, name string) *t.Column {
+	for i := range table.Columns {
+		if table.Columns[i].Name == name {
+			return &table.Columns[i]
+		}
+	}
+	return nil
+}
+
+// maskValue replaces the letters and digits of a value, keeping punctuation
+func maskValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return '9'
+		case r >= 'A' && r <= 'Z':
+			return 'X'
+		case r == ' ' || r == '-' || r == '.' || r == '@' || r == '_' || r == '+' || r == '/' || r == ':' || r == ',':
+			return r
+		default:
+			return 'x'
+		}
+	}, value)
+}
+
+// WriteSampleSection writes the Markdown section of a documentation page
+// linking to the sample CSV file of the table
+func WriteSampleSection(w io.Writer, file string, sample *t.QueryResult, masked []string) error {
+	var sb strings.Builder
+	sb.WriteString("\n## Sample data\n\n")
+	sb.WriteString(fmt.Sprintf("[%d example rows](%s)", len(sample.Rows), file))
+	if len(masked) > 0 {
+		sb.WriteString(fmt.Sprintf(", with the values of %s masked", strings.Join(masked, ", ")))
+	}
+	sb.WriteString(".\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
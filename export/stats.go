@@ -0,0 +1,62 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// FormatBytes formats a byte count using binary units, e.g. "12.3 MB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// WriteStatsMarkdown writes schema statistics as a Markdown report section
+func WriteStatsMarkdown(w io.Writer, stats *t.SchemaStats) error {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("## Schema statistics: %s\n\n", stats.Schema))
+
+	sb.WriteString("| Objects | Count |\n|---|---:|\n")
+	sb.WriteString(fmt.Sprintf("| Tables | %d |\n", stats.Tables))
+	sb.WriteString(fmt.Sprintf("| Views | %d |\n", stats.Views))
+	sb.WriteString(fmt.Sprintf("| Indexes | %d |\n", stats.Indexes))
+	sb.WriteString(fmt.Sprintf("| Functions | %d |\n", stats.Functions))
+	sb.WriteString(fmt.Sprintf("| Sequences | %d |\n", stats.Sequences))
+	sb.WriteString(fmt.Sprintf("\n**Total size:** %s\n\n", FormatBytes(stats.TotalBytes)))
+
+	if len(stats.LargestTables) > 0 {
+		sb.WriteString("### Largest tables\n\n| Table | Size | Rows (est.) |\n|---|---:|---:|\n")
+		for _, size := range stats.LargestTables {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %d |\n", size.Name, FormatBytes(size.Bytes), size.Rows))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("### Foreign keys\n\n")
+	sb.WriteString(fmt.Sprintf("* %d foreign keys\n", stats.ForeignKeys))
+	sb.WriteString(fmt.Sprintf("* %d tables connected by foreign keys\n", stats.ConnectedTables))
+	sb.WriteString(fmt.Sprintf("* %d isolated tables\n", stats.IsolatedTables))
+
+	if len(stats.MostReferenced) > 0 {
+		sb.WriteString("\n| Most referenced table | Incoming foreign keys |\n|---|---:|\n")
+		for _, ref := range stats.MostReferenced {
+			sb.WriteString(fmt.Sprintf("| %s | %d |\n", ref.Name, ref.References))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
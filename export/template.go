@@ -0,0 +1,60 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// TemplateData is the data a custom export template is rendered with
+type TemplateData struct {
+	Schema    string
+	Tables    []*t.Table
+	Generated string // Time of the export, as 2006-01-02 15:04
+}
+
+// templateFuncs are the functions available to custom export templates, in
+// addition to the text/template built-ins
+var templateFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"replace": strings.ReplaceAll,
+	"pascal":  pascalCase,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// references returns the "table.column" a column references, "" if none
+	"references": func(col t.Column) string {
+		if ref, refColumn, ok := col.ForeignKeyTarget(); ok {
+			return ref + "." + refColumn
+		}
+		return ""
+	},
+}
+
+// ParseTemplate parses a custom export template file, a Go text/template
+// rendered with TemplateData
+func ParseTemplate(path string) (*template.Template, error) {
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// WriteTemplate renders the tables of a schema through a custom template
+func WriteTemplate(w io.Writer, tmpl *template.Template, schema string, tables []*t.Table) error {
+	data := TemplateData{Schema: schema, Tables: tables, Generated: time.Now().Format("2006-01-02 15:04")}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("error rendering template: %v", err)
+	}
+	return nil
+}
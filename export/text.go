@@ -0,0 +1,116 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// WriteSchemaText writes the structure of tables as plain text for the console,
+// a block per table separated by blank lines
+func WriteSchemaText(w io.Writer, _ string, tables []*t.Table) error {
+	var sb strings.Builder
+	for i, table := range tables {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		writeTableText(&sb, table)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeTableText writes the structure of a table, a section per kind of object
+func writeTableText(w io.Writer, table *t.Table) {
+	fmt.Fprintf(w, "%s.%s\n", table.Schema, table.Name)
+	if table.Comment != "" {
+		fmt.Fprintf(w, "  %s\n", table.Comment)
+	}
+	for _, prop := range table.Properties {
+		fmt.Fprintf(w, "  %s: %s\n", prop.Name, prop.Value)
+	}
+
+	fmt.Fprintln(w, "\nCOLUMNS")
+	writeRows(w, len(table.Columns), func(i int) []string {
+		col := table.Columns[i]
+		nullable := ""
+		if !col.Nullable {
+			nullable = "NOT NULL"
+		}
+		var extras []string
+		if col.DefaultValue.Valid {
+			extras = append(extras, "DEFAULT "+col.DefaultValue.String)
+		}
+		if col.IsPrimaryKey {
+			extras = append(extras, "PRIMARY KEY")
+		}
+		if col.ForeignKey.Valid {
+			extras = append(extras, "REFERENCES "+col.ForeignKey.String)
+		}
+		return []string{col.Name, col.Type, nullable, strings.Join(extras, " ")}
+	})
+
+	if len(table.Indexes) > 0 {
+		fmt.Fprintln(w, "\nINDEXES")
+		writeRows(w, len(table.Indexes), func(i int) []string {
+			idx := table.Indexes[i]
+			var extras []string
+			switch {
+			case idx.PrimaryKey:
+				extras = append(extras, "primary key")
+			case idx.Unique:
+				extras = append(extras, "unique")
+			}
+			if len(idx.Include) > 0 {
+				extras = append(extras, "INCLUDE ("+strings.Join(idx.Include, ", ")+")")
+			}
+			if idx.Predicate != "" {
+				extras = append(extras, "WHERE "+idx.Predicate)
+			}
+			return []string{idx.Name, "(" + strings.Join(idx.Columns, ", ") + ")", strings.Join(extras, " ")}
+		})
+	}
+
+	if len(table.Constraints) > 0 {
+		fmt.Fprintln(w, "\nCONSTRAINTS")
+		writeRows(w, len(table.Constraints), func(i int) []string {
+			return []string{table.Constraints[i].Name, table.Constraints[i].Definition}
+		})
+	}
+
+	if len(table.Triggers) > 0 {
+		fmt.Fprintln(w, "\nTRIGGERS")
+		writeRows(w, len(table.Triggers), func(i int) []string {
+			trigger := table.Triggers[i]
+			var extras []string
+			if trigger.Function != "" {
+				extras = append(extras, "EXECUTE "+trigger.Function)
+			}
+			if !trigger.Enabled {
+				extras = append(extras, "(disabled)")
+			}
+			return []string{trigger.Name, trigger.Timing + " " + strings.Join(trigger.Events, " OR "),
+				"FOR EACH " + trigger.ForEach, strings.Join(extras, " ")}
+		})
+	}
+}
+
+// writeRows prints indented rows of cells aligned in columns, without the
+// padding empty cells leave at the end of rows
+func writeRows(w io.Writer, n int, row func(i int) []string) {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	for i := range n {
+		fmt.Fprintln(tw, "  "+strings.Join(row(i), "\t"))
+	}
+	tw.Flush()
+
+	for _, line := range strings.SplitAfter(sb.String(), "\n") {
+		if line != "" {
+			fmt.Fprintln(w, strings.TrimRight(line, " \n"))
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// usageHeader is the header row of the table usage heatmap
+var usageHeader = []string{
+	"schema", "table", "heat", "score", "accesses", "accesses_per_day",
+	"seq_scans", "index_scans", "inserts", "updates", "deletes", "rows", "bytes",
+}
+
+// UsageWindowDays returns the length in days of the statistics window, 0 if its start is unknown
+func UsageWindowDays(usage *t.SchemaUsage) float64 {
+	if usage.Since.IsZero() {
+		return 0
+	}
+	return time.Since(usage.Since).Hours() / 24
+}
+
+// formatPerDay formats the accesses per day over the statistics window, "" if its length is unknown
+func formatPerDay(accesses int64, days float64) string {
+	if days <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(accesses)/days, 'f', 1, 64)
+}
+
+// WriteUsageCSV writes the rated table usage of a schema as a CSV heatmap, one
+// row per table, busiest first
+func WriteUsageCSV(w io.Writer, usage *t.SchemaUsage) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(usageHeader); err != nil {
+		return fmt.Errorf("error writing table usage: %v", err)
+	}
+
+	days := UsageWindowDays(usage)
+	for _, table := range usage.Tables {
+		record := []string{
+			usage.Schema, table.Name, table.Heat, strconv.FormatFloat(table.Score, 'f', 2, 64),
+			strconv.FormatInt(table.Accesses(), 10), formatPerDay(table.Accesses(), days),
+			strconv.FormatInt(table.SeqScans, 10), strconv.FormatInt(table.IndexScans, 10),
+			strconv.FormatInt(table.Inserts, 10), strconv.FormatInt(table.Updates, 10),
+			strconv.FormatInt(table.Deletes, 10), strconv.FormatInt(table.Rows, 10),
+			strconv.FormatInt(table.Bytes, 10),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("error writing table usage: %v", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("error writing table usage: %v", err)
+	}
+	return nil
+}
+
+// WriteUsageMarkdown writes the rated table usage of a schema as a Markdown report section
+func WriteUsageMarkdown(w io.Writer, usage *t.SchemaUsage) error {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("## Table usage: %s\n\n", usage.Schema))
+	if usage.Since.IsZero() {
+		sb.WriteString("Accesses since the statistics were collected.\n\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("Accesses since %s (%.0f days).\n\n",
+			usage.Since.Format("2006-01-02 15:04"), UsageWindowDays(usage)))
+	}
+
+	if len(usage.Tables) == 0 {
+		sb.WriteString("No table statistics.\n")
+	} else {
+		sb.WriteString("| Table | Heat | Accesses | Per day | Scans | Row changes | Size |\n|---|---|---:|---:|---:|---:|---:|\n")
+		days := UsageWindowDays(usage)
+		for _, table := range usage.Tables {
+			size := ""
+			if table.Bytes > 0 {
+				size = FormatBytes(table.Bytes)
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %d | %s | %d | %d | %s |\n",
+				markdownCell(table.Name), table.Heat, table.Accesses(), formatPerDay(table.Accesses(), days),
+				table.SeqScans+table.IndexScans, table.Inserts+table.Updates+table.Deletes, size))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
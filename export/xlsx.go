@@ -0,0 +1,263 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// overviewSheet is the name of the first sheet of the workbook, listing the tables
+const overviewSheet = "Overview"
+
+// maxSheetName is the longest sheet name Excel accepts
+const maxSheetName = 31
+
+// WriteWorkbook writes a schema as an Excel workbook: an overview sheet listing
+// the tables, linked to a sheet per table with its columns and indexes
+func WriteWorkbook(w io.Writer, schema string, tables []*t.Table) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	styles, err := newWorkbookStyles(f)
+	if err != nil {
+		return err
+	}
+	if err := f.SetSheetName("Sheet1", overviewSheet); err != nil {
+		return fmt.Errorf("error creating workbook: %v", err)
+	}
+
+	sheets := sheetNames(tables)
+	if err := writeOverviewSheet(f, styles, schema, tables, sheets); err != nil {
+		return err
+	}
+	for i, table := range tables {
+		if err := writeTableSheet(f, styles, table, sheets[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := f.Write(w); err != nil {
+		return fmt.Errorf("error writing workbook: %v", err)
+	}
+	return nil
+}
+
+// WriteTableWorkbook writes a single table as an Excel workbook
+func WriteTableWorkbook(w io.Writer, table *t.Table) error {
+	return WriteWorkbook(w, table.Schema, []*t.Table{table})
+}
+
+// workbookStyles are the cell styles of the workbook
+type workbookStyles struct {
+	title, header, link int
+}
+
+// newWorkbookStyles registers the cell styles in the workbook
+func newWorkbookStyles(f *excelize.File) (workbookStyles, error) {
+	var styles workbookStyles
+	var err error
+	if styles.title, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}}); err != nil {
+		return styles, fmt.Errorf("error creating workbook styles: %v", err)
+	}
+	styles.header, err = f.NewStyle(&excelize.Style{
+		Font:   &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:   excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"4472C4"}},
+		Border: []excelize.Border{{Type: "bottom", Color: "2F5597", Style: 1}},
+	})
+	if err != nil {
+		return styles, fmt.Errorf("error creating workbook styles: %v", err)
+	}
+	if styles.link, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "0563C1", Underline: "single"}}); err != nil {
+		return styles, fmt.Errorf("error creating workbook styles: %v", err)
+	}
+	return styles, nil
+}
+
+// writeOverviewSheet lists the tables, each linked to its sheet
+func writeOverviewSheet(f *excelize.File, styles workbookStyles, schema string, tables []*t.Table, sheets []string) error {
+	sheet := overviewSheet
+	rows := [][]any{
+		{"Schema " + schema},
+		{},
+		{"Table", "Columns", "Indexes", "Comment"},
+	}
+	for _, table := range tables {
+		summary, _, _ := strings.Cut(table.Comment, "\n")
+		rows = append(rows, []any{table.Name, len(table.Columns), len(table.Indexes), summary})
+	}
+	if err := setRows(f, sheet, rows); err != nil {
+		return err
+	}
+
+	if err := f.SetCellStyle(sheet, "A1", "A1", styles.title); err != nil {
+		return fmt.Errorf("error styling sheet %s: %v", sheet, err)
+	}
+	if err := f.SetCellStyle(sheet, "A3", "D3", styles.header); err != nil {
+		return fmt.Errorf("error styling sheet %s: %v", sheet, err)
+	}
+	for i := range tables {
+		cell := fmt.Sprintf("A%d", i+4)
+		if err := f.SetCellHyperLink(sheet, cell, sheetReference(sheets[i]), "Location"); err != nil {
+			return fmt.Errorf("error linking sheet %s: %v", sheets[i], err)
+		}
+		if err := f.SetCellStyle(sheet, cell, cell, styles.link); err != nil {
+			return fmt.Errorf("error styling sheet %s: %v", sheet, err)
+		}
+	}
+
+	return formatSheet(f, sheet, []float64{30, 10, 10, 60}, 3)
+}
+
+// writeTableSheet writes the columns and indexes of a table into a sheet of its own
+func writeTableSheet(f *excelize.File, styles workbookStyles, table *t.Table, sheet string) error {
+	if _, err := f.NewSheet(sheet); err != nil {
+		return fmt.Errorf("error creating sheet %s: %v", sheet, err)
+	}
+
+	rows := [][]any{
+		{table.Schema + "." + table.Name},
+		{table.Comment},
+		{"Column", "Type", "Nullable", "Default", "Primary key", "Foreign key", "Comment"},
+	}
+	for _, col := range table.Columns {
+		defaultValue := ""
+		if col.DefaultValue.Valid {
+			defaultValue = col.DefaultValue.String
+		}
+		foreignKey := ""
+		if ref, refColumn, ok := col.ForeignKeyTarget(); ok {
+			foreignKey = ref + "." + refColumn
+		}
+		rows = append(rows, []any{col.Name, col.Type, yesNo(col.Nullable), defaultValue, yesNo(col.IsPrimaryKey), foreignKey, col.Comment})
+	}
+
+	indexHeader := 0
+	if len(table.Indexes) > 0 {
+		rows = append(rows, []any{})
+		indexHeader = len(rows) + 1
+		rows = append(rows, []any{"Index", "Columns", "Unique", "Primary key"})
+		for _, idx := range table.Indexes {
+			rows = append(rows, []any{idx.Name, strings.Join(idx.Columns, ", "), yesNo(idx.Unique), yesNo(idx.PrimaryKey)})
+		}
+	}
+	if err := setRows(f, sheet, rows); err != nil {
+		return err
+	}
+
+	if err := f.SetCellStyle(sheet, "A1", "A1", styles.title); err != nil {
+		return fmt.Errorf("error styling sheet %s: %v", sheet, err)
+	}
+	if err := f.SetCellStyle(sheet, "A3", "G3", styles.header); err != nil {
+		return fmt.Errorf("error styling sheet %s: %v", sheet, err)
+	}
+	if indexHeader > 0 {
+		if err := f.SetCellStyle(sheet, fmt.Sprintf("A%d", indexHeader), fmt.Sprintf("D%d", indexHeader), styles.header); err != nil {
+			return fmt.Errorf("error styling sheet %s: %v", sheet, err)
+		}
+	}
+
+	// A link back to the overview, right of the title
+	if err := f.SetCellValue(sheet, "G1", "Back to overview"); err != nil {
+		return fmt.Errorf("error writing sheet %s: %v", sheet, err)
+	}
+	if err := f.SetCellHyperLink(sheet, "G1", sheetReference(overviewSheet), "Location"); err != nil {
+		return fmt.Errorf("error linking sheet %s: %v", overviewSheet, err)
+	}
+	if err := f.SetCellStyle(sheet, "G1", "G1", styles.link); err != nil {
+		return fmt.Errorf("error styling sheet %s: %v", sheet, err)
+	}
+
+	return formatSheet(f, sheet, []float64{30, 25, 10, 25, 12, 30, 50}, 3)
+}
+
+// setRows writes rows into a sheet, starting at A1
+func setRows(f *excelize.File, sheet string, rows [][]any) error {
+	for i, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		if err := f.SetSheetRow(sheet, fmt.Sprintf("A%d", i+1), &row); err != nil {
+			return fmt.Errorf("error writing sheet %s: %v", sheet, err)
+		}
+	}
+	return nil
+}
+
+// formatSheet sets the column widths of a sheet and freezes the rows up to the header
+func formatSheet(f *excelize.File, sheet string, widths []float64, headerRow int) error {
+	for i, width := range widths {
+		col, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return fmt.Errorf("error formatting sheet %s: %v", sheet, err)
+		}
+		if err := f.SetColWidth(sheet, col, col, width); err != nil {
+			return fmt.Errorf("error formatting sheet %s: %v", sheet, err)
+		}
+	}
+
+	err := f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      headerRow,
+		TopLeftCell: fmt.Sprintf("A%d", headerRow+1),
+		ActivePane:  "bottomLeft",
+	})
+	if err != nil {
+		return fmt.Errorf("error formatting sheet %s: %v", sheet, err)
+	}
+	return nil
+}
+
+// sheetNames returns a sheet name per table. Excel limits names to 31
+// characters, forbids some punctuation and ignores case, so names are cleaned,
+// shortened and numbered where they would clash.
+func sheetNames(tables []*t.Table) []string {
+	used := map[string]bool{strings.ToLower(overviewSheet): true}
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		base := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return '_'
+			}
+			return r
+		}, table.Name)
+		base = strings.Trim(base, "'")
+		if base == "" {
+			base = "table"
+		}
+
+		name := truncateRunes(base, maxSheetName)
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf("~%d", n)
+			name = truncateRunes(base, maxSheetName-len(suffix)) + suffix
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+// truncateRunes shortens a string to at most n runes
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// sheetReference returns the location of the first cell of a sheet, for links
+func sheetReference(sheet string) string {
+	return "'" + strings.ReplaceAll(sheet, "'", "''") + "'!A1"
+}
+
+// yesNo formats a flag for readers who do not know true and false
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
@@ -0,0 +1,33 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	t "github.com/carloberd/db-reader/types"
+	"gopkg.in/yaml.v3"
+)
+
+// WriteTableYAML writes a table as a TableDocument in YAML
+func WriteTableYAML(w io.Writer, table *t.Table) error {
+	return writeYAML(w, NewTableDocument(table))
+}
+
+// WriteSchemaYAML writes the tables of a schema as a SchemaDocument in YAML,
+// with the same fields and version as the JSON export
+func WriteSchemaYAML(w io.Writer, schema string, tables []*t.Table) error {
+	return writeYAML(w, NewSchemaDocument(schema, tables))
+}
+
+// writeYAML encodes a document with two-space indentation
+func writeYAML(w io.Writer, doc any) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("error writing YAML: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("error writing YAML: %v", err)
+	}
+	return nil
+}
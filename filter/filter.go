@@ -0,0 +1,55 @@
+package filter
+
+import (
+	"strings"
+)
+
+// ToolTables lists tables created by migration tools and frameworks rather than the application
+var ToolTables = []string{
+	"schema_migrations",           // golang-migrate, Rails, dbmate
+	"flyway_schema_history",       // Flyway
+	"schema_version",              // Flyway before 5.0
+	"alembic_version",             // Alembic
+	"goose_db_version",            // goose
+	"gorp_migrations",             // sql-migrate
+	"databasechangelog",           // Liquibase
+	"databasechangeloglock",       // Liquibase
+	"ar_internal_metadata",        // Rails
+	"knex_migrations",             // Knex
+	"knex_migrations_lock",        // Knex
+	"__efmigrationshistory",       // Entity Framework
+	"django_migrations",           // Django
+	"_prisma_migrations",          // Prisma
+	"sequelizemeta",               // Sequelize
+	"typeorm_metadata",            // TypeORM
+	"doctrine_migration_versions", // Doctrine
+}
+
+// IsToolTable reports whether a table name belongs to known migration or framework tooling
+func IsToolTable(name string) bool {
+	name = strings.ToLower(name)
+	for _, tool := range ToolTables {
+		if name == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// Tables returns the table names that are neither extension-owned nor tool tables
+func Tables(names []string, extensionTables []string) []string {
+	owned := make(map[string]bool, len(extensionTables))
+	for _, name := range extensionTables {
+		owned[name] = true
+	}
+
+	var filtered []string
+	for _, name := range names {
+		if owned[name] || IsToolTable(name) {
+			continue
+		}
+		filtered = append(filtered, name)
+	}
+
+	return filtered
+}
@@ -0,0 +1,71 @@
+package filter
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Selection picks tables by name or by name pattern (path.Match syntax).
+// An empty selection picks all tables.
+type Selection struct {
+	Tables []string
+	Match  []string
+}
+
+// Apply returns the selected names in their original order. Explicitly named
+// tables must exist.
+func (s Selection) Apply(names []string) ([]string, error) {
+	if len(s.Tables) == 0 && len(s.Match) == 0 {
+		return names, nil
+	}
+
+	for _, pattern := range s.Match {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid table pattern '%s': %v", pattern, err)
+		}
+	}
+
+	exists := make(map[string]bool, len(names))
+	for _, name := range names {
+		exists[name] = true
+	}
+
+	wanted := make(map[string]bool, len(s.Tables))
+	for _, name := range s.Tables {
+		if !exists[name] {
+			return nil, fmt.Errorf("table '%s' does not exist", name)
+		}
+		wanted[name] = true
+	}
+
+	var selected []string
+	for _, name := range names {
+		if wanted[name] || MatchesAny(s.Match, name) {
+			selected = append(selected, name)
+		}
+	}
+
+	return selected, nil
+}
+
+// MatchesAny reports whether a name matches one of the patterns
+func MatchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseList splits a comma separated list, dropping empty entries
+func ParseList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
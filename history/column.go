@@ -0,0 +1,160 @@
+package history
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/carloberd/db-reader/diff"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Kinds of column events
+const (
+	FirstSeen       = "first seen"
+	Appeared        = "appeared"
+	Renamed         = "renamed"
+	TypeChanged     = "type changed"
+	NullableChanged = "nullability changed"
+	DefaultChanged  = "default changed"
+)
+
+// ColumnEvent is a change of a column seen between two consecutive snapshots
+type ColumnEvent struct {
+	Taken time.Time // Snapshot in which the change was first seen
+	Kind  string
+	Old   string
+	New   string
+}
+
+// String describes the event on one line
+func (e ColumnEvent) String() string {
+	when := e.Taken.Local().Format("2006-01-02 15:04")
+	switch e.Kind {
+	case FirstSeen:
+		return fmt.Sprintf("%s  present in the first snapshot as %s", when, e.New)
+	case Appeared:
+		return fmt.Sprintf("%s  appeared as %s", when, e.New)
+	case Renamed:
+		return fmt.Sprintf("%s  renamed from %s to %s", when, e.Old, e.New)
+	}
+	return fmt.Sprintf("%s  %s: %s → %s", when, e.Kind, e.Old, e.New)
+}
+
+// ColumnHistory traces a column of a table back through snapshots given oldest first,
+// and returns its changes, oldest first. A column that disappears while another
+// column of the same type takes its place in the previous snapshot is taken to be
+// renamed. The first snapshot can only tell that the column existed by then.
+func ColumnHistory(snapshots []*diff.Schema, table, column string) []ColumnEvent {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	name := column
+	newer, newerIdx := findColumn(snapshots[len(snapshots)-1], table, name)
+	if newer == nil {
+		return nil
+	}
+
+	var events []ColumnEvent
+	for i := len(snapshots) - 1; i > 0; i-- {
+		taken := snapshots[i].Taken
+		older, olderIdx := findColumn(snapshots[i-1], table, name)
+
+		// Events are collected newest first and reversed at the end, so the
+		// changes within a snapshot are appended in reverse as well
+		var rename *ColumnEvent
+		if older == nil {
+			renamed := renamedFrom(snapshots[i-1], snapshots[i], table, newerIdx, newer.Type)
+			if renamed == nil {
+				events = append(events, ColumnEvent{Taken: taken, Kind: Appeared, New: describeColumn(newer)})
+				return reverse(events)
+			}
+			rename = &ColumnEvent{Taken: taken, Kind: Renamed, Old: renamed.Name, New: name}
+			name = renamed.Name
+			older, olderIdx = findColumn(snapshots[i-1], table, name)
+		}
+
+		if defaultValue(older) != defaultValue(newer) {
+			events = append(events, ColumnEvent{Taken: taken, Kind: DefaultChanged, Old: defaultValue(older), New: defaultValue(newer)})
+		}
+		if older.Nullable != newer.Nullable {
+			events = append(events, ColumnEvent{Taken: taken, Kind: NullableChanged,
+				Old: nullability(older.Nullable), New: nullability(newer.Nullable)})
+		}
+		if older.Type != newer.Type {
+			events = append(events, ColumnEvent{Taken: taken, Kind: TypeChanged, Old: older.Type, New: newer.Type})
+		}
+		if rename != nil {
+			events = append(events, *rename)
+		}
+
+		newer, newerIdx = older, olderIdx
+	}
+
+	// The column was there from the first snapshot on
+	events = append(events, ColumnEvent{Taken: snapshots[0].Taken, Kind: FirstSeen, New: describeColumn(newer)})
+	return reverse(events)
+}
+
+// findColumn returns a column of a table in a snapshot and its position, or nil
+func findColumn(schema *diff.Schema, table, column string) (*t.Column, int) {
+	for _, tbl := range schema.Tables {
+		if tbl.Name != table {
+			continue
+		}
+		for i := range tbl.Columns {
+			if tbl.Columns[i].Name == column {
+				return &tbl.Columns[i], i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// renamedFrom returns the column of the older snapshot that sits at the position
+// of a new column, has the same type and no longer exists in the newer snapshot
+func renamedFrom(older, newer *diff.Schema, table string, position int, typ string) *t.Column {
+	for _, tbl := range older.Tables {
+		if tbl.Name != table || position >= len(tbl.Columns) {
+			continue
+		}
+		candidate := &tbl.Columns[position]
+		if candidate.Type != typ {
+			return nil
+		}
+		if col, _ := findColumn(newer, table, candidate.Name); col != nil {
+			return nil
+		}
+		return candidate
+	}
+	return nil
+}
+
+// describeColumn returns the type and nullability of a column
+func describeColumn(col *t.Column) string {
+	return col.Type + " " + nullability(col.Nullable)
+}
+
+// nullability describes whether a column accepts NULLs
+func nullability(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+// defaultValue returns the default of a column, or "none"
+func defaultValue(col *t.Column) string {
+	if !col.DefaultValue.Valid {
+		return "none"
+	}
+	return col.DefaultValue.String
+}
+
+// reverse returns the events in the opposite order
+func reverse(events []ColumnEvent) []ColumnEvent {
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events
+}
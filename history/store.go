@@ -0,0 +1,118 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/carloberd/db-reader/diff"
+)
+
+// fileTimeFormat names snapshot files after the time they were taken, so they sort chronologically
+const fileTimeFormat = "20060102T150405Z"
+
+// Store keeps the snapshots of every schema in a directory of its own
+type Store struct {
+	Dir string
+}
+
+// Entry is a stored snapshot
+type Entry struct {
+	Path  string
+	Taken time.Time
+}
+
+// keyDir returns the directory of a schema key, with path separators and other
+// characters unsafe in file names replaced
+func (s Store) keyDir(key string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, key)
+	return filepath.Join(s.Dir, safe)
+}
+
+// Save stores a snapshot of a schema under its key
+func (s Store) Save(key string, schema *diff.Schema) error {
+	dir := s.keyDir(key)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("error creating snapshot directory: %v", err)
+	}
+
+	path := filepath.Join(dir, time.Now().UTC().Format(fileTimeFormat)+".json")
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating snapshot: %v", err)
+	}
+	defer f.Close()
+
+	if err := diff.WriteSnapshot(f, schema); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Entries returns the stored snapshots of a schema key, oldest first
+func (s Store) Entries(key string) ([]Entry, error) {
+	files, err := os.ReadDir(s.keyDir(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing snapshots: %v", err)
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), ".json")
+		if !ok || file.IsDir() {
+			continue
+		}
+		taken, err := time.Parse(fileTimeFormat, name)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{Path: filepath.Join(s.keyDir(key), file.Name()), Taken: taken})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Taken.Before(entries[j].Taken)
+	})
+	return entries, nil
+}
+
+// Due reports whether the latest snapshot of a schema key is older than the interval
+func (s Store) Due(key string, interval time.Duration) (bool, error) {
+	entries, err := s.Entries(key)
+	if err != nil {
+		return false, err
+	}
+	if len(entries) == 0 {
+		return true, nil
+	}
+	return time.Since(entries[len(entries)-1].Taken) >= interval, nil
+}
+
+// Load reads every stored snapshot of a schema key, oldest first
+func (s Store) Load(key string) ([]*diff.Schema, error) {
+	entries, err := s.Entries(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*diff.Schema
+	for _, entry := range entries {
+		snap, err := diff.ReadSnapshot(entry.Path)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,303 @@
+package hive
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beltran/gohive"
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// connectTimeout limits how long connecting waits for the server to answer
+const connectTimeout = 10 * time.Second
+
+// HiveConnector implements the DatabaseConnector interface for HiveServer2 and
+// the Spark Thrift Server, which speak the same protocol. Hive databases are
+// the schemas, and tables are described with DESCRIBE FORMATTED.
+type HiveConnector struct {
+	sqlutil.QueryLog
+
+	conn *gohive.Connection
+	mu   sync.Mutex // Serializes statements, as the session is not safe for concurrent use
+}
+
+// Connect opens a session on the server. Session settings are Hive or Spark
+// configuration values, except auth, which selects the authentication (NONE
+// by default, NOSASL, LDAP or KERBEROS), transportMode (binary or http),
+// httpPath and service, the Kerberos service name.
+func (hc *HiveConnector) Connect(params t.ConnectionParams) error {
+	port, err := strconv.Atoi(params.Port)
+	if err != nil {
+		return fmt.Errorf("invalid port '%s'", params.Port)
+	}
+
+	cfg := gohive.NewConnectConfiguration()
+	cfg.Username = params.User
+	cfg.Password = params.Password
+	cfg.Database = params.Database
+	cfg.ConnectTimeout = connectTimeout
+	cfg.HiveConfiguration = make(map[string]string)
+	auth := "NONE"
+	for key, value := range params.Settings {
+		switch key {
+		case "auth":
+			auth = value
+		case "transportMode":
+			cfg.TransportMode = value
+		case "httpPath":
+			cfg.HTTPPath = value
+		case "service":
+			cfg.Service = value
+		default:
+			cfg.HiveConfiguration[key] = value
+		}
+	}
+
+	conn, err := gohive.Connect(params.Host, port, auth, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	hc.conn = conn
+	return nil
+}
+
+// Disconnect closes the session
+func (hc *HiveConnector) Disconnect() error {
+	if hc.conn != nil {
+		hc.mu.Lock()
+		defer hc.mu.Unlock()
+		err := hc.conn.Close()
+		hc.conn = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetSchemas returns the databases on the server
+func (hc *HiveConnector) GetSchemas() ([]string, error) {
+	if hc.conn == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Hive names the column database_name, and Spark namespace or databaseName
+	res, err := hc.query("SHOW DATABASES")
+	if err != nil {
+		return nil, fmt.Errorf("error querying schemas: %v", err)
+	}
+
+	var schemas []string
+	for _, row := range res.rows {
+		schemas = append(schemas, row[0])
+	}
+	sort.Strings(schemas)
+
+	return schemas, nil
+}
+
+// GetTables returns a list of tables in the specified database. Views are left
+// out when the server can list them, which needs Hive 2.2 or Spark 3.
+func (hc *HiveConnector) GetTables(schema string) ([]string, error) {
+	if hc.conn == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	res, err := hc.query("SHOW TABLES IN " + quoteIdentifier(schema))
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+
+	views := make(map[string]bool)
+	if viewRes, err := hc.query("SHOW VIEWS IN " + quoteIdentifier(schema)); err == nil {
+		for _, row := range viewRes.rows {
+			views[viewRes.value(row, "tab_name", "viewName")] = true
+		}
+	}
+
+	var tables []string
+	for _, row := range res.rows {
+		// Spark also lists the temporary views of the session
+		if res.value(row, "isTemporary") == "true" {
+			continue
+		}
+		name := res.value(row, "tab_name", "tableName")
+		if !views[name] {
+			tables = append(tables, name)
+		}
+	}
+	sort.Strings(tables)
+
+	return tables, nil
+}
+
+// GetTableStructure returns the structure of the specified table. Partition
+// columns follow the other columns and are also given as a table property,
+// along with the table type, storage format, location and owner.
+func (hc *HiveConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if hc.conn == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Check if the table exists
+	tables, err := hc.GetTables(schema)
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	if i := sort.SearchStrings(tables, tableName); i == len(tables) || tables[i] != tableName {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	info, err := hc.describe(schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &t.Table{Name: tableName, Schema: schema, Columns: info.columns, Comment: info.comment}
+	if len(info.partitionedBy) > 0 {
+		table.Properties = append(table.Properties, t.Property{Name: "Partitioned by", Value: strings.Join(info.partitionedBy, ", ")})
+	}
+	for _, prop := range []struct{ name, value string }{
+		{"Table type", info.detail("Table Type", "Type")},
+		{"Format", info.detail("Provider", "InputFormat")},
+		{"Location", info.details["Location"]},
+		{"Owner", info.details["Owner"]},
+	} {
+		if prop.value != "" {
+			table.Properties = append(table.Properties, t.Property{Name: prop.name, Value: prop.value})
+		}
+	}
+
+	return table, nil
+}
+
+// tableInfo is what DESCRIBE FORMATTED reports about a table
+type tableInfo struct {
+	columns       []t.Column
+	partitionedBy []string
+	comment       string
+	details       map[string]string // Detailed table information by key, without the colon Hive adds
+	parameters    map[string]string // Hive table parameters, e.g. numRows
+}
+
+// detail returns the first of the named details that is set. Hive and Spark
+// name some differently, e.g. Hive gives the table type as Table Type and
+// Spark as Type, and only Spark has the Provider of data source tables.
+func (info *tableInfo) detail(keys ...string) string {
+	for _, key := range keys {
+		if value := info.details[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// describe reads the columns, partitioning and details of a table from
+// DESCRIBE FORMATTED. The output has sections introduced by "#" rows: Hive lists
+// the partition columns only in their section, Spark also with the columns.
+func (hc *HiveConnector) describe(schema, tableName string) (*tableInfo, error) {
+	res, err := hc.query("DESCRIBE FORMATTED " + quoteQualified(schema, tableName))
+	if err != nil {
+		return nil, fmt.Errorf("error describing table: %v", err)
+	}
+	return parseDescription(res), nil
+}
+
+// parseDescription reads the sections of DESCRIBE FORMATTED output
+func parseDescription(res *result) *tableInfo {
+	info := &tableInfo{details: make(map[string]string), parameters: make(map[string]string)}
+	columns := make(map[string]bool)
+	section := "columns"
+	for _, row := range res.rows {
+		if len(row) < 3 {
+			continue
+		}
+		name, value, comment := strings.TrimSpace(row[0]), strings.TrimSpace(row[1]), strings.TrimSpace(row[2])
+
+		if strings.HasPrefix(name, "#") {
+			switch name {
+			case "# col_name":
+				// Header of the column lists
+			case "# Partition Information":
+				section = "partitions"
+			case "# Partitioning":
+				section = "transforms"
+			case "# Detailed Table Information", "# Storage Information":
+				section = "details"
+			default:
+				section = ""
+			}
+			continue
+		}
+
+		switch section {
+		case "columns", "partitions":
+			if name == "" {
+				continue
+			}
+			if section == "partitions" {
+				info.partitionedBy = append(info.partitionedBy, name)
+			}
+			if !columns[name] {
+				columns[name] = true
+				info.columns = append(info.columns, t.Column{Name: name, Type: value, Nullable: true, Comment: comment})
+			}
+		case "transforms":
+			// Spark data source tables list partition transforms, e.g. Part 0 | days(ts)
+			if value != "" {
+				info.partitionedBy = append(info.partitionedBy, value)
+			}
+		case "details":
+			if name == "" {
+				// Hive table parameters are listed in the value and comment columns
+				if value != "" {
+					info.parameters[value] = comment
+				}
+				continue
+			}
+			info.details[strings.TrimSuffix(name, ":")] = value
+		}
+	}
+
+	info.comment = info.parameters["comment"]
+	if comment := info.details["Comment"]; comment != "" {
+		info.comment = comment
+	}
+
+	return info
+}
+
+// quoteIdentifier quotes a Hive identifier
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteQualified returns a quoted database-qualified name for use in queries
+func quoteQualified(schema, name string) string {
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// NewHiveConnector creates a connector for HiveServer2 and the Spark Thrift Server
+func NewHiveConnector() t.DatabaseConnector {
+	return &HiveConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverHive, NewHiveConnector, t.ConnectorInfo{
+		Title:  "Hive / Spark SQL",
+		Fields: t.ServerFields,
+		Defaults: func(p *t.ConnectionParams) {
+			// The databases of Hive are its schemas
+			p.DefaultServer("10000", "hive")
+			p.Schema = cmp.Or(p.Schema, p.Database)
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+)
+
+// result holds the rows of a statement, with the values formatted as text
+type result struct {
+	columns []string
+	rows    [][]string
+}
+
+// value returns the value of the first of the named columns in the result
+func (r *result) value(row []string, names ...string) string {
+	for _, name := range names {
+		for i, column := range r.columns {
+			if column == name {
+				return row[i]
+			}
+		}
+	}
+	return ""
+}
+
+// query runs a catalog statement, recording it in the query log, and fetches all its rows
+func (hc *HiveConnector) query(query string) (*result, error) {
+	return hc.queryContext(context.Background(), query)
+}
+
+// queryContext runs a statement that can be cancelled through the context
+func (hc *HiveConnector) queryContext(ctx context.Context, query string) (*result, error) {
+	hc.LogQuery(query, nil)
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	cursor := hc.conn.Cursor()
+	defer cursor.Close()
+	cursor.Exec(ctx, query)
+	if cursor.Err != nil {
+		return nil, cursor.Err
+	}
+
+	res := &result{}
+	for _, column := range cursor.Description() {
+		res.columns = append(res.columns, column[0])
+	}
+	if cursor.Err != nil {
+		return nil, cursor.Err
+	}
+	for cursor.HasMore(ctx) {
+		if cursor.Err != nil {
+			return nil, cursor.Err
+		}
+		values := cursor.RowMap(ctx)
+		if cursor.Err != nil {
+			return nil, cursor.Err
+		}
+		row := make([]string, len(res.columns))
+		for i, column := range res.columns {
+			if value := values[column]; value != nil {
+				row[i] = fmt.Sprint(value)
+			}
+		}
+		res.rows = append(res.rows, row)
+	}
+	if cursor.Err != nil {
+		return nil, cursor.Err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,61 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EstimateRowCount returns the row count gathered by ANALYZE TABLE, which Hive
+// keeps in the numRows table parameter and Spark in the table statistics.
+// Tables never analyzed give -1.
+func (hc *HiveConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if hc.conn == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	info, err := hc.describe(schema, tableName)
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+
+	// Hive reports -1 when the statistics are missing
+	if rows, err := strconv.ParseInt(info.parameters["numRows"], 10, 64); err == nil && rows >= 0 {
+		return rows, nil
+	}
+	// Spark statistics read e.g. "8192 bytes, 100 rows"
+	for _, part := range strings.Split(info.details["Statistics"], ",") {
+		if count, ok := strings.CutSuffix(strings.TrimSpace(part), " rows"); ok {
+			if rows, err := strconv.ParseInt(count, 10, 64); err == nil {
+				return rows, nil
+			}
+		}
+	}
+	return -1, nil
+}
+
+// CountRows returns the exact number of rows in a table. The count runs as a
+// job on the cluster, and other statements wait for it to finish.
+func (hc *HiveConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if hc.conn == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	res, err := hc.queryContext(ctx, "SELECT COUNT(*) FROM "+quoteQualified(schema, tableName))
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	if len(res.rows) != 1 {
+		return 0, fmt.Errorf("error counting rows: no result")
+	}
+
+	count, err := strconv.ParseInt(res.rows[0][0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
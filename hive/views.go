@@ -0,0 +1,48 @@
+package hive
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViews returns the views and materialized views of the database, with
+// their columns and SELECT statements. Listing them needs Hive 2.2 or Spark 3.
+func (hc *HiveConnector) GetViews(schema string) ([]t.View, error) {
+	if hc.conn == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	res, err := hc.query("SHOW VIEWS IN " + quoteIdentifier(schema))
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+
+	var names []string
+	for _, row := range res.rows {
+		// Spark also lists the temporary views of the session
+		if res.value(row, "isTemporary") != "true" {
+			names = append(names, res.value(row, "tab_name", "viewName"))
+		}
+	}
+	sort.Strings(names)
+
+	views := make([]t.View, 0, len(names))
+	for _, name := range names {
+		info, err := hc.describe(schema, name)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, t.View{
+			Name:         name,
+			Schema:       schema,
+			Columns:      info.columns,
+			Definition:   info.detail("View Original Text", "View Text"),
+			Materialized: strings.EqualFold(info.detail("Table Type", "Type"), "MATERIALIZED_VIEW"),
+			Comment:      info.comment,
+		})
+	}
+	return views, nil
+}
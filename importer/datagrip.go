@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carloberd/db-reader/config"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// dataGripProject is the relevant part of a dataSources.xml or dataSources.local.xml file
+type dataGripProject struct {
+	Components []struct {
+		DataSources []struct {
+			Name      string `xml:"name,attr"`
+			UUID      string `xml:"uuid,attr"`
+			DriverRef string `xml:"driver-ref"`
+			JDBCURL   string `xml:"jdbc-url"`
+			UserName  string `xml:"user-name"`
+		} `xml:"data-source"`
+	} `xml:"component"`
+}
+
+// readDataGrip reads the PostgreSQL data sources of a DataGrip project. User names
+// are taken from dataSources.local.xml next to it when present; passwords are kept
+// in the IDE's own credential store and are not imported.
+func readDataGrip(path string) ([]config.Profile, error) {
+	project, err := readDataGripFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]string)
+	local, err := readDataGripFile(filepath.Join(filepath.Dir(path), "dataSources.local.xml"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if local != nil {
+		for _, component := range local.Components {
+			for _, ds := range component.DataSources {
+				users[ds.UUID] = ds.UserName
+			}
+		}
+	}
+
+	var profiles []config.Profile
+	for _, component := range project.Components {
+		for _, ds := range component.DataSources {
+			if ds.DriverRef != "postgresql" && !strings.HasPrefix(ds.JDBCURL, "jdbc:postgresql:") {
+				continue
+			}
+
+			target, err := parseJDBC(ds.JDBCURL)
+			if err != nil {
+				continue
+			}
+
+			user := target.User
+			if u := users[ds.UUID]; u != "" {
+				user = u
+			}
+			if ds.UserName != "" {
+				user = ds.UserName
+			}
+
+			profiles = append(profiles, config.Profile{
+				Name: profileName(ds.Name, user, target.Host, target.Port, target.Database),
+				Params: t.ConnectionParams{
+					Host:     target.Host,
+					Port:     target.Port,
+					User:     user,
+					Database: target.Database,
+					Schema:   "public",
+				},
+			})
+		}
+	}
+
+	return profiles, nil
+}
+
+// readDataGripFile parses a DataGrip data sources file
+func readDataGripFile(path string) (*dataGripProject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error reading DataGrip data sources: %v", err)
+	}
+
+	var project dataGripProject
+	if err := xml.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("error parsing DataGrip data sources: %v", err)
+	}
+	return &project, nil
+}
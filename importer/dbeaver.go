@@ -0,0 +1,143 @@
+package importer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/carloberd/db-reader/config"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// dbeaverCredentialsKey is the fixed key DBeaver uses to obscure credentials-config.json
+const dbeaverCredentialsKey = "babb4a9f774ab853c96c2d653dfe544a"
+
+// dbeaverDataSources is the relevant part of DBeaver's data-sources.json
+type dbeaverDataSources struct {
+	Connections map[string]struct {
+		Provider      string `json:"provider"`
+		Name          string `json:"name"`
+		Configuration struct {
+			Host     string `json:"host"`
+			Port     string `json:"port"`
+			Database string `json:"database"`
+			User     string `json:"user"`
+			Password string `json:"password"`
+			URL      string `json:"url"`
+		} `json:"configuration"`
+	} `json:"connections"`
+}
+
+// dbeaverCredentials maps connection ids to the credentials stored for them
+type dbeaverCredentials map[string]struct {
+	Connection struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	} `json:"#connection"`
+}
+
+// readDBeaver reads the PostgreSQL connections of a DBeaver workspace
+func readDBeaver(path string) ([]config.Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading DBeaver data sources: %v", err)
+	}
+
+	var sources dbeaverDataSources
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("error parsing DBeaver data sources: %v", err)
+	}
+
+	// Saved credentials live in a separate file; connections without it simply have no password
+	credentials, err := readDBeaverCredentials(filepath.Join(filepath.Dir(path), "credentials-config.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []config.Profile
+	for id, conn := range sources.Connections {
+		if conn.Provider != "postgresql" {
+			continue
+		}
+
+		cfg := conn.Configuration
+		params := t.ConnectionParams{
+			Host:     cfg.Host,
+			Port:     cfg.Port,
+			User:     cfg.User,
+			Password: cfg.Password,
+			Database: cfg.Database,
+			Schema:   "public",
+		}
+		if params.Host == "" && cfg.URL != "" {
+			if target, err := parseJDBC(cfg.URL); err == nil {
+				params.Host, params.Port, params.Database = target.Host, target.Port, target.Database
+			}
+		}
+		if params.Port == "" {
+			params.Port = "5432"
+		}
+		if cred, ok := credentials[id]; ok {
+			if cred.Connection.User != "" {
+				params.User = cred.Connection.User
+			}
+			if cred.Connection.Password != "" {
+				params.Password = cred.Connection.Password
+			}
+		}
+
+		profiles = append(profiles, config.Profile{
+			Name:   profileName(conn.Name, params.User, params.Host, params.Port, params.Database),
+			Params: params,
+		})
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles, nil
+}
+
+// readDBeaverCredentials decrypts DBeaver's credentials-config.json, which is
+// AES-CBC encrypted with a fixed key and the IV in the first block
+func readDBeaverCredentials(path string) (dbeaverCredentials, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading DBeaver credentials: %v", err)
+	}
+
+	key, _ := hex.DecodeString(dbeaverCredentialsKey)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting DBeaver credentials: %v", err)
+	}
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("error decrypting DBeaver credentials: unexpected file size")
+	}
+
+	iv, ciphertext := data[:aes.BlockSize], data[aes.BlockSize:]
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	// Strip the PKCS#7 padding
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, fmt.Errorf("error decrypting DBeaver credentials: invalid padding")
+	}
+	plaintext = plaintext[:len(plaintext)-padding]
+
+	var credentials dbeaverCredentials
+	if err := json.Unmarshal(plaintext, &credentials); err != nil {
+		return nil, fmt.Errorf("error parsing DBeaver credentials: %v", err)
+	}
+	return credentials, nil
+}
@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/carloberd/db-reader/config"
+)
+
+// Source is a tool whose connection definitions can be imported
+type Source string
+
+const (
+	// DBeaver reads data-sources.json, with passwords from credentials-config.json next to it
+	DBeaver Source = "DBeaver"
+
+	// PgAdmin reads a servers.json file exported from pgAdmin
+	PgAdmin Source = "pgAdmin"
+
+	// DataGrip reads the dataSources.xml of a DataGrip or IntelliJ project
+	DataGrip Source = "DataGrip"
+
+	// Pgpass reads a libpq password file
+	Pgpass Source = "pgpass"
+)
+
+// Sources lists the supported import sources
+var Sources = []Source{DBeaver, PgAdmin, DataGrip, Pgpass}
+
+// Import reads the PostgreSQL connections defined in a file of the source tool.
+// Connections to other database systems are skipped.
+func Import(source Source, path string) ([]config.Profile, error) {
+	switch source {
+	case DBeaver:
+		return readDBeaver(path)
+	case PgAdmin:
+		return readPgAdmin(path)
+	case DataGrip:
+		return readDataGrip(path)
+	case Pgpass:
+		return readPgpass(path)
+	default:
+		return nil, fmt.Errorf("unknown import source '%s'", source)
+	}
+}
+
+// DefaultPath returns the usual location of the connection file of a source,
+// or an empty string if there is none
+func DefaultPath(source Source) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	switch source {
+	case DBeaver:
+		var dataDir string
+		switch runtime.GOOS {
+		case "windows":
+			dataDir = filepath.Join(os.Getenv("APPDATA"), "DBeaverData")
+		case "darwin":
+			dataDir = filepath.Join(home, "Library", "DBeaverData")
+		default:
+			dataDir = filepath.Join(home, ".local", "share", "DBeaverData")
+		}
+		return filepath.Join(dataDir, "workspace6", "General", ".dbeaver", "data-sources.json")
+
+	case Pgpass:
+		if path := os.Getenv("PGPASSFILE"); path != "" {
+			return path
+		}
+		if runtime.GOOS == "windows" {
+			return filepath.Join(os.Getenv("APPDATA"), "postgresql", "pgpass.conf")
+		}
+		return filepath.Join(home, ".pgpass")
+
+	default:
+		return ""
+	}
+}
+
+// profileName names an imported connection, falling back to its address
+func profileName(name, user, host, port, database string) string {
+	if name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s@%s:%s/%s", user, host, port, database)
+}
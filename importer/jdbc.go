@@ -0,0 +1,49 @@
+package importer
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// jdbcTarget is the connection target described by a JDBC URL
+type jdbcTarget struct {
+	Host     string
+	Port     string
+	Database string
+	User     string
+}
+
+// parseJDBC parses a jdbc:postgresql://host[:port]/database[?user=...] URL
+func parseJDBC(jdbcURL string) (*jdbcTarget, error) {
+	rest, ok := strings.CutPrefix(jdbcURL, "jdbc:postgresql:")
+	if !ok {
+		return nil, fmt.Errorf("not a PostgreSQL JDBC URL: %s", jdbcURL)
+	}
+
+	u, err := url.Parse("postgresql:" + rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JDBC URL %s: %v", jdbcURL, err)
+	}
+
+	// With several hosts for failover, the first one is used
+	host, _, _ := strings.Cut(u.Host, ",")
+	target := &jdbcTarget{
+		Host:     host,
+		Port:     "5432",
+		Database: strings.TrimPrefix(u.Path, "/"),
+		User:     u.Query().Get("user"),
+	}
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		target.Host, target.Port = h, p
+	}
+	if target.Host == "" {
+		target.Host = "localhost"
+	}
+	if target.Database == "" {
+		target.Database = "postgres"
+	}
+
+	return target, nil
+}
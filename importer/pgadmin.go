@@ -0,0 +1,70 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/carloberd/db-reader/config"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// pgAdminServers is the format of pgAdmin's server export (File > Export Servers).
+// pgAdmin never exports passwords.
+type pgAdminServers struct {
+	Servers map[string]struct {
+		Name          string `json:"Name"`
+		Group         string `json:"Group"`
+		Host          string `json:"Host"`
+		Port          int    `json:"Port"`
+		MaintenanceDB string `json:"MaintenanceDB"`
+		Username      string `json:"Username"`
+	} `json:"Servers"`
+}
+
+// readPgAdmin reads the servers of a pgAdmin export file
+func readPgAdmin(path string) ([]config.Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading pgAdmin servers: %v", err)
+	}
+
+	var export pgAdminServers
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("error parsing pgAdmin servers: %v", err)
+	}
+
+	var profiles []config.Profile
+	for _, server := range export.Servers {
+		params := t.ConnectionParams{
+			Host:     server.Host,
+			Port:     strconv.Itoa(server.Port),
+			User:     server.Username,
+			Database: server.MaintenanceDB,
+			Schema:   "public",
+		}
+		if server.Port == 0 {
+			params.Port = "5432"
+		}
+		if params.Database == "" {
+			params.Database = "postgres"
+		}
+
+		name := server.Name
+		if name != "" && server.Group != "" && server.Group != "Servers" {
+			name = server.Group + "/" + name
+		}
+
+		profiles = append(profiles, config.Profile{
+			Name:   profileName(name, params.User, params.Host, params.Port, params.Database),
+			Params: params,
+		})
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles, nil
+}
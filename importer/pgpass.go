@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/carloberd/db-reader/config"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// readPgpass reads the entries of a libpq password file. Entries with a wildcard
+// host or user cannot be turned into a connection and are skipped.
+func readPgpass(path string) ([]config.Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening password file: %v", err)
+	}
+	defer f.Close()
+
+	var profiles []config.Profile
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := splitPgpassLine(line)
+		if len(fields) != 5 {
+			continue
+		}
+		host, port, database, user, password := fields[0], fields[1], fields[2], fields[3], fields[4]
+		if host == "*" || user == "*" {
+			continue
+		}
+		if port == "*" {
+			port = "5432"
+		}
+		if database == "*" {
+			database = "postgres"
+		}
+
+		profiles = append(profiles, config.Profile{
+			Name: profileName("", user, host, port, database),
+			Params: t.ConnectionParams{
+				Host:     host,
+				Port:     port,
+				User:     user,
+				Password: password,
+				Database: database,
+				Schema:   "public",
+			},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading password file: %v", err)
+	}
+
+	return profiles, nil
+}
+
+// splitPgpassLine splits a password file line at colons, honouring \: and \\ escapes
+func splitPgpassLine(line string) []string {
+	var fields []string
+	var field strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line):
+			i++
+			field.WriteByte(line[i])
+		case line[i] == ':':
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteByte(line[i])
+		}
+	}
+	return append(fields, field.String())
+}
@@ -0,0 +1,51 @@
+package inspector
+
+import (
+	"fmt"
+
+	"github.com/carloberd/db-reader/diff"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Schema is a set of tables to compare
+type Schema = diff.Schema
+
+// DiffResult holds the typed differences between two schemas
+type DiffResult = diff.Result
+
+// Ignore lists the differences left out of a comparison
+type Ignore = diff.Ignore
+
+// Diff compares two schemas, for tools checking schema drift without parsing command output
+func Diff(a, b *Schema) *DiffResult {
+	return diff.Diff(a, b, Ignore{})
+}
+
+// DiffIgnoring compares two schemas, leaving out the differences selected by the ignore rules
+func DiffIgnoring(a, b *Schema, ignore Ignore) *DiffResult {
+	return diff.Diff(a, b, ignore)
+}
+
+// LoadSchema reads the structure of all tables in the given schemas of a connected database
+func LoadSchema(connector t.DatabaseConnector, name string, schemas ...string) (*Schema, error) {
+	s := &Schema{Name: name}
+	for _, schema := range schemas {
+		names, err := connector.GetTables(schema)
+		if err != nil {
+			return nil, err
+		}
+		for _, tableName := range names {
+			table, err := connector.GetTableStructure(schema, tableName)
+			if err != nil {
+				return nil, fmt.Errorf("error loading table %s.%s: %v", schema, tableName, err)
+			}
+			s.Tables = append(s.Tables, table)
+		}
+	}
+	return s, nil
+}
+
+// ReadSnapshot loads a schema snapshot written by the snapshot command
+func ReadSnapshot(path string) (*Schema, error) {
+	return diff.ReadSnapshot(path)
+}
@@ -0,0 +1,195 @@
+package inspectortest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	t "github.com/carloberd/db-reader/types"
+	"github.com/carloberd/db-reader/validate"
+)
+
+// Schema is a schema of a database under test. Tables are read when first
+// asserted on and kept for the following assertions.
+type Schema struct {
+	Name      string
+	connector t.DatabaseConnector
+
+	mu     sync.Mutex
+	names  []string
+	tables map[string]*t.Table
+}
+
+// Connect connects to a database for the duration of a test, failing the test
+// if it cannot. The connector of the driver must be registered, by importing
+// its package, e.g. _ "github.com/carloberd/db-reader/postgresql".
+func Connect(tb testing.TB, params t.ConnectionParams) t.DatabaseConnector {
+	tb.Helper()
+
+	connector, err := t.NewConnector(params.Driver)
+	if err != nil {
+		tb.Fatalf("inspectortest: %v", err)
+	}
+	params.ApplyDefaults()
+	if err := connector.Connect(params); err != nil {
+		tb.Fatalf("inspectortest: error connecting to %s: %v", params.Database, err)
+	}
+	tb.Cleanup(func() { connector.Disconnect() })
+	return connector
+}
+
+// Load returns the schema of a connected database to assert on, failing the
+// test if its tables cannot be listed
+func Load(tb testing.TB, connector t.DatabaseConnector, schema string) *Schema {
+	tb.Helper()
+
+	names, err := connector.GetTables(schema)
+	if err != nil {
+		tb.Fatalf("inspectortest: error listing tables of %s: %v", schema, err)
+	}
+	return &Schema{Name: schema, connector: connector, names: names, tables: make(map[string]*t.Table)}
+}
+
+// HasTable reports whether the schema has a table
+func (s *Schema) HasTable(name string) bool {
+	return slices.Contains(s.names, name)
+}
+
+// table returns the structure of a table, nil if the schema has no such table
+func (s *Schema) table(name string) (*t.Table, error) {
+	if !s.HasTable(name) {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if table, ok := s.tables[name]; ok {
+		return table, nil
+	}
+	table, err := s.connector.GetTableStructure(s.Name, name)
+	if err != nil {
+		return nil, fmt.Errorf("error loading table %s: %v", name, err)
+	}
+	s.tables[name] = table
+	return table, nil
+}
+
+// RequireTable fails the test now if the schema has no such table, and
+// returns its structure for further checks
+func RequireTable(tb testing.TB, s *Schema, name string) *t.Table {
+	tb.Helper()
+
+	table, err := s.table(name)
+	if err != nil {
+		tb.Fatalf("inspectortest: %v", err)
+	}
+	if table == nil {
+		tb.Fatalf("table %s.%s is missing", s.Name, name)
+	}
+	return table
+}
+
+// RequireNoTable fails the test if the schema has the table, e.g. one a
+// migration drops
+func RequireNoTable(tb testing.TB, s *Schema, name string) {
+	tb.Helper()
+	if s.HasTable(name) {
+		tb.Errorf("table %s.%s exists, expected it not to", s.Name, name)
+	}
+}
+
+// ColumnOption is a property RequireColumn checks a column has
+type ColumnOption struct {
+	description string
+	holds       func(col t.Column) bool
+}
+
+// Column options for RequireColumn
+var (
+	NotNull    = ColumnOption{"NOT NULL", func(col t.Column) bool { return !col.Nullable }}
+	Nullable   = ColumnOption{"nullable", func(col t.Column) bool { return col.Nullable }}
+	PrimaryKey = ColumnOption{"part of the primary key", func(col t.Column) bool { return col.IsPrimaryKey }}
+	NoDefault  = ColumnOption{"without default", func(col t.Column) bool { return !col.DefaultValue.Valid }}
+)
+
+// Default requires the default expression of a column, as the database reports it
+func Default(expr string) ColumnOption {
+	return ColumnOption{"with default " + expr, func(col t.Column) bool {
+		return col.DefaultValue.Valid && col.DefaultValue.String == expr
+	}}
+}
+
+// References requires a column to be a foreign key to a column of a table
+func References(table, column string) ColumnOption {
+	return ColumnOption{"referencing " + table + "." + column, func(col t.Column) bool {
+		ref, refColumn, ok := col.ForeignKeyTarget()
+		return ok && ref == table && refColumn == column
+	}}
+}
+
+// Comment requires the comment of a column
+func Comment(text string) ColumnOption {
+	return ColumnOption{"with comment '" + text + "'", func(col t.Column) bool { return col.Comment == text }}
+}
+
+// RequireColumn fails the test if a table lacks a column of a type, "" for
+// any, or the column lacks one of the options, e.g.
+//
+//	inspectortest.RequireColumn(t, schema, "users", "email", "varchar(255)", inspectortest.NotNull)
+//
+// Types are compared ignoring case and the usual aliases, so "int8" matches
+// "bigint". A missing table or column stops the test.
+func RequireColumn(tb testing.TB, s *Schema, table, column, typ string, opts ...ColumnOption) {
+	tb.Helper()
+
+	col, ok := findColumn(RequireTable(tb, s, table), column)
+	if !ok {
+		tb.Fatalf("column %s.%s is missing", table, column)
+	}
+	if typ != "" && !validate.SameType(typ, col.Type) {
+		tb.Errorf("column %s.%s has type %s, expected %s", table, column, col.Type, typ)
+	}
+	for _, opt := range opts {
+		if !opt.holds(col) {
+			tb.Errorf("column %s.%s is not %s", table, column, opt.description)
+		}
+	}
+}
+
+// RequireNoColumn fails the test if a table has a column, e.g. one a
+// migration drops
+func RequireNoColumn(tb testing.TB, s *Schema, table, column string) {
+	tb.Helper()
+	if _, ok := findColumn(RequireTable(tb, s, table), column); ok {
+		tb.Errorf("column %s.%s exists, expected it not to", table, column)
+	}
+}
+
+// RequireIndex fails the test if a table has no index on exactly the columns,
+// in order, or only a non-unique one when unique is set
+func RequireIndex(tb testing.TB, s *Schema, table string, unique bool, columns ...string) {
+	tb.Helper()
+
+	for _, idx := range RequireTable(tb, s, table).Indexes {
+		if slices.Equal(idx.Columns, columns) && (idx.Unique || !unique) {
+			return
+		}
+	}
+	kind := "index"
+	if unique {
+		kind = "unique index"
+	}
+	tb.Errorf("%s on %s (%s) is missing", kind, table, strings.Join(columns, ", "))
+}
+
+// findColumn returns the column of a table with a name
+func findColumn(table *t.Table, name string) (t.Column, bool) {
+	for _, col := range table.Columns {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	return t.Column{}, false
+}
@@ -0,0 +1,218 @@
+package inspectortest
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/carloberd/db-reader/types"
+)
+
+// fakeTB records the failures of the helpers under test. Fatal failures stop
+// the goroutine running the helper, as they stop a test.
+type fakeTB struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Cleanup(func()) {}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Fatalf(format string, args ...any) {
+	f.Errorf(format, args...)
+	f.fatal = true
+	runtime.Goexit()
+}
+
+// run calls a helper with a fake TB and returns it once the helper returns or fails fatally
+func run(check func(tb testing.TB)) *fakeTB {
+	tb := &fakeTB{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		check(tb)
+	}()
+	<-done
+	return tb
+}
+
+// memoryConnector serves tables held in memory
+type memoryConnector struct {
+	tables map[string]*types.Table
+	err    error // Returned by every call, if set
+}
+
+func (m *memoryConnector) Connect(types.ConnectionParams) error { return m.err }
+
+func (m *memoryConnector) Disconnect() error { return nil }
+
+func (m *memoryConnector) GetTables(string) ([]string, error) {
+	var names []string
+	for name := range m.tables {
+		names = append(names, name)
+	}
+	return names, m.err
+}
+
+func (m *memoryConnector) GetTableStructure(_, name string) (*types.Table, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.tables[name], nil
+}
+
+func (m *memoryConnector) GetViews(string) ([]types.View, error) {
+	return nil, types.ErrViewsNotSupported
+}
+
+// testSchema returns a schema of users and posts tables held in memory
+func testSchema(tb testing.TB) *Schema {
+	connector := &memoryConnector{tables: map[string]*types.Table{
+		"users": {
+			Name: "users",
+			Columns: []types.Column{
+				{Name: "id", Type: "bigint", IsPrimaryKey: true},
+				{Name: "email", Type: "character varying(255)", Comment: "Login"},
+				{Name: "created_at", Type: "timestamp(3) with time zone",
+					DefaultValue: sql.NullString{String: "now()", Valid: true}},
+				{Name: "bio", Type: "text", Nullable: true},
+			},
+			Indexes: []types.Index{
+				{Name: "users_pkey", Columns: []string{"id"}, Unique: true, PrimaryKey: true},
+				{Name: "users_email_key", Columns: []string{"email"}, Unique: true},
+				{Name: "users_created_bio_idx", Columns: []string{"created_at", "bio"}},
+			},
+		},
+		"posts": {
+			Name: "posts",
+			Columns: []types.Column{
+				{Name: "author_id", Type: "integer",
+					ForeignKey: sql.NullString{String: "public.users (id)", Valid: true}},
+			},
+		},
+	}}
+	return Load(tb, connector, "public")
+}
+
+func TestRequireColumn(t *testing.T) {
+	tests := []struct {
+		name   string
+		table  string
+		column string
+		typ    string
+		opts   []ColumnOption
+		errors int
+		fatal  bool
+	}{
+		{"matching", "users", "email", "varchar(255)", []ColumnOption{NotNull, NoDefault, Comment("Login")}, 0, false},
+		{"any type", "users", "bio", "", []ColumnOption{Nullable}, 0, false},
+		{"type alias", "users", "id", "int8", []ColumnOption{PrimaryKey}, 0, false},
+		{"type case", "users", "bio", "TEXT", nil, 0, false},
+		{"precision timestamp", "users", "created_at", "timestamptz(3)", []ColumnOption{Default("now()")}, 0, false},
+		{"other precision", "users", "created_at", "timestamptz(6)", nil, 1, false},
+		{"without time zone", "users", "created_at", "timestamp(3)", nil, 1, false},
+		{"wrong type", "users", "email", "text", nil, 1, false},
+		{"reference", "posts", "author_id", "integer", []ColumnOption{References("users", "id")}, 0, false},
+		{"wrong reference", "posts", "author_id", "", []ColumnOption{References("users", "email")}, 1, false},
+		{"not a key", "users", "email", "", []ColumnOption{PrimaryKey, References("users", "id")}, 2, false},
+		{"nullability", "users", "bio", "", []ColumnOption{NotNull}, 1, false},
+		{"not nullable", "users", "id", "", []ColumnOption{Nullable}, 1, false},
+		{"default", "users", "created_at", "", []ColumnOption{NoDefault, Default("now")}, 2, false},
+		{"no default", "users", "bio", "", []ColumnOption{Default("now()")}, 1, false},
+		{"comment", "users", "bio", "", []ColumnOption{Comment("Login")}, 1, false},
+		{"type and option", "users", "bio", "integer", []ColumnOption{NotNull}, 2, false},
+		{"missing column", "users", "name", "text", []ColumnOption{NotNull}, 1, true},
+		{"missing table", "accounts", "id", "", nil, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := run(func(tb testing.TB) {
+				RequireColumn(tb, testSchema(t), tt.table, tt.column, tt.typ, tt.opts...)
+			})
+			if len(tb.errors) != tt.errors || tb.fatal != tt.fatal {
+				t.Errorf("got errors %q (fatal %v), want %d (fatal %v)", tb.errors, tb.fatal, tt.errors, tt.fatal)
+			}
+		})
+	}
+}
+
+func TestRequireIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   string
+		unique  bool
+		columns []string
+		errors  int
+		fatal   bool
+	}{
+		{"primary key", "users", true, []string{"id"}, 0, false},
+		{"unique", "users", true, []string{"email"}, 0, false},
+		{"unique as any index", "users", false, []string{"email"}, 0, false},
+		{"several columns", "users", false, []string{"created_at", "bio"}, 0, false},
+		{"not unique", "users", true, []string{"created_at", "bio"}, 1, false},
+		{"column order", "users", false, []string{"bio", "created_at"}, 1, false},
+		{"prefix of the columns", "users", false, []string{"created_at"}, 1, false},
+		{"no index", "posts", false, []string{"author_id"}, 1, false},
+		{"missing table", "accounts", false, []string{"id"}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := run(func(tb testing.TB) {
+				RequireIndex(tb, testSchema(t), tt.table, tt.unique, tt.columns...)
+			})
+			if len(tb.errors) != tt.errors || tb.fatal != tt.fatal {
+				t.Errorf("got errors %q (fatal %v), want %d (fatal %v)", tb.errors, tb.fatal, tt.errors, tt.fatal)
+			}
+		})
+	}
+}
+
+func TestRequireNoColumn(t *testing.T) {
+	tb := run(func(tb testing.TB) { RequireNoColumn(tb, testSchema(t), "users", "name") })
+	if len(tb.errors) != 0 {
+		t.Errorf("absent column failed with %q", tb.errors)
+	}
+
+	tb = run(func(tb testing.TB) { RequireNoColumn(tb, testSchema(t), "users", "email") })
+	if len(tb.errors) != 1 || tb.fatal {
+		t.Errorf("present column failed with %q (fatal %v), want one error", tb.errors, tb.fatal)
+	}
+}
+
+func TestRequireNoTable(t *testing.T) {
+	tb := run(func(tb testing.TB) { RequireNoTable(tb, testSchema(t), "accounts") })
+	if len(tb.errors) != 0 {
+		t.Errorf("absent table failed with %q", tb.errors)
+	}
+
+	tb = run(func(tb testing.TB) { RequireNoTable(tb, testSchema(t), "users") })
+	if len(tb.errors) != 1 || tb.fatal {
+		t.Errorf("present table failed with %q (fatal %v), want one error", tb.errors, tb.fatal)
+	}
+}
+
+func TestConnectorErrors(t *testing.T) {
+	failing := &memoryConnector{err: errors.New("connection lost")}
+
+	tb := run(func(tb testing.TB) { Load(tb, failing, "public") })
+	if !tb.fatal || len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "connection lost") {
+		t.Errorf("listing tables failed with %q (fatal %v), want a fatal error", tb.errors, tb.fatal)
+	}
+
+	schema := &Schema{Name: "public", connector: failing, names: []string{"users"}, tables: make(map[string]*types.Table)}
+	tb = run(func(tb testing.TB) { RequireColumn(tb, schema, "users", "id", "bigint") })
+	if !tb.fatal || len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "connection lost") {
+		t.Errorf("loading a table failed with %q (fatal %v), want a fatal error", tb.errors, tb.fatal)
+	}
+}
@@ -0,0 +1,349 @@
+package lineage
+
+import (
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+)
+
+// Source is a base table column a view column derives from
+type Source struct {
+	Table  string
+	Column string
+}
+
+// String returns the source as table.column
+func (s Source) String() string {
+	return s.Table + "." + s.Column
+}
+
+// ColumnLineage describes where a view column comes from
+type ColumnLineage struct {
+	Column     string
+	Sources    []Source
+	Expression bool // Computed from an expression rather than copied from a column
+}
+
+// ViewColumns parses a view definition and maps its output columns back to the
+// base table columns they derive from. This is a best-effort hint: only the first
+// branch of the outermost SELECT is analysed, references into subqueries are not
+// followed and unqualified columns resolve only when a single table is queried.
+func ViewColumns(definition string) []ColumnLineage {
+	tokens := sqlutil.Tokenize(definition)
+
+	start := findTopLevel(tokens, 0, "SELECT")
+	if start < 0 {
+		return nil
+	}
+	start = skipSelectModifiers(tokens, start+1)
+
+	from := findTopLevel(tokens, start, "FROM")
+	selectEnd := from
+	if selectEnd < 0 {
+		selectEnd = clauseEnd(tokens, start)
+	}
+
+	var aliases map[string]string
+	var tables []string
+	if from >= 0 {
+		aliases, tables = parseFrom(tokens[from+1 : clauseEnd(tokens, from+1)])
+	}
+
+	var columns []ColumnLineage
+	for _, item := range splitTopLevel(tokens[start:selectEnd]) {
+		expr, name := splitAlias(item)
+		if len(expr) == 0 {
+			continue
+		}
+
+		// Star expansions are left out: PostgreSQL stores them expanded anyway
+		if expr[len(expr)-1].IsSymbol("*") {
+			continue
+		}
+
+		col := ColumnLineage{Column: name, Expression: !isColumnRef(expr)}
+		seen := make(map[Source]bool)
+		for _, ref := range columnRefs(expr) {
+			src, ok := resolve(ref, aliases, tables)
+			if ok && !seen[src] {
+				seen[src] = true
+				col.Sources = append(col.Sources, src)
+			}
+		}
+		if col.Column == "" {
+			if refs := columnRefs(expr); !col.Expression && len(refs) == 1 {
+				col.Column = refs[0][len(refs[0])-1]
+			} else {
+				col.Column = "?column?"
+			}
+		}
+
+		columns = append(columns, col)
+	}
+
+	return columns
+}
+
+// clauseKeywords end the FROM clause or the select list of a query
+var clauseKeywords = map[string]bool{
+	"WHERE": true, "GROUP": true, "HAVING": true, "WINDOW": true, "ORDER": true,
+	"LIMIT": true, "OFFSET": true, "FETCH": true, "FOR": true,
+	"UNION": true, "INTERSECT": true, "EXCEPT": true,
+}
+
+// joinKeywords can follow a table reference in a FROM clause and are never aliases
+var joinKeywords = map[string]bool{
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "OUTER": true,
+	"CROSS": true, "NATURAL": true, "ON": true, "USING": true, "LATERAL": true, "TABLESAMPLE": true,
+}
+
+// exprKeywords are words inside expressions that are not column references
+var exprKeywords = map[string]bool{
+	"CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true,
+	"AND": true, "OR": true, "NOT": true, "NULL": true, "TRUE": true, "FALSE": true,
+	"IS": true, "IN": true, "LIKE": true, "ILIKE": true, "SIMILAR": true, "BETWEEN": true,
+	"DISTINCT": true, "AS": true, "ASC": true, "DESC": true, "OVER": true, "PARTITION": true,
+	"BY": true, "ORDER": true, "FILTER": true, "WHERE": true, "EXISTS": true, "SELECT": true,
+	"FROM": true, "ANY": true, "ALL": true, "SOME": true, "COLLATE": true, "ARRAY": true,
+	"ROWS": true, "RANGE": true, "UNBOUNDED": true, "PRECEDING": true, "FOLLOWING": true,
+	"CURRENT": true, "ROW": true, "INTERVAL": true, "ESCAPE": true, "NULLS": true,
+	"FIRST": true, "LAST": true, "WITHIN": true, "GROUP": true, "CURRENT_DATE": true,
+	"CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "CURRENT_USER": true, "SESSION_USER": true,
+	"LOCALTIME": true, "LOCALTIMESTAMP": true,
+}
+
+// findTopLevel returns the index of the first keyword outside parentheses at or after start, or -1
+func findTopLevel(tokens []sqlutil.Token, start int, keyword string) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		switch {
+		case tokens[i].IsSymbol("("):
+			depth++
+		case tokens[i].IsSymbol(")"):
+			depth--
+		case depth == 0 && tokens[i].Is(keyword):
+			return i
+		}
+	}
+	return -1
+}
+
+// clauseEnd returns the index where the clause starting at start ends
+func clauseEnd(tokens []sqlutil.Token, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.IsSymbol("("):
+			depth++
+		case tok.IsSymbol(")"):
+			depth--
+			if depth < 0 {
+				return i
+			}
+		case depth == 0 && tok.IsSymbol(";"):
+			return i
+		case depth == 0 && tok.Kind == sqlutil.Word && clauseKeywords[strings.ToUpper(tok.Text)]:
+			return i
+		}
+	}
+	return len(tokens)
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open
+func matchingParen(tokens []sqlutil.Token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].IsSymbol("(") {
+			depth++
+		} else if tokens[i].IsSymbol(")") {
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// skipSelectModifiers skips DISTINCT, DISTINCT ON (...) and ALL after SELECT
+func skipSelectModifiers(tokens []sqlutil.Token, i int) int {
+	if i < len(tokens) && tokens[i].Is("ALL") {
+		return i + 1
+	}
+	if i < len(tokens) && tokens[i].Is("DISTINCT") {
+		i++
+		if i+1 < len(tokens) && tokens[i].Is("ON") && tokens[i+1].IsSymbol("(") {
+			i = matchingParen(tokens, i+1) + 1
+		}
+	}
+	return i
+}
+
+// splitTopLevel splits a token list at commas outside parentheses
+func splitTopLevel(tokens []sqlutil.Token) [][]sqlutil.Token {
+	var items [][]sqlutil.Token
+	depth, start := 0, 0
+	for i, tok := range tokens {
+		switch {
+		case tok.IsSymbol("("), tok.IsSymbol("["):
+			depth++
+		case tok.IsSymbol(")"), tok.IsSymbol("]"):
+			depth--
+		case depth == 0 && tok.IsSymbol(","):
+			items = append(items, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		items = append(items, tokens[start:])
+	}
+	return items
+}
+
+// splitAlias separates a select item into its expression and output column name.
+// The name is empty when the item has no alias.
+func splitAlias(item []sqlutil.Token) ([]sqlutil.Token, string) {
+	n := len(item)
+	if n >= 3 && item[n-2].Is("AS") && item[n-1].IsName() {
+		return item[:n-2], item[n-1].Name()
+	}
+
+	// Implicit alias, as in "count(*) total"
+	if n >= 2 && item[n-1].IsName() && !isKeyword(item[n-1], exprKeywords) {
+		prev := item[n-2]
+		if prev.IsName() && !isKeyword(prev, exprKeywords) || prev.IsSymbol(")") || prev.Kind == sqlutil.Literal {
+			return item[:n-1], item[n-1].Name()
+		}
+	}
+
+	return item, ""
+}
+
+// isKeyword reports whether a bare word is in the given keyword set
+func isKeyword(tok sqlutil.Token, keywords map[string]bool) bool {
+	return tok.Kind == sqlutil.Word && keywords[strings.ToUpper(tok.Text)]
+}
+
+// qualifiedName reads a dotted name starting at i and returns its parts and the index after it
+func qualifiedName(tokens []sqlutil.Token, i int) ([]string, int) {
+	parts := []string{tokens[i].Name()}
+	i++
+	for i+1 < len(tokens) && tokens[i].IsSymbol(".") && tokens[i+1].IsName() {
+		parts = append(parts, tokens[i+1].Name())
+		i += 2
+	}
+	return parts, i
+}
+
+// isColumnRef reports whether an expression is a plain, possibly qualified, column reference
+func isColumnRef(expr []sqlutil.Token) bool {
+	if !expr[0].IsName() || isKeyword(expr[0], exprKeywords) {
+		return false
+	}
+	_, next := qualifiedName(expr, 0)
+	return next == len(expr)
+}
+
+// columnRefs returns the dotted column references used in an expression
+func columnRefs(expr []sqlutil.Token) [][]string {
+	var refs [][]string
+	for i := 0; i < len(expr); i++ {
+		tok := expr[i]
+
+		// Skip type names of casts, as in "x::character varying(10)" or "CAST(x AS int)"
+		if tok.IsSymbol("::") || tok.Is("AS") {
+			for i+1 < len(expr) && expr[i+1].IsName() && !isKeyword(expr[i+1], exprKeywords) {
+				i++
+			}
+			if i+1 < len(expr) && expr[i+1].IsSymbol("(") {
+				i = matchingParen(expr, i+1)
+			}
+			continue
+		}
+
+		if !tok.IsName() || isKeyword(tok, exprKeywords) {
+			continue
+		}
+
+		parts, next := qualifiedName(expr, i)
+		i = next - 1
+
+		// Function calls and typed literals such as date '2024-01-01'
+		if next < len(expr) && (expr[next].IsSymbol("(") || expr[next].Kind == sqlutil.Literal) {
+			continue
+		}
+
+		refs = append(refs, parts)
+	}
+	return refs
+}
+
+// parseFrom reads the table references of a FROM clause. It returns the
+// tables by alias and the queried tables in order; subqueries are ignored.
+func parseFrom(tokens []sqlutil.Token) (map[string]string, []string) {
+	aliases := make(map[string]string)
+	var tables []string
+
+	expectTable := true
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.IsSymbol(","), tok.Is("JOIN"):
+			expectTable = true
+
+		case tok.IsSymbol("("):
+			// Subqueries are skipped, parenthesised joins are read through
+			if i+1 < len(tokens) && (tokens[i+1].Is("SELECT") || tokens[i+1].Is("WITH") || tokens[i+1].Is("VALUES")) {
+				i = matchingParen(tokens, i)
+				expectTable = false
+			}
+
+		case tok.Is("LATERAL"), tok.Is("ONLY"):
+			// Modifiers before a table reference
+
+		case expectTable && tok.IsName():
+			parts, next := qualifiedName(tokens, i)
+			i = next - 1
+			expectTable = false
+
+			// Set-returning functions are not tables
+			if next < len(tokens) && tokens[next].IsSymbol("(") {
+				i = matchingParen(tokens, next)
+				continue
+			}
+
+			table := parts[len(parts)-1]
+			alias := table
+			if next < len(tokens) && tokens[next].Is("AS") {
+				next++
+			}
+			if next < len(tokens) && tokens[next].IsName() && !isKeyword(tokens[next], joinKeywords) {
+				alias = tokens[next].Name()
+				i = next
+			}
+
+			aliases[alias] = table
+			tables = append(tables, table)
+		}
+	}
+
+	return aliases, tables
+}
+
+// resolve maps a column reference to the base table column it names
+func resolve(ref []string, aliases map[string]string, tables []string) (Source, bool) {
+	column := ref[len(ref)-1]
+	switch len(ref) {
+	case 1:
+		if len(tables) == 1 {
+			return Source{Table: tables[0], Column: column}, true
+		}
+	default:
+		// qualifier is either an alias or, for schema.table.column, the table
+		if table, ok := aliases[ref[len(ref)-2]]; ok {
+			return Source{Table: table, Column: column}, true
+		}
+	}
+	return Source{}, false
+}
@@ -0,0 +1,67 @@
+package lineage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestViewColumns(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		want       []ColumnLineage
+	}{
+		{
+			name:       "single table",
+			definition: "SELECT id, name AS title FROM books",
+			want: []ColumnLineage{
+				{Column: "id", Sources: []Source{{"books", "id"}}},
+				{Column: "title", Sources: []Source{{"books", "name"}}},
+			},
+		},
+		{
+			name:       "aliases and joins",
+			definition: "SELECT b.id, a.name author FROM public.books b JOIN authors AS a ON a.id = b.author_id",
+			want: []ColumnLineage{
+				{Column: "id", Sources: []Source{{"books", "id"}}},
+				{Column: "author", Sources: []Source{{"authors", "name"}}},
+			},
+		},
+		{
+			name:       "expression",
+			definition: "SELECT o.qty * o.price AS amount, count(*) FROM orders o GROUP BY 1",
+			want: []ColumnLineage{
+				{Column: "amount", Sources: []Source{{"orders", "qty"}, {"orders", "price"}}, Expression: true},
+				{Column: "?column?", Expression: true},
+			},
+		},
+		{
+			name:       "unqualified columns over a join",
+			definition: "SELECT id FROM a, b",
+			want:       []ColumnLineage{{Column: "id"}},
+		},
+		{
+			name:       "star and subquery",
+			definition: "SELECT *, x.total FROM (SELECT sum(n) AS total FROM t) x",
+			want:       []ColumnLineage{{Column: "total"}},
+		},
+		{
+			name:       "union uses the first branch",
+			definition: "SELECT DISTINCT id FROM a UNION SELECT id FROM b",
+			want:       []ColumnLineage{{Column: "id", Sources: []Source{{"a", "id"}}}},
+		},
+		{
+			name:       "not a query",
+			definition: "VALUES (1)",
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ViewColumns(tt.definition); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ViewColumns(%q) =\n%+v\nwant\n%+v", tt.definition, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,13 +2,42 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"fyne.io/fyne/v2/app"
 
+	"github.com/carloberd/db-reader/cli"
 	"github.com/carloberd/db-reader/ui"
+
+	// Connectors register themselves with their driver names
+	_ "github.com/carloberd/db-reader/bigquery"
+	_ "github.com/carloberd/db-reader/clickhouse"
+	_ "github.com/carloberd/db-reader/db2"
+	_ "github.com/carloberd/db-reader/demo"
+	_ "github.com/carloberd/db-reader/duckdb"
+	_ "github.com/carloberd/db-reader/hive"
+	_ "github.com/carloberd/db-reader/mongodb"
+	_ "github.com/carloberd/db-reader/mssql"
+	_ "github.com/carloberd/db-reader/mysql"
+	_ "github.com/carloberd/db-reader/odbc"
+	_ "github.com/carloberd/db-reader/oracle"
+	_ "github.com/carloberd/db-reader/pgdump"
+	_ "github.com/carloberd/db-reader/postgresql"
+	_ "github.com/carloberd/db-reader/snapshot"
+	_ "github.com/carloberd/db-reader/snowflake"
+	_ "github.com/carloberd/db-reader/sqlite"
+	_ "github.com/carloberd/db-reader/trino"
+
+	// Export formats register themselves with their names
+	_ "github.com/carloberd/db-reader/diagram"
 )
 
 func main() {
+	// Run a command line command if one is given
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	// Create and initialize the application
 	a := app.New()
 	inspector := ui.NewDBInspector(a)
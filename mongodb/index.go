@@ -0,0 +1,274 @@
+package mongodb
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// defaultSampleSize is the number of documents sampled to infer the fields of a collection
+const defaultSampleSize = 1000
+
+// connectTimeout limits how long connecting waits for a server to answer
+const connectTimeout = 10 * time.Second
+
+// MongoConnector implements the DatabaseConnector interface for MongoDB.
+// Collections are the tables, and their columns are inferred from a sample of
+// documents. The schema names a database on the server, like with MySQL.
+type MongoConnector struct {
+	sqlutil.QueryLog
+
+	client     *mongo.Client
+	database   string // Database connected to, used when no schema is given
+	sampleSize int    // Documents sampled per collection
+}
+
+// Connect establishes a connection to the MongoDB server. The host may also be
+// a whole connection string, e.g. mongodb+srv://cluster0.example.net. Session
+// settings are connection string options such as authSource=admin, except
+// sampleSize, which sets the number of documents sampled per collection.
+func (mc *MongoConnector) Connect(params t.ConnectionParams) error {
+	mc.sampleSize = defaultSampleSize
+	settings := make(map[string]string, len(params.Settings))
+	for key, value := range params.Settings {
+		settings[key] = value
+	}
+	if value, ok := settings["sampleSize"]; ok {
+		size, err := strconv.Atoi(value)
+		if err != nil || size <= 0 {
+			return fmt.Errorf("invalid sampleSize '%s'", value)
+		}
+		mc.sampleSize = size
+		delete(settings, "sampleSize")
+	}
+
+	uri, err := connectionURI(params, settings)
+	if err != nil {
+		return err
+	}
+	client, err := mongo.Connect(options.Client().ApplyURI(uri).SetServerSelectionTimeout(connectTimeout))
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	// Test the connection
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	mc.client = client
+	mc.database = params.Database
+	return nil
+}
+
+// connectionURI builds the connection string from the host, or completes the one given as host
+func connectionURI(params t.ConnectionParams, settings map[string]string) (string, error) {
+	u := &url.URL{Scheme: "mongodb", Host: net.JoinHostPort(params.Host, params.Port), Path: "/"}
+	if strings.Contains(params.Host, "://") {
+		var err error
+		if u, err = url.Parse(params.Host); err != nil {
+			return "", fmt.Errorf("invalid connection string: %v", err)
+		}
+	}
+
+	if params.User != "" && u.User == nil {
+		u.User = url.User(params.User)
+		if params.Password != "" {
+			u.User = url.UserPassword(params.User, params.Password)
+		}
+	}
+	query := u.Query()
+	for key, value := range settings {
+		query.Set(key, value)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Disconnect closes the database connection
+func (mc *MongoConnector) Disconnect() error {
+	if mc.client != nil {
+		err := mc.client.Disconnect(context.Background())
+		mc.client = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// schemaName returns the database to inspect, defaulting to the connected one
+func (mc *MongoConnector) schemaName(schema string) string {
+	if schema == "" {
+		return mc.database
+	}
+	return schema
+}
+
+// GetTables returns the collections of the specified database, without views
+func (mc *MongoConnector) GetTables(schema string) ([]string, error) {
+	if mc.client == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = mc.schemaName(schema)
+
+	filter := bson.D{{Key: "type", Value: "collection"}}
+	mc.logCommand(schema, "", "getCollectionInfos", filter)
+	names, err := mc.client.Database(schema).ListCollectionNames(context.Background(), filter)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+
+	// System collections such as system.profile are not part of the application schema
+	var tables []string
+	for _, name := range names {
+		if !strings.HasPrefix(name, "system.") {
+			tables = append(tables, name)
+		}
+	}
+	sort.Strings(tables)
+
+	return tables, nil
+}
+
+// GetTableStructure returns the structure of the specified collection, with the
+// columns inferred from a random sample of its documents
+func (mc *MongoConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if mc.client == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = mc.schemaName(schema)
+
+	// Check if the collection exists
+	tables, err := mc.GetTables(schema)
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	if i := sort.SearchStrings(tables, tableName); i == len(tables) || tables[i] != tableName {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	collection := mc.client.Database(schema).Collection(tableName)
+	ctx := context.Background()
+
+	pipeline := bson.A{bson.D{{Key: "$sample", Value: bson.D{{Key: "size", Value: mc.sampleSize}}}}}
+	mc.logCommand(schema, tableName, "aggregate", pipeline)
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error sampling documents: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	sample := newSample()
+	for cursor.Next(ctx) {
+		if err := sample.add(cursor.Current); err != nil {
+			return nil, fmt.Errorf("error reading sampled document: %v", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("error sampling documents: %v", err)
+	}
+
+	table := &t.Table{
+		Name:       tableName,
+		Schema:     schema,
+		Columns:    sample.columns(),
+		Properties: []t.Property{{Name: "Sampled documents", Value: strconv.Itoa(sample.documents)}},
+	}
+	if table.Indexes, err = mc.getIndexes(schema, collection); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// getIndexes returns the indexes of a collection, with keys in index order
+func (mc *MongoConnector) getIndexes(schema string, collection *mongo.Collection) ([]t.Index, error) {
+	ctx := context.Background()
+	mc.logCommand(schema, collection.Name(), "getIndexes", nil)
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error querying indexes: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var indexes []t.Index
+	for cursor.Next(ctx) {
+		var spec struct {
+			Name                    string `bson:"name"`
+			Key                     bson.D `bson:"key"`
+			Unique                  bool   `bson:"unique"`
+			PartialFilterExpression bson.M `bson:"partialFilterExpression"`
+		}
+		if err := cursor.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("error scanning index results: %v", err)
+		}
+
+		idx := t.Index{
+			Name:       spec.Name,
+			Unique:     spec.Unique || spec.Name == "_id_",
+			PrimaryKey: spec.Name == "_id_",
+		}
+		for _, key := range spec.Key {
+			idx.Columns = append(idx.Columns, key.Key)
+			// Numeric keys give the sort direction; special indexes name their kind, e.g. "text"
+			if kind, ok := key.Value.(string); ok {
+				idx.Method = kind
+			}
+		}
+		if spec.PartialFilterExpression != nil {
+			filter, err := bson.MarshalExtJSON(spec.PartialFilterExpression, false, false)
+			if err == nil {
+				idx.Predicate = string(filter)
+			}
+		}
+		indexes = append(indexes, idx)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+	return indexes, nil
+}
+
+// NewMongoConnector creates a connector for MongoDB servers
+func NewMongoConnector() t.DatabaseConnector {
+	return &MongoConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverMongoDB, NewMongoConnector, t.ConnectorInfo{
+		Title:  "MongoDB",
+		Fields: t.ServerFields,
+		Defaults: func(p *t.ConnectionParams) {
+			// Servers without access control need no user
+			p.DefaultServer("27017", "")
+			p.Schema = cmp.Or(p.Schema, p.Database)
+		},
+	})
+}
+
+// GetViews is not supported, as the collections are described from sampled documents
+func (mc *MongoConnector) GetViews(schema string) ([]t.View, error) {
+	return nil, t.ErrViewsNotSupported
+}
@@ -0,0 +1,133 @@
+package mongodb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// typeNames maps BSON types to the aliases used by the MongoDB $type operator
+var typeNames = map[bson.Type]string{
+	bson.TypeDouble:           "double",
+	bson.TypeString:           "string",
+	bson.TypeEmbeddedDocument: "object",
+	bson.TypeArray:            "array",
+	bson.TypeBinary:           "binData",
+	bson.TypeUndefined:        "undefined",
+	bson.TypeObjectID:         "objectId",
+	bson.TypeBoolean:          "bool",
+	bson.TypeDateTime:         "date",
+	bson.TypeNull:             "null",
+	bson.TypeRegex:            "regex",
+	bson.TypeDBPointer:        "dbPointer",
+	bson.TypeJavaScript:       "javascript",
+	bson.TypeSymbol:           "symbol",
+	bson.TypeCodeWithScope:    "javascriptWithScope",
+	bson.TypeInt32:            "int",
+	bson.TypeTimestamp:        "timestamp",
+	bson.TypeInt64:            "long",
+	bson.TypeDecimal128:       "decimal",
+	bson.TypeMinKey:           "minKey",
+	bson.TypeMaxKey:           "maxKey",
+}
+
+// fieldStats collects what the sampled documents hold in a field
+type fieldStats struct {
+	present int            // Documents having the field
+	nulls   int            // Documents where the field is null
+	types   map[string]int // Documents by type of the value, without nulls
+}
+
+// sample infers the fields of a collection from its documents. Fields of
+// embedded documents are listed as paths, e.g. address.city; arrays are not
+// looked into.
+type sample struct {
+	documents int
+	fields    map[string]*fieldStats
+	order     []string // Field paths in the order first seen
+}
+
+// newSample creates an empty sample
+func newSample() *sample {
+	return &sample{fields: make(map[string]*fieldStats)}
+}
+
+// add records the fields of a document
+func (s *sample) add(doc bson.Raw) error {
+	s.documents++
+	return s.addFields("", doc)
+}
+
+// addFields records the fields of a document or embedded document under a path prefix
+func (s *sample) addFields(prefix string, doc bson.Raw) error {
+	elements, err := doc.Elements()
+	if err != nil {
+		return err
+	}
+
+	for _, elem := range elements {
+		path := prefix + elem.Key()
+		stats, ok := s.fields[path]
+		if !ok {
+			stats = &fieldStats{types: make(map[string]int)}
+			s.fields[path] = stats
+			s.order = append(s.order, path)
+		}
+		stats.present++
+
+		value := elem.Value()
+		if value.Type == bson.TypeNull {
+			stats.nulls++
+			continue
+		}
+		name, ok := typeNames[value.Type]
+		if !ok {
+			name = value.Type.String()
+		}
+		stats.types[name]++
+
+		if embedded, ok := value.DocumentOK(); ok {
+			if err := s.addFields(path+".", embedded); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// columns returns the inferred columns. The type lists the types found, most
+// frequent first, and the comment gives how often the field is present.
+func (s *sample) columns() []t.Column {
+	columns := make([]t.Column, 0, len(s.order))
+	for _, path := range s.order {
+		stats := s.fields[path]
+
+		types := make([]string, 0, len(stats.types))
+		for name := range stats.types {
+			types = append(types, name)
+		}
+		sort.Slice(types, func(i, j int) bool {
+			if stats.types[types[i]] != stats.types[types[j]] {
+				return stats.types[types[i]] > stats.types[types[j]]
+			}
+			return types[i] < types[j]
+		})
+		typ := strings.Join(types, "|")
+		if typ == "" {
+			typ = "null"
+		}
+
+		columns = append(columns, t.Column{
+			Name:         path,
+			Type:         typ,
+			Nullable:     stats.present < s.documents || stats.nulls > 0,
+			IsPrimaryKey: path == "_id",
+			Comment: fmt.Sprintf("Present in %d%% of %d sampled documents",
+				stats.present*100/s.documents, s.documents),
+		})
+	}
+	return columns
+}
@@ -0,0 +1,69 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestSampleColumns(t *testing.T) {
+	docs := []bson.D{
+		{{Key: "_id", Value: bson.NewObjectID()}, {Key: "name", Value: "Ada"}, {Key: "age", Value: int32(36)},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "London"}}}},
+		{{Key: "_id", Value: bson.NewObjectID()}, {Key: "name", Value: "Alan"}, {Key: "age", Value: int64(41)},
+			{Key: "tags", Value: bson.A{"a", "b"}}},
+		{{Key: "_id", Value: bson.NewObjectID()}, {Key: "name", Value: nil}, {Key: "age", Value: int32(28)}},
+		{{Key: "_id", Value: bson.NewObjectID()}, {Key: "name", Value: "Grace"}, {Key: "age", Value: 30.5}},
+	}
+
+	s := newSample()
+	for _, doc := range docs {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.add(raw); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type column struct {
+		name, typ  string
+		nullable   bool
+		primaryKey bool
+		comment    string
+	}
+	want := []column{
+		{"_id", "objectId", false, true, "Present in 100% of 4 sampled documents"},
+		{"name", "string", true, false, "Present in 100% of 4 sampled documents"},
+		{"age", "int|double|long", false, false, "Present in 100% of 4 sampled documents"},
+		{"address", "object", true, false, "Present in 25% of 4 sampled documents"},
+		{"address.city", "string", true, false, "Present in 25% of 4 sampled documents"},
+		{"tags", "array", true, false, "Present in 25% of 4 sampled documents"},
+	}
+
+	var got []column
+	for _, col := range s.columns() {
+		got = append(got, column{col.Name, col.Type, col.Nullable, col.IsPrimaryKey, col.Comment})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("columns are\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestSampleNullOnly(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{{Key: "deleted_at", Value: nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := newSample()
+	if err := s.add(raw); err != nil {
+		t.Fatal(err)
+	}
+	columns := s.columns()
+	if len(columns) != 1 || columns[0].Type != "null" || !columns[0].Nullable {
+		t.Errorf("columns are %+v, want a nullable column of type null", columns)
+	}
+}
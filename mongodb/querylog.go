@@ -0,0 +1,28 @@
+package mongodb
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// logCommand logs a command the way the MongoDB shell writes it, e.g.
+// db.getSiblingDB("shop").orders.aggregate([...])
+func (mc *MongoConnector) logCommand(database, collection, command string, arg any) {
+	if !mc.Logging() {
+		return
+	}
+
+	target := fmt.Sprintf("db.getSiblingDB(%q)", database)
+	if collection != "" {
+		target += fmt.Sprintf(".getCollection(%q)", collection)
+	}
+	argument := ""
+	if arg != nil {
+		// Arrays cannot be marshaled on their own, so they are wrapped and unwrapped
+		if value, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: arg}}, false, false); err == nil {
+			argument = string(value[len(`{"v":`) : len(value)-1])
+		}
+	}
+	mc.LogQuery(fmt.Sprintf("%s.%s(%s)", target, command, argument), nil)
+}
@@ -0,0 +1,41 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// EstimateRowCount returns the document count kept in the collection metadata
+func (mc *MongoConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if mc.client == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+	schema = mc.schemaName(schema)
+
+	mc.logCommand(schema, tableName, "estimatedDocumentCount", nil)
+	count, err := mc.client.Database(schema).Collection(tableName).EstimatedDocumentCount(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	return count, nil
+}
+
+// CountRows returns the exact number of documents in a collection
+func (mc *MongoConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if mc.client == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+	schema = mc.schemaName(schema)
+
+	mc.logCommand(schema, tableName, "countDocuments", bson.D{})
+	count, err := mc.client.Database(schema).Collection(tableName).CountDocuments(ctx, bson.D{})
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
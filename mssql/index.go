@@ -0,0 +1,486 @@
+package mssql
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	mssql "github.com/denisenkom/go-mssqldb" // SQL Server driver
+)
+
+// MSSQLConnector implements the DatabaseConnector interface for Microsoft SQL Server
+type MSSQLConnector struct {
+	sqlutil.QueryLog
+
+	db *sql.DB
+}
+
+// Connect establishes a connection to the SQL Server database. Session settings
+// are passed as connection string parameters, e.g. encrypt=disable.
+func (mc *MSSQLConnector) Connect(params t.ConnectionParams) error {
+	query := url.Values{}
+	for key, value := range params.Settings {
+		query.Set(key, value)
+	}
+	query.Set("database", params.Database)
+
+	dsn := &url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(params.User, params.Password),
+		Host:     net.JoinHostPort(params.Host, params.Port),
+		RawQuery: query.Encode(),
+	}
+
+	connector, err := mssql.NewConnector(dsn.String())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+	mc.db = sql.OpenDB(connector)
+
+	// Test the connection
+	if err := mc.db.Ping(); err != nil {
+		mc.db.Close()
+		mc.db = nil
+		return fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	return nil
+}
+
+// Disconnect closes the database connection
+func (mc *MSSQLConnector) Disconnect() error {
+	if mc.db != nil {
+		err := mc.db.Close()
+		mc.db = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetTables returns a list of user tables in the specified schema
+func (mc *MSSQLConnector) GetTables(schema string) ([]string, error) {
+	if mc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			t.name
+		FROM
+			sys.tables t
+		JOIN
+			sys.schemas s ON s.schema_id = t.schema_id
+		WHERE
+			s.name = @p1
+		AND
+			t.is_ms_shipped = 0
+		ORDER BY
+			t.name
+	`
+
+	rows, err := mc.Query(mc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	return tables, rows.Err()
+}
+
+// objectID returns the object id and description of a table, or 0 if it does not exist
+func (mc *MSSQLConnector) objectID(schema, tableName string) (int64, string, error) {
+	rows, err := mc.Query(mc.db, `
+		SELECT
+			t.object_id,
+			COALESCE(CAST(ep.value AS nvarchar(max)), '')
+		FROM
+			sys.tables t
+		JOIN
+			sys.schemas s ON s.schema_id = t.schema_id
+		LEFT JOIN
+			sys.extended_properties ep ON ep.class = 1 AND ep.major_id = t.object_id
+			AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+		WHERE
+			s.name = @p1 AND t.name = @p2
+	`, schema, tableName)
+	if err != nil {
+		return 0, "", fmt.Errorf("error checking table existence: %v", err)
+	}
+	defer rows.Close()
+
+	var id int64
+	var comment string
+	if rows.Next() {
+		if err := rows.Scan(&id, &comment); err != nil {
+			return 0, "", fmt.Errorf("error checking table existence: %v", err)
+		}
+	}
+	return id, comment, rows.Err()
+}
+
+// GetTableStructure returns the structure of the specified table
+func (mc *MSSQLConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if mc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	id, comment, err := mc.objectID(schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	table := &t.Table{Name: tableName, Schema: schema, Comment: comment}
+	if table.Columns, err = mc.getColumns(id, schema); err != nil {
+		return nil, err
+	}
+	if table.Indexes, err = mc.getIndexes(id); err != nil {
+		return nil, err
+	}
+	if table.Constraints, err = mc.getConstraints(id); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// formatDataType builds a type name with its length, precision or scale
+func formatDataType(name string, maxLength, precision, scale int) string {
+	switch name {
+	case "varchar", "char", "varbinary", "binary":
+		if maxLength < 0 {
+			return name + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", name, maxLength)
+	case "nvarchar", "nchar":
+		// The length is stored in bytes, two per character
+		if maxLength < 0 {
+			return name + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", name, maxLength/2)
+	case "decimal", "numeric":
+		return fmt.Sprintf("%s(%d,%d)", name, precision, scale)
+	case "datetime2", "datetimeoffset", "time":
+		return fmt.Sprintf("%s(%d)", name, scale)
+	}
+	return name
+}
+
+// getColumns returns the columns of a table with their foreign key references
+func (mc *MSSQLConnector) getColumns(id int64, schema string) ([]t.Column, error) {
+	query := `
+		SELECT
+			c.name,
+			TYPE_NAME(c.user_type_id),
+			c.max_length,
+			c.precision,
+			c.scale,
+			c.is_nullable,
+			OBJECT_DEFINITION(c.default_object_id),
+			CAST(CASE WHEN pk.column_id IS NULL THEN 0 ELSE 1 END AS bit),
+			fk.ref_schema,
+			fk.ref_table,
+			fk.ref_column,
+			COALESCE(CAST(ep.value AS nvarchar(max)), '')
+		FROM
+			sys.columns c
+		LEFT JOIN (
+			SELECT ic.object_id, ic.column_id
+			FROM sys.indexes i
+			JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+			WHERE i.is_primary_key = 1
+		) pk ON pk.object_id = c.object_id AND pk.column_id = c.column_id
+		OUTER APPLY (
+			SELECT TOP 1
+				OBJECT_SCHEMA_NAME(fkc.referenced_object_id) AS ref_schema,
+				OBJECT_NAME(fkc.referenced_object_id) AS ref_table,
+				COL_NAME(fkc.referenced_object_id, fkc.referenced_column_id) AS ref_column
+			FROM sys.foreign_key_columns fkc
+			WHERE fkc.parent_object_id = c.object_id AND fkc.parent_column_id = c.column_id
+			ORDER BY fkc.constraint_object_id
+		) fk
+		LEFT JOIN
+			sys.extended_properties ep ON ep.class = 1 AND ep.major_id = c.object_id
+			AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'
+		WHERE
+			c.object_id = @p1
+		ORDER BY
+			c.column_id
+	`
+
+	rows, err := mc.Query(mc.db, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []t.Column
+	for rows.Next() {
+		var col t.Column
+		var typeName string
+		var maxLength, precision, scale int
+		var refSchema, refTable, refColumn sql.NullString
+
+		err := rows.Scan(&col.Name, &typeName, &maxLength, &precision, &scale, &col.Nullable,
+			&col.DefaultValue, &col.IsPrimaryKey, &refSchema, &refTable, &refColumn, &col.Comment)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+
+		col.Type = formatDataType(typeName, maxLength, precision, scale)
+		if refTable.Valid {
+			ref := refTable.String
+			if refSchema.String != schema {
+				ref = refSchema.String + "." + ref
+			}
+			col.ForeignKey = sql.NullString{String: fmt.Sprintf("%s (%s)", ref, refColumn.String), Valid: true}
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, rows.Err()
+}
+
+// getIndexes returns the indexes of a table, with key columns in index key order.
+// Included columns are not part of the key and are left out.
+func (mc *MSSQLConnector) getIndexes(id int64) ([]t.Index, error) {
+	query := `
+		SELECT
+			i.name,
+			c.name,
+			i.is_unique,
+			i.is_primary_key,
+			LOWER(i.type_desc),
+			COALESCE(i.filter_definition, '')
+		FROM
+			sys.indexes i
+		JOIN
+			sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN
+			sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE
+			i.object_id = @p1
+			AND i.index_id > 0
+			AND i.is_hypothetical = 0
+			AND ic.is_included_column = 0
+		ORDER BY
+			i.name, ic.key_ordinal
+	`
+
+	rows, err := mc.Query(mc.db, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error querying indexes: %v", err)
+	}
+	defer rows.Close()
+
+	indexMap := make(map[string]*t.Index)
+	for rows.Next() {
+		var indexName, columnName, method, predicate string
+		var isUnique, isPrimary bool
+
+		if err := rows.Scan(&indexName, &columnName, &isUnique, &isPrimary, &method, &predicate); err != nil {
+			return nil, fmt.Errorf("error scanning index results: %v", err)
+		}
+
+		if idx, exists := indexMap[indexName]; exists {
+			idx.Columns = append(idx.Columns, columnName)
+			continue
+		}
+		indexMap[indexName] = &t.Index{
+			Name:       indexName,
+			Columns:    []string{columnName},
+			Unique:     isUnique,
+			PrimaryKey: isPrimary,
+			Method:     method,
+			Predicate:  predicate,
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert map to slice, sorted by name for a stable output
+	var indexes []t.Index
+	for _, idx := range indexMap {
+		indexes = append(indexes, *idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+
+	return indexes, nil
+}
+
+// getConstraints returns the key, foreign key and check constraints of a table
+func (mc *MSSQLConnector) getConstraints(id int64) ([]t.Constraint, error) {
+	query := `
+		SELECT
+			kc.name,
+			CASE kc.type WHEN 'PK' THEN 'PRIMARY KEY' ELSE 'UNIQUE' END,
+			c.name,
+			NULL,
+			NULL,
+			ic.key_ordinal
+		FROM
+			sys.key_constraints kc
+		JOIN
+			sys.index_columns ic ON ic.object_id = kc.parent_object_id AND ic.index_id = kc.unique_index_id
+		JOIN
+			sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE
+			kc.parent_object_id = @p1 AND ic.is_included_column = 0
+		UNION ALL
+		SELECT
+			fk.name,
+			'FOREIGN KEY',
+			COL_NAME(fkc.parent_object_id, fkc.parent_column_id),
+			OBJECT_SCHEMA_NAME(fkc.referenced_object_id) + '.' + OBJECT_NAME(fkc.referenced_object_id),
+			COL_NAME(fkc.referenced_object_id, fkc.referenced_column_id),
+			fkc.constraint_column_id
+		FROM
+			sys.foreign_keys fk
+		JOIN
+			sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+		WHERE
+			fk.parent_object_id = @p1
+		ORDER BY
+			1, 6
+	`
+
+	rows, err := mc.Query(mc.db, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error querying constraints: %v", err)
+	}
+	defer rows.Close()
+
+	var constraints []t.Constraint
+	var refTable string
+	var refColumns []string
+	for rows.Next() {
+		var name, conType, column string
+		var referencedTable, referencedColumn sql.NullString
+		var ordinal int
+
+		if err := rows.Scan(&name, &conType, &column, &referencedTable, &referencedColumn, &ordinal); err != nil {
+			return nil, fmt.Errorf("error scanning constraint results: %v", err)
+		}
+
+		// Rows come one per column; a new name starts the next constraint
+		if n := len(constraints); n == 0 || constraints[n-1].Name != name {
+			if n > 0 {
+				constraints[n-1].Definition = constraintDefinition(constraints[n-1], refTable, refColumns)
+			}
+			constraints = append(constraints, t.Constraint{Name: name, Type: conType})
+			refTable, refColumns = referencedTable.String, nil
+		}
+		con := &constraints[len(constraints)-1]
+		con.Columns = append(con.Columns, column)
+		if referencedColumn.Valid {
+			refColumns = append(refColumns, referencedColumn.String)
+		}
+	}
+	if n := len(constraints); n > 0 {
+		constraints[n-1].Definition = constraintDefinition(constraints[n-1], refTable, refColumns)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	checks, err := mc.getCheckConstraints(id)
+	if err != nil {
+		return nil, err
+	}
+	constraints = append(constraints, checks...)
+	sort.SliceStable(constraints, func(i, j int) bool {
+		return constraints[i].Name < constraints[j].Name
+	})
+
+	return constraints, nil
+}
+
+// getCheckConstraints returns the check constraints of a table
+func (mc *MSSQLConnector) getCheckConstraints(id int64) ([]t.Constraint, error) {
+	query := `
+		SELECT
+			cc.name,
+			COL_NAME(cc.parent_object_id, cc.parent_column_id),
+			cc.definition
+		FROM
+			sys.check_constraints cc
+		WHERE
+			cc.parent_object_id = @p1
+		ORDER BY
+			cc.name
+	`
+
+	rows, err := mc.Query(mc.db, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error querying check constraints: %v", err)
+	}
+	defer rows.Close()
+
+	var constraints []t.Constraint
+	for rows.Next() {
+		var con t.Constraint
+		var column sql.NullString // Set for column-level checks only
+		var definition string
+
+		if err := rows.Scan(&con.Name, &column, &definition); err != nil {
+			return nil, fmt.Errorf("error scanning check constraint results: %v", err)
+		}
+
+		con.Type = t.CheckConstraint
+		con.Definition = "CHECK " + definition
+		if column.Valid {
+			con.Columns = []string{column.String}
+		}
+		constraints = append(constraints, con)
+	}
+
+	return constraints, rows.Err()
+}
+
+// constraintDefinition builds the SQL definition of a key constraint
+func constraintDefinition(con t.Constraint, refTable string, refColumns []string) string {
+	def := fmt.Sprintf("%s (%s)", con.Type, strings.Join(con.Columns, ", "))
+	if con.Type == t.ForeignKeyConstraint {
+		def += fmt.Sprintf(" REFERENCES %s(%s)", refTable, strings.Join(refColumns, ", "))
+	}
+	return def
+}
+
+// NewMSSQLConnector creates a connector for Microsoft SQL Server
+func NewMSSQLConnector() t.DatabaseConnector {
+	return &MSSQLConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverMSSQL, NewMSSQLConnector, t.ConnectorInfo{
+		Title:  "SQL Server",
+		Fields: t.ServerFields,
+		Defaults: func(p *t.ConnectionParams) {
+			p.DefaultServer("1433", "sa")
+			p.Schema = cmp.Or(p.Schema, "dbo")
+		},
+	})
+}
@@ -0,0 +1,69 @@
+package mssql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetRoutines returns the stored procedures and user-defined functions of the schema
+func (mc *MSSQLConnector) GetRoutines(schema string) ([]t.Routine, error) {
+	if mc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Parameter 0 of a scalar function is its return value. Table-valued
+	// functions return TABLE, and CLR routines have no T-SQL source.
+	query := `
+		SELECT
+			o.name,
+			CASE WHEN o.type IN ('P', 'PC') THEN 'PROCEDURE' ELSE 'FUNCTION' END,
+			COALESCE(STUFF((
+				SELECT ', ' + p.name + ' ' + TYPE_NAME(p.user_type_id) + CASE WHEN p.is_output = 1 THEN ' OUTPUT' ELSE '' END
+				FROM sys.parameters p
+				WHERE p.object_id = o.object_id AND p.parameter_id > 0
+				ORDER BY p.parameter_id
+				FOR XML PATH(''), TYPE
+			).value('.', 'nvarchar(max)'), 1, 2, ''), ''),
+			CASE
+				WHEN o.type IN ('IF', 'TF', 'FT') THEN 'TABLE'
+				ELSE COALESCE((
+					SELECT TYPE_NAME(p.user_type_id) FROM sys.parameters p
+					WHERE p.object_id = o.object_id AND p.parameter_id = 0
+				), '')
+			END,
+			CASE WHEN o.type IN ('PC', 'FS', 'FT') THEN 'CLR' ELSE 'T-SQL' END,
+			COALESCE(OBJECT_DEFINITION(o.object_id), ''),
+			COALESCE(CAST(ep.value AS nvarchar(max)), '')
+		FROM
+			sys.objects o
+		JOIN
+			sys.schemas s ON s.schema_id = o.schema_id
+		LEFT JOIN
+			sys.extended_properties ep ON ep.major_id = o.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+		WHERE
+			s.name = @p1
+			AND o.type IN ('P', 'PC', 'FN', 'IF', 'TF', 'FS', 'FT')
+			AND o.is_ms_shipped = 0
+		ORDER BY
+			o.name
+	`
+
+	rows, err := mc.Query(mc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying routines: %v", err)
+	}
+	defer rows.Close()
+
+	var routines []t.Routine
+	for rows.Next() {
+		routine := t.Routine{Schema: schema}
+		if err := rows.Scan(&routine.Name, &routine.Kind, &routine.Arguments, &routine.ReturnType,
+			&routine.Language, &routine.Source, &routine.Comment); err != nil {
+			return nil, fmt.Errorf("error scanning routine results: %v", err)
+		}
+		routines = append(routines, routine)
+	}
+
+	return routines, rows.Err()
+}
@@ -0,0 +1,70 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// quoteIdentifier quotes a SQL Server identifier
+func quoteIdentifier(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// quoteQualified returns a quoted schema-qualified name for use in queries
+func quoteQualified(schema, name string) string {
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// EstimateRowCount returns the row count kept in the partition metadata of a table
+func (mc *MSSQLConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if mc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	id, _, err := mc.objectID(schema, tableName)
+	if err != nil {
+		return 0, err
+	}
+	if id == 0 {
+		return 0, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	// The heap or clustered index holds every row once
+	rows, err := mc.Query(mc.db, `
+		SELECT COALESCE(SUM(p.rows), 0)
+		FROM sys.partitions p
+		WHERE p.object_id = @p1 AND p.index_id IN (0, 1)
+	`, id)
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	defer rows.Close()
+
+	var estimate int64
+	if rows.Next() {
+		if err := rows.Scan(&estimate); err != nil {
+			return 0, fmt.Errorf("error querying row estimate: %v", err)
+		}
+	}
+	return estimate, rows.Err()
+}
+
+// CountRows returns the exact number of rows in a table
+func (mc *MSSQLConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if mc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := "SELECT COUNT_BIG(*) FROM " + quoteQualified(schema, tableName)
+	mc.LogQuery(query, nil)
+
+	var count int64
+	if err := mc.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
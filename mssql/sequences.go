@@ -0,0 +1,58 @@
+package mssql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetSequences returns the sequences of the schema, with the column whose
+// default takes its values from each, if any
+func (mc *MSSQLConnector) GetSequences(schema string) ([]t.Sequence, error) {
+	if mc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			q.name,
+			TYPE_NAME(q.user_type_id),
+			CAST(q.current_value AS bigint),
+			CAST(q.increment AS bigint),
+			CAST(q.minimum_value AS varchar(40)),
+			CAST(q.maximum_value AS varchar(40)),
+			q.is_cycling,
+			COALESCE((
+				SELECT TOP 1 OBJECT_NAME(dc.parent_object_id) + '.' + c.name
+				FROM sys.sql_expression_dependencies d
+				JOIN sys.default_constraints dc ON dc.object_id = d.referencing_id
+				JOIN sys.columns c ON c.object_id = dc.parent_object_id AND c.column_id = dc.parent_column_id
+				WHERE d.referenced_id = q.object_id
+			), '')
+		FROM
+			sys.sequences q
+		JOIN
+			sys.schemas s ON s.schema_id = q.schema_id
+		WHERE
+			s.name = @p1
+		ORDER BY
+			q.name
+	`
+
+	rows, err := mc.Query(mc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying sequences: %v", err)
+	}
+	defer rows.Close()
+
+	var sequences []t.Sequence
+	for rows.Next() {
+		seq := t.Sequence{Schema: schema}
+		if err := rows.Scan(&seq.Name, &seq.DataType, &seq.Current, &seq.Increment, &seq.Min, &seq.Max, &seq.Cycle, &seq.OwnedBy); err != nil {
+			return nil, fmt.Errorf("error scanning sequence results: %v", err)
+		}
+		sequences = append(sequences, seq)
+	}
+
+	return sequences, rows.Err()
+}
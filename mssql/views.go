@@ -0,0 +1,71 @@
+package mssql
+
+import (
+	"fmt"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViews returns the views of the schema, with their columns and SELECT
+// statements. Indexed views are reported as materialized.
+func (mc *MSSQLConnector) GetViews(schema string) ([]t.View, error) {
+	if mc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			v.object_id,
+			v.name,
+			CAST(CASE WHEN EXISTS (
+				SELECT 1 FROM sys.indexes i WHERE i.object_id = v.object_id AND i.type = 1
+			) THEN 1 ELSE 0 END AS bit),
+			COALESCE(OBJECT_DEFINITION(v.object_id), ''),
+			COALESCE(CAST(ep.value AS nvarchar(max)), '')
+		FROM
+			sys.views v
+		JOIN
+			sys.schemas s ON s.schema_id = v.schema_id
+		LEFT JOIN
+			sys.extended_properties ep ON ep.class = 1 AND ep.major_id = v.object_id
+			AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+		WHERE
+			s.name = @p1
+		AND
+			v.is_ms_shipped = 0
+		ORDER BY
+			v.name
+	`
+
+	rows, err := mc.Query(mc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+	defer rows.Close()
+
+	var views []t.View
+	var ids []int64
+	for rows.Next() {
+		view := t.View{Schema: schema}
+		var id int64
+		if err := rows.Scan(&id, &view.Name, &view.Materialized, &view.Definition, &view.Comment); err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		// SQL Server keeps the CREATE VIEW statement
+		view.Definition = sqlutil.ViewQuery(view.Definition)
+		views = append(views, view)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading views: %v", err)
+	}
+	rows.Close()
+
+	for i := range views {
+		if views[i].Columns, err = mc.getColumns(ids[i], schema); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
@@ -0,0 +1,26 @@
+package mysql
+
+import "fmt"
+
+// GetTableDDL returns the CREATE TABLE statement of SHOW CREATE TABLE, which
+// includes the indexes and foreign keys
+func (mc *MySQLConnector) GetTableDDL(schema, tableName string) (string, error) {
+	if mc.db == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+
+	rows, err := mc.Query(mc.db, "SHOW CREATE TABLE "+quoteQualified(mc.schemaName(schema), tableName))
+	if err != nil {
+		return "", fmt.Errorf("error querying table definition: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return "", fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	var name, ddl string
+	if err := rows.Scan(&name, &ddl); err != nil {
+		return "", fmt.Errorf("error scanning table definition: %v", err)
+	}
+	return ddl + ";\n", nil
+}
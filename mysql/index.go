@@ -0,0 +1,364 @@
+package mysql
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	"github.com/go-sql-driver/mysql" // MySQL driver
+)
+
+// MySQLConnector implements the DatabaseConnector interface for MySQL and MariaDB.
+// MySQL has no schemas within a database, so the schema names a database on the server.
+type MySQLConnector struct {
+	sqlutil.QueryLog
+
+	db       *sql.DB
+	database string // Database connected to, used when no schema is given
+}
+
+// Connect establishes a connection to the MySQL server
+func (mc *MySQLConnector) Connect(params t.ConnectionParams) error {
+	cfg := mysql.NewConfig()
+	cfg.User = params.User
+	cfg.Passwd = params.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(params.Host, params.Port)
+	cfg.DBName = params.Database
+	cfg.Params = params.Settings
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+	mc.db = sql.OpenDB(connector)
+
+	// Test the connection
+	if err := mc.db.Ping(); err != nil {
+		mc.db.Close()
+		mc.db = nil
+		return fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	mc.database = params.Database
+	return nil
+}
+
+// Disconnect closes the database connection
+func (mc *MySQLConnector) Disconnect() error {
+	if mc.db != nil {
+		err := mc.db.Close()
+		mc.db = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// schemaName returns the database to inspect, defaulting to the connected one
+func (mc *MySQLConnector) schemaName(schema string) string {
+	if schema == "" {
+		return mc.database
+	}
+	return schema
+}
+
+// GetTables returns a list of tables in the specified database
+func (mc *MySQLConnector) GetTables(schema string) ([]string, error) {
+	if mc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			table_name
+		FROM
+			information_schema.tables
+		WHERE
+			table_schema = ?
+		AND
+			table_type = 'BASE TABLE'
+		ORDER BY
+			table_name
+	`
+
+	rows, err := mc.Query(mc.db, query, mc.schemaName(schema))
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	return tables, rows.Err()
+}
+
+// GetTableStructure returns the structure of the specified table
+func (mc *MySQLConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if mc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = mc.schemaName(schema)
+
+	// Check if the table exists, reading its comment at the same time
+	rows, err := mc.Query(mc.db, `
+		SELECT table_comment
+		FROM information_schema.tables
+		WHERE table_schema = ? AND table_name = ?
+	`, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	table := &t.Table{Name: tableName, Schema: schema}
+	exists := rows.Next()
+	if exists {
+		err = rows.Scan(&table.Comment)
+	}
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	if table.Columns, err = mc.getColumns(schema, tableName); err != nil {
+		return nil, err
+	}
+	if table.Indexes, err = mc.getIndexes(schema, tableName); err != nil {
+		return nil, err
+	}
+	if table.Constraints, err = mc.getConstraints(schema, tableName); err != nil {
+		return nil, err
+	}
+	if table.Triggers, err = mc.getTriggers(schema, tableName); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// getColumns returns the columns of a table with their foreign key references
+func (mc *MySQLConnector) getColumns(schema, tableName string) ([]t.Column, error) {
+	query := `
+		SELECT
+			c.column_name,
+			c.column_type,
+			c.is_nullable = 'YES' AS is_nullable,
+			c.column_default,
+			c.column_key = 'PRI' AS is_primary_key,
+			k.referenced_table_schema,
+			k.referenced_table_name,
+			k.referenced_column_name,
+			c.column_comment
+		FROM
+			information_schema.columns c
+		LEFT JOIN
+			information_schema.key_column_usage k ON k.table_schema = c.table_schema
+			AND k.table_name = c.table_name AND k.column_name = c.column_name
+			AND k.referenced_table_name IS NOT NULL
+		WHERE
+			c.table_schema = ? AND c.table_name = ?
+		ORDER BY
+			c.ordinal_position
+	`
+
+	rows, err := mc.Query(mc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []t.Column
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var col t.Column
+		var refSchema, refTable, refColumn sql.NullString
+
+		err := rows.Scan(&col.Name, &col.Type, &col.Nullable, &col.DefaultValue, &col.IsPrimaryKey,
+			&refSchema, &refTable, &refColumn, &col.Comment)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+
+		// A column in several foreign keys shows up once per key; the first one is kept
+		if seen[col.Name] {
+			continue
+		}
+		seen[col.Name] = true
+
+		if refTable.Valid {
+			ref := refTable.String
+			if refSchema.String != schema {
+				ref = refSchema.String + "." + ref
+			}
+			col.ForeignKey = sql.NullString{String: fmt.Sprintf("%s (%s)", ref, refColumn.String), Valid: true}
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, rows.Err()
+}
+
+// getIndexes returns the indexes of a table, with columns in index key order
+func (mc *MySQLConnector) getIndexes(schema, tableName string) ([]t.Index, error) {
+	query := `
+		SELECT
+			index_name,
+			column_name,
+			non_unique = 0 AS is_unique,
+			index_type
+		FROM
+			information_schema.statistics
+		WHERE
+			table_schema = ? AND table_name = ?
+		ORDER BY
+			index_name, seq_in_index
+	`
+
+	rows, err := mc.Query(mc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying indexes: %v", err)
+	}
+	defer rows.Close()
+
+	indexMap := make(map[string]*t.Index)
+	for rows.Next() {
+		var indexName, indexType string
+		var columnName sql.NullString // NULL for functional key parts
+		var isUnique bool
+
+		if err := rows.Scan(&indexName, &columnName, &isUnique, &indexType); err != nil {
+			return nil, fmt.Errorf("error scanning index results: %v", err)
+		}
+
+		idx, exists := indexMap[indexName]
+		if !exists {
+			idx = &t.Index{
+				Name:       indexName,
+				Unique:     isUnique,
+				PrimaryKey: indexName == "PRIMARY",
+				Method:     strings.ToLower(indexType),
+			}
+			indexMap[indexName] = idx
+		}
+		if columnName.Valid {
+			idx.Columns = append(idx.Columns, columnName.String)
+		} else {
+			idx.Expression = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert map to slice, sorted by name for a stable output
+	var indexes []t.Index
+	for _, idx := range indexMap {
+		indexes = append(indexes, *idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+
+	return indexes, nil
+}
+
+// getConstraints returns the primary key, unique and foreign key constraints of a table
+func (mc *MySQLConnector) getConstraints(schema, tableName string) ([]t.Constraint, error) {
+	query := `
+		SELECT
+			tc.constraint_name,
+			tc.constraint_type,
+			k.column_name,
+			k.referenced_table_name,
+			k.referenced_column_name
+		FROM
+			information_schema.table_constraints tc
+		JOIN
+			information_schema.key_column_usage k ON k.constraint_schema = tc.constraint_schema
+			AND k.constraint_name = tc.constraint_name AND k.table_name = tc.table_name
+		WHERE
+			tc.table_schema = ? AND tc.table_name = ?
+			AND tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+		ORDER BY
+			tc.constraint_name, k.ordinal_position
+	`
+
+	rows, err := mc.Query(mc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying constraints: %v", err)
+	}
+	defer rows.Close()
+
+	var constraints []t.Constraint
+	var refTable string
+	var refColumns []string
+	for rows.Next() {
+		var name, conType, column string
+		var referencedTable, referencedColumn sql.NullString
+
+		if err := rows.Scan(&name, &conType, &column, &referencedTable, &referencedColumn); err != nil {
+			return nil, fmt.Errorf("error scanning constraint results: %v", err)
+		}
+
+		// Rows come one per column; a new name starts the next constraint
+		if n := len(constraints); n == 0 || constraints[n-1].Name != name {
+			if n > 0 {
+				constraints[n-1].Definition = constraintDefinition(constraints[n-1], refTable, refColumns)
+			}
+			constraints = append(constraints, t.Constraint{Name: name, Type: conType})
+			refTable, refColumns = referencedTable.String, nil
+		}
+		con := &constraints[len(constraints)-1]
+		con.Columns = append(con.Columns, column)
+		if referencedColumn.Valid {
+			refColumns = append(refColumns, referencedColumn.String)
+		}
+	}
+	if n := len(constraints); n > 0 {
+		constraints[n-1].Definition = constraintDefinition(constraints[n-1], refTable, refColumns)
+	}
+
+	return constraints, rows.Err()
+}
+
+// constraintDefinition builds the SQL definition of a key constraint, which MySQL does not store
+func constraintDefinition(con t.Constraint, refTable string, refColumns []string) string {
+	def := fmt.Sprintf("%s (%s)", con.Type, strings.Join(con.Columns, ", "))
+	if con.Type == t.ForeignKeyConstraint {
+		def += fmt.Sprintf(" REFERENCES %s(%s)", refTable, strings.Join(refColumns, ", "))
+	}
+	return def
+}
+
+// NewMySQLConnector creates a connector for MySQL and MariaDB servers
+func NewMySQLConnector() t.DatabaseConnector {
+	return &MySQLConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverMySQL, NewMySQLConnector, t.ConnectorInfo{
+		Title:  "MySQL / MariaDB",
+		Fields: t.ServerFields,
+		Defaults: func(p *t.ConnectionParams) {
+			// MySQL has no schemas, so the schema is the database
+			p.DefaultServer("3306", "root")
+			p.Schema = cmp.Or(p.Schema, p.Database)
+		},
+	})
+}
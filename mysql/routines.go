@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetRoutines returns the stored functions and procedures in the database
+func (mc *MySQLConnector) GetRoutines(schema string) ([]t.Routine, error) {
+	if mc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = mc.schemaName(schema)
+
+	// routine_definition is NULL for users who are neither the definer nor
+	// granted SHOW_ROUTINE
+	query := `
+		SELECT
+			r.routine_name,
+			r.routine_type,
+			COALESCE((
+				SELECT GROUP_CONCAT(CONCAT_WS(' ', p.parameter_mode, p.parameter_name, p.dtd_identifier)
+					ORDER BY p.ordinal_position SEPARATOR ', ')
+				FROM information_schema.parameters p
+				WHERE p.specific_schema = r.routine_schema
+					AND p.specific_name = r.specific_name
+					AND p.ordinal_position > 0
+			), ''),
+			COALESCE(r.dtd_identifier, ''),
+			r.routine_body,
+			COALESCE(r.routine_definition, ''),
+			r.routine_comment
+		FROM
+			information_schema.routines r
+		WHERE
+			r.routine_schema = ?
+		ORDER BY
+			r.routine_name
+	`
+
+	rows, err := mc.Query(mc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying routines: %v", err)
+	}
+	defer rows.Close()
+
+	var routines []t.Routine
+	for rows.Next() {
+		routine := t.Routine{Schema: schema}
+		if err := rows.Scan(&routine.Name, &routine.Kind, &routine.Arguments, &routine.ReturnType,
+			&routine.Language, &routine.Source, &routine.Comment); err != nil {
+			return nil, fmt.Errorf("error scanning routine results: %v", err)
+		}
+		routines = append(routines, routine)
+	}
+
+	return routines, rows.Err()
+}
@@ -0,0 +1,146 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// quoteIdentifier quotes a MySQL identifier
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteQualified returns a quoted database-qualified name for use in queries
+func quoteQualified(schema, name string) string {
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// EstimateRowCount returns the row estimate the storage engine keeps for a table
+func (mc *MySQLConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if mc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := mc.Query(mc.db, `
+		SELECT table_rows
+		FROM information_schema.tables
+		WHERE table_schema = ? AND table_name = ?
+	`, mc.schemaName(schema), tableName)
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	var estimate sql.NullInt64
+	if err := rows.Scan(&estimate); err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	if !estimate.Valid {
+		return -1, nil
+	}
+	return estimate.Int64, nil
+}
+
+// GetTableSizes returns the row estimate and the data and index size the
+// storage engine keeps for every table in the schema
+func (mc *MySQLConnector) GetTableSizes(schema string) ([]t.TableSize, error) {
+	if mc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := mc.Query(mc.db, `
+		SELECT table_name, COALESCE(data_length + index_length, 0), table_rows
+		FROM information_schema.tables
+		WHERE table_schema = ? AND table_type = 'BASE TABLE'
+	`, mc.schemaName(schema))
+	if err != nil {
+		return nil, fmt.Errorf("error querying table sizes: %v", err)
+	}
+	defer rows.Close()
+
+	var sizes []t.TableSize
+	for rows.Next() {
+		var size t.TableSize
+		var estimate sql.NullInt64
+		if err := rows.Scan(&size.Name, &size.Bytes, &estimate); err != nil {
+			return nil, fmt.Errorf("error scanning table sizes: %v", err)
+		}
+		size.Rows = -1
+		if estimate.Valid {
+			size.Rows = estimate.Int64
+		}
+		sizes = append(sizes, size)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading table sizes: %v", err)
+	}
+
+	return sizes, nil
+}
+
+// CountRows returns the exact number of rows in a table
+func (mc *MySQLConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if mc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := "SELECT COUNT(*) FROM " + quoteQualified(mc.schemaName(schema), tableName)
+	mc.LogQuery(query, nil)
+
+	var count int64
+	if err := mc.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
+
+// ChecksumTable returns the row count of a table and a hash of its rows. Each row
+// is hashed from its column values and the hashes are summed, so the result does
+// not depend on row order.
+func (mc *MySQLConnector) ChecksumTable(ctx context.Context, schema, tableName string) (int64, string, error) {
+	if mc.db == nil {
+		return 0, "", fmt.Errorf("not connected to database")
+	}
+	schema = mc.schemaName(schema)
+
+	columns, err := mc.getColumns(schema, tableName)
+	if err != nil {
+		return 0, "", err
+	}
+	if len(columns) == 0 {
+		return 0, "", fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	// NULLs get a marker of their own, as CONCAT_WS would skip them
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		values[i] = fmt.Sprintf("COALESCE(HEX(%s), 'NULL')", quoteIdentifier(col.Name))
+	}
+	query := fmt.Sprintf(`
+		SELECT
+			COUNT(*),
+			CAST(COALESCE(SUM(CAST(CONV(LEFT(MD5(CONCAT_WS('#', %s)), 16), 16, 10) AS UNSIGNED)), 0) AS CHAR)
+		FROM %s`, strings.Join(values, ", "), quoteQualified(schema, tableName))
+	mc.LogQuery(query, nil)
+
+	var rows int64
+	var hash string
+	if err := mc.db.QueryRowContext(ctx, query).Scan(&rows, &hash); err != nil {
+		if ctx.Err() != nil {
+			return 0, "", fmt.Errorf("checksum cancelled")
+		}
+		return 0, "", fmt.Errorf("error computing checksum of %s: %v", tableName, err)
+	}
+
+	return rows, hash, nil
+}
@@ -0,0 +1,48 @@
+package mysql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// getTriggers returns the triggers of a table. MySQL triggers fire on a
+// single event, for each row, and cannot be disabled.
+func (mc *MySQLConnector) getTriggers(schema, tableName string) ([]t.Trigger, error) {
+	query := `
+		SELECT
+			trigger_name,
+			action_timing,
+			event_manipulation,
+			action_orientation,
+			action_statement
+		FROM
+			information_schema.triggers
+		WHERE
+			event_object_schema = ?
+			AND event_object_table = ?
+		ORDER BY
+			trigger_name
+	`
+
+	rows, err := mc.Query(mc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying triggers: %v", err)
+	}
+	defer rows.Close()
+
+	var triggers []t.Trigger
+	for rows.Next() {
+		trigger := t.Trigger{Enabled: true}
+		var event, body string
+		if err := rows.Scan(&trigger.Name, &trigger.Timing, &event, &trigger.ForEach, &body); err != nil {
+			return nil, fmt.Errorf("error scanning trigger results: %v", err)
+		}
+		trigger.Events = []string{event}
+		trigger.Definition = fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH %s %s",
+			quoteIdentifier(trigger.Name), trigger.Timing, event, quoteIdentifier(tableName), trigger.ForEach, body)
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers, rows.Err()
+}
@@ -0,0 +1,53 @@
+package mysql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViews returns the views in the database, with their columns and SELECT statements
+func (mc *MySQLConnector) GetViews(schema string) ([]t.View, error) {
+	if mc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = mc.schemaName(schema)
+
+	query := `
+		SELECT
+			table_name,
+			view_definition
+		FROM
+			information_schema.views
+		WHERE
+			table_schema = ?
+		ORDER BY
+			table_name
+	`
+
+	rows, err := mc.Query(mc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+	defer rows.Close()
+
+	var views []t.View
+	for rows.Next() {
+		view := t.View{Schema: schema}
+		if err := rows.Scan(&view.Name, &view.Definition); err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading views: %v", err)
+	}
+	rows.Close()
+
+	for i := range views {
+		if views[i].Columns, err = mc.getColumns(schema, views[i].Name); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
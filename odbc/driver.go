@@ -0,0 +1,21 @@
+//go:build odbc
+
+package odbc
+
+import (
+	_ "github.com/alexbrainman/odbc" // ODBC driver
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// init registers the connector under its driver name. The ODBC driver links
+// against the unixODBC or Windows driver manager, so the connector is only
+// built with the odbc build tag.
+func init() {
+	t.RegisterConnector(t.DriverODBC, NewODBCConnector, t.ConnectorInfo{
+		Title: "ODBC data source",
+		// The data source gives the server and takes the settings as attributes
+		Fields: []string{t.FieldUser, t.FieldPassword, t.FieldSecret, t.FieldDatabase, t.FieldSchema, t.FieldSettings},
+		Hint:   "Data source name, or connection string",
+	})
+}
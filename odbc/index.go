@@ -0,0 +1,388 @@
+package odbc
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// catalogSchemas are the schemas holding the standard catalog views, in the order
+// they are tried. Db2 keeps them in SYSIBM; Informix installs them on request.
+var catalogSchemas = []string{"information_schema", "SYSIBM"}
+
+// ODBCConnector implements the DatabaseConnector interface for ODBC data sources,
+// introspected through the SQL standard information_schema views
+type ODBCConnector struct {
+	sqlutil.QueryLog
+
+	db          *sql.DB
+	catalog     string // Schema of the catalog views of the data source
+	constraints bool   // Data source has the constraint views, which Informix lacks
+}
+
+// Connect opens an ODBC data source. The database is a data source name, or a
+// whole connection string such as "Driver={IBM DB2 ODBC DRIVER};Database=SAMPLE";
+// session settings are added to the connection string as attributes.
+func (oc *ODBCConnector) Connect(params t.ConnectionParams) error {
+	var err error
+	oc.db, err = sql.Open("odbc", connectionString(params))
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	// Test the connection
+	if err := oc.db.Ping(); err != nil {
+		oc.db.Close()
+		oc.db = nil
+		return fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	// Find the catalog views, which not every data source has
+	oc.catalog = ""
+	for _, schema := range catalogSchemas {
+		if oc.hasView(schema + ".tables") {
+			oc.catalog = schema
+			break
+		}
+	}
+	if oc.catalog == "" {
+		oc.db.Close()
+		oc.db = nil
+		return fmt.Errorf("the data source has no information_schema views to read the schema from")
+	}
+	oc.constraints = oc.hasView(oc.catalog+".table_constraints") && oc.hasView(oc.catalog+".referential_constraints")
+
+	return nil
+}
+
+// hasView reports whether a catalog view can be queried
+func (oc *ODBCConnector) hasView(view string) bool {
+	rows, err := oc.Query(oc.db, "SELECT 1 FROM "+view+" WHERE 1 = 0")
+	if err != nil {
+		return false
+	}
+	rows.Close()
+	return true
+}
+
+// connectionString returns the ODBC connection string for the connection parameters
+func connectionString(params t.ConnectionParams) string {
+	conn := params.Database
+	if !strings.Contains(conn, "=") {
+		conn = "DSN=" + conn
+	}
+	conn = strings.TrimSuffix(conn, ";")
+
+	attributes := [][2]string{{"UID", params.User}, {"PWD", params.Password}}
+	keys := make([]string, 0, len(params.Settings))
+	for key := range params.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		attributes = append(attributes, [2]string{key, params.Settings[key]})
+	}
+
+	for _, attr := range attributes {
+		if attr[1] != "" {
+			conn += ";" + attr[0] + "=" + quoteAttribute(attr[1])
+		}
+	}
+	return conn
+}
+
+// quoteAttribute braces an attribute value holding characters special to connection strings
+func quoteAttribute(value string) string {
+	if !strings.ContainsAny(value, ";{}= ") {
+		return value
+	}
+	return "{" + strings.ReplaceAll(value, "}", "}}") + "}"
+}
+
+// Disconnect closes the database connection
+func (oc *ODBCConnector) Disconnect() error {
+	if oc.db != nil {
+		err := oc.db.Close()
+		oc.db = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetTables returns a list of tables in the specified schema
+func (oc *ODBCConnector) GetTables(schema string) ([]string, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			table_name
+		FROM
+			` + oc.catalog + `.tables
+		WHERE
+			table_schema = ?
+			AND table_type = 'BASE TABLE'
+		ORDER BY
+			table_name
+	`
+
+	rows, err := oc.Query(oc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, strings.TrimSpace(tableName))
+	}
+
+	return tables, rows.Err()
+}
+
+// GetTableStructure returns the structure of the specified table. The standard
+// views describe neither indexes nor comments, so tables have none.
+func (oc *ODBCConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Check if table exists
+	rows, err := oc.Query(oc.db, `
+		SELECT table_name
+		FROM `+oc.catalog+`.tables
+		WHERE table_schema = ? AND table_name = ?
+	`, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	exists := rows.Next()
+	rows.Close()
+	if !exists {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	table := &t.Table{Name: tableName, Schema: schema}
+	if oc.constraints {
+		if table.Constraints, err = oc.getConstraints(schema, tableName); err != nil {
+			return nil, err
+		}
+	}
+	if table.Columns, err = oc.getColumns(schema, tableName, table.Constraints); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// getColumns returns the columns of a table, with the primary and foreign keys
+// taken from its constraints
+func (oc *ODBCConnector) getColumns(schema, tableName string, constraints []t.Constraint) ([]t.Column, error) {
+	query := `
+		SELECT
+			column_name,
+			data_type,
+			character_maximum_length,
+			numeric_precision,
+			numeric_scale,
+			is_nullable,
+			column_default
+		FROM
+			` + oc.catalog + `.columns
+		WHERE
+			table_schema = ? AND table_name = ?
+		ORDER BY
+			ordinal_position
+	`
+
+	rows, err := oc.Query(oc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []t.Column
+	for rows.Next() {
+		var col t.Column
+		var dataType, isNullable string
+		var length, precision, scale sql.NullInt64
+
+		err := rows.Scan(&col.Name, &dataType, &length, &precision, &scale, &isNullable, &col.DefaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+
+		// Fixed width catalog columns of legacy databases come padded with blanks
+		col.Name = strings.TrimSpace(col.Name)
+		col.Type = formatDataType(strings.TrimSpace(dataType), length, precision, scale)
+		col.Nullable = strings.TrimSpace(isNullable) == "YES"
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range columns {
+		col := &columns[i]
+		for _, con := range constraints {
+			switch con.Type {
+			case t.PrimaryKeyConstraint:
+				if indexOf(con.Columns, col.Name) >= 0 {
+					col.IsPrimaryKey = true
+				}
+			case t.ForeignKeyConstraint:
+				if ref, ok := foreignKeyRef(con, col.Name); ok && !col.ForeignKey.Valid {
+					col.ForeignKey = sql.NullString{String: ref, Valid: true}
+				}
+			}
+		}
+	}
+
+	return columns, nil
+}
+
+// formatDataType adds the length, or precision and scale, to the types that have them
+func formatDataType(dataType string, length, precision, scale sql.NullInt64) string {
+	upper := strings.ToUpper(dataType)
+	switch {
+	case strings.Contains(upper, "CHAR") || strings.Contains(upper, "BINARY"):
+		if length.Valid {
+			return fmt.Sprintf("%s(%d)", dataType, length.Int64)
+		}
+	case upper == "DECIMAL" || upper == "NUMERIC":
+		if precision.Valid && scale.Valid {
+			return fmt.Sprintf("%s(%d,%d)", dataType, precision.Int64, scale.Int64)
+		}
+	}
+	return dataType
+}
+
+// getConstraints returns the primary key, unique and foreign key constraints of a
+// table. Referenced columns are matched to the key columns by their position in
+// the referenced unique constraint.
+func (oc *ODBCConnector) getConstraints(schema, tableName string) ([]t.Constraint, error) {
+	query := `
+		SELECT
+			tc.constraint_name,
+			tc.constraint_type,
+			k.column_name,
+			rk.table_schema,
+			rk.table_name,
+			rk.column_name
+		FROM
+			` + oc.catalog + `.table_constraints tc
+		JOIN
+			` + oc.catalog + `.key_column_usage k ON k.constraint_schema = tc.constraint_schema
+			AND k.constraint_name = tc.constraint_name
+		LEFT JOIN
+			` + oc.catalog + `.referential_constraints rc ON rc.constraint_schema = tc.constraint_schema
+			AND rc.constraint_name = tc.constraint_name
+		LEFT JOIN
+			` + oc.catalog + `.key_column_usage rk ON rk.constraint_schema = rc.unique_constraint_schema
+			AND rk.constraint_name = rc.unique_constraint_name
+			AND rk.ordinal_position = k.position_in_unique_constraint
+		WHERE
+			tc.table_schema = ? AND tc.table_name = ?
+			AND tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+		ORDER BY
+			tc.constraint_name, k.ordinal_position
+	`
+
+	rows, err := oc.Query(oc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying constraints: %v", err)
+	}
+	defer rows.Close()
+
+	var constraints []t.Constraint
+	var refTable string
+	var refColumns []string
+	for rows.Next() {
+		var name, conType, column string
+		var referencedSchema, referencedTable, referencedColumn sql.NullString
+
+		err := rows.Scan(&name, &conType, &column, &referencedSchema, &referencedTable, &referencedColumn)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning constraint results: %v", err)
+		}
+		name = strings.TrimSpace(name)
+
+		// Rows come one per column; a new name starts the next constraint
+		if n := len(constraints); n == 0 || constraints[n-1].Name != name {
+			if n > 0 {
+				constraints[n-1].Definition = constraintDefinition(constraints[n-1], refTable, refColumns)
+			}
+			constraints = append(constraints, t.Constraint{Name: name, Type: strings.TrimSpace(conType)})
+			refTable, refColumns = strings.TrimSpace(referencedTable.String), nil
+			if referencedSchema.Valid && strings.TrimSpace(referencedSchema.String) != schema {
+				refTable = strings.TrimSpace(referencedSchema.String) + "." + refTable
+			}
+		}
+		con := &constraints[len(constraints)-1]
+		con.Columns = append(con.Columns, strings.TrimSpace(column))
+		if referencedColumn.Valid {
+			refColumns = append(refColumns, strings.TrimSpace(referencedColumn.String))
+		}
+	}
+	if n := len(constraints); n > 0 {
+		constraints[n-1].Definition = constraintDefinition(constraints[n-1], refTable, refColumns)
+	}
+
+	return constraints, rows.Err()
+}
+
+// constraintDefinition builds the SQL definition of a key constraint, which the standard views do not hold
+func constraintDefinition(con t.Constraint, refTable string, refColumns []string) string {
+	def := fmt.Sprintf("%s (%s)", con.Type, strings.Join(con.Columns, ", "))
+	if con.Type == t.ForeignKeyConstraint {
+		def += fmt.Sprintf(" REFERENCES %s(%s)", refTable, strings.Join(refColumns, ", "))
+	}
+	return def
+}
+
+// foreignKeyRef returns the "table (column)" reference of a column from a foreign key definition
+func foreignKeyRef(con t.Constraint, column string) (string, bool) {
+	pos := indexOf(con.Columns, column)
+	if pos < 0 {
+		return "", false
+	}
+	_, target, ok := strings.Cut(con.Definition, " REFERENCES ")
+	if !ok {
+		return "", false
+	}
+	open := strings.Index(target, "(")
+	if open < 0 || !strings.HasSuffix(target, ")") {
+		return "", false
+	}
+	refColumns := strings.Split(target[open+1:len(target)-1], ", ")
+	if pos >= len(refColumns) {
+		return "", false
+	}
+	return fmt.Sprintf("%s (%s)", target[:open], refColumns[pos]), true
+}
+
+// indexOf returns the position of a name in a list, or -1
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// NewODBCConnector creates a connector for ODBC data sources
+func NewODBCConnector() t.DatabaseConnector {
+	return &ODBCConnector{}
+}
@@ -0,0 +1,61 @@
+package odbc
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViews returns the views of the schema, with their columns and SELECT
+// statements. Data sources without a views catalog view have no views to list.
+func (oc *ODBCConnector) GetViews(schema string) ([]t.View, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if !oc.hasView(oc.catalog + ".views") {
+		return nil, t.ErrViewsNotSupported
+	}
+
+	query := `
+		SELECT
+			table_name,
+			view_definition
+		FROM
+			` + oc.catalog + `.views
+		WHERE
+			table_schema = ?
+		ORDER BY
+			table_name
+	`
+
+	rows, err := oc.Query(oc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+	defer rows.Close()
+
+	var views []t.View
+	for rows.Next() {
+		view := t.View{Schema: schema}
+		var definition sql.NullString
+		if err := rows.Scan(&view.Name, &definition); err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		view.Name = strings.TrimSpace(view.Name)
+		view.Definition = strings.TrimSpace(definition.String)
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading views: %v", err)
+	}
+	rows.Close()
+
+	for i := range views {
+		if views[i].Columns, err = oc.getColumns(schema, views[i].Name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
@@ -0,0 +1,490 @@
+package oracle
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	go_ora "github.com/sijms/go-ora/v2" // Oracle driver
+)
+
+// OracleConnector implements the DatabaseConnector interface for Oracle Database.
+// The database name is the service name and schemas are the owners of tables.
+type OracleConnector struct {
+	sqlutil.QueryLog
+
+	db *sql.DB
+}
+
+// Connect establishes a connection to the Oracle service. Session settings are
+// passed as connection URL options, e.g. SSL=enable.
+func (oc *OracleConnector) Connect(params t.ConnectionParams) error {
+	port, err := strconv.Atoi(params.Port)
+	if err != nil {
+		return fmt.Errorf("invalid port '%s'", params.Port)
+	}
+
+	dsn := go_ora.BuildUrl(params.Host, port, params.Database, params.User, params.Password, params.Settings)
+	oc.db = sql.OpenDB(go_ora.NewConnector(dsn))
+
+	// Test the connection
+	if err := oc.db.Ping(); err != nil {
+		oc.db.Close()
+		oc.db = nil
+		return fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	return nil
+}
+
+// Disconnect closes the database connection
+func (oc *OracleConnector) Disconnect() error {
+	if oc.db != nil {
+		err := oc.db.Close()
+		oc.db = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetTables returns a list of tables owned by the specified schema
+func (oc *OracleConnector) GetTables(schema string) ([]string, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			table_name
+		FROM
+			all_tables
+		WHERE
+			owner = :1
+		AND
+			nested = 'NO' AND secondary = 'N' AND dropped = 'NO'
+		ORDER BY
+			table_name
+	`
+
+	rows, err := oc.Query(oc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	return tables, rows.Err()
+}
+
+// GetTableStructure returns the structure of the specified table
+func (oc *OracleConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Check if the table exists, reading its comment at the same time
+	rows, err := oc.Query(oc.db, `
+		SELECT c.comments
+		FROM all_tables t
+		LEFT JOIN all_tab_comments c ON c.owner = t.owner AND c.table_name = t.table_name
+		WHERE t.owner = :1 AND t.table_name = :2
+	`, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	var comment sql.NullString
+	exists := rows.Next()
+	if exists {
+		err = rows.Scan(&comment)
+	}
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	table := &t.Table{Name: tableName, Schema: schema, Comment: comment.String}
+
+	// Constraints come first, as they give the primary and foreign keys of columns
+	if table.Constraints, err = oc.getConstraints(schema, tableName); err != nil {
+		return nil, err
+	}
+	if table.Columns, err = oc.getColumns(schema, tableName, table.Constraints); err != nil {
+		return nil, err
+	}
+	if table.Indexes, err = oc.getIndexes(schema, tableName); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// formatDataType converts an Oracle column type to the compact type names used
+// for PostgreSQL, so that structures of both can be compared
+func formatDataType(dataType string, charLength int, precision, scale sql.NullInt64) string {
+	switch dataType {
+	case "VARCHAR2", "NVARCHAR2":
+		return fmt.Sprintf("varchar(%d)", charLength)
+	case "CHAR", "NCHAR":
+		return fmt.Sprintf("char(%d)", charLength)
+	case "NUMBER":
+		switch {
+		case !precision.Valid && scale.Valid && scale.Int64 == 0:
+			// Declared as INTEGER
+			return "integer"
+		case !precision.Valid:
+			return "numeric"
+		case scale.Int64 == 0:
+			return fmt.Sprintf("numeric(%d)", precision.Int64)
+		}
+		return fmt.Sprintf("numeric(%d,%d)", precision.Int64, scale.Int64)
+	case "FLOAT", "BINARY_DOUBLE":
+		return "double"
+	case "BINARY_FLOAT":
+		return "real"
+	case "CLOB", "NCLOB", "LONG":
+		return "text"
+	case "BLOB", "RAW", "LONG RAW":
+		return "bytea"
+	}
+
+	// Timestamps and intervals already read like PostgreSQL types
+	return strings.ToLower(dataType)
+}
+
+// getColumns returns the columns of a table, marking the keys found among its constraints
+func (oc *OracleConnector) getColumns(schema, tableName string, constraints []t.Constraint) ([]t.Column, error) {
+	query := `
+		SELECT
+			c.column_name,
+			c.data_type,
+			c.char_length,
+			c.data_precision,
+			c.data_scale,
+			CASE WHEN c.nullable = 'Y' THEN 1 ELSE 0 END,
+			c.data_default,
+			cc.comments
+		FROM
+			all_tab_columns c
+		LEFT JOIN
+			all_col_comments cc ON cc.owner = c.owner AND cc.table_name = c.table_name
+			AND cc.column_name = c.column_name
+		WHERE
+			c.owner = :1 AND c.table_name = :2
+		ORDER BY
+			c.column_id
+	`
+
+	rows, err := oc.Query(oc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	primaryKey := make(map[string]bool)
+	foreignKeys := make(map[string]string)
+	for _, con := range constraints {
+		switch con.Type {
+		case t.PrimaryKeyConstraint:
+			for _, col := range con.Columns {
+				primaryKey[col] = true
+			}
+		case t.ForeignKeyConstraint:
+			if m := referencePattern.FindStringSubmatch(con.Definition); m != nil {
+				refColumns := strings.Split(m[2], ", ")
+				ref := strings.TrimPrefix(m[1], schema+".")
+				for i, col := range con.Columns {
+					if _, seen := foreignKeys[col]; !seen && i < len(refColumns) {
+						foreignKeys[col] = fmt.Sprintf("%s (%s)", ref, refColumns[i])
+					}
+				}
+			}
+		}
+	}
+
+	var columns []t.Column
+	for rows.Next() {
+		var col t.Column
+		var dataType string
+		var charLength, nullable int
+		var precision, scale sql.NullInt64
+		var defaultValue, comment sql.NullString
+
+		err := rows.Scan(&col.Name, &dataType, &charLength, &precision, &scale, &nullable, &defaultValue, &comment)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+
+		col.Type = formatDataType(dataType, charLength, precision, scale)
+		col.Nullable = nullable == 1
+		col.IsPrimaryKey = primaryKey[col.Name]
+		col.Comment = comment.String
+
+		// Defaults are stored as the text typed in the DDL, often with trailing whitespace
+		if defaultValue.Valid {
+			col.DefaultValue = sql.NullString{String: strings.TrimSpace(defaultValue.String), Valid: true}
+		}
+		if ref, ok := foreignKeys[col.Name]; ok {
+			col.ForeignKey = sql.NullString{String: ref, Valid: true}
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, rows.Err()
+}
+
+// getIndexes returns the indexes of a table, with columns in index key order
+func (oc *OracleConnector) getIndexes(schema, tableName string) ([]t.Index, error) {
+	query := `
+		SELECT
+			i.index_name,
+			ic.column_name,
+			CASE WHEN i.uniqueness = 'UNIQUE' THEN 1 ELSE 0 END,
+			CASE WHEN EXISTS (
+				SELECT 1 FROM all_constraints c
+				WHERE c.owner = i.table_owner AND c.table_name = i.table_name
+				AND c.constraint_type = 'P' AND c.index_name = i.index_name
+			) THEN 1 ELSE 0 END,
+			i.index_type
+		FROM
+			all_indexes i
+		JOIN
+			all_ind_columns ic ON ic.index_owner = i.owner AND ic.index_name = i.index_name
+		WHERE
+			i.table_owner = :1 AND i.table_name = :2
+		ORDER BY
+			i.index_name, ic.column_position
+	`
+
+	rows, err := oc.Query(oc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying indexes: %v", err)
+	}
+	defer rows.Close()
+
+	indexMap := make(map[string]*t.Index)
+	for rows.Next() {
+		var indexName, columnName, indexType string
+		var isUnique, isPrimary int
+
+		if err := rows.Scan(&indexName, &columnName, &isUnique, &isPrimary, &indexType); err != nil {
+			return nil, fmt.Errorf("error scanning index results: %v", err)
+		}
+
+		idx, exists := indexMap[indexName]
+		if !exists {
+			idx = &t.Index{
+				Name:       indexName,
+				Unique:     isUnique == 1,
+				PrimaryKey: isPrimary == 1,
+				Method:     strings.ToLower(indexType),
+			}
+			indexMap[indexName] = idx
+		}
+
+		// Function-based key parts show up as hidden SYS_NC columns
+		if strings.HasPrefix(indexType, "FUNCTION-BASED") && strings.HasPrefix(columnName, "SYS_NC") {
+			idx.Expression = true
+			continue
+		}
+		idx.Columns = append(idx.Columns, columnName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert map to slice, sorted by name for a stable output
+	var indexes []t.Index
+	for _, idx := range indexMap {
+		indexes = append(indexes, *idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+
+	return indexes, nil
+}
+
+// constraintTypes maps ALL_CONSTRAINTS.CONSTRAINT_TYPE to constraint kinds
+var constraintTypes = map[string]string{
+	"P": t.PrimaryKeyConstraint,
+	"U": t.UniqueConstraint,
+	"R": t.ForeignKeyConstraint,
+}
+
+// referencePattern parses the referenced table and columns of a foreign key definition
+var referencePattern = regexp.MustCompile(`REFERENCES (.+)\((.+)\)$`)
+
+// getConstraints returns the key, foreign key and check constraints of a table
+func (oc *OracleConnector) getConstraints(schema, tableName string) ([]t.Constraint, error) {
+	query := `
+		SELECT
+			c.constraint_name,
+			c.constraint_type,
+			cc.column_name,
+			r.owner,
+			r.table_name,
+			rcc.column_name
+		FROM
+			all_constraints c
+		JOIN
+			all_cons_columns cc ON cc.owner = c.owner AND cc.constraint_name = c.constraint_name
+			AND cc.table_name = c.table_name
+		LEFT JOIN
+			all_constraints r ON r.owner = c.r_owner AND r.constraint_name = c.r_constraint_name
+		LEFT JOIN
+			all_cons_columns rcc ON rcc.owner = r.owner AND rcc.constraint_name = r.constraint_name
+			AND rcc.position = cc.position
+		WHERE
+			c.owner = :1 AND c.table_name = :2
+			AND c.constraint_type IN ('P', 'U', 'R')
+		ORDER BY
+			c.constraint_name, cc.position
+	`
+
+	rows, err := oc.Query(oc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying constraints: %v", err)
+	}
+	defer rows.Close()
+
+	var constraints []t.Constraint
+	var refTable string
+	var refColumns []string
+	for rows.Next() {
+		var name, conType, column string
+		var refOwner, referencedTable, referencedColumn sql.NullString
+
+		if err := rows.Scan(&name, &conType, &column, &refOwner, &referencedTable, &referencedColumn); err != nil {
+			return nil, fmt.Errorf("error scanning constraint results: %v", err)
+		}
+
+		// Rows come one per column; a new name starts the next constraint
+		if n := len(constraints); n == 0 || constraints[n-1].Name != name {
+			if n > 0 {
+				constraints[n-1].Definition = constraintDefinition(constraints[n-1], refTable, refColumns)
+			}
+			constraints = append(constraints, t.Constraint{Name: name, Type: constraintTypes[conType]})
+			refTable, refColumns = referencedTable.String, nil
+			if refOwner.Valid {
+				refTable = refOwner.String + "." + refTable
+			}
+		}
+		con := &constraints[len(constraints)-1]
+		con.Columns = append(con.Columns, column)
+		if referencedColumn.Valid {
+			refColumns = append(refColumns, referencedColumn.String)
+		}
+	}
+	if n := len(constraints); n > 0 {
+		constraints[n-1].Definition = constraintDefinition(constraints[n-1], refTable, refColumns)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	checks, err := oc.getCheckConstraints(schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	constraints = append(constraints, checks...)
+	sort.SliceStable(constraints, func(i, j int) bool {
+		return constraints[i].Name < constraints[j].Name
+	})
+
+	return constraints, nil
+}
+
+// notNullPattern matches the conditions of the check constraints Oracle creates for NOT NULL columns
+var notNullPattern = regexp.MustCompile(`^"?[^"\s]+"? IS NOT NULL$`)
+
+// getCheckConstraints returns the check constraints of a table. NOT NULL columns
+// are reported by the columns themselves, so their system-named checks are left out.
+func (oc *OracleConnector) getCheckConstraints(schema, tableName string) ([]t.Constraint, error) {
+	query := `
+		SELECT
+			constraint_name,
+			search_condition,
+			generated
+		FROM
+			all_constraints
+		WHERE
+			owner = :1 AND table_name = :2 AND constraint_type = 'C'
+		ORDER BY
+			constraint_name
+	`
+
+	rows, err := oc.Query(oc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying check constraints: %v", err)
+	}
+	defer rows.Close()
+
+	var constraints []t.Constraint
+	for rows.Next() {
+		var name, generated string
+		var condition sql.NullString
+
+		if err := rows.Scan(&name, &condition, &generated); err != nil {
+			return nil, fmt.Errorf("error scanning check constraint results: %v", err)
+		}
+
+		condition.String = strings.TrimSpace(condition.String)
+		if generated == "GENERATED NAME" && notNullPattern.MatchString(condition.String) {
+			continue
+		}
+
+		constraints = append(constraints, t.Constraint{
+			Name:       name,
+			Type:       t.CheckConstraint,
+			Definition: fmt.Sprintf("CHECK (%s)", condition.String),
+		})
+	}
+
+	return constraints, rows.Err()
+}
+
+// constraintDefinition builds the SQL definition of a key constraint
+func constraintDefinition(con t.Constraint, refTable string, refColumns []string) string {
+	def := fmt.Sprintf("%s (%s)", con.Type, strings.Join(con.Columns, ", "))
+	if con.Type == t.ForeignKeyConstraint {
+		def += fmt.Sprintf(" REFERENCES %s(%s)", refTable, strings.Join(refColumns, ", "))
+	}
+	return def
+}
+
+// NewOracleConnector creates a connector for Oracle Database
+func NewOracleConnector() t.DatabaseConnector {
+	return &OracleConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverOracle, NewOracleConnector, t.ConnectorInfo{
+		Title:  "Oracle",
+		Fields: t.ServerFields,
+		Defaults: func(p *t.ConnectionParams) {
+			// Schemas are users, whose unquoted names are stored in upper case
+			p.DefaultServer("1521", "system")
+			p.Schema = cmp.Or(p.Schema, strings.ToUpper(p.User))
+		},
+	})
+}
@@ -0,0 +1,105 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetRoutines returns the standalone functions and procedures of the schema,
+// leaving out the ones in packages
+func (oc *OracleConnector) GetRoutines(schema string) ([]t.Routine, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Argument 0 of a function is its return value
+	query := `
+		SELECT
+			o.object_name,
+			o.object_type,
+			COALESCE((
+				SELECT LISTAGG(a.argument_name || ' ' || a.in_out || ' ' || a.data_type, ', ')
+					WITHIN GROUP (ORDER BY a.position)
+				FROM all_arguments a
+				WHERE a.owner = o.owner AND a.object_name = o.object_name
+					AND a.package_name IS NULL AND a.position > 0 AND a.data_level = 0
+			), ''),
+			COALESCE((
+				SELECT MAX(a.data_type)
+				FROM all_arguments a
+				WHERE a.owner = o.owner AND a.object_name = o.object_name
+					AND a.package_name IS NULL AND a.position = 0 AND a.data_level = 0
+			), '')
+		FROM
+			all_objects o
+		WHERE
+			o.owner = :1
+			AND o.object_type IN ('FUNCTION', 'PROCEDURE')
+		ORDER BY
+			o.object_name
+	`
+
+	rows, err := oc.Query(oc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying routines: %v", err)
+	}
+	defer rows.Close()
+
+	var routines []t.Routine
+	byName := make(map[string]int)
+	for rows.Next() {
+		routine := t.Routine{Schema: schema, Language: "PL/SQL"}
+		if err := rows.Scan(&routine.Name, &routine.Kind, &routine.Arguments, &routine.ReturnType); err != nil {
+			return nil, fmt.Errorf("error scanning routine results: %v", err)
+		}
+		byName[routine.Name] = len(routines)
+		routines = append(routines, routine)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading routines: %v", err)
+	}
+
+	// The sources of all the routines at once, line by line
+	sourceQuery := `
+		SELECT
+			name,
+			text
+		FROM
+			all_source
+		WHERE
+			owner = :1
+			AND type IN ('FUNCTION', 'PROCEDURE')
+		ORDER BY
+			name, line
+	`
+
+	sourceRows, err := oc.Query(oc.db, sourceQuery, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying routine sources: %v", err)
+	}
+	defer sourceRows.Close()
+
+	sources := make(map[string]*strings.Builder)
+	for sourceRows.Next() {
+		var name, text string
+		if err := sourceRows.Scan(&name, &text); err != nil {
+			return nil, fmt.Errorf("error scanning routine source results: %v", err)
+		}
+		if sources[name] == nil {
+			sources[name] = &strings.Builder{}
+		}
+		sources[name].WriteString(text)
+	}
+	if err := sourceRows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading routine sources: %v", err)
+	}
+
+	for name, source := range sources {
+		if i, ok := byName[name]; ok {
+			routines[i].Source = source.String()
+		}
+	}
+	return routines, nil
+}
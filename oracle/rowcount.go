@@ -0,0 +1,67 @@
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// quoteIdentifier quotes an Oracle identifier. Quoted names are case sensitive,
+// which matches the upper case names stored in the catalog.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteQualified returns a quoted schema-qualified name for use in queries
+func quoteQualified(schema, name string) string {
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// EstimateRowCount returns the row count gathered by the optimizer statistics of a table
+func (oc *OracleConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if oc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := oc.Query(oc.db, `
+		SELECT num_rows
+		FROM all_tables
+		WHERE owner = :1 AND table_name = :2
+	`, schema, tableName)
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	var estimate sql.NullInt64
+	if err := rows.Scan(&estimate); err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	if !estimate.Valid {
+		return -1, nil
+	}
+	return estimate.Int64, nil
+}
+
+// CountRows returns the exact number of rows in a table
+func (oc *OracleConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if oc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := "SELECT COUNT(*) FROM " + quoteQualified(schema, tableName)
+	oc.LogQuery(query, nil)
+
+	var count int64
+	if err := oc.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,55 @@
+package oracle
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetSequences returns the sequences of the schema, with the identity column
+// owning each generated one
+func (oc *OracleConnector) GetSequences(schema string) ([]t.Sequence, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// last_number is the next value to be written to disk, as the values in the
+	// sequence cache are not visible to other sessions
+	query := `
+		SELECT
+			s.sequence_name,
+			s.last_number,
+			s.increment_by,
+			TO_CHAR(s.min_value),
+			TO_CHAR(s.max_value),
+			s.cycle_flag,
+			COALESCE(i.table_name || '.' || i.column_name, '')
+		FROM
+			all_sequences s
+		LEFT JOIN
+			all_tab_identity_cols i ON i.owner = s.sequence_owner AND i.sequence_name = s.sequence_name
+		WHERE
+			s.sequence_owner = :1
+		ORDER BY
+			s.sequence_name
+	`
+
+	rows, err := oc.Query(oc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying sequences: %v", err)
+	}
+	defer rows.Close()
+
+	var sequences []t.Sequence
+	for rows.Next() {
+		seq := t.Sequence{Schema: schema, DataType: "NUMBER"}
+		var cycle string
+		if err := rows.Scan(&seq.Name, &seq.Current, &seq.Increment, &seq.Min, &seq.Max, &cycle, &seq.OwnedBy); err != nil {
+			return nil, fmt.Errorf("error scanning sequence results: %v", err)
+		}
+		seq.Cycle = cycle == "Y"
+		sequences = append(sequences, seq)
+	}
+
+	return sequences, rows.Err()
+}
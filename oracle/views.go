@@ -0,0 +1,84 @@
+package oracle
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// viewQueries read the views and the materialized views of a schema, apart as
+// their LONG query text cannot be combined in a UNION
+var viewQueries = []struct {
+	query        string
+	materialized bool
+}{
+	{`
+		SELECT
+			v.view_name,
+			v.text,
+			c.comments
+		FROM
+			all_views v
+		LEFT JOIN
+			all_tab_comments c ON c.owner = v.owner AND c.table_name = v.view_name
+		WHERE
+			v.owner = :1
+	`, false},
+	{`
+		SELECT
+			m.mview_name,
+			m.query,
+			c.comments
+		FROM
+			all_mviews m
+		LEFT JOIN
+			all_mview_comments c ON c.owner = m.owner AND c.mview_name = m.mview_name
+		WHERE
+			m.owner = :1
+	`, true},
+}
+
+// GetViews returns the views and materialized views of the schema, with their
+// columns and SELECT statements
+func (oc *OracleConnector) GetViews(schema string) ([]t.View, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var views []t.View
+	for _, q := range viewQueries {
+		rows, err := oc.Query(oc.db, q.query, schema)
+		if err != nil {
+			return nil, fmt.Errorf("error querying views: %v", err)
+		}
+
+		for rows.Next() {
+			view := t.View{Schema: schema, Materialized: q.materialized}
+			var comment sql.NullString
+			if err := rows.Scan(&view.Name, &view.Definition, &comment); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("error scanning view results: %v", err)
+			}
+			view.Definition = strings.TrimSpace(view.Definition)
+			view.Comment = comment.String
+			views = append(views, view)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading views: %v", err)
+		}
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+
+	for i := range views {
+		var err error
+		if views[i].Columns, err = oc.getColumns(schema, views[i].Name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
@@ -0,0 +1,932 @@
+package pgdump
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// objectName identifies a table or view by schema and name
+type objectName struct {
+	schema, name string
+}
+
+// table is a table defined by the scripts, with the targets of its foreign keys
+type table struct {
+	t.Table
+	references map[string]reference // Targets of the foreign keys, by constraint name
+}
+
+// reference is the target of a foreign key
+type reference struct {
+	schema, table string
+	columns       []string // Empty when the key references the primary key
+}
+
+// dump holds the objects defined by DDL scripts. Statements are applied in
+// order, so a directory of migrations gives the schema after the last one.
+type dump struct {
+	schemas    map[string]bool
+	tables     map[objectName]*table
+	views      map[objectName]string // View definitions
+	searchPath string                // Schema of unqualified names
+}
+
+// newDump creates an empty dump, where unqualified names are in the public schema
+func newDump() *dump {
+	return &dump{
+		schemas:    make(map[string]bool),
+		tables:     make(map[objectName]*table),
+		views:      make(map[objectName]string),
+		searchPath: "public",
+	}
+}
+
+// schema returns the schema of a name, which is the first schema of the search
+// path when the name is not qualified
+func (d *dump) schema(schema string) string {
+	if schema == "" {
+		return d.searchPath
+	}
+	return schema
+}
+
+// applyScript applies the statements of a script. Statements other than the
+// DDL describing tables, indexes and views are ignored, as are psql commands.
+func (d *dump) applyScript(script string) {
+	lines := strings.Split(script, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), `\`) {
+			lines[i] = ""
+		}
+	}
+	for _, stmt := range sqlutil.SplitStatements(strings.Join(lines, "\n")) {
+		d.apply(newParser(stmt))
+	}
+}
+
+// apply applies one statement
+func (d *dump) apply(p *parser) {
+	switch {
+	case p.accept("CREATE", "SCHEMA"):
+		p.accept("IF", "NOT", "EXISTS")
+		if name, ok := p.name(); ok {
+			d.schemas[name] = true
+		}
+	case p.accept("SET"):
+		d.setSearchPath(p)
+	case p.accept("SELECT"):
+		// pg_dump empties the search path and qualifies every name
+		if p.accept("pg_catalog") {
+			p.acceptSymbol(".")
+		}
+		if !p.accept("set_config") {
+			return
+		}
+		if elements, ok := p.group(); ok && len(elements) >= 2 && unquote(p.tokens[elements[0][0]]) == "search_path" {
+			d.searchPath = firstSchema(strings.Split(unquote(p.tokens[elements[1][0]]), ","))
+		}
+	case p.accept("CREATE"):
+		p.accept("OR", "REPLACE")
+		if !p.accept("GLOBAL") {
+			p.accept("LOCAL")
+		}
+		p.accept("UNLOGGED")
+		if !p.accept("TEMP") {
+			p.accept("TEMPORARY")
+		}
+		switch {
+		case p.accept("TABLE"):
+			d.createTable(p)
+		case p.accept("UNIQUE", "INDEX"):
+			d.createIndex(p, true)
+		case p.accept("INDEX"):
+			d.createIndex(p, false)
+		case p.accept("VIEW"), p.accept("RECURSIVE", "VIEW"), p.accept("MATERIALIZED", "VIEW"):
+			d.createView(p)
+		}
+	case p.accept("ALTER", "TABLE"):
+		d.alterTable(p)
+	case p.accept("COMMENT", "ON"):
+		d.comment(p)
+	case p.accept("DROP"):
+		d.drop(p)
+	}
+}
+
+// setSearchPath applies SET search_path, e.g. SET search_path = app, public
+func (d *dump) setSearchPath(p *parser) {
+	if !p.accept("SESSION") {
+		p.accept("LOCAL")
+	}
+	if !p.accept("search_path") || !(p.acceptSymbol("=") || p.accept("TO")) {
+		return
+	}
+
+	var schemas []string
+	for !p.done() {
+		tok := p.next()
+		if tok.Kind == sqlutil.Literal {
+			schemas = append(schemas, strings.Split(unquote(tok), ",")...)
+		} else if tok.IsName() {
+			schemas = append(schemas, tok.Name())
+		}
+	}
+	d.searchPath = firstSchema(schemas)
+}
+
+// firstSchema returns the schema where unqualified tables are created with a
+// search path, skipping "$user"
+func firstSchema(schemas []string) string {
+	for _, schema := range schemas {
+		schema = strings.Trim(strings.TrimSpace(schema), `"`)
+		if schema != "" && schema != "$user" {
+			return schema
+		}
+	}
+	return "public"
+}
+
+// createTable applies CREATE TABLE. Partitions created with PARTITION OF get
+// the columns of their parent.
+func (d *dump) createTable(p *parser) {
+	p.accept("IF", "NOT", "EXISTS")
+	schema, name, ok := p.qualifiedName()
+	if !ok {
+		return
+	}
+	tbl := &table{
+		Table:      t.Table{Name: name, Schema: d.schema(schema)},
+		references: make(map[string]reference),
+	}
+
+	if p.accept("PARTITION", "OF") {
+		parentSchema, parentName, ok := p.qualifiedName()
+		if !ok {
+			return
+		}
+		if parent := d.tables[objectName{d.schema(parentSchema), parentName}]; parent != nil {
+			tbl.Columns = append(tbl.Columns, parent.Columns...)
+		}
+		d.tables[objectName{tbl.Schema, name}] = tbl
+		return
+	}
+
+	// CREATE TABLE AS and typed tables have no column list
+	elements, ok := p.group()
+	if !ok {
+		return
+	}
+	for _, el := range elements {
+		element := p.sub(el[0], el[1])
+		switch {
+		case isConstraintStart(element.peek()):
+			d.addTableConstraint(tbl, element)
+		case element.peek().Is("LIKE"):
+			// Columns copied from another table are not resolved
+		default:
+			d.addColumn(tbl, element)
+		}
+	}
+	d.tables[objectName{tbl.Schema, name}] = tbl
+}
+
+// isConstraintStart reports whether a table element or ADD action is a constraint
+func isConstraintStart(tok sqlutil.Token) bool {
+	for _, keyword := range []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN", "EXCLUDE"} {
+		if tok.Is(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnKeywords start the constraints and options that follow the type of a column
+var columnKeywords = map[string]bool{
+	"COLLATE": true, "CONSTRAINT": true, "NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true,
+	"UNIQUE": true, "REFERENCES": true, "CHECK": true, "GENERATED": true, "COMPRESSION": true, "STORAGE": true,
+}
+
+// isColumnKeyword reports whether a token ends the type or default of a column
+func isColumnKeyword(tok sqlutil.Token) bool {
+	return tok.Kind == sqlutil.Word && columnKeywords[strings.ToUpper(tok.Text)]
+}
+
+// serialTypes maps the serial pseudo-types to the types of their columns
+var serialTypes = map[string]string{
+	"smallserial": "smallint", "serial2": "smallint",
+	"serial": "integer", "serial4": "integer",
+	"bigserial": "bigint", "serial8": "bigint",
+}
+
+// addColumn adds a column definition, with its constraints, to a table
+func (d *dump) addColumn(tbl *table, p *parser) {
+	name, ok := p.name()
+	if !ok {
+		return
+	}
+	start := p.pos
+	end := p.skip(isColumnKeyword)
+	col := t.Column{Name: name, Type: formatDataType(p.text(start, end, tbl.Schema)), Nullable: true}
+
+	// Serial columns are integers taking their default from a sequence
+	if typ, ok := serialTypes[strings.ToLower(col.Type)]; ok {
+		col.Type = formatDataType(typ)
+		col.Nullable = false
+		col.DefaultValue = sql.NullString{String: "nextval('" + tbl.Name + "_" + name + "_seq'::regclass)", Valid: true}
+	}
+
+	for !p.done() {
+		var conName string
+		if p.accept("CONSTRAINT") {
+			conName, _ = p.name()
+		}
+
+		start := p.pos
+		switch {
+		case p.accept("NOT", "NULL"):
+			col.Nullable = false
+		case p.accept("NULL"):
+		case p.accept("DEFAULT"):
+			// The expression may start with a keyword, e.g. NULL::text
+			start := p.pos
+			p.next()
+			end := p.skip(isColumnKeyword)
+			col.DefaultValue = sql.NullString{String: p.text(start, end, tbl.Schema), Valid: true}
+		case p.accept("COLLATE"):
+			p.qualifiedName()
+		case p.accept("GENERATED"):
+			if !p.accept("ALWAYS") {
+				p.accept("BY", "DEFAULT")
+			}
+			p.accept("AS")
+			if p.accept("IDENTITY") {
+				col.Nullable = false
+				p.group()
+				break
+			}
+			// Generated columns show their expression as the default, like in the catalogs
+			start := p.pos
+			if _, ok := p.group(); ok {
+				col.DefaultValue = sql.NullString{String: p.text(start, p.pos, tbl.Schema), Valid: true}
+			}
+			p.accept("STORED")
+		case p.accept("PRIMARY", "KEY"):
+			col.Nullable = false
+			d.addConstraint(tbl, t.Constraint{
+				Name: conName, Type: t.PrimaryKeyConstraint, Columns: []string{name}, Definition: "PRIMARY KEY (" + name + ")",
+			}, nil)
+		case p.accept("UNIQUE"):
+			d.addConstraint(tbl, t.Constraint{
+				Name: conName, Type: t.UniqueConstraint, Columns: []string{name}, Definition: "UNIQUE (" + name + ")",
+			}, nil)
+		case p.accept("CHECK"):
+			p.group()
+			p.accept("NO", "INHERIT")
+			d.addConstraint(tbl, t.Constraint{
+				Name: conName, Type: t.CheckConstraint, Columns: []string{name}, Definition: p.text(start, p.pos, tbl.Schema),
+			}, nil)
+		case p.accept("REFERENCES"):
+			refStart := p.pos
+			ref, ok := d.reference(p)
+			if !ok {
+				return
+			}
+			d.addConstraint(tbl, t.Constraint{
+				Name: conName, Type: t.ForeignKeyConstraint, Columns: []string{name},
+				Definition: "FOREIGN KEY (" + name + ") REFERENCES " + p.text(refStart, p.pos, tbl.Schema),
+			}, &ref)
+		default:
+			// Options such as STORAGE or DEFERRABLE do not change the structure
+			p.next()
+		}
+	}
+
+	tbl.Columns = append(tbl.Columns, col)
+}
+
+// reference reads the target and actions of a foreign key
+func (d *dump) reference(p *parser) (reference, bool) {
+	schema, name, ok := p.qualifiedName()
+	if !ok {
+		return reference{}, false
+	}
+	ref := reference{schema: d.schema(schema), table: name}
+	if p.peek().IsSymbol("(") {
+		if ref.columns, ok = p.names(); !ok {
+			return reference{}, false
+		}
+	}
+
+	for {
+		switch {
+		case p.accept("MATCH"):
+			p.next()
+		case p.accept("ON"):
+			p.next()
+			if p.accept("SET") {
+				p.next()
+				if p.peek().IsSymbol("(") {
+					p.names()
+				}
+			} else if !p.accept("NO", "ACTION") {
+				p.next()
+			}
+		case p.accept("NOT", "DEFERRABLE"), p.accept("DEFERRABLE"):
+		case p.accept("INITIALLY"):
+			p.next()
+		default:
+			return ref, true
+		}
+	}
+}
+
+// addTableConstraint adds a constraint given as a table element or ADD action
+func (d *dump) addTableConstraint(tbl *table, p *parser) {
+	var con t.Constraint
+	if p.accept("CONSTRAINT") {
+		con.Name, _ = p.name()
+	}
+
+	start := p.pos
+	var ref *reference
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		con.Type = t.PrimaryKeyConstraint
+		con.Columns, _ = p.names()
+	case p.accept("UNIQUE"):
+		if !p.accept("NULLS", "NOT", "DISTINCT") {
+			p.accept("NULLS", "DISTINCT")
+		}
+		con.Type = t.UniqueConstraint
+		con.Columns, _ = p.names()
+	case p.accept("CHECK"):
+		con.Type = t.CheckConstraint
+	case p.accept("FOREIGN", "KEY"):
+		con.Type = t.ForeignKeyConstraint
+		con.Columns, _ = p.names()
+		if !p.accept("REFERENCES") {
+			return
+		}
+		target, ok := d.reference(p)
+		if !ok {
+			return
+		}
+		ref = &target
+	case p.accept("EXCLUDE"):
+		con.Type = t.ExcludeConstraint
+		if p.accept("USING") {
+			p.name()
+		}
+		// Elements are e.g. room WITH =, or expressions
+		elements, _ := p.group()
+		for _, el := range elements {
+			if el[1]-el[0] >= 2 && p.tokens[el[0]].IsName() && p.tokens[el[0]+1].Is("WITH") {
+				con.Columns = append(con.Columns, p.tokens[el[0]].Name())
+			}
+		}
+	default:
+		return
+	}
+
+	// The definition runs to the end, including options such as NOT VALID
+	p.skip(func(sqlutil.Token) bool { return false })
+	con.Definition = p.text(start, p.pos, tbl.Schema)
+	d.addConstraint(tbl, con, ref)
+}
+
+// constraintSuffixes are the suffixes of the names PostgreSQL gives constraints
+var constraintSuffixes = map[string]string{
+	t.PrimaryKeyConstraint: "pkey",
+	t.UniqueConstraint:     "key",
+	t.CheckConstraint:      "check",
+	t.ForeignKeyConstraint: "fkey",
+	t.ExcludeConstraint:    "excl",
+}
+
+// addConstraint adds a constraint to a table, naming it like PostgreSQL if it
+// has no name. Primary keys and unique constraints come with their index.
+func (d *dump) addConstraint(tbl *table, con t.Constraint, ref *reference) {
+	if con.Name == "" {
+		parts := []string{tbl.Name}
+		if con.Type != t.PrimaryKeyConstraint {
+			parts = append(parts, con.Columns...)
+		}
+		con.Name = strings.Join(append(parts, constraintSuffixes[con.Type]), "_")
+	}
+
+	tbl.dropConstraint(con.Name)
+	tbl.Constraints = append(tbl.Constraints, con)
+	if ref != nil {
+		tbl.references[con.Name] = *ref
+	}
+	if con.Type == t.PrimaryKeyConstraint || con.Type == t.UniqueConstraint {
+		tbl.Indexes = append(tbl.Indexes, t.Index{
+			Name:       con.Name,
+			Columns:    con.Columns,
+			Unique:     true,
+			PrimaryKey: con.Type == t.PrimaryKeyConstraint,
+			Method:     "btree",
+		})
+	}
+}
+
+// dropConstraint removes a constraint and the index that comes with it
+func (tbl *table) dropConstraint(name string) {
+	tbl.Constraints = removeNamed(tbl.Constraints, func(con t.Constraint) bool { return con.Name == name })
+	tbl.Indexes = removeNamed(tbl.Indexes, func(idx t.Index) bool { return idx.Name == name })
+	delete(tbl.references, name)
+}
+
+// removeNamed returns the elements of a list for which drop returns false
+func removeNamed[T any](list []T, drop func(T) bool) []T {
+	kept := list[:0]
+	for _, el := range list {
+		if !drop(el) {
+			kept = append(kept, el)
+		}
+	}
+	return kept
+}
+
+// column returns a column of the table, or nil
+func (tbl *table) column(name string) *t.Column {
+	for i := range tbl.Columns {
+		if tbl.Columns[i].Name == name {
+			return &tbl.Columns[i]
+		}
+	}
+	return nil
+}
+
+// createIndex applies CREATE INDEX. Keys that are not plain columns mark the
+// index as an expression index.
+func (d *dump) createIndex(p *parser, unique bool) {
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "NOT", "EXISTS")
+	idx := t.Index{Unique: unique, Method: "btree"}
+	if !p.peek().Is("ON") {
+		idx.Name, _ = p.name()
+	}
+	if !p.accept("ON") {
+		return
+	}
+	p.accept("ONLY")
+	schema, name, ok := p.qualifiedName()
+	if !ok {
+		return
+	}
+	tbl := d.tables[objectName{d.schema(schema), name}]
+	if tbl == nil {
+		return
+	}
+	if p.accept("USING") {
+		idx.Method, _ = p.name()
+	}
+
+	elements, ok := p.group()
+	if !ok {
+		return
+	}
+	for _, el := range elements {
+		// Plain keys may be followed by a collation, operator class or ordering
+		if p.tokens[el[0]].IsName() && (el[1]-el[0] == 1 || p.tokens[el[0]+1].Kind != sqlutil.Symbol) {
+			idx.Columns = append(idx.Columns, p.tokens[el[0]].Name())
+		} else {
+			idx.Expression = true
+		}
+	}
+	if idx.Name == "" {
+		idx.Name = strings.Join(append(append([]string{tbl.Name}, idx.Columns...), "idx"), "_")
+	}
+
+	for !p.done() {
+		if p.accept("WHERE") {
+			start := p.pos
+			p.skip(func(sqlutil.Token) bool { return false })
+			idx.Predicate = p.text(start, p.pos, tbl.Schema)
+			break
+		}
+		if _, ok := p.group(); !ok {
+			p.next()
+		}
+	}
+
+	tbl.Indexes = removeNamed(tbl.Indexes, func(other t.Index) bool { return other.Name == idx.Name })
+	tbl.Indexes = append(tbl.Indexes, idx)
+}
+
+// createView applies CREATE VIEW and CREATE MATERIALIZED VIEW
+func (d *dump) createView(p *parser) {
+	p.accept("IF", "NOT", "EXISTS")
+	schema, name, ok := p.qualifiedName()
+	if !ok {
+		return
+	}
+	p.skip(func(tok sqlutil.Token) bool { return tok.Is("AS") })
+	if !p.accept("AS") {
+		return
+	}
+
+	// Leave out WITH [NO] DATA of materialized views and WITH CHECK OPTION
+	start := p.pos
+	end := len(p.tokens)
+	for i := end - 1; i > start; i-- {
+		if p.tokens[i].Is("WITH") {
+			if rest := p.tokens[i+1:]; len(rest) > 0 && (rest[len(rest)-1].Is("DATA") || rest[len(rest)-1].Is("OPTION")) {
+				end = i
+			}
+			break
+		}
+		if p.tokens[i].Kind != sqlutil.Word {
+			break
+		}
+	}
+	schema = d.schema(schema)
+	d.views[objectName{schema, name}] = p.text(start, end, schema)
+}
+
+// alterTable applies the actions of ALTER TABLE that change the structure
+func (d *dump) alterTable(p *parser) {
+	p.accept("IF", "EXISTS")
+	p.accept("ONLY")
+	schema, name, ok := p.qualifiedName()
+	if !ok {
+		return
+	}
+	key := objectName{d.schema(schema), name}
+	tbl := d.tables[key]
+	if tbl == nil {
+		return
+	}
+
+	if p.accept("RENAME", "TO") {
+		if newName, ok := p.name(); ok {
+			delete(d.tables, key)
+			tbl.Name = newName
+			d.tables[objectName{tbl.Schema, newName}] = tbl
+		}
+		return
+	}
+
+	for !p.done() {
+		start := p.pos
+		end := p.skip(func(tok sqlutil.Token) bool { return tok.IsSymbol(",") })
+		d.alterAction(tbl, p.sub(start, end))
+		if !p.acceptSymbol(",") {
+			return
+		}
+	}
+}
+
+// alterAction applies one action of ALTER TABLE
+func (d *dump) alterAction(tbl *table, p *parser) {
+	switch {
+	case p.accept("ADD"):
+		if isConstraintStart(p.peek()) {
+			d.addTableConstraint(tbl, p)
+			return
+		}
+		p.accept("COLUMN")
+		p.accept("IF", "NOT", "EXISTS")
+		d.addColumn(tbl, p)
+
+	case p.accept("ALTER"):
+		p.accept("COLUMN")
+		name, _ := p.name()
+		col := tbl.column(name)
+		if col == nil {
+			return
+		}
+		switch {
+		case p.accept("SET", "DEFAULT"):
+			col.DefaultValue = sql.NullString{String: p.text(p.pos, len(p.tokens), tbl.Schema), Valid: true}
+		case p.accept("DROP", "DEFAULT"):
+			col.DefaultValue = sql.NullString{}
+		case p.accept("SET", "NOT", "NULL"):
+			col.Nullable = false
+		case p.accept("DROP", "NOT", "NULL"):
+			col.Nullable = true
+		case p.accept("SET", "DATA", "TYPE"), p.accept("TYPE"):
+			start := p.pos
+			end := p.skip(func(tok sqlutil.Token) bool { return tok.Is("USING") || tok.Is("COLLATE") })
+			col.Type = formatDataType(p.text(start, end, tbl.Schema))
+		}
+
+	case p.accept("DROP", "CONSTRAINT"):
+		p.accept("IF", "EXISTS")
+		if name, ok := p.name(); ok {
+			tbl.dropConstraint(name)
+		}
+
+	case p.accept("DROP"):
+		p.accept("COLUMN")
+		p.accept("IF", "EXISTS")
+		if name, ok := p.name(); ok {
+			tbl.dropColumn(name)
+		}
+
+	case p.accept("RENAME", "CONSTRAINT"):
+		oldName, _ := p.name()
+		if p.accept("TO") {
+			newName, _ := p.name()
+			tbl.renameConstraint(oldName, newName)
+		}
+
+	case p.accept("RENAME"):
+		p.accept("COLUMN")
+		oldName, _ := p.name()
+		if p.accept("TO") {
+			newName, _ := p.name()
+			tbl.renameColumn(oldName, newName)
+		}
+	}
+}
+
+// dropColumn removes a column with the constraints and indexes that use it
+func (tbl *table) dropColumn(name string) {
+	tbl.Columns = removeNamed(tbl.Columns, func(col t.Column) bool { return col.Name == name })
+	var dropped []string
+	for _, con := range tbl.Constraints {
+		if contains(con.Columns, name) {
+			dropped = append(dropped, con.Name)
+		}
+	}
+	for _, constraint := range dropped {
+		tbl.dropConstraint(constraint)
+	}
+	tbl.Indexes = removeNamed(tbl.Indexes, func(idx t.Index) bool { return contains(idx.Columns, name) })
+}
+
+// renameColumn renames a column and its uses in constraints and indexes
+func (tbl *table) renameColumn(oldName, newName string) {
+	if col := tbl.column(oldName); col != nil {
+		col.Name = newName
+	}
+	for i := range tbl.Constraints {
+		replaceName(tbl.Constraints[i].Columns, oldName, newName)
+	}
+	for i := range tbl.Indexes {
+		replaceName(tbl.Indexes[i].Columns, oldName, newName)
+	}
+}
+
+// renameConstraint renames a constraint and the index that comes with it
+func (tbl *table) renameConstraint(oldName, newName string) {
+	for i := range tbl.Constraints {
+		if tbl.Constraints[i].Name == oldName {
+			tbl.Constraints[i].Name = newName
+		}
+	}
+	for i := range tbl.Indexes {
+		if tbl.Indexes[i].Name == oldName {
+			tbl.Indexes[i].Name = newName
+		}
+	}
+	if ref, ok := tbl.references[oldName]; ok {
+		delete(tbl.references, oldName)
+		tbl.references[newName] = ref
+	}
+}
+
+// contains reports whether a list holds a name
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceName replaces a name in a list
+func replaceName(names []string, oldName, newName string) {
+	for i, n := range names {
+		if n == oldName {
+			names[i] = newName
+		}
+	}
+}
+
+// comment applies COMMENT ON TABLE and COMMENT ON COLUMN
+func (d *dump) comment(p *parser) {
+	var kind string
+	switch {
+	case p.accept("TABLE"):
+		kind = "table"
+	case p.accept("COLUMN"):
+		kind = "column"
+	default:
+		return
+	}
+
+	// Names are schema.table, schema.table.column or shorter
+	var names []string
+	for {
+		name, ok := p.name()
+		if !ok {
+			return
+		}
+		names = append(names, name)
+		if !p.acceptSymbol(".") {
+			break
+		}
+	}
+	if !p.accept("IS") {
+		return
+	}
+	var text string
+	if tok := p.next(); tok.Kind == sqlutil.Literal {
+		text = unquote(tok)
+	} else if tok.Is("E") && p.peek().Kind == sqlutil.Literal {
+		text = unescape(unquote(p.next()))
+	}
+
+	var column string
+	if kind == "column" {
+		if len(names) < 2 {
+			return
+		}
+		column = names[len(names)-1]
+		names = names[:len(names)-1]
+	}
+	key := objectName{d.searchPath, names[len(names)-1]}
+	if len(names) == 2 {
+		key.schema = names[0]
+	}
+	tbl := d.tables[key]
+	if tbl == nil {
+		return
+	}
+
+	if kind == "table" {
+		tbl.Comment = text
+	} else if col := tbl.column(column); col != nil {
+		col.Comment = text
+	}
+}
+
+// unescape replaces the backslash escapes of an E'...' string that comments use
+func unescape(text string) string {
+	return strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\'`, "'", `\\`, `\`).Replace(text)
+}
+
+// drop applies DROP TABLE, DROP VIEW and DROP INDEX
+func (d *dump) drop(p *parser) {
+	var kind string
+	switch {
+	case p.accept("TABLE"):
+		kind = "table"
+	case p.accept("VIEW"), p.accept("MATERIALIZED", "VIEW"):
+		kind = "view"
+	case p.accept("INDEX"):
+		kind = "index"
+		p.accept("CONCURRENTLY")
+	default:
+		return
+	}
+	p.accept("IF", "EXISTS")
+
+	for {
+		schema, name, ok := p.qualifiedName()
+		if !ok {
+			return
+		}
+		key := objectName{d.schema(schema), name}
+		switch kind {
+		case "table":
+			delete(d.tables, key)
+		case "view":
+			delete(d.views, key)
+		case "index":
+			for _, tbl := range d.tables {
+				if tbl.Schema == key.schema {
+					tbl.Indexes = removeNamed(tbl.Indexes, func(idx t.Index) bool { return idx.Name == name })
+				}
+			}
+		}
+		if !p.acceptSymbol(",") {
+			return
+		}
+	}
+}
+
+// build returns a copy of a table, with the key columns marked and the indexes
+// and constraints sorted by name like the PostgreSQL connector returns them
+func (d *dump) build(tbl *table) *t.Table {
+	result := tbl.Table
+	result.Columns = append([]t.Column(nil), tbl.Columns...)
+	result.Indexes = make([]t.Index, len(tbl.Indexes))
+	for i, idx := range tbl.Indexes {
+		idx.Columns = append([]string(nil), idx.Columns...)
+		result.Indexes[i] = idx
+	}
+	result.Constraints = make([]t.Constraint, len(tbl.Constraints))
+	for i, con := range tbl.Constraints {
+		con.Columns = append([]string(nil), con.Columns...)
+		result.Constraints[i] = con
+	}
+	sort.Slice(result.Indexes, func(i, j int) bool {
+		return result.Indexes[i].Name < result.Indexes[j].Name
+	})
+	sort.Slice(result.Constraints, func(i, j int) bool {
+		return result.Constraints[i].Name < result.Constraints[j].Name
+	})
+
+	for _, con := range result.Constraints {
+		for pos, name := range con.Columns {
+			col := findColumn(result.Columns, name)
+			if col == nil {
+				continue
+			}
+			switch con.Type {
+			case t.PrimaryKeyConstraint:
+				col.IsPrimaryKey = true
+			case t.ForeignKeyConstraint:
+				if ref, ok := d.foreignKeyRef(tbl, con.Name, pos); ok && !col.ForeignKey.Valid {
+					col.ForeignKey = sql.NullString{String: ref, Valid: true}
+				}
+			}
+		}
+	}
+
+	return &result
+}
+
+// findColumn returns a column of a list, or nil
+func findColumn(columns []t.Column, name string) *t.Column {
+	for i := range columns {
+		if columns[i].Name == name {
+			return &columns[i]
+		}
+	}
+	return nil
+}
+
+// foreignKeyRef returns the "table (column)" reference of a key column. The
+// table is qualified when it is in another schema, and keys without target
+// columns reference the primary key.
+func (d *dump) foreignKeyRef(tbl *table, constraint string, pos int) (string, bool) {
+	ref, ok := tbl.references[constraint]
+	if !ok {
+		return "", false
+	}
+	columns := ref.columns
+	if len(columns) == 0 {
+		if target := d.tables[objectName{ref.schema, ref.table}]; target != nil {
+			for _, con := range target.Constraints {
+				if con.Type == t.PrimaryKeyConstraint {
+					columns = con.Columns
+				}
+			}
+		}
+	}
+	if pos >= len(columns) {
+		return "", false
+	}
+
+	name := ref.table
+	if ref.schema != tbl.Schema {
+		name = ref.schema + "." + name
+	}
+	return name + " (" + columns[pos] + ")", true
+}
+
+// typeNames maps type aliases to the names PostgreSQL shows
+var typeNames = map[string]string{
+	"int": "integer", "int4": "integer", "int2": "smallint", "int8": "bigint",
+	"bool": "boolean", "float4": "real", "float8": "double precision", "float": "double precision",
+	"decimal": "numeric", "varchar": "character varying", "char": "character", "bpchar": "character",
+	"timestamptz": "timestamp with time zone", "timestamp": "timestamp without time zone",
+	"timetz": "time with time zone", "time": "time without time zone", "varbit": "bit varying",
+}
+
+// formatDataType returns a type as the PostgreSQL connector shows it. Aliases
+// used in hand-written DDL are first replaced with the names pg_dump uses.
+func formatDataType(typ string) string {
+	word := typ
+	if i := strings.IndexAny(typ, "( ["); i >= 0 {
+		word = typ[:i]
+	}
+	modifier, array := "", typ[len(word):]
+	if strings.HasPrefix(array, "(") {
+		end := strings.Index(array, ")") + 1
+		modifier, array = array[:end], array[end:]
+	}
+	if name, ok := typeNames[strings.ToLower(word)]; ok && (array == "" || strings.HasPrefix(array, "[")) {
+		// Modifiers of time types go before the time zone, e.g. timestamp(3) with time zone
+		if i := strings.Index(name, " with"); i >= 0 {
+			typ = name[:i] + modifier + name[i:] + array
+		} else {
+			typ = name + modifier + array
+		}
+	}
+
+	typ = strings.Replace(typ, "character varying", "varchar", -1)
+	typ = strings.Replace(typ, "character", "char", -1)
+	typ = strings.Replace(typ, "double precision", "double", -1)
+	return typ
+}
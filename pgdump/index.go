@@ -0,0 +1,175 @@
+package pgdump
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/carloberd/db-reader/lineage"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// DumpConnector implements the DatabaseConnector interface over the output of
+// pg_dump --schema-only, or a directory of DDL scripts, without a database.
+// The scripts are read when connecting and describe tables as PostgreSQL would.
+type DumpConnector struct {
+	dump *dump
+}
+
+// Connect reads the schema file, or every .sql file under the directory in
+// path order, which is the order of numbered migrations
+func (dc *DumpConnector) Connect(params t.ConnectionParams) error {
+	info, err := os.Stat(params.Database)
+	if err != nil {
+		return fmt.Errorf("failed to open schema file: %v", err)
+	}
+
+	files := []string{params.Database}
+	if info.IsDir() {
+		files = nil
+		err := filepath.WalkDir(params.Database, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(path), ".sql") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read schema directory: %v", err)
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no .sql files in '%s'", params.Database)
+		}
+	}
+
+	d := newDump()
+	for _, file := range files {
+		script, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read schema file: %v", err)
+		}
+		d.applyScript(string(script))
+	}
+
+	dc.dump = d
+	return nil
+}
+
+// Disconnect releases the schema read from the files
+func (dc *DumpConnector) Disconnect() error {
+	dc.dump = nil
+	return nil
+}
+
+// GetSchemas returns the schemas created or holding tables in the scripts
+func (dc *DumpConnector) GetSchemas() ([]string, error) {
+	if dc.dump == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	seen := make(map[string]bool)
+	for schema := range dc.dump.schemas {
+		seen[schema] = true
+	}
+	for key := range dc.dump.tables {
+		seen[key.schema] = true
+	}
+	schemas := make([]string, 0, len(seen))
+	for schema := range seen {
+		schemas = append(schemas, schema)
+	}
+	sort.Strings(schemas)
+	return schemas, nil
+}
+
+// GetTables returns a list of tables in the specified schema
+func (dc *DumpConnector) GetTables(schema string) ([]string, error) {
+	if dc.dump == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var tables []string
+	for key := range dc.dump.tables {
+		if key.schema == schema {
+			tables = append(tables, key.name)
+		}
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// GetTableStructure returns a copy of a table defined by the scripts, which callers may modify
+func (dc *DumpConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if dc.dump == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	tbl := dc.dump.tables[objectName{schema, tableName}]
+	if tbl == nil {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	return dc.dump.build(tbl), nil
+}
+
+// GetViewDefinitions returns the views and materialized views of a schema
+func (dc *DumpConnector) GetViewDefinitions(schema string) (map[string]string, error) {
+	if dc.dump == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	views := make(map[string]string)
+	for key, definition := range dc.dump.views {
+		if key.schema == schema {
+			views[key.name] = definition
+		}
+	}
+	return views, nil
+}
+
+// GetViews returns the views and materialized views of a schema. The dump
+// gives only their definitions, so the columns are named after the SELECT list
+// and have no types.
+func (dc *DumpConnector) GetViews(schema string) ([]t.View, error) {
+	if dc.dump == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var views []t.View
+	for key, definition := range dc.dump.views {
+		if key.schema != schema {
+			continue
+		}
+		view := t.View{Name: key.name, Schema: schema, Definition: definition}
+		for _, col := range lineage.ViewColumns(definition) {
+			view.Columns = append(view.Columns, t.Column{Name: col.Column, Nullable: true})
+		}
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	return views, nil
+}
+
+// NewDumpConnector creates a connector for pg_dump schema files
+func NewDumpConnector() t.DatabaseConnector {
+	return &DumpConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverPgDump, NewDumpConnector, t.ConnectorInfo{
+		Title:      "pg_dump schema file",
+		Fields:     []string{t.FieldDatabase, t.FieldSchema},
+		Hint:       "schema.sql, or a directory of .sql files",
+		File:       true,
+		Extensions: []string{".sql"},
+		Defaults: func(p *t.ConnectionParams) {
+			p.Schema = cmp.Or(p.Schema, "public")
+		},
+	})
+}
@@ -0,0 +1,100 @@
+package pgdump_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/carloberd/db-reader/inspectortest"
+	_ "github.com/carloberd/db-reader/pgdump"
+	"github.com/carloberd/db-reader/types"
+)
+
+// load connects to a fixture under testdata and returns one of its schemas
+func load(tb testing.TB, fixture, schema string) *inspectortest.Schema {
+	tb.Helper()
+	connector := inspectortest.Connect(tb, types.ConnectionParams{
+		Driver:   types.DriverPgDump,
+		Database: filepath.Join("testdata", fixture),
+	})
+	return inspectortest.Load(tb, connector, schema)
+}
+
+func TestDumpFile(t *testing.T) {
+	shop := load(t, "schema.sql", "shop")
+
+	inspectortest.RequireColumn(t, shop, "customers", "id", "integer", inspectortest.NotNull, inspectortest.PrimaryKey)
+	inspectortest.RequireColumn(t, shop, "customers", "email", "varchar(255)", inspectortest.NotNull,
+		inspectortest.Comment("Login, it's unique"))
+	inspectortest.RequireColumn(t, shop, "customers", "created_at", "timestamptz(3)", inspectortest.NotNull,
+		inspectortest.Default("now()"))
+	inspectortest.RequireColumn(t, shop, "customers", "note", "text", inspectortest.Nullable, inspectortest.NoDefault)
+	inspectortest.RequireColumn(t, shop, "orders", "customer_id", "integer", inspectortest.References("customers", "id"))
+	inspectortest.RequireColumn(t, shop, "orders", "total", "numeric(10,2)", inspectortest.Default("0"))
+	inspectortest.RequireColumn(t, shop, "orders", "tags", "text[]")
+
+	inspectortest.RequireIndex(t, shop, "customers", true, "email")
+	inspectortest.RequireIndex(t, shop, "customers", true, "id")
+	inspectortest.RequireIndex(t, shop, "orders", false, "customer_id")
+
+	// The view is not a table, and semicolons in comments do not split statements
+	inspectortest.RequireNoTable(t, shop, "order_totals")
+	if table := inspectortest.RequireTable(t, shop, "customers"); table.Comment != "People who order; one row each" {
+		t.Errorf("comment of customers is %q", table.Comment)
+	}
+}
+
+func TestMigrationDirectory(t *testing.T) {
+	public := load(t, "migrations", "public")
+
+	// 002 renames, drops and adds columns of the table 001 creates
+	inspectortest.RequireColumn(t, public, "accounts", "id", "integer", inspectortest.PrimaryKey,
+		inspectortest.Default("nextval('accounts_id_seq'::regclass)"))
+	inspectortest.RequireColumn(t, public, "accounts", "display_name", "text", inspectortest.NotNull)
+	inspectortest.RequireNoColumn(t, public, "accounts", "name")
+	inspectortest.RequireNoColumn(t, public, "accounts", "legacy_code")
+	inspectortest.RequireColumn(t, public, "accounts", "active", "boolean", inspectortest.NotNull,
+		inspectortest.Default("true"))
+
+	inspectortest.RequireColumn(t, public, "sessions", "account_id", "integer", inspectortest.Nullable,
+		inspectortest.References("accounts", "id"))
+	inspectortest.RequireColumn(t, public, "sessions", "started_at", "timestamp")
+	inspectortest.RequireIndex(t, public, "sessions", false, "account_id")
+	inspectortest.RequireNoTable(t, public, "sessions_old")
+}
+
+func TestViews(t *testing.T) {
+	connector := inspectortest.Connect(t, types.ConnectionParams{
+		Driver:   types.DriverPgDump,
+		Database: filepath.Join("testdata", "schema.sql"),
+	})
+
+	views, err := connector.GetViews("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(views) != 1 || views[0].Name != "order_totals" {
+		t.Fatalf("views are %v, expected order_totals", views)
+	}
+
+	var columns []string
+	for _, col := range views[0].Columns {
+		columns = append(columns, col.Name)
+	}
+	if len(columns) != 2 || columns[0] != "email" || columns[1] != "total" {
+		t.Errorf("view columns are %v, expected [email total]", columns)
+	}
+}
+
+func TestConnectErrors(t *testing.T) {
+	for name, database := range map[string]string{
+		"missing file":      filepath.Join("testdata", "missing.sql"),
+		"no scripts in dir": t.TempDir(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			connector, _ := types.NewConnector(types.DriverPgDump)
+			if err := connector.Connect(types.ConnectionParams{Database: database}); err == nil {
+				t.Errorf("connecting to %s succeeded", database)
+			}
+		})
+	}
+}
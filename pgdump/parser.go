@@ -0,0 +1,192 @@
+package pgdump
+
+import (
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+)
+
+// parser walks the tokens of one statement
+type parser struct {
+	stmt   string
+	tokens []sqlutil.Token
+	pos    int
+	end    int // Byte offset where the tokens end
+}
+
+// newParser tokenizes a statement
+func newParser(stmt string) *parser {
+	return &parser{stmt: stmt, tokens: sqlutil.Tokenize(stmt), end: len(stmt)}
+}
+
+// sub returns a parser over a range of the tokens, e.g. an element of a list
+func (p *parser) sub(from, to int) *parser {
+	return &parser{stmt: p.stmt, tokens: p.tokens[:to], pos: from, end: p.offset(to)}
+}
+
+// done reports whether all tokens were consumed
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// peek returns the current token, or an empty one at the end
+func (p *parser) peek() sqlutil.Token {
+	if p.done() {
+		return sqlutil.Token{}
+	}
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the current token
+func (p *parser) next() sqlutil.Token {
+	tok := p.peek()
+	if !p.done() {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes a sequence of keywords if the statement continues with all of them
+func (p *parser) accept(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if p.pos+i >= len(p.tokens) || !p.tokens[p.pos+i].Is(keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+// acceptSymbol consumes a punctuation token if it is the current one
+func (p *parser) acceptSymbol(symbol string) bool {
+	if p.peek().IsSymbol(symbol) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// name consumes an identifier
+func (p *parser) name() (string, bool) {
+	if !p.peek().IsName() {
+		return "", false
+	}
+	return p.next().Name(), true
+}
+
+// qualifiedName consumes a name that may be qualified with its schema. The
+// schema is empty when the name is not qualified.
+func (p *parser) qualifiedName() (schema, name string, ok bool) {
+	if name, ok = p.name(); !ok {
+		return "", "", false
+	}
+	if p.acceptSymbol(".") {
+		schema = name
+		if name, ok = p.name(); !ok {
+			return "", "", false
+		}
+	}
+	return schema, name, true
+}
+
+// skip consumes tokens up to the first one at the top nesting level for which
+// stop returns true, or to the end, and returns the position reached
+func (p *parser) skip(stop func(tok sqlutil.Token) bool) int {
+	depth := 0
+	for !p.done() {
+		tok := p.peek()
+		if depth == 0 && stop(tok) {
+			break
+		}
+		switch {
+		case tok.IsSymbol("(") || tok.IsSymbol("["):
+			depth++
+		case tok.IsSymbol(")") || tok.IsSymbol("]"):
+			depth--
+			if depth < 0 {
+				return p.pos
+			}
+		}
+		p.pos++
+	}
+	return p.pos
+}
+
+// group consumes a parenthesized group and returns the ranges of tokens of its
+// comma-separated elements
+func (p *parser) group() ([][2]int, bool) {
+	if !p.acceptSymbol("(") {
+		return nil, false
+	}
+
+	var elements [][2]int
+	for {
+		start := p.pos
+		end := p.skip(func(tok sqlutil.Token) bool { return tok.IsSymbol(",") })
+		if end > start {
+			elements = append(elements, [2]int{start, end})
+		}
+		if p.acceptSymbol(",") {
+			continue
+		}
+		return elements, p.acceptSymbol(")")
+	}
+}
+
+// names consumes a parenthesized list of identifiers, e.g. the columns of a key
+func (p *parser) names() ([]string, bool) {
+	elements, ok := p.group()
+	if !ok {
+		return nil, false
+	}
+	names := make([]string, 0, len(elements))
+	for _, el := range elements {
+		if el[1]-el[0] != 1 || !p.tokens[el[0]].IsName() {
+			return nil, false
+		}
+		names = append(names, p.tokens[el[0]].Name())
+	}
+	return names, true
+}
+
+// offset returns the byte offset where the token at a position starts
+func (p *parser) offset(pos int) int {
+	if pos >= len(p.tokens) {
+		return p.end
+	}
+	return p.tokens[pos].Pos
+}
+
+// text returns the source of the tokens in a range. Qualification with the
+// schema of the table is dropped, as PostgreSQL leaves out the schemas on the
+// search path when showing definitions, while pg_dump qualifies every name.
+func (p *parser) text(from, to int, schema string) string {
+	var sb strings.Builder
+	last := p.offset(from)
+	for i := from; i < to; i++ {
+		tok := p.tokens[i]
+		switch {
+		case tok.IsName() && tok.Name() == schema && i+2 < to &&
+			p.tokens[i+1].IsSymbol(".") && p.tokens[i+2].IsName():
+			sb.WriteString(p.stmt[last:tok.Pos])
+			last = p.tokens[i+2].Pos
+			i++
+		case tok.Kind == sqlutil.Literal && strings.HasPrefix(tok.Text, "'"+schema+"."):
+			// Sequences in nextval('public.orders_id_seq'::regclass)
+			sb.WriteString(p.stmt[last:tok.Pos])
+			sb.WriteString("'")
+			last = tok.Pos + len(schema) + 2
+		}
+	}
+	sb.WriteString(p.stmt[last:p.offset(to)])
+	return strings.TrimSpace(sb.String())
+}
+
+// unquote returns the text of a string literal, e.g. a comment
+func unquote(tok sqlutil.Token) string {
+	text := tok.Text
+	if len(text) >= 2 && text[0] == '\'' && text[len(text)-1] == '\'' {
+		text = text[1 : len(text)-1]
+	}
+	return strings.ReplaceAll(text, "''", "'")
+}
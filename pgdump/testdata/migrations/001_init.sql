@@ -0,0 +1,11 @@
+CREATE TABLE accounts (
+    id serial PRIMARY KEY,
+    name text NOT NULL,
+    legacy_code text
+);
+
+CREATE TABLE sessions (
+    id uuid PRIMARY KEY,
+    account_id integer REFERENCES accounts (id),
+    started_at timestamp DEFAULT CURRENT_TIMESTAMP
+);
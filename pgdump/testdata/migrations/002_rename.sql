@@ -0,0 +1,5 @@
+ALTER TABLE accounts RENAME COLUMN name TO display_name;
+ALTER TABLE accounts DROP COLUMN legacy_code;
+ALTER TABLE accounts ADD COLUMN active boolean DEFAULT true NOT NULL;
+CREATE INDEX sessions_account_idx ON sessions (account_id);
+DROP TABLE IF EXISTS sessions_old;
@@ -0,0 +1,58 @@
+--
+-- PostgreSQL database dump
+--
+
+SET statement_timeout = 0;
+SET client_encoding = 'UTF8';
+SELECT pg_catalog.set_config('search_path', '', false);
+
+CREATE SCHEMA shop;
+
+COMMENT ON SCHEMA shop IS 'Orders; and customers';
+
+SET default_tablespace = '';
+
+--
+-- Name: customers; Type: TABLE; Schema: shop; Owner: app
+--
+
+CREATE TABLE shop.customers (
+    id integer NOT NULL,
+    email character varying(255) NOT NULL,
+    created_at timestamp(3) with time zone DEFAULT now() NOT NULL,
+    note text
+);
+
+COMMENT ON TABLE shop.customers IS 'People who order; one row each';
+COMMENT ON COLUMN shop.customers.email IS 'Login, it''s unique';
+
+CREATE TABLE shop.orders (
+    id bigint NOT NULL,
+    customer_id integer NOT NULL,
+    total numeric(10,2) DEFAULT 0,
+    tags text[]
+);
+
+CREATE VIEW shop.order_totals AS
+ SELECT c.email,
+    sum(o.total) AS total
+   FROM (shop.orders o
+     JOIN shop.customers c ON ((c.id = o.customer_id)))
+  GROUP BY c.email;
+
+ALTER TABLE ONLY shop.customers
+    ADD CONSTRAINT customers_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY shop.orders
+    ADD CONSTRAINT orders_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX customers_email_key ON shop.customers USING btree (email);
+
+CREATE INDEX orders_customer_id_idx ON shop.orders USING btree (customer_id);
+
+ALTER TABLE ONLY shop.orders
+    ADD CONSTRAINT orders_customer_id_fkey FOREIGN KEY (customer_id) REFERENCES shop.customers(id);
+
+--
+-- PostgreSQL database dump complete
+--
@@ -0,0 +1,202 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+	"github.com/lib/pq"
+)
+
+// detectDialect checks whether the server is CockroachDB or Amazon Redshift,
+// which speak the PostgreSQL protocol but lack parts of pg_catalog used by the
+// regular queries
+func (pc *PostgresConnector) detectDialect() error {
+	var version string
+	if err := pc.QueryRow(pc.db, "SELECT version()").Scan(&version); err != nil {
+		return fmt.Errorf("error querying server version: %v", err)
+	}
+	pc.cockroach = strings.Contains(version, "CockroachDB")
+	pc.redshift = strings.Contains(version, "Redshift")
+	return nil
+}
+
+// showRows runs a SHOW statement and returns its rows as values by column name.
+// The columns of SHOW output vary between CockroachDB versions, so they are not
+// scanned by position.
+func (pc *PostgresConnector) showRows(stmt string) ([]map[string]string, error) {
+	rows, err := pc.Query(pc.db, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(names))
+		dest := make([]any, len(names))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]string, len(names))
+		for i, name := range names {
+			if values[i].Valid {
+				row[name] = values[i].String
+			}
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+// foreignKeyPattern parses the details of a CockroachDB foreign key constraint
+var foreignKeyPattern = regexp.MustCompile(`^FOREIGN KEY \((.+?)\) REFERENCES (.+?)\((.+?)\)`)
+
+// constraintColumnsPattern finds the column list of a key constraint definition
+var constraintColumnsPattern = regexp.MustCompile(`\((.+?)\)`)
+
+// splitColumnList splits a column list of a constraint definition
+func splitColumnList(list string) []string {
+	var columns []string
+	for _, col := range strings.Split(list, ",") {
+		col = strings.TrimSpace(col)
+		// Index columns may carry a direction
+		col = strings.TrimSuffix(strings.TrimSuffix(col, " ASC"), " DESC")
+		columns = append(columns, strings.Trim(col, `"`))
+	}
+	return columns
+}
+
+// getCockroachStructure fills in a table using SHOW statements instead of pg_catalog
+func (pc *PostgresConnector) getCockroachStructure(table *t.Table) (*t.Table, error) {
+	qualified := quoteQualified(table.Schema, table.Name)
+
+	tables, err := pc.showRows(fmt.Sprintf("SHOW TABLES FROM %s WITH COMMENT", pq.QuoteIdentifier(table.Schema)))
+	if err != nil {
+		return nil, fmt.Errorf("error querying table comment: %v", err)
+	}
+	for _, row := range tables {
+		if row["table_name"] == table.Name {
+			table.Comment = row["comment"]
+		}
+	}
+
+	// Constraints come first, as they give the primary and foreign keys of columns
+	constraints, err := pc.showRows("SHOW CONSTRAINTS FROM " + qualified)
+	if err != nil {
+		return nil, fmt.Errorf("error querying constraints: %v", err)
+	}
+
+	primaryKey := make(map[string]bool)
+	foreignKeys := make(map[string]string)
+	var primaryIndex string
+	for _, row := range constraints {
+		con := t.Constraint{
+			Name:       row["constraint_name"],
+			Type:       row["constraint_type"],
+			Definition: row["details"],
+		}
+
+		switch con.Type {
+		case t.ForeignKeyConstraint:
+			if m := foreignKeyPattern.FindStringSubmatch(con.Definition); m != nil {
+				con.Columns = splitColumnList(m[1])
+				refColumns := splitColumnList(m[3])
+				ref := strings.TrimPrefix(strings.TrimSpace(m[2]), table.Schema+".")
+				for i, col := range con.Columns {
+					if _, seen := foreignKeys[col]; !seen && i < len(refColumns) {
+						foreignKeys[col] = fmt.Sprintf("%s (%s)", ref, refColumns[i])
+					}
+				}
+			}
+		case t.PrimaryKeyConstraint, t.UniqueConstraint:
+			if m := constraintColumnsPattern.FindStringSubmatch(con.Definition); m != nil {
+				con.Columns = splitColumnList(m[1])
+			}
+			if con.Type == t.PrimaryKeyConstraint {
+				primaryIndex = con.Name
+				for _, col := range con.Columns {
+					primaryKey[col] = true
+				}
+			}
+		}
+
+		table.Constraints = append(table.Constraints, con)
+	}
+	sort.Slice(table.Constraints, func(i, j int) bool {
+		return table.Constraints[i].Name < table.Constraints[j].Name
+	})
+
+	columns, err := pc.showRows(fmt.Sprintf("SHOW COLUMNS FROM %s WITH COMMENT", qualified))
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	for _, row := range columns {
+		// Hidden columns such as the implicit rowid are not part of the table definition
+		if row["is_hidden"] == "true" {
+			continue
+		}
+
+		col := t.Column{
+			Name:         row["column_name"],
+			Type:         formatDataType(strings.ToLower(row["data_type"])),
+			Nullable:     row["is_nullable"] == "true",
+			IsPrimaryKey: primaryKey[row["column_name"]],
+			Comment:      row["comment"],
+		}
+		if def, ok := row["column_default"]; ok {
+			col.DefaultValue = sql.NullString{String: def, Valid: true}
+		}
+		if ref, ok := foreignKeys[col.Name]; ok {
+			col.ForeignKey = sql.NullString{String: ref, Valid: true}
+		}
+		table.Columns = append(table.Columns, col)
+	}
+
+	indexes, err := pc.showRows("SHOW INDEXES FROM " + qualified)
+	if err != nil {
+		return nil, fmt.Errorf("error querying indexes: %v", err)
+	}
+
+	// Rows come one per column in key order; stored and implicit columns are not part of the key
+	indexMap := make(map[string]*t.Index)
+	for _, row := range indexes {
+		if row["storing"] == "true" || row["implicit"] == "true" {
+			continue
+		}
+
+		name := row["index_name"]
+		idx, exists := indexMap[name]
+		if !exists {
+			idx = &t.Index{
+				Name:       name,
+				Unique:     row["non_unique"] == "false",
+				PrimaryKey: name == primaryIndex,
+			}
+			indexMap[name] = idx
+		}
+		idx.Columns = append(idx.Columns, row["column_name"])
+	}
+
+	for _, idx := range indexMap {
+		table.Indexes = append(table.Indexes, *idx)
+	}
+	sort.Slice(table.Indexes, func(i, j int) bool {
+		return table.Indexes[i].Name < table.Indexes[j].Name
+	})
+
+	return table, nil
+}
@@ -0,0 +1,37 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// SetTableComment replaces the comment of a table. An empty comment removes it.
+func (pc *PostgresConnector) SetTableComment(schema, tableName, comment string) error {
+	return pc.setComment("TABLE "+quoteQualified(schema, tableName), comment)
+}
+
+// SetColumnComment replaces the comment of a column. An empty comment removes it.
+func (pc *PostgresConnector) SetColumnComment(schema, tableName, column, comment string) error {
+	return pc.setComment("COLUMN "+quoteQualified(schema, tableName)+"."+pq.QuoteIdentifier(column), comment)
+}
+
+// setComment runs COMMENT ON for an object. The statement takes no parameters,
+// so the comment is quoted as a literal.
+func (pc *PostgresConnector) setComment(object, comment string) error {
+	if pc.db == nil {
+		return fmt.Errorf("not connected to database")
+	}
+
+	value := "NULL"
+	if comment != "" {
+		value = pq.QuoteLiteral(comment)
+	}
+	stmt := fmt.Sprintf("COMMENT ON %s IS %s", object, value)
+	pc.LogQuery(stmt, nil)
+
+	if _, err := pc.db.Exec(stmt); err != nil {
+		return fmt.Errorf("error setting comment: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetTableDDL returns the CREATE TABLE, CREATE INDEX and foreign key statements
+// of a table, built from pg_catalog with the definitions of pg_get_constraintdef
+// and pg_get_indexdef, so they match what the server would recreate
+func (pc *PostgresConnector) GetTableDDL(schema, tableName string) (string, error) {
+	if pc.db == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+	switch {
+	case pc.cockroach:
+		return pc.cockroachDDL(schema, tableName)
+	case pc.redshift:
+		return "", t.ErrDDLNotSupported
+	}
+
+	qualified := quoteQualified(schema, tableName)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", qualified))
+
+	columnQuery := `
+		SELECT
+			quote_ident(a.attname),
+			format_type(a.atttypid, a.atttypmod),
+			a.attnotnull,
+			pg_get_expr(d.adbin, d.adrelid),
+			a.attidentity
+		FROM
+			pg_catalog.pg_attribute a
+		LEFT JOIN
+			pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE
+			a.attrelid = $1::regclass
+			AND a.attnum > 0
+			AND NOT a.attisdropped
+		ORDER BY
+			a.attnum
+	`
+	rows, err := pc.Query(pc.db, columnQuery, qualified)
+	if err != nil {
+		return "", fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var name, dataType, identity string
+		var notNull bool
+		var defaultValue sql.NullString
+		if err := rows.Scan(&name, &dataType, &notNull, &defaultValue, &identity); err != nil {
+			return "", fmt.Errorf("error scanning columns: %v", err)
+		}
+		line := "    " + name + " " + dataType
+		switch identity {
+		case "a":
+			line += " GENERATED ALWAYS AS IDENTITY"
+		case "d":
+			line += " GENERATED BY DEFAULT AS IDENTITY"
+		}
+		if notNull && identity == "" {
+			line += " NOT NULL"
+		}
+		if defaultValue.Valid {
+			line += " DEFAULT " + defaultValue.String
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("error reading columns: %v", err)
+	}
+	if len(lines) == 0 {
+		return "", fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	// Primary keys first. Foreign keys are added after the indexes, as pg_dump
+	// does once every table is created, so circular references can be restored.
+	constraintQuery := `
+		SELECT
+			quote_ident(conname),
+			pg_get_constraintdef(oid),
+			contype = 'f'
+		FROM
+			pg_catalog.pg_constraint
+		WHERE
+			conrelid = $1::regclass
+		ORDER BY
+			contype <> 'p', conname
+	`
+	constraints, err := pc.Query(pc.db, constraintQuery, qualified)
+	if err != nil {
+		return "", fmt.Errorf("error querying constraints: %v", err)
+	}
+	defer constraints.Close()
+
+	var foreignKeys []string
+	for constraints.Next() {
+		var name, definition string
+		var foreignKey bool
+		if err := constraints.Scan(&name, &definition, &foreignKey); err != nil {
+			return "", fmt.Errorf("error scanning constraints: %v", err)
+		}
+		if foreignKey {
+			foreignKeys = append(foreignKeys, fmt.Sprintf("ALTER TABLE ONLY %s\n    ADD CONSTRAINT %s %s;\n", qualified, name, definition))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("    CONSTRAINT %s %s", name, definition))
+	}
+	if err := constraints.Err(); err != nil {
+		return "", fmt.Errorf("error reading constraints: %v", err)
+	}
+
+	sb.WriteString(strings.Join(lines, ",\n"))
+	sb.WriteString("\n);\n")
+
+	// Indexes backing constraints are created by the constraints
+	indexQuery := `
+		SELECT
+			pg_get_indexdef(i.indexrelid)
+		FROM
+			pg_catalog.pg_index i
+		JOIN
+			pg_catalog.pg_class c ON c.oid = i.indexrelid
+		WHERE
+			i.indrelid = $1::regclass
+			AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_constraint con WHERE con.conindid = i.indexrelid)
+		ORDER BY
+			c.relname
+	`
+	indexes, err := pc.Query(pc.db, indexQuery, qualified)
+	if err != nil {
+		return "", fmt.Errorf("error querying indexes: %v", err)
+	}
+	defer indexes.Close()
+
+	wroteIndex := false
+	for indexes.Next() {
+		var definition string
+		if err := indexes.Scan(&definition); err != nil {
+			return "", fmt.Errorf("error scanning indexes: %v", err)
+		}
+		if !wroteIndex {
+			sb.WriteString("\n")
+			wroteIndex = true
+		}
+		sb.WriteString(definition + ";\n")
+	}
+	if err := indexes.Err(); err != nil {
+		return "", fmt.Errorf("error reading indexes: %v", err)
+	}
+
+	if len(foreignKeys) > 0 {
+		sb.WriteString("\n" + strings.Join(foreignKeys, ""))
+	}
+
+	return sb.String(), nil
+}
+
+// cockroachDDL returns the CREATE TABLE statement CockroachDB generates, which
+// includes the indexes
+func (pc *PostgresConnector) cockroachDDL(schema, tableName string) (string, error) {
+	rows, err := pc.showRows("SHOW CREATE TABLE " + quoteQualified(schema, tableName))
+	if err != nil {
+		return "", fmt.Errorf("error querying table definition: %v", err)
+	}
+	if len(rows) == 0 {
+		return "", fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	return rows[0]["create_statement"] + ";\n", nil
+}
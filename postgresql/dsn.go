@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// defaultApplicationName identifies our sessions in pg_stat_activity unless a profile overrides it
+const defaultApplicationName = "db-reader"
+
+// settingNamePattern matches valid run-time parameter names
+var settingNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// reservedSettings are connection string keys that cannot be used as session settings
+var reservedSettings = map[string]bool{
+	"host": true, "port": true, "user": true, "password": true, "dbname": true,
+	"sslmode": true, "sslcert": true, "sslkey": true, "sslrootcert": true,
+	"connect_timeout": true, "fallback_application_name": true,
+	"krbsrvname": true, "krbspn": true, "sslinline": true, "sslsni": true,
+	"binary_parameters": true,
+}
+
+// buildDSN creates the connection string for the given parameters.
+// Session settings are passed as startup parameters, so they apply to every
+// connection the pool opens and not just the first one. Behind pgbouncer only
+// the application name is, as pgbouncer rejects other startup parameters, and
+// statements with arguments are sent in a single round trip without preparing them.
+func buildDSN(params t.ConnectionParams, pgbouncer bool) (string, error) {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		dsnValue(params.Host), dsnValue(params.Port), dsnValue(params.User),
+		dsnValue(params.Password), dsnValue(params.Database))
+
+	settings, err := sessionSettings(params)
+	if err != nil {
+		return "", err
+	}
+	if pgbouncer {
+		dsn += " binary_parameters=yes"
+		settings = map[string]string{"application_name": settings["application_name"]}
+	}
+
+	// Sort keys so the connection string is deterministic
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		dsn += fmt.Sprintf(" %s=%s", key, dsnValue(settings[key]))
+	}
+
+	return dsn, nil
+}
+
+// sessionSettings collects the run-time parameters to apply to each session
+func sessionSettings(params t.ConnectionParams) (map[string]string, error) {
+	settings := map[string]string{
+		"application_name": defaultApplicationName,
+	}
+
+	for key, value := range params.Settings {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !settingNamePattern.MatchString(key) || reservedSettings[key] {
+			return nil, fmt.Errorf("invalid session setting '%s'", key)
+		}
+		settings[key] = value
+	}
+
+	if params.StatementTimeout != "" {
+		settings["statement_timeout"] = params.StatementTimeout
+	}
+	if params.LockTimeout != "" {
+		settings["lock_timeout"] = params.LockTimeout
+	}
+
+	return settings, nil
+}
+
+// dsnValue quotes a value for use in a key/value connection string
+func dsnValue(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
@@ -0,0 +1,128 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// planNode mirrors a node of EXPLAIN (FORMAT JSON) output
+type planNode struct {
+	NodeType     string     `json:"Node Type"`
+	RelationName string     `json:"Relation Name"`
+	Schema       string     `json:"Schema"`
+	Alias        string     `json:"Alias"`
+	IndexName    string     `json:"Index Name"`
+	Filter       string     `json:"Filter"`
+	StartupCost  float64    `json:"Startup Cost"`
+	TotalCost    float64    `json:"Total Cost"`
+	PlanRows     float64    `json:"Plan Rows"`
+	PlanWidth    int        `json:"Plan Width"`
+	Plans        []planNode `json:"Plans"`
+}
+
+// ExplainQuery returns the estimated execution plan of a statement without running it
+func (pc *PostgresConnector) ExplainQuery(query string) (*t.PlanNode, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	tx, err := pc.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("error starting read-only transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var output []byte
+	err = tx.QueryRow("EXPLAIN (FORMAT JSON, VERBOSE) " + query).Scan(&output)
+	if err != nil {
+		return nil, fmt.Errorf("error explaining query: %v", err)
+	}
+
+	plan, err := parsePlan(output)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach table size estimates to scans, so large sequential scans can be spotted
+	err = pc.fillTableRows(tx, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// parsePlan converts EXPLAIN (FORMAT JSON) output to a plan tree
+func parsePlan(output []byte) (*t.PlanNode, error) {
+	var explained []struct {
+		Plan planNode `json:"Plan"`
+	}
+
+	if err := json.Unmarshal(output, &explained); err != nil {
+		return nil, fmt.Errorf("error parsing query plan: %v", err)
+	}
+	if len(explained) == 0 {
+		return nil, fmt.Errorf("empty query plan")
+	}
+
+	return convertPlanNode(explained[0].Plan), nil
+}
+
+// convertPlanNode recursively converts a JSON plan node to a PlanNode
+func convertPlanNode(pn planNode) *t.PlanNode {
+	node := &t.PlanNode{
+		NodeType:     pn.NodeType,
+		RelationName: pn.RelationName,
+		Schema:       pn.Schema,
+		Alias:        pn.Alias,
+		IndexName:    pn.IndexName,
+		Filter:       pn.Filter,
+		StartupCost:  pn.StartupCost,
+		TotalCost:    pn.TotalCost,
+		PlanRows:     pn.PlanRows,
+		PlanWidth:    pn.PlanWidth,
+	}
+
+	for _, child := range pn.Plans {
+		node.Children = append(node.Children, convertPlanNode(child))
+	}
+
+	return node
+}
+
+// fillTableRows sets TableRows on every plan node that scans a relation
+func (pc *PostgresConnector) fillTableRows(tx *sql.Tx, node *t.PlanNode) error {
+	if node.RelationName != "" && node.Schema != "" {
+		query := `
+			SELECT 
+				c.reltuples::bigint
+			FROM 
+				pg_catalog.pg_class c
+			JOIN 
+				pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			WHERE 
+				c.relname = $1 
+				AND n.nspname = $2
+		`
+
+		var rows int64
+		pc.LogQuery(query, []any{node.RelationName, node.Schema})
+		err := tx.QueryRow(query, node.RelationName, node.Schema).Scan(&rows)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("error querying table size: %v", err)
+		}
+		node.TableRows = rows
+	}
+
+	for _, child := range node.Children {
+		if err := pc.fillTableRows(tx, child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package postgresql
+
+import (
+	"fmt"
+)
+
+// GetExtensionTables returns the tables in the schema that are members of an extension
+func (pc *PostgresConnector) GetExtensionTables(schema string) ([]string, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT 
+			c.relname
+		FROM 
+			pg_catalog.pg_class c
+		JOIN 
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		JOIN 
+			pg_catalog.pg_depend d ON d.classid = 'pg_catalog.pg_class'::regclass 
+			AND d.objid = c.oid 
+			AND d.refclassid = 'pg_catalog.pg_extension'::regclass 
+			AND d.deptype = 'e'
+		WHERE 
+			n.nspname = $1
+			AND c.relkind IN ('r', 'p')
+		ORDER BY 
+			c.relname
+	`
+
+	rows, err := pc.Query(pc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying extension tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning extension table results: %v", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	return tables, nil
+}
@@ -1,27 +1,81 @@
 package postgresql
 
 import (
+	"cmp"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/carloberd/db-reader/sqlutil"
 	t "github.com/carloberd/db-reader/types"
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq" // PostgreSQL driver
 )
 
 // PostgresConnector implements the DatabaseConnector interface for PostgreSQL
 type PostgresConnector struct {
-	db *sql.DB
+	sqlutil.QueryLog
+
+	db        *sql.DB
+	cockroach bool              // Server is CockroachDB, introspected with SHOW statements
+	redshift  bool              // Server is Amazon Redshift, introspected without array functions
+	pgbouncer bool              // Connected through pgbouncer in transaction pooling mode
+	settings  map[string]string // Session settings applied to each read-only transaction behind pgbouncer
 }
 
 // Connect establishes a connection to the PostgreSQL database
 func (pc *PostgresConnector) Connect(params t.ConnectionParams) error {
+	pgbouncer, err := pgbouncerMode(params)
+	if err != nil {
+		return err
+	}
+
+	if pgbouncer {
+		if err := checkPgBouncerSettings(params); err != nil {
+			return err
+		}
+	}
+
+	// pgbouncer rejects the session settings sent as startup parameters,
+	// which tells it apart when the mode is detected
+	err = pc.open(params, pgbouncer)
+	if err != nil && params.PgBouncer == "" && !pgbouncer && isStartupParameterError(err) {
+		if err := checkPgBouncerSettings(params); err != nil {
+			return err
+		}
+		pgbouncer = true
+		err = pc.open(params, pgbouncer)
+	}
+	if err != nil {
+		return err
+	}
+
+	pc.pgbouncer = pgbouncer
+	pc.settings = nil
+	if pgbouncer {
+		pc.settings, _ = sessionSettings(params)
+		delete(pc.settings, "application_name")
+	}
+
+	// Pick the introspection queries for the server
+	if err := pc.detectDialect(); err != nil {
+		pc.db.Close()
+		pc.db = nil
+		return err
+	}
+
+	return nil
+}
+
+// open opens the connection pool and checks that the server can be reached
+func (pc *PostgresConnector) open(params t.ConnectionParams, pgbouncer bool) error {
 	// Create connection string
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		params.Host, params.Port, params.User, params.Password, params.Database)
+	dsn, err := buildDSN(params, pgbouncer)
+	if err != nil {
+		return err
+	}
 
 	// Open the connection
-	var err error
 	pc.db, err = sql.Open("postgres", dsn)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %v", err)
@@ -69,7 +123,7 @@ func (pc *PostgresConnector) GetTables(schema string) ([]string, error) {
 			table_name
 	`
 
-	rows, err := pc.db.Query(query, schema)
+	rows, err := pc.Query(pc.db, query, schema)
 	if err != nil {
 		return nil, fmt.Errorf("error querying tables: %v", err)
 	}
@@ -99,6 +153,11 @@ func formatDataType(pgType string) string {
 	return pgType
 }
 
+// quoteQualified returns a quoted schema-qualified name for use in queries
+func quoteQualified(schema, name string) string {
+	return pq.QuoteIdentifier(schema) + "." + pq.QuoteIdentifier(name)
+}
+
 // GetTableStructure returns the structure of the specified table
 func (pc *PostgresConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
 	if pc.db == nil {
@@ -115,7 +174,7 @@ func (pc *PostgresConnector) GetTableStructure(schema, tableName string) (*t.Tab
 			AND table_name = $2
 		)
 	`
-	err := pc.db.QueryRow(checkQuery, schema, tableName).Scan(&exists)
+	err := pc.QueryRow(pc.db, checkQuery, schema, tableName).Scan(&exists)
 	if err != nil {
 		return nil, fmt.Errorf("error checking table existence: %v", err)
 	}
@@ -129,7 +188,22 @@ func (pc *PostgresConnector) GetTableStructure(schema, tableName string) (*t.Tab
 		Schema: schema,
 	}
 
-	// Get column information with foreign keys
+	if pc.cockroach {
+		return pc.getCockroachStructure(table)
+	}
+	if pc.redshift {
+		return pc.getRedshiftStructure(table)
+	}
+
+	// Get the table comment
+	commentQuery := `SELECT COALESCE(obj_description($1::regclass, 'pg_class'), '')`
+	err = pc.QueryRow(pc.db, commentQuery, quoteQualified(schema, tableName)).Scan(&table.Comment)
+	if err != nil {
+		return nil, fmt.Errorf("error querying table comment: %v", err)
+	}
+
+	// Get column information with foreign keys. Types, defaults and referenced
+	// tables are schema-qualified only when not visible on the search_path.
 	query := `
 		SELECT 
 			a.attname AS column_name,
@@ -139,9 +213,10 @@ func (pc *PostgresConnector) GetTableStructure(schema, tableName string) (*t.Tab
 			CASE WHEN prim.contype = 'p' THEN true ELSE false END AS is_primary_key,
 			CASE 
 				WHEN fk.conname IS NOT NULL THEN 
-					fk_cl.relname || ' (' || att2.attname || ')'
+					fk_cl.oid::regclass::text || ' (' || att2.attname || ')'
 				ELSE NULL 
-			END AS foreign_key_ref
+			END AS foreign_key_ref,
+			COALESCE(col_description(a.attrelid, a.attnum), '') AS comment
 		FROM 
 			pg_catalog.pg_attribute a
 		LEFT JOIN 
@@ -165,7 +240,7 @@ func (pc *PostgresConnector) GetTableStructure(schema, tableName string) (*t.Tab
 			a.attnum
 	`
 
-	rows, err := pc.db.Query(query, tableName, schema)
+	rows, err := pc.Query(pc.db, query, tableName, schema)
 	if err != nil {
 		return nil, fmt.Errorf("error querying columns: %v", err)
 	}
@@ -184,6 +259,7 @@ func (pc *PostgresConnector) GetTableStructure(schema, tableName string) (*t.Tab
 			&defaultValue,
 			&col.IsPrimaryKey,
 			&foreignKeyRef,
+			&col.Comment,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning column results: %v", err)
@@ -195,33 +271,40 @@ func (pc *PostgresConnector) GetTableStructure(schema, tableName string) (*t.Tab
 		table.Columns = append(table.Columns, col)
 	}
 
-	// Get index information
+	// Get index information, with columns in index key order
 	indexQuery := `
 		SELECT
 			i.relname AS index_name,
 			a.attname AS column_name,
 			ix.indisunique AS is_unique,
-			ix.indisprimary AS is_primary
+			ix.indisprimary AS is_primary,
+			am.amname AS method,
+			COALESCE(pg_get_expr(ix.indpred, ix.indrelid), '') AS predicate,
+			ix.indexprs IS NOT NULL AS has_expressions,
+			pg_relation_size(i.oid) AS size,
+			array_position(ix.indkey::int2[], a.attnum) > ix.indnkeyatts AS included
 		FROM
 			pg_catalog.pg_class t,
 			pg_catalog.pg_class i,
 			pg_catalog.pg_index ix,
 			pg_catalog.pg_attribute a,
-			pg_catalog.pg_namespace n
+			pg_catalog.pg_namespace n,
+			pg_catalog.pg_am am
 		WHERE
 			t.oid = ix.indrelid
 			AND i.oid = ix.indexrelid
 			AND a.attrelid = t.oid
 			AND a.attnum = ANY(ix.indkey)
+			AND am.oid = i.relam
 			AND t.relkind = 'r'
 			AND t.relname = $1
 			AND n.oid = t.relnamespace
 			AND n.nspname = $2
 		ORDER BY
-			i.relname, a.attnum
+			i.relname, array_position(ix.indkey::int2[], a.attnum)
 	`
 
-	indexRows, err := pc.db.Query(indexQuery, tableName, schema)
+	indexRows, err := pc.Query(pc.db, indexQuery, tableName, schema)
 	if err != nil {
 		return nil, fmt.Errorf("error querying indexes: %v", err)
 	}
@@ -230,36 +313,132 @@ func (pc *PostgresConnector) GetTableStructure(schema, tableName string) (*t.Tab
 	indexMap := make(map[string]*t.Index)
 
 	for indexRows.Next() {
-		var indexName, columnName string
-		var isUnique, isPrimary bool
+		var indexName, columnName, method, predicate string
+		var isUnique, isPrimary, hasExpressions, included bool
+		var size int64
 
-		err := indexRows.Scan(&indexName, &columnName, &isUnique, &isPrimary)
+		err := indexRows.Scan(&indexName, &columnName, &isUnique, &isPrimary,
+			&method, &predicate, &hasExpressions, &size, &included)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning index results: %v", err)
 		}
 
-		if idx, exists := indexMap[indexName]; exists {
-			idx.Columns = append(idx.Columns, columnName)
-		} else {
-			idx := &t.Index{
+		idx, exists := indexMap[indexName]
+		if !exists {
+			idx = &t.Index{
 				Name:       indexName,
-				Columns:    []string{columnName},
 				Unique:     isUnique,
 				PrimaryKey: isPrimary,
+				Method:     method,
+				Predicate:  predicate,
+				Expression: hasExpressions,
+				Size:       size,
 			}
 			indexMap[indexName] = idx
 		}
+		// Only the first indnkeyatts columns are keys, the others are INCLUDE columns
+		if included {
+			idx.Include = append(idx.Include, columnName)
+		} else {
+			idx.Columns = append(idx.Columns, columnName)
+		}
 	}
 
-	// Convert map to slice
+	// Convert map to slice, sorted by name for a stable output
 	for _, idx := range indexMap {
 		table.Indexes = append(table.Indexes, *idx)
 	}
+	sort.Slice(table.Indexes, func(i, j int) bool {
+		return table.Indexes[i].Name < table.Indexes[j].Name
+	})
+
+	// Get constraints
+	table.Constraints, err = pc.getConstraints(schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table.Triggers, err = pc.getTriggers(schema, tableName)
+	if err != nil {
+		return nil, err
+	}
 
 	return table, nil
 }
 
+// constraintTypes maps pg_constraint.contype to constraint kinds
+var constraintTypes = map[string]string{
+	"p": t.PrimaryKeyConstraint,
+	"f": t.ForeignKeyConstraint,
+	"u": t.UniqueConstraint,
+	"c": t.CheckConstraint,
+	"x": t.ExcludeConstraint,
+}
+
+// getConstraints returns the constraints of a table
+func (pc *PostgresConnector) getConstraints(schema, tableName string) ([]t.Constraint, error) {
+	query := `
+		SELECT
+			con.conname,
+			con.contype,
+			COALESCE(
+				(SELECT array_agg(a.attname ORDER BY k.ord)
+				 FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+				 JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum),
+				'{}'
+			) AS columns,
+			pg_get_constraintdef(con.oid, true) AS definition
+		FROM
+			pg_catalog.pg_constraint con
+		JOIN
+			pg_catalog.pg_class c ON c.oid = con.conrelid
+		JOIN
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE
+			c.relname = $1
+			AND n.nspname = $2
+			AND con.contype IN ('p', 'f', 'u', 'c', 'x')
+		ORDER BY
+			con.conname
+	`
+
+	rows, err := pc.Query(pc.db, query, tableName, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying constraints: %v", err)
+	}
+	defer rows.Close()
+
+	var constraints []t.Constraint
+	for rows.Next() {
+		var con t.Constraint
+		var contype string
+
+		err := rows.Scan(&con.Name, &contype, pq.Array(&con.Columns), &con.Definition)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning constraint results: %v", err)
+		}
+
+		con.Type = constraintTypes[contype]
+		constraints = append(constraints, con)
+	}
+
+	return constraints, nil
+}
+
 // Implementation of factory method
 func NewPostgresConnector() t.DatabaseConnector {
 	return &PostgresConnector{}
 }
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverPostgres, NewPostgresConnector, t.ConnectorInfo{
+		Title:  "PostgreSQL",
+		Fields: t.ServerFields,
+		Defaults: func(p *t.ConnectionParams) {
+			p.DefaultServer("5432", "postgres")
+			p.Database = cmp.Or(p.Database, "postgres")
+			p.Schema = cmp.Or(p.Schema, "public")
+		},
+	})
+}
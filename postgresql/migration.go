@@ -0,0 +1,187 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetMigrationStatus detects Flyway, golang-migrate (and compatible) and Alembic
+// history tables in the schema and returns the version each of them records
+func (pc *PostgresConnector) GetMigrationStatus(schema string) ([]t.MigrationStatus, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	detectors := []struct {
+		table  string
+		detect func(schema, table string) (*t.MigrationStatus, error)
+	}{
+		{"flyway_schema_history", pc.flywayStatus},
+		{"schema_migrations", pc.schemaMigrationsStatus},
+		{"alembic_version", pc.alembicStatus},
+	}
+
+	var statuses []t.MigrationStatus
+	for _, d := range detectors {
+		exists, err := pc.tableExists(schema, d.table)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		status, err := d.detect(schema, d.table)
+		if err != nil {
+			return nil, err
+		}
+		status.Table = schema + "." + d.table
+		statuses = append(statuses, *status)
+	}
+
+	return statuses, nil
+}
+
+// tableExists reports whether a table exists in the schema
+func (pc *PostgresConnector) tableExists(schema, tableName string) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 
+			FROM information_schema.tables 
+			WHERE table_schema = $1 
+			AND table_name = $2
+		)
+	`
+	err := pc.QueryRow(pc.db, query, schema, tableName).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("error checking table existence: %v", err)
+	}
+	return exists, nil
+}
+
+// columnExists reports whether a column exists in a table
+func (pc *PostgresConnector) columnExists(schema, tableName, columnName string) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 
+			FROM information_schema.columns 
+			WHERE table_schema = $1 
+			AND table_name = $2
+			AND column_name = $3
+		)
+	`
+	err := pc.QueryRow(pc.db, query, schema, tableName, columnName).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("error checking column existence: %v", err)
+	}
+	return exists, nil
+}
+
+// flywayStatus reads the latest versioned migration from a Flyway history table
+func (pc *PostgresConnector) flywayStatus(schema, tableName string) (*t.MigrationStatus, error) {
+	status := &t.MigrationStatus{Tool: "Flyway"}
+	table := quoteQualified(schema, tableName)
+
+	query := fmt.Sprintf(`
+		SELECT 
+			version, 
+			description, 
+			installed_on, 
+			success
+		FROM 
+			%s
+		WHERE 
+			version IS NOT NULL
+		ORDER BY 
+			installed_rank DESC
+		LIMIT 1
+	`, table)
+
+	var installedOn time.Time
+	var success bool
+	err := pc.QueryRow(pc.db, query).Scan(&status.Version, &status.Description, &installedOn, &success)
+	if err == sql.ErrNoRows {
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading Flyway history: %v", err)
+	}
+	status.AppliedAt = installedOn.Format(time.RFC3339)
+	status.Dirty = !success
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE version IS NOT NULL AND success", table)
+	if err := pc.QueryRow(pc.db, countQuery).Scan(&status.Applied); err != nil {
+		return nil, fmt.Errorf("error counting Flyway migrations: %v", err)
+	}
+
+	return status, nil
+}
+
+// schemaMigrationsStatus reads a schema_migrations table. golang-migrate keeps a
+// single row with a dirty flag, while Rails and dbmate keep one row per applied version.
+func (pc *PostgresConnector) schemaMigrationsStatus(schema, tableName string) (*t.MigrationStatus, error) {
+	table := quoteQualified(schema, tableName)
+	hasDirty, err := pc.columnExists(schema, tableName, "dirty")
+	if err != nil {
+		return nil, err
+	}
+
+	if hasDirty {
+		status := &t.MigrationStatus{Tool: "golang-migrate"}
+
+		query := fmt.Sprintf("SELECT version::text, dirty FROM %s LIMIT 1", table)
+		err := pc.QueryRow(pc.db, query).Scan(&status.Version, &status.Dirty)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("error reading golang-migrate version: %v", err)
+		}
+		return status, nil
+	}
+
+	status := &t.MigrationStatus{Tool: "schema_migrations"}
+
+	// Versions are usually fixed-width timestamps, so the byte-wise maximum is the latest
+	query := fmt.Sprintf(`
+		SELECT 
+			COALESCE(MAX(version::text COLLATE "C"), ''), 
+			COUNT(*)
+		FROM 
+			%s
+	`, table)
+	err = pc.QueryRow(pc.db, query).Scan(&status.Version, &status.Applied)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations versions: %v", err)
+	}
+
+	return status, nil
+}
+
+// alembicStatus reads the current revision(s) from an Alembic version table
+func (pc *PostgresConnector) alembicStatus(schema, tableName string) (*t.MigrationStatus, error) {
+	status := &t.MigrationStatus{Tool: "Alembic"}
+	table := quoteQualified(schema, tableName)
+
+	rows, err := pc.Query(pc.db, fmt.Sprintf("SELECT version_num FROM %s ORDER BY version_num", table))
+	if err != nil {
+		return nil, fmt.Errorf("error reading Alembic version: %v", err)
+	}
+	defer rows.Close()
+
+	// Alembic keeps one row per head when branches are not merged
+	var heads []string
+	for rows.Next() {
+		var head string
+		if err := rows.Scan(&head); err != nil {
+			return nil, fmt.Errorf("error scanning Alembic version: %v", err)
+		}
+		heads = append(heads, head)
+	}
+	status.Version = strings.Join(heads, ", ")
+
+	return status, nil
+}
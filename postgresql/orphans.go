@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// FindOrphanedObjects returns objects in the schema that are candidates for cleanup:
+// sequences that no column owns or uses, invalid indexes left behind by failed
+// concurrent builds, and empty tables that have never been scanned
+func (pc *PostgresConnector) FindOrphanedObjects(schema string) ([]t.Finding, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	checks := []struct {
+		check   string
+		message string
+		query   string
+	}{
+		{
+			check:   "orphaned-sequence",
+			message: "Sequence is not owned by any column and not used by any column default",
+			query: `
+				SELECT 
+					c.relname,
+					pg_relation_size(c.oid)
+				FROM 
+					pg_catalog.pg_class c
+				JOIN 
+					pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+				WHERE 
+					n.nspname = $1
+					AND c.relkind = 'S'
+					AND NOT EXISTS (
+						SELECT 1 FROM pg_catalog.pg_depend d
+						WHERE d.classid = 'pg_catalog.pg_class'::regclass
+						AND d.objid = c.oid
+						AND d.deptype IN ('a', 'i', 'e')
+					)
+					AND NOT EXISTS (
+						SELECT 1 FROM pg_catalog.pg_depend d
+						WHERE d.refclassid = 'pg_catalog.pg_class'::regclass
+						AND d.refobjid = c.oid
+						AND d.classid = 'pg_catalog.pg_attrdef'::regclass
+					)
+				ORDER BY 
+					c.relname
+			`,
+		},
+		{
+			check:   "invalid-index",
+			message: "Index is invalid, probably left behind by a failed CREATE INDEX CONCURRENTLY",
+			query: `
+				SELECT 
+					i.relname,
+					pg_relation_size(i.oid)
+				FROM 
+					pg_catalog.pg_index ix
+				JOIN 
+					pg_catalog.pg_class i ON i.oid = ix.indexrelid
+				JOIN 
+					pg_catalog.pg_namespace n ON n.oid = i.relnamespace
+				WHERE 
+					n.nspname = $1
+					AND NOT ix.indisvalid
+				ORDER BY 
+					i.relname
+			`,
+		},
+		{
+			check:   "unused-empty-table",
+			message: "Table is empty and has never been scanned since statistics were reset",
+			query: `
+				SELECT 
+					s.relname,
+					pg_total_relation_size(s.relid)
+				FROM 
+					pg_catalog.pg_stat_user_tables s
+				WHERE 
+					s.schemaname = $1
+					AND s.n_live_tup = 0
+					AND s.seq_scan = 0
+					AND COALESCE(s.idx_scan, 0) = 0
+				ORDER BY 
+					s.relname
+			`,
+		},
+	}
+
+	var findings []t.Finding
+	for _, check := range checks {
+		rows, err := pc.Query(pc.db, check.query, schema)
+		if err != nil {
+			return nil, fmt.Errorf("error running %s check: %v", check.check, err)
+		}
+
+		for rows.Next() {
+			finding := t.Finding{Check: check.check, Message: check.message}
+			if err := rows.Scan(&finding.Object, &finding.Bytes); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("error scanning %s results: %v", check.check, err)
+			}
+			findings = append(findings, finding)
+		}
+		rows.Close()
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,76 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// pgbouncerPort is the port pgbouncer listens on by default
+const pgbouncerPort = "6432"
+
+// pgbouncerMode reports whether to connect in the mode suited to pgbouncer in
+// transaction pooling mode, where consecutive statements of a session may run
+// on different server connections. The profile may turn it on or off; otherwise
+// it is on for the default pgbouncer port, and Connect turns it on when the
+// server rejects the session settings as pgbouncer does.
+func pgbouncerMode(params t.ConnectionParams) (bool, error) {
+	switch params.PgBouncer {
+	case "":
+		return params.Port == pgbouncerPort, nil
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid pgbouncer mode '%s', expected on or off", params.PgBouncer)
+	}
+}
+
+// pgbouncerTimeouts are the session settings that cannot be applied behind
+// pgbouncer: catalog queries run outside transactions, so they would only bound
+// the statements of the query editor
+var pgbouncerTimeouts = []string{"statement_timeout", "lock_timeout"}
+
+// checkPgBouncerSettings rejects the timeouts of a connection through pgbouncer,
+// rather than silently running the catalog queries without them
+func checkPgBouncerSettings(params t.ConnectionParams) error {
+	settings, err := sessionSettings(params)
+	if err != nil {
+		return err
+	}
+	for _, name := range pgbouncerTimeouts {
+		if settings[name] != "" {
+			return fmt.Errorf("%s cannot be applied through pgbouncer in transaction pooling mode: remove it from the connection, or turn pgbouncer mode off when connecting to PostgreSQL directly", name)
+		}
+	}
+	return nil
+}
+
+// isStartupParameterError reports whether connecting failed because pgbouncer
+// does not support one of the startup parameters
+func isStartupParameterError(err error) bool {
+	return strings.Contains(err.Error(), "unsupported startup parameter")
+}
+
+// applyTransactionSettings applies the session settings to a transaction only,
+// behind pgbouncer, where a session-level SET would leak to other clients
+// sharing the server connection
+func (pc *PostgresConnector) applyTransactionSettings(ctx context.Context, tx *sql.Tx) error {
+	keys := make([]string, 0, len(pc.settings))
+	for key := range pc.settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, err := tx.ExecContext(ctx, "SELECT set_config($1, $2, true)", key, pc.settings[key]); err != nil {
+			return fmt.Errorf("error applying session setting %s: %v", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// maxResultRows limits the number of rows fetched for a single editor query
+const maxResultRows = 1000
+
+// cancelGracePeriod is how long a cancelled query may keep running before
+// pg_cancel_backend is used to stop it
+const cancelGracePeriod = 2 * time.Second
+
+// ExecuteQuery runs a single statement inside a read-only transaction,
+// aborting it when the context is cancelled
+func (pc *PostgresConnector) ExecuteQuery(ctx context.Context, query string) (*t.QueryResult, error) {
+	return pc.executeReadOnly(ctx, query)
+}
+
+// ExecuteCatalogQuery runs a user-defined catalog query with arguments inside
+// a read-only transaction, recording it in the query log
+func (pc *PostgresConnector) ExecuteCatalogQuery(ctx context.Context, query string, args ...any) (*t.QueryResult, error) {
+	pc.LogQuery(query, args)
+	return pc.executeReadOnly(ctx, query, args...)
+}
+
+// executeReadOnly runs a statement on a dedicated connection inside a read-only transaction
+func (pc *PostgresConnector) executeReadOnly(ctx context.Context, query string, args ...any) (*t.QueryResult, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Use a dedicated connection so the backend running the query is known
+	conn, err := pc.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring connection: %v", err)
+	}
+	defer conn.Close()
+
+	var pid int
+	if !pc.pgbouncer {
+		err = conn.QueryRowContext(ctx, "SELECT pg_backend_pid()").Scan(&pid)
+		if err != nil {
+			return nil, fmt.Errorf("error querying backend pid: %v", err)
+		}
+	}
+
+	// The transaction is never committed, and being read-only the server
+	// rejects any statement that would modify data
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("error starting read-only transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Behind pgbouncer the server connection is only ours for the transaction,
+	// so its backend is looked up and the session settings applied within it
+	if pc.pgbouncer {
+		err = tx.QueryRowContext(ctx, "SELECT pg_backend_pid()").Scan(&pid)
+		if err != nil {
+			return nil, fmt.Errorf("error querying backend pid: %v", err)
+		}
+		if err := pc.applyTransactionSettings(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	cancelErr := make(chan error, 1)
+	go cancelOnDone(ctx, pc.db, done, pid, cancelErr)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, cancelledError(cancelErr)
+		}
+		return nil, fmt.Errorf("error executing query: %v", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("error reading result columns: %v", err)
+	}
+
+	result := &t.QueryResult{
+		Statement: query,
+		Columns:   columns,
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if len(result.Rows) >= maxResultRows {
+			result.Truncated = true
+			break
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("error scanning query results: %v", err)
+		}
+
+		row := make([]string, len(columns))
+		for i, v := range values {
+			row[i] = formatValue(v)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		if ctx.Err() != nil {
+			return nil, cancelledError(cancelErr)
+		}
+		return nil, fmt.Errorf("error reading query results: %v", err)
+	}
+
+	return result, nil
+}
+
+// cancelOnDone stops the query running on backend pid once ctx is cancelled.
+// The driver already sends a cancel request when the context ends; if the query
+// is still running after cancelGracePeriod, pg_cancel_backend is used instead.
+// Failing to cancel the query that way is sent to failed.
+func cancelOnDone(ctx context.Context, db *sql.DB, done <-chan struct{}, pid int, failed chan<- error) {
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+	}
+
+	select {
+	case <-done:
+	case <-time.After(cancelGracePeriod):
+		var cancelled bool
+		if err := db.QueryRow("SELECT pg_cancel_backend($1)", pid).Scan(&cancelled); err != nil {
+			failed <- err
+		} else if !cancelled {
+			failed <- fmt.Errorf("backend %d could not be signalled", pid)
+		}
+	}
+}
+
+// cancelledError is the error of a cancelled query, telling whether
+// pg_cancel_backend failed to stop it
+func cancelledError(failed <-chan error) error {
+	select {
+	case err := <-failed:
+		return fmt.Errorf("query cancelled, but pg_cancel_backend failed: %v", err)
+	default:
+		return fmt.Errorf("query cancelled")
+	}
+}
+
+// formatValue converts a scanned column value to its display representation
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(val)
+	}
+}
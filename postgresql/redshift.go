@@ -0,0 +1,313 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+	"github.com/lib/pq"
+)
+
+// redshiftColumn is a column with the distribution and sort key flags Redshift keeps in pg_attribute
+type redshiftColumn struct {
+	t.Column
+	num       int64
+	distKey   bool
+	sortOrder int // Position in the sort key, negative for interleaved sort keys, 0 if not in it
+}
+
+// getRedshiftStructure fills in a table for Amazon Redshift, whose leader node
+// lacks array_position and rejects some joins of the regular queries. Columns
+// are read from pg_attribute the way pg_table_def reads them, as pg_table_def
+// itself only lists tables in schemas on the search_path. Redshift has no
+// indexes; the distribution and sort keys are reported as table properties.
+func (pc *PostgresConnector) getRedshiftStructure(table *t.Table) (*t.Table, error) {
+	commentQuery := `SELECT COALESCE(obj_description($1::regclass, 'pg_class'), '')`
+	err := pc.QueryRow(pc.db, commentQuery, quoteQualified(table.Schema, table.Name)).Scan(&table.Comment)
+	if err != nil {
+		return nil, fmt.Errorf("error querying table comment: %v", err)
+	}
+
+	columns, err := pc.getRedshiftColumns(table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Constraints give the primary and foreign keys of columns
+	names := make(map[int64]string, len(columns))
+	for _, col := range columns {
+		names[col.num] = col.Name
+	}
+	primaryKey := make(map[string]bool)
+	foreignKeys := make(map[string]string)
+	table.Constraints, err = pc.getRedshiftConstraints(table.Schema, table.Name, names, primaryKey, foreignKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var distKey string
+	var sortKey []redshiftColumn
+	for _, col := range columns {
+		col.IsPrimaryKey = primaryKey[col.Name]
+		if ref, ok := foreignKeys[col.Name]; ok {
+			col.ForeignKey = sql.NullString{String: ref, Valid: true}
+		}
+		table.Columns = append(table.Columns, col.Column)
+
+		if col.distKey {
+			distKey = col.Name
+		}
+		if col.sortOrder != 0 {
+			sortKey = append(sortKey, col)
+		}
+	}
+
+	table.Properties, err = pc.getRedshiftProperties(table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	if distKey != "" {
+		table.Properties = append(table.Properties, t.Property{Name: "Distribution key", Value: distKey})
+	}
+	if len(sortKey) > 0 {
+		table.Properties = append(table.Properties, t.Property{Name: "Sort key", Value: formatSortKey(sortKey)})
+	}
+
+	return table, nil
+}
+
+// getRedshiftColumns returns the columns of a table with their distribution and sort key flags
+func (pc *PostgresConnector) getRedshiftColumns(schema, tableName string) ([]redshiftColumn, error) {
+	query := `
+		SELECT
+			a.attnum,
+			a.attname,
+			pg_catalog.format_type(a.atttypid, a.atttypmod),
+			NOT a.attnotnull,
+			adef.adsrc,
+			a.attisdistkey,
+			a.attsortkeyord,
+			COALESCE(col_description(a.attrelid, a.attnum), '')
+		FROM
+			pg_catalog.pg_attribute a
+		JOIN
+			pg_catalog.pg_class c ON c.oid = a.attrelid
+		JOIN
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN
+			pg_catalog.pg_attrdef adef ON adef.adrelid = a.attrelid AND adef.adnum = a.attnum
+		WHERE
+			c.relname = $1
+			AND n.nspname = $2
+			AND a.attnum > 0
+			AND NOT a.attisdropped
+		ORDER BY
+			a.attnum
+	`
+
+	rows, err := pc.Query(pc.db, query, tableName, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []redshiftColumn
+	for rows.Next() {
+		var col redshiftColumn
+		var pgType string
+
+		err := rows.Scan(&col.num, &col.Name, &pgType, &col.Nullable, &col.DefaultValue,
+			&col.distKey, &col.sortOrder, &col.Comment)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+
+		col.Type = formatDataType(pgType)
+		columns = append(columns, col)
+	}
+
+	return columns, rows.Err()
+}
+
+// getRedshiftConstraints returns the constraints of a table, filling in the
+// primary key and foreign key references of its columns. Key columns are
+// matched by attribute number in Go, as Redshift cannot unnest the key arrays.
+func (pc *PostgresConnector) getRedshiftConstraints(schema, tableName string, names map[int64]string,
+	primaryKey map[string]bool, foreignKeys map[string]string) ([]t.Constraint, error) {
+	query := `
+		SELECT
+			con.conname,
+			con.contype,
+			con.conkey,
+			COALESCE(con.confkey, '{}'),
+			con.confrelid,
+			CASE WHEN con.contype = 'f' THEN con.confrelid::regclass::text ELSE '' END,
+			pg_get_constraintdef(con.oid)
+		FROM
+			pg_catalog.pg_constraint con
+		JOIN
+			pg_catalog.pg_class c ON c.oid = con.conrelid
+		JOIN
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE
+			c.relname = $1
+			AND n.nspname = $2
+			AND con.contype IN ('p', 'f', 'u')
+		ORDER BY
+			con.conname
+	`
+
+	rows, err := pc.Query(pc.db, query, tableName, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying constraints: %v", err)
+	}
+
+	type foreignKey struct {
+		columns  []string
+		refKeys  []int64
+		refTable int64
+		ref      string
+	}
+	var constraints []t.Constraint
+	var foreign []foreignKey
+	for rows.Next() {
+		var con t.Constraint
+		var contype, ref string
+		var keys, refKeys []int64
+		var refTable int64
+
+		err := rows.Scan(&con.Name, &contype, pq.Array(&keys), pq.Array(&refKeys), &refTable, &ref, &con.Definition)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning constraint results: %v", err)
+		}
+
+		con.Type = constraintTypes[contype]
+		for _, num := range keys {
+			con.Columns = append(con.Columns, names[num])
+		}
+		switch con.Type {
+		case t.PrimaryKeyConstraint:
+			for _, col := range con.Columns {
+				primaryKey[col] = true
+			}
+		case t.ForeignKeyConstraint:
+			foreign = append(foreign, foreignKey{columns: con.Columns, refKeys: refKeys, refTable: refTable, ref: ref})
+		}
+		constraints = append(constraints, con)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Referenced columns are looked up once the constraint rows are closed
+	for _, fk := range foreign {
+		refNames, err := pc.attributeNames(fk.refTable)
+		if err != nil {
+			return nil, err
+		}
+		for i, col := range fk.columns {
+			if _, seen := foreignKeys[col]; !seen && i < len(fk.refKeys) {
+				foreignKeys[col] = fmt.Sprintf("%s (%s)", fk.ref, refNames[fk.refKeys[i]])
+			}
+		}
+	}
+
+	return constraints, nil
+}
+
+// attributeNames returns the column names of a relation by attribute number
+func (pc *PostgresConnector) attributeNames(relid int64) (map[int64]string, error) {
+	rows, err := pc.Query(pc.db, `
+		SELECT attnum, attname
+		FROM pg_catalog.pg_attribute
+		WHERE attrelid = $1 AND attnum > 0 AND NOT attisdropped
+	`, relid)
+	if err != nil {
+		return nil, fmt.Errorf("error querying referenced columns: %v", err)
+	}
+	defer rows.Close()
+
+	names := make(map[int64]string)
+	for rows.Next() {
+		var num int64
+		var name string
+		if err := rows.Scan(&num, &name); err != nil {
+			return nil, fmt.Errorf("error scanning referenced column results: %v", err)
+		}
+		names[num] = name
+	}
+
+	return names, rows.Err()
+}
+
+// getRedshiftProperties returns the distribution style and storage figures of
+// a table from SVV_TABLE_INFO. The view has no row for empty tables, nor for
+// tables the user cannot select from, which then have no properties.
+func (pc *PostgresConnector) getRedshiftProperties(schema, tableName string) ([]t.Property, error) {
+	rows, err := pc.Query(pc.db, `
+		SELECT diststyle, size, unsorted
+		FROM svv_table_info
+		WHERE "schema" = $1 AND "table" = $2
+	`, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying table info: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	var distStyle sql.NullString
+	var size sql.NullInt64
+	var unsorted sql.NullFloat64
+	if err := rows.Scan(&distStyle, &size, &unsorted); err != nil {
+		return nil, fmt.Errorf("error scanning table info: %v", err)
+	}
+
+	var properties []t.Property
+	if distStyle.Valid {
+		properties = append(properties, t.Property{Name: "Distribution style", Value: distStyle.String})
+	}
+	if size.Valid {
+		properties = append(properties, t.Property{Name: "Size", Value: fmt.Sprintf("%d MB", size.Int64)})
+	}
+	if unsorted.Valid {
+		properties = append(properties, t.Property{Name: "Unsorted", Value: fmt.Sprintf("%.1f%%", unsorted.Float64)})
+	}
+	return properties, nil
+}
+
+// formatSortKey lists the sort key columns in key order, marking interleaved sort keys
+func formatSortKey(columns []redshiftColumn) string {
+	interleaved := false
+	for _, col := range columns {
+		if col.sortOrder < 0 {
+			interleaved = true
+		}
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return abs(columns[i].sortOrder) < abs(columns[j].sortOrder)
+	})
+
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	key := strings.Join(names, ", ")
+	if interleaved {
+		key = "INTERLEAVED (" + key + ")"
+	}
+	return key
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,20 @@
+package postgresql
+
+import (
+	"fmt"
+)
+
+// IsReplica reports whether the connected server is a standby in recovery
+func (pc *PostgresConnector) IsReplica() (bool, error) {
+	if pc.db == nil {
+		return false, fmt.Errorf("not connected to database")
+	}
+
+	var inRecovery bool
+	err := pc.QueryRow(pc.db, "SELECT pg_is_in_recovery()").Scan(&inRecovery)
+	if err != nil {
+		return false, fmt.Errorf("error checking recovery status: %v", err)
+	}
+
+	return inRecovery, nil
+}
@@ -0,0 +1,64 @@
+package postgresql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetRoutines returns the functions and procedures in the schema, leaving out
+// the ones installed by extensions
+func (pc *PostgresConnector) GetRoutines(schema string) ([]t.Routine, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Redshift, forked before PostgreSQL 11, has no prokind nor procedures
+	kind := `CASE p.prokind WHEN 'p' THEN 'PROCEDURE' WHEN 'a' THEN 'AGGREGATE' WHEN 'w' THEN 'WINDOW' ELSE 'FUNCTION' END`
+	if pc.redshift {
+		kind = `CASE WHEN p.proisagg THEN 'AGGREGATE' ELSE 'FUNCTION' END`
+	}
+
+	query := `
+		SELECT
+			p.proname,
+			` + kind + `,
+			pg_get_function_arguments(p.oid),
+			COALESCE(pg_get_function_result(p.oid), ''),
+			l.lanname,
+			COALESCE(p.prosrc, ''),
+			COALESCE(obj_description(p.oid, 'pg_proc'), '')
+		FROM
+			pg_catalog.pg_proc p
+		JOIN
+			pg_catalog.pg_namespace n ON n.oid = p.pronamespace
+		JOIN
+			pg_catalog.pg_language l ON l.oid = p.prolang
+		WHERE
+			n.nspname = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM pg_catalog.pg_depend d
+				WHERE d.classid = 'pg_catalog.pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+			)
+		ORDER BY
+			p.proname, pg_get_function_arguments(p.oid)
+	`
+
+	rows, err := pc.Query(pc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying routines: %v", err)
+	}
+	defer rows.Close()
+
+	var routines []t.Routine
+	for rows.Next() {
+		routine := t.Routine{Schema: schema}
+		if err := rows.Scan(&routine.Name, &routine.Kind, &routine.Arguments, &routine.ReturnType,
+			&routine.Language, &routine.Source, &routine.Comment); err != nil {
+			return nil, fmt.Errorf("error scanning routine results: %v", err)
+		}
+		routines = append(routines, routine)
+	}
+
+	return routines, rows.Err()
+}
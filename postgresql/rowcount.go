@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// EstimateRowCount returns the statistics-based row estimate of a table,
+// or -1 if the table has never been analyzed
+func (pc *PostgresConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if pc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT 
+			c.reltuples::bigint
+		FROM 
+			pg_catalog.pg_class c
+		JOIN 
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE 
+			c.relname = $1 
+			AND n.nspname = $2
+	`
+
+	var estimate int64
+	err := pc.QueryRow(pc.db, query, tableName, schema).Scan(&estimate)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+
+	// Since PostgreSQL 14, tables that were never analyzed report -1
+	if estimate < 0 {
+		return -1, nil
+	}
+
+	return estimate, nil
+}
+
+// GetTableSizes returns the row estimate and total size, indexes and TOAST
+// included, of every table in the schema
+func (pc *PostgresConnector) GetTableSizes(schema string) ([]t.TableSize, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			c.relname,
+			pg_total_relation_size(c.oid),
+			c.reltuples::bigint
+		FROM
+			pg_catalog.pg_class c
+		JOIN
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE
+			n.nspname = $1
+			AND c.relkind IN ('r', 'p')
+	`
+	rows, err := pc.Query(pc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying table sizes: %v", err)
+	}
+	defer rows.Close()
+
+	var sizes []t.TableSize
+	for rows.Next() {
+		var size t.TableSize
+		if err := rows.Scan(&size.Name, &size.Bytes, &size.Rows); err != nil {
+			return nil, fmt.Errorf("error scanning table sizes: %v", err)
+		}
+		// Since PostgreSQL 14, tables that were never analyzed report -1
+		if size.Rows < 0 {
+			size.Rows = -1
+		}
+		sizes = append(sizes, size)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading table sizes: %v", err)
+	}
+
+	return sizes, nil
+}
+
+// CountRows returns the exact number of rows in a table
+func (pc *PostgresConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if pc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := "SELECT COUNT(*) FROM " + quoteQualified(schema, tableName)
+
+	var count int64
+	pc.LogQuery(query, nil)
+	err := pc.db.QueryRowContext(ctx, query).Scan(&count)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,15 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// SampleRows returns up to limit rows of a table, in no particular order
+func (pc *PostgresConnector) SampleRows(ctx context.Context, schema, tableName string, limit int) (*t.QueryResult, error) {
+	query := fmt.Sprintf("SELECT * FROM %s LIMIT %d", quoteQualified(schema, tableName), limit)
+	pc.LogQuery(query, nil)
+	return pc.executeReadOnly(ctx, query)
+}
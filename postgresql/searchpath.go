@@ -0,0 +1,49 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// SearchPath returns the effective search_path, excluding implicit system schemas
+// and schemas that do not exist
+func (pc *PostgresConnector) SearchPath() ([]string, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Behind pgbouncer the search_path of the profile only applies within transactions
+	if pc.pgbouncer && pc.settings["search_path"] != "" {
+		return pc.transactionSearchPath()
+	}
+
+	var schemas []string
+	err := pc.QueryRow(pc.db, "SELECT current_schemas(false)").Scan(pq.Array(&schemas))
+	if err != nil {
+		return nil, fmt.Errorf("error querying search path: %v", err)
+	}
+
+	return schemas, nil
+}
+
+// transactionSearchPath returns the search_path in a transaction with the session settings applied
+func (pc *PostgresConnector) transactionSearchPath() ([]string, error) {
+	ctx := context.Background()
+	tx, err := pc.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("error starting read-only transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := pc.applyTransactionSettings(ctx, tx); err != nil {
+		return nil, err
+	}
+	var schemas []string
+	if err := tx.QueryRowContext(ctx, "SELECT current_schemas(false)").Scan(pq.Array(&schemas)); err != nil {
+		return nil, fmt.Errorf("error querying search path: %v", err)
+	}
+	return schemas, nil
+}
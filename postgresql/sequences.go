@@ -0,0 +1,68 @@
+package postgresql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetSequences returns the sequences in the schema with the column owning each,
+// through OWNED BY, a serial or an identity column
+func (pc *PostgresConnector) GetSequences(schema string) ([]t.Sequence, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	// Redshift has identity columns, but no sequences nor pg_sequences
+	if pc.redshift {
+		return nil, nil
+	}
+
+	query := `
+		SELECT
+			s.sequencename,
+			s.data_type::text,
+			s.last_value,
+			s.increment_by,
+			s.min_value::text,
+			s.max_value::text,
+			s.cycle,
+			COALESCE((
+				SELECT t.relname || '.' || a.attname
+				FROM pg_catalog.pg_depend d
+				JOIN pg_catalog.pg_class t ON t.oid = d.refobjid
+				JOIN pg_catalog.pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+				WHERE d.classid = 'pg_catalog.pg_class'::regclass
+					AND d.objid = c.oid
+					AND d.refclassid = 'pg_catalog.pg_class'::regclass
+					AND d.deptype IN ('a', 'i')
+				LIMIT 1
+			), '')
+		FROM
+			pg_catalog.pg_sequences s
+		JOIN
+			pg_catalog.pg_namespace n ON n.nspname = s.schemaname
+		JOIN
+			pg_catalog.pg_class c ON c.relnamespace = n.oid AND c.relname = s.sequencename
+		WHERE
+			s.schemaname = $1
+		ORDER BY
+			s.sequencename
+	`
+
+	rows, err := pc.Query(pc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying sequences: %v", err)
+	}
+	defer rows.Close()
+
+	var sequences []t.Sequence
+	for rows.Next() {
+		seq := t.Sequence{Schema: schema}
+		if err := rows.Scan(&seq.Name, &seq.DataType, &seq.Current, &seq.Increment, &seq.Min, &seq.Max, &seq.Cycle, &seq.OwnedBy); err != nil {
+			return nil, fmt.Errorf("error scanning sequence results: %v", err)
+		}
+		sequences = append(sequences, seq)
+	}
+
+	return sequences, rows.Err()
+}
@@ -0,0 +1,136 @@
+package postgresql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// topTablesLimit is the number of tables listed in the largest and most referenced rankings
+const topTablesLimit = 10
+
+// GetSchemaStats returns object counts, sizes and foreign key statistics of a schema
+func (pc *PostgresConnector) GetSchemaStats(schema string) (*t.SchemaStats, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	stats := &t.SchemaStats{Schema: schema}
+
+	// Object counts and total size
+	countQuery := `
+		SELECT
+			COUNT(*) FILTER (WHERE c.relkind IN ('r', 'p')),
+			COUNT(*) FILTER (WHERE c.relkind IN ('v', 'm')),
+			COUNT(*) FILTER (WHERE c.relkind IN ('i', 'I')),
+			COUNT(*) FILTER (WHERE c.relkind = 'S'),
+			COALESCE(SUM(pg_total_relation_size(c.oid)) FILTER (WHERE c.relkind IN ('r', 'm')), 0)::bigint,
+			(SELECT COUNT(*) FROM pg_catalog.pg_proc p WHERE p.pronamespace = n.oid)
+		FROM
+			pg_catalog.pg_namespace n
+		LEFT JOIN
+			pg_catalog.pg_class c ON c.relnamespace = n.oid
+		WHERE
+			n.nspname = $1
+		GROUP BY
+			n.oid
+	`
+	err := pc.QueryRow(pc.db, countQuery, schema).Scan(
+		&stats.Tables, &stats.Views, &stats.Indexes, &stats.Sequences,
+		&stats.TotalBytes, &stats.Functions,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying schema statistics: %v", err)
+	}
+
+	// Largest tables
+	sizeQuery := `
+		SELECT
+			c.relname,
+			pg_total_relation_size(c.oid),
+			GREATEST(c.reltuples, 0)::bigint
+		FROM
+			pg_catalog.pg_class c
+		JOIN
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE
+			n.nspname = $1
+			AND c.relkind = 'r'
+		ORDER BY
+			2 DESC, 1
+		LIMIT $2
+	`
+	rows, err := pc.Query(pc.db, sizeQuery, schema, topTablesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying table sizes: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var size t.TableSize
+		if err := rows.Scan(&size.Name, &size.Bytes, &size.Rows); err != nil {
+			return nil, fmt.Errorf("error scanning table sizes: %v", err)
+		}
+		stats.LargestTables = append(stats.LargestTables, size)
+	}
+
+	// Foreign key connectivity
+	fkQuery := `
+		WITH tables AS (
+			SELECT c.oid
+			FROM pg_catalog.pg_class c
+			JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = $1 AND c.relkind IN ('r', 'p')
+		),
+		fks AS (
+			SELECT conrelid, confrelid
+			FROM pg_catalog.pg_constraint
+			WHERE contype = 'f' AND conrelid IN (SELECT oid FROM tables)
+		)
+		SELECT
+			(SELECT COUNT(*) FROM fks),
+			(SELECT COUNT(*) FROM tables
+			 WHERE oid IN (SELECT conrelid FROM fks) OR oid IN (SELECT confrelid FROM fks))
+	`
+	err = pc.QueryRow(pc.db, fkQuery, schema).Scan(&stats.ForeignKeys, &stats.ConnectedTables)
+	if err != nil {
+		return nil, fmt.Errorf("error querying foreign key statistics: %v", err)
+	}
+	stats.IsolatedTables = stats.Tables - stats.ConnectedTables
+
+	// Most referenced tables
+	refQuery := `
+		SELECT
+			c.relname,
+			COUNT(*)
+		FROM
+			pg_catalog.pg_constraint con
+		JOIN
+			pg_catalog.pg_class c ON c.oid = con.confrelid
+		JOIN
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE
+			con.contype = 'f'
+			AND n.nspname = $1
+		GROUP BY
+			c.relname
+		ORDER BY
+			2 DESC, 1
+		LIMIT $2
+	`
+	refRows, err := pc.Query(pc.db, refQuery, schema, topTablesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying referenced tables: %v", err)
+	}
+	defer refRows.Close()
+
+	for refRows.Next() {
+		var ref t.TableReferences
+		if err := refRows.Scan(&ref.Name, &ref.References); err != nil {
+			return nil, fmt.Errorf("error scanning referenced tables: %v", err)
+		}
+		stats.MostReferenced = append(stats.MostReferenced, ref)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,82 @@
+package postgresql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Bits of pg_trigger.tgtype
+const (
+	triggerRow      = 1 << 0
+	triggerBefore   = 1 << 1
+	triggerInsert   = 1 << 2
+	triggerDelete   = 1 << 3
+	triggerUpdate   = 1 << 4
+	triggerTruncate = 1 << 5
+	triggerInstead  = 1 << 6
+)
+
+// getTriggers returns the triggers of a table, leaving out the internal ones
+// enforcing foreign keys
+func (pc *PostgresConnector) getTriggers(schema, tableName string) ([]t.Trigger, error) {
+	query := `
+		SELECT
+			tg.tgname,
+			tg.tgtype,
+			tg.tgfoid::regprocedure::text,
+			tg.tgenabled <> 'D',
+			pg_get_triggerdef(tg.oid, true)
+		FROM
+			pg_catalog.pg_trigger tg
+		JOIN
+			pg_catalog.pg_class c ON c.oid = tg.tgrelid
+		JOIN
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE
+			n.nspname = $1
+			AND c.relname = $2
+			AND NOT tg.tgisinternal
+		ORDER BY
+			tg.tgname
+	`
+
+	rows, err := pc.Query(pc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying triggers: %v", err)
+	}
+	defer rows.Close()
+
+	var triggers []t.Trigger
+	for rows.Next() {
+		var trigger t.Trigger
+		var tgtype int
+		if err := rows.Scan(&trigger.Name, &tgtype, &trigger.Function, &trigger.Enabled, &trigger.Definition); err != nil {
+			return nil, fmt.Errorf("error scanning trigger results: %v", err)
+		}
+
+		switch {
+		case tgtype&triggerInstead != 0:
+			trigger.Timing = "INSTEAD OF"
+		case tgtype&triggerBefore != 0:
+			trigger.Timing = "BEFORE"
+		default:
+			trigger.Timing = "AFTER"
+		}
+		for _, event := range []struct {
+			bit  int
+			name string
+		}{{triggerInsert, "INSERT"}, {triggerUpdate, "UPDATE"}, {triggerDelete, "DELETE"}, {triggerTruncate, "TRUNCATE"}} {
+			if tgtype&event.bit != 0 {
+				trigger.Events = append(trigger.Events, event.name)
+			}
+		}
+		trigger.ForEach = "STATEMENT"
+		if tgtype&triggerRow != 0 {
+			trigger.ForEach = "ROW"
+		}
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers, rows.Err()
+}
@@ -0,0 +1,68 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetTableUsage returns the scans and row changes of the tables in the schema
+// since the statistics of the database were last reset
+func (pc *PostgresConnector) GetTableUsage(schema string) (*t.SchemaUsage, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	// CockroachDB has pg_stat_user_tables, but does not fill it
+	if pc.cockroach {
+		return nil, fmt.Errorf("table usage statistics are not supported for CockroachDB")
+	}
+
+	usage := &t.SchemaUsage{Schema: schema}
+
+	var since sql.NullTime
+	resetQuery := `
+		SELECT stats_reset
+		FROM pg_catalog.pg_stat_database
+		WHERE datname = current_database()
+	`
+	if err := pc.QueryRow(pc.db, resetQuery).Scan(&since); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error querying statistics reset time: %v", err)
+	}
+	usage.Since = since.Time
+
+	query := `
+		SELECT
+			s.relname,
+			s.seq_scan,
+			COALESCE(s.idx_scan, 0),
+			s.n_tup_ins,
+			s.n_tup_upd,
+			s.n_tup_del,
+			s.n_live_tup,
+			pg_total_relation_size(s.relid)
+		FROM
+			pg_catalog.pg_stat_user_tables s
+		WHERE
+			s.schemaname = $1
+		ORDER BY
+			s.relname
+	`
+	rows, err := pc.Query(pc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying table usage: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var table t.TableUsage
+		err := rows.Scan(&table.Name, &table.SeqScans, &table.IndexScans,
+			&table.Inserts, &table.Updates, &table.Deletes, &table.Rows, &table.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning table usage: %v", err)
+		}
+		usage.Tables = append(usage.Tables, table)
+	}
+
+	return usage, rows.Err()
+}
@@ -0,0 +1,131 @@
+package postgresql
+
+import (
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViewDefinitions returns the definitions of the views and materialized views in the schema
+func (pc *PostgresConnector) GetViewDefinitions(schema string) (map[string]string, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT 
+			c.relname,
+			pg_get_viewdef(c.oid, true)
+		FROM 
+			pg_catalog.pg_class c
+		JOIN 
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE 
+			n.nspname = $1
+			AND c.relkind IN ('v', 'm')
+	`
+
+	rows, err := pc.Query(pc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying view definitions: %v", err)
+	}
+	defer rows.Close()
+
+	views := make(map[string]string)
+	for rows.Next() {
+		var name, definition string
+		if err := rows.Scan(&name, &definition); err != nil {
+			return nil, fmt.Errorf("error scanning view definition results: %v", err)
+		}
+		views[name] = definition
+	}
+
+	return views, nil
+}
+
+// GetViews returns the views and materialized views in the schema, with their columns and definitions
+func (pc *PostgresConnector) GetViews(schema string) ([]t.View, error) {
+	if pc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			c.relname,
+			c.relkind = 'm',
+			pg_get_viewdef(c.oid, true),
+			COALESCE(obj_description(c.oid, 'pg_class'), '')
+		FROM
+			pg_catalog.pg_class c
+		JOIN
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE
+			n.nspname = $1
+			AND c.relkind IN ('v', 'm')
+		ORDER BY
+			c.relname
+	`
+
+	rows, err := pc.Query(pc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+	defer rows.Close()
+
+	var views []t.View
+	byName := make(map[string]int)
+	for rows.Next() {
+		view := t.View{Schema: schema}
+		if err := rows.Scan(&view.Name, &view.Materialized, &view.Definition, &view.Comment); err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		byName[view.Name] = len(views)
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading views: %v", err)
+	}
+
+	// The columns of all the views at once
+	columnQuery := `
+		SELECT
+			c.relname,
+			a.attname,
+			pg_catalog.format_type(a.atttypid, a.atttypmod),
+			NOT a.attnotnull,
+			COALESCE(col_description(a.attrelid, a.attnum), '')
+		FROM
+			pg_catalog.pg_attribute a
+		JOIN
+			pg_catalog.pg_class c ON c.oid = a.attrelid
+		JOIN
+			pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE
+			n.nspname = $1
+			AND c.relkind IN ('v', 'm')
+			AND a.attnum > 0
+			AND NOT a.attisdropped
+		ORDER BY
+			c.relname, a.attnum
+	`
+
+	columnRows, err := pc.Query(pc.db, columnQuery, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying view columns: %v", err)
+	}
+	defer columnRows.Close()
+
+	for columnRows.Next() {
+		var viewName, pgType string
+		var col t.Column
+		if err := columnRows.Scan(&viewName, &col.Name, &pgType, &col.Nullable, &col.Comment); err != nil {
+			return nil, fmt.Errorf("error scanning view column results: %v", err)
+		}
+		col.Type = formatDataType(pgType)
+		if i, ok := byName[viewName]; ok {
+			views[i].Columns = append(views[i].Columns, col)
+		}
+	}
+
+	return views, columnRows.Err()
+}
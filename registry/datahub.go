@@ -0,0 +1,187 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// dataHub reads datasets from the DataHub GraphQL API
+type dataHub struct {
+	client   *client
+	platform string
+	env      string
+}
+
+// datasetQuery reads the documentation of a dataset. The editable properties
+// hold what users entered in DataHub, the others what ingestion found.
+const datasetQuery = `query dataset($urn: String!) {
+  dataset(urn: $urn) {
+    properties { description }
+    editableProperties { description }
+    tags { ...tags }
+    glossaryTerms { ...terms }
+    schemaMetadata { fields { fieldPath description tags { ...tags } glossaryTerms { ...terms } } }
+    editableSchemaMetadata { editableSchemaFieldInfo { fieldPath description tags { ...tags } glossaryTerms { ...terms } } }
+  }
+}
+fragment tags on GlobalTags { tags { tag { urn properties { name } } } }
+fragment terms on GlossaryTerms { terms { term { urn properties { name } } } }`
+
+// dhTags are the tags attached to an entity
+type dhTags struct {
+	Tags []struct {
+		Tag dhNamed `json:"tag"`
+	} `json:"tags"`
+}
+
+// dhTerms are the glossary terms attached to an entity
+type dhTerms struct {
+	Terms []struct {
+		Term dhNamed `json:"term"`
+	} `json:"terms"`
+}
+
+// dhNamed is a tag or glossary term, whose name is optional
+type dhNamed struct {
+	URN        string `json:"urn"`
+	Properties *struct {
+		Name string `json:"name"`
+	} `json:"properties"`
+}
+
+// dhField is the documentation of a schema field
+type dhField struct {
+	FieldPath     string   `json:"fieldPath"`
+	Description   string   `json:"description"`
+	Tags          *dhTags  `json:"tags"`
+	GlossaryTerms *dhTerms `json:"glossaryTerms"`
+}
+
+// dhDescribed is an aspect with a description
+type dhDescribed struct {
+	Description string `json:"description"`
+}
+
+// dhDataset is the part of a dataset that is shown
+type dhDataset struct {
+	Properties         *dhDescribed `json:"properties"`
+	EditableProperties *dhDescribed `json:"editableProperties"`
+	Tags               *dhTags      `json:"tags"`
+	GlossaryTerms      *dhTerms     `json:"glossaryTerms"`
+	SchemaMetadata     *struct {
+		Fields []dhField `json:"fields"`
+	} `json:"schemaMetadata"`
+	EditableSchemaMetadata *struct {
+		Fields []dhField `json:"editableSchemaFieldInfo"`
+	} `json:"editableSchemaMetadata"`
+}
+
+// Lookup reads a dataset by its URN, built from the platform, the qualified
+// table name and the environment
+func (dh *dataHub) Lookup(ctx context.Context, database, schema, table string) (*Entry, error) {
+	urn := fmt.Sprintf("urn:li:dataset:(urn:li:dataPlatform:%s,%s,%s)", dh.platform, tableName(database, schema, table), dh.env)
+	body, err := json.Marshal(map[string]any{
+		"query":     datasetQuery,
+		"variables": map[string]string{"urn": urn},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding catalog request: %v", err)
+	}
+
+	data, err := dh.client.do(ctx, http.MethodPost, "/api/graphql", "application/json", bytes.NewReader(body))
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			Dataset *dhDataset `json:"dataset"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("error parsing catalog response: %v", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("catalog error: %s", resp.Errors[0].Message)
+	}
+	dataset := resp.Data.Dataset
+	if dataset == nil {
+		return nil, nil
+	}
+
+	entry := &Entry{Source: "DataHub", Tags: dhLabels(dataset.Tags, dataset.GlossaryTerms), Columns: make(map[string]ColumnEntry)}
+	if dataset.Properties != nil {
+		entry.Description = dataset.Properties.Description
+	}
+	if dataset.EditableProperties != nil && dataset.EditableProperties.Description != "" {
+		entry.Description = dataset.EditableProperties.Description
+	}
+
+	// Edits made in DataHub take precedence over the ingested documentation
+	var fields []dhField
+	if dataset.SchemaMetadata != nil {
+		fields = append(fields, dataset.SchemaMetadata.Fields...)
+	}
+	if dataset.EditableSchemaMetadata != nil {
+		fields = append(fields, dataset.EditableSchemaMetadata.Fields...)
+	}
+	for _, field := range fields {
+		name := fieldName(field.FieldPath)
+		col := entry.Columns[name]
+		if field.Description != "" {
+			col.Description = field.Description
+		}
+		for _, label := range dhLabels(field.Tags, field.GlossaryTerms) {
+			if !slices.Contains(col.Tags, label) {
+				col.Tags = append(col.Tags, label)
+			}
+		}
+		if col.Description != "" || len(col.Tags) > 0 {
+			entry.Columns[name] = col
+		}
+	}
+
+	return entry, nil
+}
+
+// dhLabels returns the names of tags and glossary terms
+func dhLabels(tags *dhTags, terms *dhTerms) []string {
+	var labels []string
+	if tags != nil {
+		for _, tag := range tags.Tags {
+			labels = append(labels, tag.Tag.name("urn:li:tag:"))
+		}
+	}
+	if terms != nil {
+		for _, term := range terms.Terms {
+			labels = append(labels, term.Term.name("urn:li:glossaryTerm:"))
+		}
+	}
+	return labels
+}
+
+// name returns the display name, or the URN without its prefix if there is none
+func (n dhNamed) name(prefix string) string {
+	if n.Properties != nil && n.Properties.Name != "" {
+		return n.Properties.Name
+	}
+	return strings.TrimPrefix(n.URN, prefix)
+}
+
+// fieldAnnotation matches a step annotation of a version 2 field path
+var fieldAnnotation = regexp.MustCompile(`\[[^\]]*\]\.?`)
+
+// fieldName returns the column path of a field. Version 2 paths annotate each
+// step with its type, e.g. [version=2.0].[type=struct].address.[type=string].city.
+func fieldName(path string) string {
+	return fieldAnnotation.ReplaceAllString(path, "")
+}
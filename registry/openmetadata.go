@@ -0,0 +1,90 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// openMetadata reads tables from the OpenMetadata REST API
+type openMetadata struct {
+	client  *client
+	service string
+}
+
+// omTag is a tag or glossary term attached to an entity
+type omTag struct {
+	TagFQN string `json:"tagFQN"`
+}
+
+// omColumn is a column of an OpenMetadata table. Columns of struct types have children.
+type omColumn struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Tags        []omTag    `json:"tags"`
+	Children    []omColumn `json:"children"`
+}
+
+// omTable is the part of an OpenMetadata table entity that is shown
+type omTable struct {
+	Description string     `json:"description"`
+	Tags        []omTag    `json:"tags"`
+	Columns     []omColumn `json:"columns"`
+}
+
+// Lookup reads a table by its fully qualified name, service.database.schema.table
+func (om *openMetadata) Lookup(ctx context.Context, database, schema, table string) (*Entry, error) {
+	if database == schema {
+		database = ""
+	}
+	fqn := qualifiedName(quoteFQN(om.service), quoteFQN(database), quoteFQN(schema), quoteFQN(table))
+	data, err := om.client.do(ctx, http.MethodGet, "/api/v1/tables/name/"+url.PathEscape(fqn)+"?fields=tags,columns", "", nil)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var tbl omTable
+	if err := json.Unmarshal(data, &tbl); err != nil {
+		return nil, fmt.Errorf("error parsing catalog response: %v", err)
+	}
+
+	entry := &Entry{
+		Source:      "OpenMetadata",
+		Description: tbl.Description,
+		Tags:        omTagNames(tbl.Tags),
+		Columns:     make(map[string]ColumnEntry),
+	}
+	var addColumns func(prefix string, columns []omColumn)
+	addColumns = func(prefix string, columns []omColumn) {
+		for _, col := range columns {
+			name := qualifiedName(prefix, col.Name)
+			if col.Description != "" || len(col.Tags) > 0 {
+				entry.Columns[name] = ColumnEntry{Description: col.Description, Tags: omTagNames(col.Tags)}
+			}
+			addColumns(name, col.Children)
+		}
+	}
+	addColumns("", tbl.Columns)
+
+	return entry, nil
+}
+
+// omTagNames returns the qualified names of tags, e.g. PII.Sensitive
+func omTagNames(tags []omTag) []string {
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.TagFQN)
+	}
+	return names
+}
+
+// quoteFQN quotes a part of a fully qualified name that contains a dot
+func quoteFQN(name string) string {
+	if strings.Contains(name, ".") {
+		return `"` + name + `"`
+	}
+	return name
+}
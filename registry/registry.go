@@ -0,0 +1,136 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Kinds of data catalog the registry can read from
+const (
+	KindOpenMetadata = "openmetadata"
+	KindDataHub      = "datahub"
+)
+
+// requestTimeout limits how long a lookup waits for the catalog
+const requestTimeout = 10 * time.Second
+
+// Settings selects the data catalog that documents the databases. Tables are
+// looked up by their qualified name, database.schema.table, which the catalog
+// prefixes with the service (OpenMetadata) or platform (DataHub) name.
+type Settings struct {
+	Kind    string `json:"kind"`              // openmetadata or datahub
+	URL     string `json:"url"`               // Base URL of the catalog, e.g. http://localhost:8585
+	Token   string `json:"token,omitempty"`   // Sent as "Authorization: Bearer <token>"
+	Service string `json:"service,omitempty"` // OpenMetadata database service, or DataHub platform, e.g. postgres
+	Env     string `json:"env,omitempty"`     // DataHub environment of the datasets, PROD if empty
+}
+
+// Entry is what the catalog says about a table
+type Entry struct {
+	Source      string // Name of the catalog, for display
+	Description string
+	Tags        []string
+	Columns     map[string]ColumnEntry // By column name
+}
+
+// ColumnEntry is what the catalog says about a column
+type ColumnEntry struct {
+	Description string
+	Tags        []string
+}
+
+// Source looks up tables in a data catalog. Catalogs are only read.
+type Source interface {
+	// Lookup returns the entry of a table, or nil if the catalog does not know it
+	Lookup(ctx context.Context, database, schema, table string) (*Entry, error)
+}
+
+// New returns the source for the settings, or nil when no catalog is configured
+func New(settings *Settings) (Source, error) {
+	if settings == nil || settings.URL == "" {
+		return nil, nil
+	}
+
+	c := &client{
+		http:    &http.Client{Timeout: requestTimeout},
+		baseURL: strings.TrimSuffix(settings.URL, "/"),
+		token:   settings.Token,
+	}
+	switch settings.Kind {
+	case KindOpenMetadata:
+		return &openMetadata{client: c, service: settings.Service}, nil
+	case KindDataHub:
+		env := settings.Env
+		if env == "" {
+			env = "PROD"
+		}
+		return &dataHub{client: c, platform: settings.Service, env: env}, nil
+	default:
+		return nil, fmt.Errorf("unknown catalog kind '%s'", settings.Kind)
+	}
+}
+
+// client sends authenticated requests to a catalog API
+type client struct {
+	http    *http.Client
+	baseURL string
+	token   string
+}
+
+// do sends a request and returns the response body. A missing resource gives
+// a nil body and no error.
+func (c *client) do(ctx context.Context, method, path, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating catalog request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error querying catalog: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading catalog response: %v", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("catalog returned %s", resp.Status)
+	}
+	return data, nil
+}
+
+// tableName returns the qualified name of a table. Databases without schemas,
+// such as MySQL, give the database as the schema, which is only named once.
+func tableName(database, schema, table string) string {
+	if database == schema {
+		database = ""
+	}
+	return qualifiedName(database, schema, table)
+}
+
+// qualifiedName joins the non-empty parts of a name with dots
+func qualifiedName(parts ...string) string {
+	var names []string
+	for _, part := range parts {
+		if part != "" {
+			names = append(names, part)
+		}
+	}
+	return strings.Join(names, ".")
+}
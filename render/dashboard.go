@@ -0,0 +1,85 @@
+package render
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/export"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// NewStatsDashboard creates the schema statistics dashboard
+func NewStatsDashboard(stats *t.SchemaStats) fyne.CanvasObject {
+	counts := container.NewGridWithColumns(3,
+		newStatCard("Tables", fmt.Sprint(stats.Tables)),
+		newStatCard("Views", fmt.Sprint(stats.Views)),
+		newStatCard("Indexes", fmt.Sprint(stats.Indexes)),
+		newStatCard("Functions", fmt.Sprint(stats.Functions)),
+		newStatCard("Sequences", fmt.Sprint(stats.Sequences)),
+		newStatCard("Total size", export.FormatBytes(stats.TotalBytes)),
+	)
+
+	// Bar chart of the largest tables, relative to the biggest one
+	largest := container.NewVBox()
+	for _, size := range stats.LargestTables {
+		biggest := max(stats.LargestTables[0].Bytes, 1)
+		largest.Add(newBarRow(size.Name, float64(size.Bytes)/float64(biggest), export.FormatBytes(size.Bytes)))
+	}
+
+	// Foreign key connectivity
+	fkStats := widget.NewLabel(fmt.Sprintf("%d foreign keys, %d connected tables, %d isolated tables",
+		stats.ForeignKeys, stats.ConnectedTables, stats.IsolatedTables))
+
+	referenced := container.NewVBox()
+	for _, ref := range stats.MostReferenced {
+		most := max(stats.MostReferenced[0].References, 1)
+		referenced.Add(newBarRow(ref.Name, float64(ref.References)/float64(most),
+			fmt.Sprintf("%d references", ref.References)))
+	}
+
+	return container.NewVBox(
+		counts,
+		widget.NewLabelWithStyle("Largest tables", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		largest,
+		widget.NewLabelWithStyle("Foreign keys", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		fkStats,
+		referenced,
+	)
+}
+
+// NewUsageHeatmap creates the table usage heatmap, a bar per table scaled by its
+// rated usage, from the busiest to the least used table
+func NewUsageHeatmap(usage *t.SchemaUsage) fyne.CanvasObject {
+	window := "Accesses since the statistics were collected"
+	if days := export.UsageWindowDays(usage); days > 0 {
+		window = fmt.Sprintf("Accesses since %s (%.0f days)", usage.Since.Format("2006-01-02"), days)
+	}
+
+	heatmap := container.NewVBox(widget.NewLabel(window))
+	for _, table := range usage.Tables {
+		heatmap.Add(newBarRow(table.Name, table.Score, fmt.Sprintf("%s – %d accesses", table.Heat, table.Accesses())))
+	}
+	return heatmap
+}
+
+// newStatCard creates a small card showing a single statistic
+func newStatCard(title, value string) fyne.CanvasObject {
+	return widget.NewCard("", title,
+		widget.NewLabelWithStyle(value, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+}
+
+// newBarRow creates a labelled horizontal bar for dashboard charts
+func newBarRow(label string, value float64, text string) fyne.CanvasObject {
+	// The formatter must be set first, as setting the value renders the bar
+	bar := widget.NewProgressBar()
+	bar.TextFormatter = func() string { return text }
+	bar.SetValue(value)
+
+	name := widget.NewLabel(label)
+	name.Truncation = fyne.TextTruncateEllipsis
+
+	return container.NewGridWithColumns(2, name, bar)
+}
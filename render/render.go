@@ -0,0 +1,146 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/software"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/diagram"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// variantTheme forces the light or dark variant of the default theme, which
+// is otherwise picked from the desktop settings
+type variantTheme struct {
+	fyne.Theme
+	variant fyne.ThemeVariant
+}
+
+// Color implements fyne.Theme
+func (vt variantTheme) Color(name fyne.ThemeColorName, _ fyne.ThemeVariant) color.Color {
+	return vt.Theme.Color(name, vt.variant)
+}
+
+// headless guards the app created for rendering without a window
+var headless sync.Once
+
+// startHeadless prepares rendering without a display using the given theme variant.
+// The app is only needed for its settings and text measurement, so no window is
+// ever opened. The theme is applied before any widget is created, as widgets pick
+// their colours when they are first rendered.
+func startHeadless(dark bool) {
+	headless.Do(func() {
+		if fyne.CurrentApp() == nil {
+			app.New()
+		}
+	})
+
+	variant := theme.VariantLight
+	if dark {
+		variant = theme.VariantDark
+	}
+	fyne.CurrentApp().Settings().SetTheme(variantTheme{Theme: theme.DefaultTheme(), variant: variant})
+}
+
+// renderObject draws an object on an offscreen software canvas of at least the given width
+func renderObject(obj fyne.CanvasObject, width float32) image.Image {
+	c := software.NewCanvas()
+	c.SetContent(obj)
+
+	size := c.Content().MinSize()
+	c.Resize(fyne.NewSize(fyne.Max(size.Width+2*theme.Padding(), width), size.Height+2*theme.Padding()))
+
+	return c.Capture()
+}
+
+// Dashboard draws the schema statistics dashboard to an image without opening a window
+func Dashboard(stats *t.SchemaStats, width float32, dark bool) image.Image {
+	startHeadless(dark)
+	return renderObject(NewStatsDashboard(stats), width)
+}
+
+// Diagram draws the ER diagram of the given tables to an image without opening
+// a window. Positions arranged by hand in the GUI are honoured when given.
+func Diagram(tables []*t.Table, positions map[string]config.Position, dark bool) image.Image {
+	startHeadless(dark)
+
+	boxes := make(map[string]fyne.CanvasObject, len(tables))
+	sizes := make(map[string]diagram.Point, len(tables))
+	for _, table := range tables {
+		box := NewTableBox(table)
+		size := box.MinSize()
+		boxes[table.Name], sizes[table.Name] = box, diagram.Point{X: size.Width, Y: size.Height}
+	}
+	layout := diagram.Layout(tables, "", sizes, positions, theme.Padding())
+
+	// Lines go below the boxes
+	objects := container.NewWithoutLayout()
+	for _, edge := range diagram.Edges(tables) {
+		start, end := diagram.Endpoints(layout[edge.From], layout[edge.To])
+		objects.Add(NewEdgeLine(start, end))
+	}
+	var placed []diagram.Box
+	for _, table := range tables {
+		box, area := boxes[table.Name], layout[table.Name]
+		box.Move(fyne.NewPos(area.X, area.Y))
+		box.Resize(fyne.NewSize(area.Width, area.Height))
+		objects.Add(box)
+		placed = append(placed, area)
+	}
+
+	extent := diagram.Extent(placed)
+	size := canvas.NewRectangle(color.Transparent)
+	size.SetMinSize(fyne.NewSize(extent.X, extent.Y))
+
+	return renderObject(container.NewStack(size, objects), 0)
+}
+
+// NewTableBox creates the box showing a table and its columns in the ER diagram
+func NewTableBox(table *t.Table) fyne.CanvasObject {
+	bg := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	bg.StrokeColor = theme.Color(theme.ColorNamePrimary)
+	bg.StrokeWidth = 1
+
+	header := canvas.NewText(table.Name, theme.Color(theme.ColorNameForeground))
+	header.TextStyle = fyne.TextStyle{Bold: true}
+
+	lines := container.NewVBox(header, widget.NewSeparator())
+	for _, col := range table.Columns {
+		marker := "  "
+		if col.IsPrimaryKey {
+			marker = "PK"
+		} else if col.ForeignKey.Valid {
+			marker = "FK"
+		}
+		text := canvas.NewText(fmt.Sprintf("%s %s  %s", marker, col.Name, col.Type), theme.Color(theme.ColorNameForeground))
+		text.TextStyle = fyne.TextStyle{Monospace: true}
+		text.TextSize = theme.CaptionTextSize()
+		lines.Add(text)
+	}
+
+	return container.NewStack(bg, container.NewPadded(lines))
+}
+
+// NewEdgeLine creates the line of a foreign key between two points of the ER diagram
+func NewEdgeLine(start, end diagram.Point) *canvas.Line {
+	line := canvas.NewLine(theme.Color(theme.ColorNamePrimary))
+	line.StrokeWidth = 1.5
+	MoveEdgeLine(line, start, end)
+	return line
+}
+
+// MoveEdgeLine moves a foreign key line to new end points
+func MoveEdgeLine(line *canvas.Line, start, end diagram.Point) {
+	line.Position1 = fyne.NewPos(start.X, start.Y)
+	line.Position2 = fyne.NewPos(end.X, end.Y)
+}
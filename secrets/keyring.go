@@ -0,0 +1,34 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the service name under which passwords are stored in the OS keyring
+const keyringService = "db-reader"
+
+// KeyringAvailable reports whether the OS keyring can be used on this system
+func KeyringAvailable() bool {
+	_, err := keyring.Get(keyringService, "availability-probe")
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+// KeyringGet returns the password stored in the OS keyring under an account
+func KeyringGet(account string) (string, error) {
+	password, err := keyring.Get(keyringService, account)
+	if err != nil {
+		return "", fmt.Errorf("error reading password from the keyring: %v", err)
+	}
+	return password, nil
+}
+
+// KeyringSet stores a password in the OS keyring under an account
+func KeyringSet(account, password string) error {
+	if err := keyring.Set(keyringService, account, password); err != nil {
+		return fmt.Errorf("error storing password in the keyring: %v", err)
+	}
+	return nil
+}
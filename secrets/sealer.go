@@ -0,0 +1,116 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// passphraseCheck is sealed with a new passphrase, so that a wrong passphrase
+// can be detected before any password is needed
+const passphraseCheck = "db-reader"
+
+// Sealer encrypts secrets with a key derived from a master passphrase
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+// Encryption holds what is needed to verify a master passphrase and derive its key.
+// It contains no secret and is stored alongside the encrypted passwords.
+type Encryption struct {
+	Salt  string `json:"salt"`  // Base64 scrypt salt
+	Check string `json:"check"` // passphraseCheck sealed with the derived key
+}
+
+// NewEncryption sets up encryption with a new master passphrase
+func NewEncryption(passphrase string) (*Encryption, *Sealer, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, fmt.Errorf("error generating salt: %v", err)
+	}
+
+	sealer, err := newSealer(passphrase, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	check, err := sealer.Seal(passphraseCheck, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &Encryption{Salt: base64.StdEncoding.EncodeToString(salt), Check: check}, sealer, nil
+}
+
+// Unlock derives the sealer for a master passphrase, failing if the passphrase is wrong
+func (e *Encryption) Unlock(passphrase string) (*Sealer, error) {
+	salt, err := base64.StdEncoding.DecodeString(e.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption salt: %v", err)
+	}
+
+	sealer, err := newSealer(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if check, err := sealer.Open(e.Check, ""); err != nil || check != passphraseCheck {
+		return nil, fmt.Errorf("wrong master passphrase")
+	}
+
+	return sealer, nil
+}
+
+// newSealer derives an AES-256-GCM key from a passphrase
+func newSealer(passphrase string, salt []byte) (*Sealer, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving key: %v", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %v", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %v", err)
+	}
+
+	return &Sealer{aead: aead}, nil
+}
+
+// Seal encrypts a secret bound to additional data, such as the ID of what owns
+// it, returning base64 of the nonce followed by the ciphertext
+func (s *Sealer) Seal(plaintext, additional string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %v", err)
+	}
+
+	sealed := s.aead.Seal(nonce, nonce, []byte(plaintext), []byte(additional))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a secret sealed by Seal with the same additional data
+func (s *Sealer) Open(sealed, additional string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %v", err)
+	}
+	if len(data) < s.aead.NonceSize() {
+		return "", fmt.Errorf("invalid encrypted value")
+	}
+
+	nonce, ciphertext := data[:s.aead.NonceSize()], data[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, []byte(additional))
+	if err != nil {
+		return "", fmt.Errorf("error decrypting value: %v", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,200 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// Secret stores a reference can point to
+const (
+	StoreVault = "vault" // HashiCorp Vault, e.g. "vault:secret/data/db/prod#password"
+	StoreAWS   = "aws"   // AWS Secrets Manager, e.g. "aws:prod/db" or an ARN
+)
+
+// fetchTimeout limits how long fetching a secret waits for the store
+const fetchTimeout = 10 * time.Second
+
+// Credentials are the user and password read from a secret store. User is
+// empty when the secret only holds a password.
+type Credentials struct {
+	User     string
+	Password string
+}
+
+// Reference points to a secret: the store, the path or name of the secret in
+// the store and, optionally, the field of the secret holding the password
+type Reference struct {
+	Store string
+	Path  string
+	Field string
+}
+
+// ParseReference parses a reference of the form "store:path#field", e.g.
+// "vault:secret/data/db/prod#password" or "aws:prod/db". Without a field the
+// secret is expected to hold "username" and "password" fields, as the secrets
+// AWS rotates do, or, in AWS, to be the password itself.
+func ParseReference(ref string) (Reference, error) {
+	store, rest, ok := strings.Cut(ref, ":")
+	if !ok || rest == "" {
+		return Reference{}, fmt.Errorf("invalid secret reference '%s', expected store:path#field", ref)
+	}
+	path, field, _ := strings.Cut(rest, "#")
+	switch store {
+	case StoreVault, StoreAWS:
+		return Reference{Store: store, Path: path, Field: field}, nil
+	default:
+		return Reference{}, fmt.Errorf("unknown secret store '%s' in reference '%s', expected vault or aws", store, ref)
+	}
+}
+
+// Fetch reads the credentials a reference points to. Secrets are fetched
+// anew on every call, so a rotated password is picked up on the next one.
+func Fetch(ctx context.Context, ref string) (*Credentials, error) {
+	reference, err := ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	var values map[string]any
+	var raw string
+	switch reference.Store {
+	case StoreVault:
+		values, err = fetchVault(ctx, reference.Path)
+	case StoreAWS:
+		values, raw, err = fetchAWS(ctx, reference.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	field := func(name string) (string, bool) {
+		value, ok := values[name]
+		if !ok || value == nil {
+			return "", false
+		}
+		return fmt.Sprint(value), true
+	}
+	switch {
+	case reference.Field != "":
+		password, ok := field(reference.Field)
+		if !ok {
+			return nil, fmt.Errorf("secret %s has no field '%s'", ref, reference.Field)
+		}
+		return &Credentials{Password: password}, nil
+	case values != nil:
+		password, ok := field("password")
+		if !ok {
+			return nil, fmt.Errorf("secret %s has no password field; add #field to the reference", ref)
+		}
+		user, _ := field("username")
+		return &Credentials{User: user, Password: password}, nil
+	default:
+		return &Credentials{Password: raw}, nil
+	}
+}
+
+// fetchVault reads a secret from Vault at the address and with the token of the
+// Vault CLI: VAULT_ADDR, and VAULT_TOKEN or the token saved by "vault login".
+// KV version 2 paths include "data/", e.g. secret/data/db for the secret db.
+func fetchVault(ctx context.Context, path string) (map[string]any, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil, fmt.Errorf("VAULT_ADDR is not set")
+	}
+	token, err := vaultToken()
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimSuffix(addr, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Vault request: %v", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret %s from Vault: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret %s from Vault: %v", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error reading secret %s from Vault: %s", path, resp.Status)
+	}
+
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return nil, fmt.Errorf("error decoding secret %s from Vault: %v", path, err)
+	}
+
+	// KV version 2 nests the fields under data, next to the version metadata
+	if nested, ok := secret.Data["data"].(map[string]any); ok {
+		if _, ok := secret.Data["metadata"]; ok {
+			return nested, nil
+		}
+	}
+	return secret.Data, nil
+}
+
+// vaultToken returns the token from VAULT_TOKEN or the token file of the Vault CLI
+func vaultToken() (string, error) {
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("VAULT_TOKEN is not set")
+	}
+	token, err := os.ReadFile(filepath.Join(home, ".vault-token"))
+	if err != nil {
+		return "", fmt.Errorf("VAULT_TOKEN is not set and there is no token from vault login")
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// fetchAWS reads the current version of a secret from AWS Secrets Manager with
+// the default credentials and region of the AWS CLI. Secrets holding a JSON
+// object are returned as its fields, other secrets as the raw string.
+func fetchAWS(ctx context.Context, id string) (map[string]any, string, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("error loading AWS configuration: %v", err)
+	}
+	client := secretsmanager.NewFromConfig(cfg)
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(id)})
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading secret %s from AWS Secrets Manager: %v", id, err)
+	}
+	if out.SecretString == nil {
+		return nil, "", fmt.Errorf("secret %s is binary, expected a string", id)
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal([]byte(*out.SecretString), &values); err != nil {
+		return nil, *out.SecretString, nil
+	}
+	return values, "", nil
+}
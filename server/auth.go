@@ -0,0 +1,69 @@
+package server
+
+import (
+	"cmp"
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"slices"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// anonymous grants access to everything when authentication is disabled
+var anonymous = &t.APIToken{Name: "anonymous"}
+
+// authenticate returns the token presented by a request, or nil if it is missing or unknown
+func (s *Server) authenticate(r *http.Request) *t.APIToken {
+	if s.noAuth {
+		return anonymous
+	}
+
+	// Browsers send the token as the password of Basic authentication
+	secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		_, secret, _ = r.BasicAuth()
+	}
+	if secret == "" {
+		return nil
+	}
+
+	// Compare every token in constant time, so timing does not reveal valid prefixes
+	var match *t.APIToken
+	for i := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(s.tokens[i].Token), []byte(secret)) == 1 {
+			match = &s.tokens[i]
+		}
+	}
+	return match
+}
+
+// redactTable returns a copy of a table without the foreign keys referencing
+// tables the token may not read, so their names are not revealed
+func redactTable(table *t.Table, schema string, token *t.APIToken) *t.Table {
+	redacted := *table
+
+	redacted.Columns = slices.Clone(table.Columns)
+	for i, col := range redacted.Columns {
+		target, _, ok := col.ForeignKeyTarget()
+		if !ok {
+			continue
+		}
+		targetSchema := cmp.Or(col.ForeignKeySchema(), schema)
+		if !token.AllowsTable(targetSchema, target) {
+			redacted.Columns[i].ForeignKey = sql.NullString{}
+		}
+	}
+
+	redacted.Constraints = slices.DeleteFunc(slices.Clone(table.Constraints), func(con t.Constraint) bool {
+		if con.Type != t.ForeignKeyConstraint {
+			return false
+		}
+		// Drop the definitions that cannot be read, as they may name any table
+		targetSchema, target, ok := con.ReferencedTable()
+		return !ok || !token.AllowsTable(cmp.Or(targetSchema, schema), target)
+	})
+
+	return &redacted
+}
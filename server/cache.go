@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/carloberd/db-reader/cache"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// SetCache keeps the table lists and structures read from the database in a
+// cache for ttl. Keys are prefixed with the database, so that servers
+// sharing a Redis cache for different databases do not mix their metadata.
+func (s *Server) SetCache(c cache.Cache, ttl time.Duration, database string) {
+	s.cache = c
+	s.cacheTTL = ttl
+	s.cachePrefix = "db-reader:" + database + ":"
+}
+
+// tables lists the tables of a schema, from the cache if they are in it
+func (s *Server) tables(ctx context.Context, connector t.DatabaseConnector, schema string) ([]string, error) {
+	return cached(ctx, s, "tables:"+schema, func() ([]string, error) {
+		return timeQuery(ctx, "GetTables", schema, "", func() ([]string, error) {
+			return connector.GetTables(schema)
+		})
+	})
+}
+
+// tableStructure loads the structure of a table, from the cache if it is in it
+func (s *Server) tableStructure(ctx context.Context, connector t.DatabaseConnector, schema, name string) (*t.Table, error) {
+	return cached(ctx, s, "table:"+schema+"."+name, func() (*t.Table, error) {
+		return timeQuery(ctx, "GetTableStructure", schema, name, func() (*t.Table, error) {
+			return connector.GetTableStructure(schema, name)
+		})
+	})
+}
+
+// cached returns the value cached under a key, or loads and caches it. A
+// failing cache is logged and bypassed, so requests still read the database.
+func cached[V any](ctx context.Context, s *Server, key string, load func() (V, error)) (V, error) {
+	if s.cache == nil {
+		return load()
+	}
+	key = s.cachePrefix + key
+
+	var value V
+	data, ok, err := s.cache.Get(key)
+	if err != nil {
+		slog.WarnContext(ctx, "error reading the cache", "key", key, "error", err)
+	} else if ok {
+		err := json.Unmarshal(data, &value)
+		if err == nil {
+			return value, nil
+		}
+		slog.WarnContext(ctx, "ignoring unreadable cache entry", "key", key, "error", err)
+	}
+
+	value, err = load()
+	if err != nil {
+		return value, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		if err := s.cache.Set(key, data, s.cacheTTL); err != nil {
+			slog.WarnContext(ctx, "error writing the cache", "key", key, "error", err)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records the spans of requests and of the metadata reads they make,
+// exported once tracing is set up with tracing.Setup
+var tracer = otel.Tracer("github.com/carloberd/db-reader/server")
+
+// requestIDHeader carries the ID of a request, kept if a proxy in front of the
+// server already set it so the logs of both can be correlated
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds request IDs set by clients
+const maxRequestIDLength = 64
+
+// requestLogKey is the context key of the requestLog of a request
+type requestLogKey struct{}
+
+// requestLog collects what is logged once a request is served
+type requestLog struct {
+	id string
+
+	mu        sync.Mutex
+	token     string
+	queries   int
+	queryTime time.Duration
+}
+
+// requestLogFrom returns the requestLog of a request context, nil outside requests
+func requestLogFrom(ctx context.Context) *requestLog {
+	rl, _ := ctx.Value(requestLogKey{}).(*requestLog)
+	return rl
+}
+
+// LogHandler wraps a log handler to add the ID of the request being served to
+// the records logged with its context, e.g. by slog.ErrorContext
+func LogHandler(h slog.Handler) slog.Handler {
+	return requestIDHandler{h}
+}
+
+// requestIDHandler adds the request_id attribute to records logged during requests
+type requestIDHandler struct {
+	slog.Handler
+}
+
+// Handle adds the request ID of the context, if any, to the record
+func (h requestIDHandler) Handle(ctx context.Context, r slog.Record) error {
+	if rl := requestLogFrom(ctx); rl != nil {
+		r.AddAttrs(slog.String("request_id", rl.id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs keeps adding request IDs to the records of the derived handler
+func (h requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestIDHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup keeps adding request IDs to the records of the derived handler
+func (h requestIDHandler) WithGroup(name string) slog.Handler {
+	return requestIDHandler{h.Handler.WithGroup(name)}
+}
+
+// statusRecorder remembers the status code of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before writing it
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// withRequestLog gives every request an ID, returned in the X-Request-ID
+// header, and logs the request once served with its duration and the time
+// spent reading metadata from the database. The request is traced in a span,
+// child of the trace context of the request if it has one.
+func withRequestLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := r.Header.Get(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPRequestMethodKey.String(r.Method), semconv.URLPath(r.URL.Path)))
+		defer span.End()
+
+		rl := &requestLog{id: id}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		ctx = context.WithValue(ctx, requestLogKey{}, rl)
+		req := r.WithContext(ctx)
+		next.ServeHTTP(rec, req)
+
+		// The pattern is only known once the request is routed
+		if req.Pattern != "" {
+			span.SetName(req.Pattern)
+			span.SetAttributes(semconv.HTTPRoute(req.Pattern))
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(rec.status), attribute.String("request_id", id))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+
+		rl.mu.Lock()
+		defer rl.mu.Unlock()
+		attrs := []any{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"token", rl.token,
+			"duration_ms", milliseconds(time.Since(start)),
+			"queries", rl.queries,
+			"query_ms", milliseconds(rl.queryTime),
+		}
+		if sc := span.SpanContext(); sc.IsValid() {
+			attrs = append(attrs, "trace_id", sc.TraceID().String())
+		}
+		slog.InfoContext(ctx, "request", attrs...)
+	})
+}
+
+// newRequestID returns a random request ID
+func newRequestID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// timeQuery runs a metadata read on the database, such as GetTables, in a
+// span, logging how long it took and adding it to the timings of the request
+func timeQuery[V any](ctx context.Context, operation, schema, table string, run func() (V, error)) (V, error) {
+	attrs := []attribute.KeyValue{semconv.DBOperationName(operation), semconv.DBNamespace(schema)}
+	if table != "" {
+		attrs = append(attrs, semconv.DBCollectionName(table))
+	}
+	_, span := tracer.Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	start := time.Now()
+	value, err := run()
+	elapsed := time.Since(start)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+
+	slog.DebugContext(ctx, "query", "operation", operation, "schema", schema, "table", table,
+		"duration_ms", milliseconds(elapsed))
+	if rl := requestLogFrom(ctx); rl != nil {
+		rl.mu.Lock()
+		rl.queries++
+		rl.queryTime += elapsed
+		rl.mu.Unlock()
+	}
+	return value, err
+}
+
+// setToken records the name of the token a request was authenticated with
+func setToken(ctx context.Context, name string) {
+	if rl := requestLogFrom(ctx); rl != nil {
+		rl.mu.Lock()
+		rl.token = name
+		rl.mu.Unlock()
+	}
+}
+
+// milliseconds returns a duration in milliseconds, with microsecond precision
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/carloberd/db-reader/export"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// pageWorkers is the number of tables loaded at the same time for the schema page
+const pageWorkers = 4
+
+// SchemaURL returns the path of the page listing the tables of a schema
+func SchemaURL(schema string) string {
+	return "/schema/" + url.PathEscape(schema)
+}
+
+// TableURL returns the path of the page of a table. Sections are addressed by
+// fragment: #columns, #indexes, #constraints and #related.
+func TableURL(schema, table string) string {
+	return SchemaURL(schema) + "/table/" + url.PathEscape(table)
+}
+
+// pageLinks returns the links between the pages of a schema, to the tables a token may read
+func pageLinks(schema string, names []string) export.HTMLLinks {
+	return export.HTMLLinks{
+		Index: SchemaURL(schema),
+		Style: "/style.css",
+		Table: func(name string) string {
+			if !slices.Contains(names, name) {
+				return ""
+			}
+			return TableURL(schema, name)
+		},
+	}
+}
+
+// allowedTables lists the tables of a schema that a token may read
+func (s *Server) allowedTables(ctx context.Context, connector t.DatabaseConnector, schema string, token *t.APIToken) ([]string, error) {
+	names, err := s.tables(ctx, connector, schema)
+	if err != nil {
+		return nil, err
+	}
+	var allowed []string
+	for _, name := range names {
+		if token.AllowsTable(schema, name) {
+			allowed = append(allowed, name)
+		}
+	}
+	return allowed, nil
+}
+
+// handleSchemaPage shows the tables of a schema as the index of the HTML report
+func (s *Server) handleSchemaPage(w http.ResponseWriter, r *http.Request, token *t.APIToken) {
+	schema := r.PathValue("schema")
+	if !token.AllowsSchema(schema) {
+		writeError(w, http.StatusForbidden, fmt.Sprintf("access to schema '%s' is not allowed", schema))
+		return
+	}
+
+	connector := s.currentConnector()
+	names, err := s.allowedTables(r.Context(), connector, schema, token)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error listing tables", "schema", schema, "error", err)
+		writeError(w, http.StatusInternalServerError, "error listing tables")
+		return
+	}
+	tables, err := export.LoadTables(func(name string) (*t.Table, error) {
+		table, err := s.tableStructure(r.Context(), connector, schema, name)
+		if err != nil {
+			return nil, err
+		}
+		return redactTable(table, schema, token), nil
+	}, names, pageWorkers)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error loading tables", "schema", schema, "error", err)
+		writeError(w, http.StatusInternalServerError, "error loading tables")
+		return
+	}
+
+	var page bytes.Buffer
+	if err := export.WriteHTMLIndex(&page, schema, tables, pageLinks(schema, names)); err != nil {
+		slog.ErrorContext(r.Context(), "error rendering page", "path", r.URL.Path, "error", err)
+		writeError(w, http.StatusInternalServerError, "error rendering page")
+		return
+	}
+	writeHTML(w, page.Bytes())
+}
+
+// handleTablePage shows a table as its page of the HTML report
+func (s *Server) handleTablePage(w http.ResponseWriter, r *http.Request, token *t.APIToken) {
+	schema, name := r.PathValue("schema"), r.PathValue("table")
+
+	// Forbidden tables are reported as missing, so their existence is not revealed
+	if !token.AllowsTable(schema, name) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("table '%s.%s' not found", schema, name))
+		return
+	}
+
+	connector := s.currentConnector()
+	names, err := s.allowedTables(r.Context(), connector, schema, token)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error listing tables", "schema", schema, "error", err)
+		writeError(w, http.StatusInternalServerError, "error listing tables")
+		return
+	}
+	if !slices.Contains(names, name) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("table '%s.%s' not found", schema, name))
+		return
+	}
+
+	table, err := s.tableStructure(r.Context(), connector, schema, name)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error loading table", "schema", schema, "table", name, "error", err)
+		writeError(w, http.StatusInternalServerError, "error loading table")
+		return
+	}
+
+	var page bytes.Buffer
+	if err := export.WriteHTMLTable(&page, schema, redactTable(table, schema, token), names, pageLinks(schema, names)); err != nil {
+		slog.ErrorContext(r.Context(), "error rendering page", "path", r.URL.Path, "error", err)
+		writeError(w, http.StatusInternalServerError, "error rendering page")
+		return
+	}
+	writeHTML(w, page.Bytes())
+}
+
+// handleStyle serves the style sheet of the pages, which holds nothing secret
+func handleStyle(w http.ResponseWriter, r *http.Request) {
+	style, err := export.HTMLStyle()
+	if err != nil {
+		slog.Error("error serving style sheet", "error", err)
+		http.Error(w, "error reading style sheet", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Write(style)
+}
+
+// writeHTML writes an HTML page response
+func writeHTML(w http.ResponseWriter, page []byte) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write(page); err != nil {
+		slog.Error("error writing response", "error", err)
+	}
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/cache"
+	"github.com/carloberd/db-reader/export"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Server exposes read-only schema metadata over a REST API
+type Server struct {
+	mu        sync.RWMutex // Guards connector, which is replaced when credentials rotate
+	connector t.DatabaseConnector
+	tokens    []t.APIToken
+	noAuth    bool
+	audit     *audit.Logger
+	database  string // Connection description for audit events
+
+	cache       cache.Cache // Table lists and structures, nil to always read the database
+	cacheTTL    time.Duration
+	cachePrefix string
+}
+
+// New creates a server reading metadata through a connected connector. Requests must
+// present one of the tokens, unless noAuth is set.
+func New(connector t.DatabaseConnector, tokens []t.APIToken, noAuth bool) *Server {
+	return &Server{
+		connector: connector,
+		tokens:    tokens,
+		noAuth:    noAuth,
+	}
+}
+
+// SetConnector replaces the connector requests read through, e.g. with one
+// connected with rotated credentials, and returns the previous one to close
+func (s *Server) SetConnector(connector t.DatabaseConnector) t.DatabaseConnector {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := s.connector
+	s.connector = connector
+	return previous
+}
+
+// currentConnector returns the connector requests read through
+func (s *Server) currentConnector() t.DatabaseConnector {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.connector
+}
+
+// SetAuditLog records every API request in the audit log, by token name
+func (s *Server) SetAuditLog(logger *audit.Logger, database string) {
+	s.audit = logger
+	s.database = database
+}
+
+// Handler returns the HTTP handler of the API, which logs every request
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/schemas/{schema}/tables", s.withToken(s.handleTables))
+	mux.HandleFunc("GET /api/schemas/{schema}/tables/{table}", s.withToken(s.handleTable))
+	mux.HandleFunc("GET /schema/{schema}", s.withBrowserToken(s.handleSchemaPage))
+	mux.HandleFunc("GET /schema/{schema}/table/{table}", s.withBrowserToken(s.handleTablePage))
+	mux.HandleFunc("GET /style.css", handleStyle)
+	return withRequestLog(mux)
+}
+
+// tokenHandler is a handler for authenticated requests
+type tokenHandler func(w http.ResponseWriter, r *http.Request, token *t.APIToken)
+
+// withToken rejects requests without a valid token
+func (s *Server) withToken(h tokenHandler) http.HandlerFunc {
+	return s.withChallenge(`Bearer realm="db-reader"`, h)
+}
+
+// withBrowserToken rejects requests without a valid token like withToken, but
+// has browsers ask for the token as the password of a login prompt, so links
+// to the pages can be shared without the token
+func (s *Server) withBrowserToken(h tokenHandler) http.HandlerFunc {
+	return s.withChallenge(`Basic realm="db-reader", charset="UTF-8"`, h)
+}
+
+// withChallenge rejects requests without a valid token, asking for one with
+// the given WWW-Authenticate challenge
+func (s *Server) withChallenge(challenge string, h tokenHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := s.authenticate(r)
+		if token == nil {
+			w.Header().Set("WWW-Authenticate", challenge)
+			writeError(w, http.StatusUnauthorized, "missing or invalid API token")
+			return
+		}
+		setToken(r.Context(), token.Name)
+		s.audit.LogAs(token.Name, s.database, audit.ActionAPI, r.Method+" "+r.URL.Path)
+		h(w, r, token)
+	}
+}
+
+// handleTables lists the tables of a schema that the token may read
+func (s *Server) handleTables(w http.ResponseWriter, r *http.Request, token *t.APIToken) {
+	schema := r.PathValue("schema")
+	if !token.AllowsSchema(schema) {
+		writeError(w, http.StatusForbidden, fmt.Sprintf("access to schema '%s' is not allowed", schema))
+		return
+	}
+
+	names, err := s.tables(r.Context(), s.currentConnector(), schema)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error listing tables", "schema", schema, "error", err)
+		writeError(w, http.StatusInternalServerError, "error listing tables")
+		return
+	}
+
+	tables := []string{}
+	for _, name := range names {
+		if token.AllowsTable(schema, name) {
+			tables = append(tables, name)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, tables)
+}
+
+// handleTable returns the structure of a table
+func (s *Server) handleTable(w http.ResponseWriter, r *http.Request, token *t.APIToken) {
+	schema, name := r.PathValue("schema"), r.PathValue("table")
+
+	// Forbidden tables are reported as missing, so their existence is not revealed
+	if !token.AllowsTable(schema, name) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("table '%s.%s' not found", schema, name))
+		return
+	}
+
+	connector := s.currentConnector()
+	names, err := s.tables(r.Context(), connector, schema)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error listing tables", "schema", schema, "error", err)
+		writeError(w, http.StatusInternalServerError, "error listing tables")
+		return
+	}
+	if !slices.Contains(names, name) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("table '%s.%s' not found", schema, name))
+		return
+	}
+
+	table, err := s.tableStructure(r.Context(), connector, schema, name)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error loading table", "schema", schema, "table", name, "error", err)
+		writeError(w, http.StatusInternalServerError, "error loading table")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, export.NewTableDocument(redactTable(table, schema, token)))
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("error writing response", "error", err)
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
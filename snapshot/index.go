@@ -0,0 +1,131 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/carloberd/db-reader/diff"
+	"github.com/carloberd/db-reader/export"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// SnapshotConnector implements the DatabaseConnector interface over a schema
+// snapshot saved by the snapshot command or the scheduled snapshots of the GUI,
+// so the schema can be browsed as it was when the snapshot was taken
+type SnapshotConnector struct {
+	schema *diff.Schema
+}
+
+// Connect reads the snapshot file named by the database
+func (sc *SnapshotConnector) Connect(params t.ConnectionParams) error {
+	schema, err := diff.ReadSnapshot(params.Database)
+	if err != nil {
+		return err
+	}
+
+	sc.schema = schema
+	return nil
+}
+
+// Disconnect releases the snapshot
+func (sc *SnapshotConnector) Disconnect() error {
+	sc.schema = nil
+	return nil
+}
+
+// Taken returns when the snapshot was taken
+func (sc *SnapshotConnector) Taken() time.Time {
+	if sc.schema == nil {
+		return time.Time{}
+	}
+	return sc.schema.Taken
+}
+
+// GetSchemas returns the schemas of the tables in the snapshot
+func (sc *SnapshotConnector) GetSchemas() ([]string, error) {
+	if sc.schema == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	seen := make(map[string]bool)
+	var schemas []string
+	for _, table := range sc.schema.Tables {
+		if !seen[table.Schema] {
+			seen[table.Schema] = true
+			schemas = append(schemas, table.Schema)
+		}
+	}
+	sort.Strings(schemas)
+	return schemas, nil
+}
+
+// GetTables returns a list of tables in the specified schema
+func (sc *SnapshotConnector) GetTables(schema string) ([]string, error) {
+	if sc.schema == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var tables []string
+	for _, table := range sc.schema.Tables {
+		if table.Schema == schema {
+			tables = append(tables, table.Name)
+		}
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// GetTableStructure returns a copy of a table of the snapshot, which callers may modify
+func (sc *SnapshotConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if sc.schema == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	for _, table := range sc.schema.Tables {
+		if table.Schema == schema && table.Name == tableName {
+			// A round trip through the snapshot representation copies every slice
+			doc := export.NewTableDocument(table)
+			return doc.Table(), nil
+		}
+	}
+	return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+}
+
+// GetTableSizes returns the sizes of the tables of a schema recorded in the
+// snapshot, none if the snapshot was taken without them
+func (sc *SnapshotConnector) GetTableSizes(schema string) ([]t.TableSize, error) {
+	if sc.schema == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var sizes []t.TableSize
+	for _, stats := range sc.schema.Stats {
+		if stats.Schema == schema {
+			sizes = append(sizes, t.TableSize{Name: stats.Table, Bytes: stats.Bytes, Rows: stats.Rows})
+		}
+	}
+	return sizes, nil
+}
+
+// NewSnapshotConnector creates a connector for schema snapshot files
+func NewSnapshotConnector() t.DatabaseConnector {
+	return &SnapshotConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverSnapshot, NewSnapshotConnector, t.ConnectorInfo{
+		// Snapshots record the schema of every table, so there is nothing to default
+		Title:      "Schema snapshot file",
+		Fields:     []string{t.FieldDatabase, t.FieldSchema},
+		Hint:       "Snapshot .json file",
+		File:       true,
+		Extensions: []string{".json"},
+	})
+}
+
+// GetViews is not supported, as snapshots do not record views
+func (sc *SnapshotConnector) GetViews(schema string) ([]t.View, error) {
+	return nil, t.ErrViewsNotSupported
+}
@@ -0,0 +1,412 @@
+package snowflake
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	sf "github.com/snowflakedb/gosnowflake"
+)
+
+// SnowflakeConnector implements the DatabaseConnector interface for Snowflake.
+// Catalog queries read the INFORMATION_SCHEMA of the connected database, and
+// keys, which it does not describe, come from SHOW statements.
+type SnowflakeConnector struct {
+	sqlutil.QueryLog
+
+	db       *sql.DB
+	database string // Database connected to, which qualifies the catalog views
+}
+
+// Connect establishes a connection to a Snowflake account. The host is only
+// needed for private links and other non-default endpoints; session settings are
+// passed as Snowflake session parameters, e.g. QUERY_TAG=db-reader.
+func (sc *SnowflakeConnector) Connect(params t.ConnectionParams) error {
+	if params.Account == "" {
+		return fmt.Errorf("a Snowflake account identifier is required")
+	}
+
+	cfg := &sf.Config{
+		Account:   params.Account,
+		User:      params.User,
+		Password:  params.Password,
+		Database:  params.Database,
+		Schema:    params.Schema,
+		Warehouse: params.Warehouse,
+		Role:      params.Role,
+		Host:      params.Host,
+		Params:    make(map[string]*string, len(params.Settings)),
+	}
+	if params.Port != "" {
+		port, err := strconv.Atoi(params.Port)
+		if err != nil {
+			return fmt.Errorf("invalid port '%s'", params.Port)
+		}
+		cfg.Port = port
+	}
+	for key, value := range params.Settings {
+		cfg.Params[key] = &value
+	}
+
+	sc.db = sql.OpenDB(sf.NewConnector(sf.SnowflakeDriver{}, *cfg))
+
+	// Test the connection
+	if err := sc.db.Ping(); err != nil {
+		sc.db.Close()
+		sc.db = nil
+		return fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	sc.database = params.Database
+	return nil
+}
+
+// Disconnect closes the database connection
+func (sc *SnowflakeConnector) Disconnect() error {
+	if sc.db != nil {
+		err := sc.db.Close()
+		sc.db = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// catalog returns the qualified name of an INFORMATION_SCHEMA view of the connected database
+func (sc *SnowflakeConnector) catalog(view string) string {
+	return quoteIdentifier(sc.database) + ".INFORMATION_SCHEMA." + view
+}
+
+// GetSchemas returns the schemas of the connected database
+func (sc *SnowflakeConnector) GetSchemas() ([]string, error) {
+	if sc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := sc.Query(sc.db, `
+		SELECT schema_name
+		FROM `+sc.catalog("SCHEMATA")+`
+		WHERE schema_name <> 'INFORMATION_SCHEMA'
+		ORDER BY schema_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying schemas: %v", err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			return nil, fmt.Errorf("error scanning schema results: %v", err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, rows.Err()
+}
+
+// GetTables returns a list of tables in the specified schema
+func (sc *SnowflakeConnector) GetTables(schema string) ([]string, error) {
+	if sc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			table_name
+		FROM
+			` + sc.catalog("TABLES") + `
+		WHERE
+			table_schema = ?
+			AND table_type = 'BASE TABLE'
+		ORDER BY
+			table_name
+	`
+
+	rows, err := sc.Query(sc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	return tables, rows.Err()
+}
+
+// GetTableStructure returns the structure of the specified table. The clustering
+// key and storage options are reported as table properties.
+func (sc *SnowflakeConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if sc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// Check if the table exists, reading its comment and clustering key at the same time
+	rows, err := sc.Query(sc.db, `
+		SELECT comment, clustering_key, is_transient, retention_time
+		FROM `+sc.catalog("TABLES")+`
+		WHERE table_schema = ? AND table_name = ?
+	`, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	var comment, clusteringKey, transient sql.NullString
+	var retention sql.NullInt64
+	exists := rows.Next()
+	if exists {
+		err = rows.Scan(&comment, &clusteringKey, &transient, &retention)
+	}
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	table := &t.Table{Name: tableName, Schema: schema, Comment: comment.String}
+	if clusteringKey.Valid {
+		table.Properties = append(table.Properties, t.Property{Name: "Clustering key", Value: clusteringKey.String})
+	}
+	if transient.String == "YES" {
+		table.Properties = append(table.Properties, t.Property{Name: "Transient", Value: "yes"})
+	}
+	if retention.Valid {
+		table.Properties = append(table.Properties, t.Property{Name: "Time travel retention", Value: fmt.Sprintf("%d days", retention.Int64)})
+	}
+
+	if table.Constraints, err = sc.getConstraints(schema, tableName); err != nil {
+		return nil, err
+	}
+	if table.Columns, err = sc.getColumns(schema, tableName, table.Constraints); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// getColumns returns the columns of a table, with the primary and foreign keys
+// taken from its constraints. Snowflake declares but does not enforce them.
+func (sc *SnowflakeConnector) getColumns(schema, tableName string, constraints []t.Constraint) ([]t.Column, error) {
+	query := `
+		SELECT
+			column_name,
+			data_type,
+			character_maximum_length,
+			numeric_precision,
+			numeric_scale,
+			is_nullable,
+			column_default,
+			comment
+		FROM
+			` + sc.catalog("COLUMNS") + `
+		WHERE
+			table_schema = ? AND table_name = ?
+		ORDER BY
+			ordinal_position
+	`
+
+	rows, err := sc.Query(sc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []t.Column
+	for rows.Next() {
+		var col t.Column
+		var dataType, isNullable string
+		var length, precision, scale sql.NullInt64
+		var comment sql.NullString
+
+		err := rows.Scan(&col.Name, &dataType, &length, &precision, &scale, &isNullable, &col.DefaultValue, &comment)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+
+		col.Type = formatDataType(dataType, length, precision, scale)
+		col.Nullable = isNullable == "YES"
+		col.Comment = comment.String
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range columns {
+		col := &columns[i]
+		for _, con := range constraints {
+			pos := indexOf(con.Columns, col.Name)
+			if pos < 0 {
+				continue
+			}
+			switch con.Type {
+			case t.PrimaryKeyConstraint:
+				col.IsPrimaryKey = true
+			case t.ForeignKeyConstraint:
+				if ref, ok := foreignKeyRef(con.Definition, pos); ok && !col.ForeignKey.Valid {
+					col.ForeignKey = sql.NullString{String: ref, Valid: true}
+				}
+			}
+		}
+	}
+
+	return columns, nil
+}
+
+// formatDataType adds the length, or precision and scale, to the types that have them
+func formatDataType(dataType string, length, precision, scale sql.NullInt64) string {
+	switch dataType {
+	case "TEXT", "BINARY":
+		if length.Valid {
+			return fmt.Sprintf("%s(%d)", dataType, length.Int64)
+		}
+	case "NUMBER":
+		if precision.Valid && scale.Valid {
+			return fmt.Sprintf("NUMBER(%d,%d)", precision.Int64, scale.Int64)
+		}
+	}
+	return dataType
+}
+
+// foreignKeyRef returns the "table (column)" reference of a key column from a
+// definition such as "FOREIGN KEY (A, B) REFERENCES T(X, Y)"
+func foreignKeyRef(definition string, pos int) (string, bool) {
+	_, target, ok := strings.Cut(definition, " REFERENCES ")
+	if !ok {
+		return "", false
+	}
+	open := strings.Index(target, "(")
+	if open < 0 || !strings.HasSuffix(target, ")") {
+		return "", false
+	}
+	refColumns := strings.Split(target[open+1:len(target)-1], ",")
+	if pos >= len(refColumns) {
+		return "", false
+	}
+	return fmt.Sprintf("%s (%s)", target[:open], strings.TrimSpace(refColumns[pos])), true
+}
+
+// indexOf returns the position of a name in a list, or -1
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// showKeyKinds maps the SHOW statements listing keys to the constraint kinds they return
+var showKeyKinds = []struct {
+	stmt, kind string
+}{
+	{"SHOW PRIMARY KEYS IN TABLE ", t.PrimaryKeyConstraint},
+	{"SHOW UNIQUE KEYS IN TABLE ", t.UniqueConstraint},
+	{"SHOW IMPORTED KEYS IN TABLE ", t.ForeignKeyConstraint},
+}
+
+// getConstraints returns the primary, unique and foreign keys of a table
+func (sc *SnowflakeConnector) getConstraints(schema, tableName string) ([]t.Constraint, error) {
+	qualified := quoteIdentifier(sc.database) + "." + quoteQualified(schema, tableName)
+
+	var constraints []t.Constraint
+	for _, show := range showKeyKinds {
+		rows, err := sc.showRows(show.stmt + qualified)
+		if err != nil {
+			return nil, fmt.Errorf("error querying constraints: %v", err)
+		}
+
+		// Rows list one column each, put in key order within each constraint
+		sort.SliceStable(rows, func(i, j int) bool {
+			a, _ := strconv.Atoi(rows[i]["key_sequence"])
+			b, _ := strconv.Atoi(rows[j]["key_sequence"])
+			return a < b
+		})
+		byName := make(map[string]*keyConstraint)
+		var order []string
+		for _, row := range rows {
+			name, column := row["constraint_name"], row["column_name"]
+			if show.kind == t.ForeignKeyConstraint {
+				name, column = row["fk_name"], row["fk_column_name"]
+			}
+			key, ok := byName[name]
+			if !ok {
+				key = &keyConstraint{Constraint: t.Constraint{Name: name, Type: show.kind}}
+				if show.kind == t.ForeignKeyConstraint {
+					key.target = row["pk_table_name"]
+					if row["pk_schema_name"] != schema {
+						key.target = row["pk_schema_name"] + "." + key.target
+					}
+				}
+				byName[name] = key
+				order = append(order, name)
+			}
+			key.Columns = append(key.Columns, column)
+			key.refColumns = append(key.refColumns, row["pk_column_name"])
+		}
+
+		for _, name := range order {
+			constraints = append(constraints, byName[name].definition())
+		}
+	}
+
+	return constraints, nil
+}
+
+// keyConstraint collects the columns of a key listed by a SHOW statement
+type keyConstraint struct {
+	t.Constraint
+	target     string   // Referenced table of a foreign key
+	refColumns []string // Referenced columns of a foreign key
+}
+
+// definition returns the constraint with its SQL definition
+func (k *keyConstraint) definition() t.Constraint {
+	con := k.Constraint
+	switch con.Type {
+	case t.ForeignKeyConstraint:
+		con.Definition = fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)",
+			strings.Join(con.Columns, ", "), k.target, strings.Join(k.refColumns, ", "))
+	default:
+		con.Definition = fmt.Sprintf("%s (%s)", con.Type, strings.Join(con.Columns, ", "))
+	}
+	return con
+}
+
+// NewSnowflakeConnector creates a connector for Snowflake accounts
+func NewSnowflakeConnector() t.DatabaseConnector {
+	return &SnowflakeConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverSnowflake, NewSnowflakeConnector, t.ConnectorInfo{
+		Title:  "Snowflake",
+		Fields: append(slices.Clone(t.ServerFields), t.FieldAccount, t.FieldWarehouse, t.FieldRole),
+		Defaults: func(p *t.ConnectionParams) {
+			// The account gives the host, which is only needed for private links
+			if p.Host == "" && p.Account != "" {
+				p.Host = p.Account + ".snowflakecomputing.com"
+			}
+			p.Port = cmp.Or(p.Port, "443")
+			// Unquoted names are stored in upper case
+			p.Schema = cmp.Or(p.Schema, "PUBLIC")
+		},
+	})
+}
@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// quoteIdentifier quotes a Snowflake identifier. Quoted names are case sensitive,
+// which matches the upper case names stored in the catalog.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteQualified returns a quoted schema-qualified name for use in queries
+func quoteQualified(schema, name string) string {
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// EstimateRowCount returns the row count Snowflake keeps in the table metadata
+func (sc *SnowflakeConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if sc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := sc.Query(sc.db, `
+		SELECT row_count
+		FROM `+sc.catalog("TABLES")+`
+		WHERE table_schema = ? AND table_name = ?
+	`, schema, tableName)
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	var estimate sql.NullInt64
+	if err := rows.Scan(&estimate); err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	if !estimate.Valid {
+		return -1, nil
+	}
+	return estimate.Int64, nil
+}
+
+// CountRows returns the exact number of rows in a table. Snowflake answers it
+// from metadata, so it does not need a running warehouse.
+func (sc *SnowflakeConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if sc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := "SELECT COUNT(*) FROM " + quoteIdentifier(sc.database) + "." + quoteQualified(schema, tableName)
+	sc.LogQuery(query, nil)
+
+	var count int64
+	if err := sc.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
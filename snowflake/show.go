@@ -0,0 +1,41 @@
+package snowflake
+
+import (
+	"database/sql"
+)
+
+// showRows runs a SHOW statement and returns its rows as values by column name
+func (sc *SnowflakeConnector) showRows(stmt string) ([]map[string]string, error) {
+	rows, err := sc.Query(sc.db, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(names))
+		dest := make([]any, len(names))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]string, len(names))
+		for i, name := range names {
+			if values[i].Valid {
+				row[name] = values[i].String
+			}
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
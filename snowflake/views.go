@@ -0,0 +1,64 @@
+package snowflake
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViews returns the views and materialized views of the schema, with their
+// columns and SELECT statements
+func (sc *SnowflakeConnector) GetViews(schema string) ([]t.View, error) {
+	if sc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			t.table_name,
+			t.table_type = 'MATERIALIZED VIEW',
+			v.view_definition,
+			t.comment
+		FROM
+			` + sc.catalog("TABLES") + ` t
+		LEFT JOIN
+			` + sc.catalog("VIEWS") + ` v ON v.table_schema = t.table_schema AND v.table_name = t.table_name
+		WHERE
+			t.table_schema = ?
+			AND t.table_type IN ('VIEW', 'MATERIALIZED VIEW')
+		ORDER BY
+			t.table_name
+	`
+
+	rows, err := sc.Query(sc.db, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+	defer rows.Close()
+
+	var views []t.View
+	for rows.Next() {
+		view := t.View{Schema: schema}
+		var definition, comment sql.NullString
+		if err := rows.Scan(&view.Name, &view.Materialized, &definition, &comment); err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		// Snowflake keeps the CREATE VIEW statement
+		view.Definition = sqlutil.ViewQuery(definition.String)
+		view.Comment = comment.String
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading views: %v", err)
+	}
+	rows.Close()
+
+	for i := range views {
+		if views[i].Columns, err = sc.getColumns(schema, views[i].Name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetTableDDL returns the statements that created a table and its indexes, as
+// SQLite keeps them in sqlite_master. Indexes created by constraints have none.
+func (sc *SQLiteConnector) GetTableDDL(schema, tableName string) (string, error) {
+	if sc.db == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			sql
+		FROM
+			` + quoteIdentifier(schemaName(schema)) + `.sqlite_master
+		WHERE
+			tbl_name = ?
+			AND type IN ('table', 'index')
+			AND sql IS NOT NULL
+		ORDER BY
+			type = 'index', name
+	`
+	rows, err := sc.Query(sc.db, query, tableName)
+	if err != nil {
+		return "", fmt.Errorf("error querying table definition: %v", err)
+	}
+	defer rows.Close()
+
+	var statements []string
+	for rows.Next() {
+		var statement string
+		if err := rows.Scan(&statement); err != nil {
+			return "", fmt.Errorf("error scanning table definition: %v", err)
+		}
+		statements = append(statements, statement+";")
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("error reading table definition: %v", err)
+	}
+	if len(statements) == 0 {
+		return "", fmt.Errorf("table '%s.%s' does not exist", schemaName(schema), tableName)
+	}
+
+	return strings.Join(statements, "\n\n") + "\n", nil
+}
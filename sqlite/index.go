@@ -0,0 +1,471 @@
+package sqlite
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	_ "modernc.org/sqlite" // SQLite driver
+)
+
+// defaultSchema is the name of the main database of an SQLite connection
+const defaultSchema = "main"
+
+// SQLiteConnector implements the DatabaseConnector interface for SQLite database
+// files. Schemas are the names of attached databases, "main" by default.
+type SQLiteConnector struct {
+	sqlutil.QueryLog
+
+	db *sql.DB
+}
+
+// Connect opens the database file given as the database name, read-only
+func (sc *SQLiteConnector) Connect(params t.ConnectionParams) error {
+	// Opening a missing file would silently create an empty database
+	if _, err := os.Stat(params.Database); err != nil {
+		return fmt.Errorf("failed to open database file: %v", err)
+	}
+
+	dsn := (&url.URL{Scheme: "file", Path: params.Database, RawQuery: "mode=ro"}).String()
+
+	var err error
+	sc.db, err = sql.Open("sqlite", dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open database file: %v", err)
+	}
+
+	// Test the connection; reading the schema fails on files that are not databases
+	if _, err := sc.db.Exec("SELECT count(*) FROM sqlite_master"); err != nil {
+		sc.db.Close()
+		sc.db = nil
+		return fmt.Errorf("failed to read database file: %v", err)
+	}
+
+	return nil
+}
+
+// Disconnect closes the database file
+func (sc *SQLiteConnector) Disconnect() error {
+	if sc.db != nil {
+		err := sc.db.Close()
+		sc.db = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// quoteIdentifier quotes an SQLite identifier
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// schemaName returns the attached database to inspect, defaulting to the main one
+func schemaName(schema string) string {
+	if schema == "" {
+		return defaultSchema
+	}
+	return schema
+}
+
+// GetTables returns a list of tables in the specified attached database
+func (sc *SQLiteConnector) GetTables(schema string) ([]string, error) {
+	if sc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			name
+		FROM
+			` + quoteIdentifier(schemaName(schema)) + `.sqlite_master
+		WHERE
+			type = 'table'
+			AND name NOT LIKE 'sqlite\_%' ESCAPE '\'
+		ORDER BY
+			name
+	`
+
+	rows, err := sc.Query(sc.db, query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	return tables, rows.Err()
+}
+
+// GetTableStructure returns the structure of the specified table
+func (sc *SQLiteConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if sc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = schemaName(schema)
+
+	table := &t.Table{Name: tableName, Schema: schema}
+
+	var err error
+	if table.Columns, err = sc.getColumns(schema, tableName); err != nil {
+		return nil, err
+	}
+	if len(table.Columns) == 0 {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+	if table.Indexes, err = sc.getIndexes(schema, tableName); err != nil {
+		return nil, err
+	}
+	if table.Constraints, err = sc.getConstraints(schema, table); err != nil {
+		return nil, err
+	}
+	if table.Triggers, err = sc.getTriggers(schema, tableName); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// getColumns returns the columns of a table with their foreign key references
+func (sc *SQLiteConnector) getColumns(schema, tableName string) ([]t.Column, error) {
+	references, err := sc.getForeignKeys(schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?, ?) ORDER BY cid`
+	rows, err := sc.Query(sc.db, query, tableName, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []t.Column
+	for rows.Next() {
+		var col t.Column
+		var notNull bool
+		var pk int
+
+		if err := rows.Scan(&col.Name, &col.Type, &notNull, &col.DefaultValue, &pk); err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+
+		col.Nullable = !notNull
+		col.IsPrimaryKey = pk > 0
+		for _, fk := range references {
+			if slices.Contains(fk.columns, col.Name) {
+				col.ForeignKey = sql.NullString{String: fmt.Sprintf("%s (%s)", fk.table, fk.columnRef(col.Name)), Valid: true}
+				break
+			}
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, rows.Err()
+}
+
+// foreignKey is a foreign key as listed by pragma_foreign_key_list
+type foreignKey struct {
+	id         int
+	table      string
+	columns    []string
+	refColumns []string // Empty entries refer to the primary key of the referenced table
+}
+
+// columnRef returns the referenced column for one of the key columns
+func (fk foreignKey) columnRef(column string) string {
+	for i, c := range fk.columns {
+		if c == column && fk.refColumns[i] != "" {
+			return fk.refColumns[i]
+		}
+	}
+	return "rowid"
+}
+
+// getForeignKeys returns the foreign keys of a table, in declaration order
+func (sc *SQLiteConnector) getForeignKeys(schema, tableName string) ([]foreignKey, error) {
+	query := `SELECT id, "table", "from", "to" FROM pragma_foreign_key_list(?, ?) ORDER BY id, seq`
+	rows, err := sc.Query(sc.db, query, tableName, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying foreign keys: %v", err)
+	}
+	defer rows.Close()
+
+	var keys []foreignKey
+	for rows.Next() {
+		var id int
+		var refTable, column string
+		var refColumn sql.NullString
+
+		if err := rows.Scan(&id, &refTable, &column, &refColumn); err != nil {
+			return nil, fmt.Errorf("error scanning foreign key results: %v", err)
+		}
+
+		if n := len(keys); n == 0 || keys[n-1].id != id {
+			keys = append(keys, foreignKey{id: id, table: refTable})
+		}
+		fk := &keys[len(keys)-1]
+		fk.columns = append(fk.columns, column)
+		fk.refColumns = append(fk.refColumns, refColumn.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	// Keys declared without referenced columns refer to the primary key
+	for i, fk := range keys {
+		if fk.refColumns[0] != "" {
+			continue
+		}
+		pkColumns, err := sc.primaryKeyColumns(schema, fk.table)
+		if err != nil {
+			return nil, err
+		}
+		if len(pkColumns) == len(fk.columns) {
+			keys[i].refColumns = pkColumns
+		}
+	}
+
+	return keys, nil
+}
+
+// primaryKeyColumns returns the primary key columns of a table in key order
+func (sc *SQLiteConnector) primaryKeyColumns(schema, tableName string) ([]string, error) {
+	rows, err := sc.Query(sc.db, `SELECT name FROM pragma_table_info(?, ?) WHERE pk > 0 ORDER BY pk`, tableName, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying primary key: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("error scanning primary key results: %v", err)
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
+
+// getIndexes returns the indexes of a table, with columns in index key order
+func (sc *SQLiteConnector) getIndexes(schema, tableName string) ([]t.Index, error) {
+	query := `
+		SELECT
+			il.name,
+			il."unique",
+			il.origin = 'pk',
+			ii.name,
+			COALESCE(m.sql, '')
+		FROM
+			pragma_index_list(?, ?) il
+		JOIN
+			pragma_index_info(il.name, ?) ii
+		LEFT JOIN
+			` + quoteIdentifier(schema) + `.sqlite_master m ON m.type = 'index' AND m.name = il.name
+		ORDER BY
+			il.name, ii.seqno
+	`
+
+	rows, err := sc.Query(sc.db, query, tableName, schema, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying indexes: %v", err)
+	}
+	defer rows.Close()
+
+	indexMap := make(map[string]*t.Index)
+	for rows.Next() {
+		var indexName, definition string
+		var columnName sql.NullString // NULL for expressions and the rowid
+		var isUnique, isPrimary bool
+
+		if err := rows.Scan(&indexName, &isUnique, &isPrimary, &columnName, &definition); err != nil {
+			return nil, fmt.Errorf("error scanning index results: %v", err)
+		}
+
+		idx, exists := indexMap[indexName]
+		if !exists {
+			idx = &t.Index{
+				Name:       indexName,
+				Unique:     isUnique,
+				PrimaryKey: isPrimary,
+				Method:     "btree",
+				Predicate:  indexPredicate(definition),
+			}
+			indexMap[indexName] = idx
+		}
+		if columnName.Valid {
+			idx.Columns = append(idx.Columns, columnName.String)
+		} else {
+			idx.Expression = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Convert map to slice, sorted by name for a stable output
+	var indexes []t.Index
+	for _, idx := range indexMap {
+		indexes = append(indexes, *idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+
+	return indexes, nil
+}
+
+// indexPredicate returns the WHERE clause of a partial index from its CREATE INDEX
+// statement, found after the parenthesised column list
+func indexPredicate(definition string) string {
+	open := strings.Index(definition, "(")
+	if open < 0 {
+		return ""
+	}
+
+	depth := 0
+	for i := open; i < len(definition); i++ {
+		switch definition[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				rest := definition[i+1:]
+				if where := strings.Index(strings.ToUpper(rest), "WHERE"); where >= 0 {
+					return strings.TrimSpace(rest[where+len("WHERE"):])
+				}
+				return ""
+			}
+		}
+	}
+	return ""
+}
+
+// getConstraints returns the primary key, unique and foreign key constraints of a
+// table. SQLite does not name most constraints, so names are made up like
+// PostgreSQL would.
+func (sc *SQLiteConnector) getConstraints(schema string, table *t.Table) ([]t.Constraint, error) {
+	var constraints []t.Constraint
+
+	var pkColumns []string
+	for _, col := range table.Columns {
+		if col.IsPrimaryKey {
+			pkColumns = append(pkColumns, col.Name)
+		}
+	}
+	if len(pkColumns) > 0 {
+		constraints = append(constraints, t.Constraint{
+			Name:       table.Name + "_pkey",
+			Type:       t.PrimaryKeyConstraint,
+			Columns:    pkColumns,
+			Definition: fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkColumns, ", ")),
+		})
+	}
+
+	// Unique constraints are backed by automatic indexes
+	rows, err := sc.Query(sc.db, `SELECT name FROM pragma_index_list(?, ?) WHERE origin = 'u' ORDER BY name`, table.Name, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error querying constraints: %v", err)
+	}
+	unique := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning constraint results: %v", err)
+		}
+		unique[name] = true
+	}
+	rows.Close()
+	for _, idx := range table.Indexes {
+		if unique[idx.Name] {
+			constraints = append(constraints, t.Constraint{
+				Name:       idx.Name,
+				Type:       t.UniqueConstraint,
+				Columns:    idx.Columns,
+				Definition: fmt.Sprintf("UNIQUE (%s)", strings.Join(idx.Columns, ", ")),
+			})
+		}
+	}
+
+	keys, err := sc.getForeignKeys(schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, fk := range keys {
+		var refColumns []string
+		for _, col := range fk.columns {
+			refColumns = append(refColumns, fk.columnRef(col))
+		}
+		constraints = append(constraints, t.Constraint{
+			Name:    fmt.Sprintf("%s_%s_fkey", table.Name, strings.Join(fk.columns, "_")),
+			Type:    t.ForeignKeyConstraint,
+			Columns: fk.columns,
+			Definition: fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)",
+				strings.Join(fk.columns, ", "), fk.table, strings.Join(refColumns, ", ")),
+		})
+	}
+
+	return constraints, nil
+}
+
+// EstimateRowCount returns -1, as SQLite keeps no row statistics by default
+func (sc *SQLiteConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	return -1, nil
+}
+
+// CountRows returns the exact number of rows in a table
+func (sc *SQLiteConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if sc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	query := "SELECT COUNT(*) FROM " + quoteIdentifier(schemaName(schema)) + "." + quoteIdentifier(tableName)
+	sc.LogQuery(query, nil)
+
+	var count int64
+	if err := sc.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
+
+// NewSQLiteConnector creates a connector for SQLite database files
+func NewSQLiteConnector() t.DatabaseConnector {
+	return &SQLiteConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverSQLite, NewSQLiteConnector, t.ConnectorInfo{
+		Title:      "SQLite file",
+		Fields:     []string{t.FieldDatabase, t.FieldSchema},
+		File:       true,
+		Extensions: []string{".db", ".sqlite", ".sqlite3"},
+		Defaults: func(p *t.ConnectionParams) {
+			p.Schema = cmp.Or(p.Schema, "main")
+		},
+	})
+}
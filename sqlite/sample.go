@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// SampleRows returns up to limit rows of a table, in no particular order. The
+// database file is opened read-only, so no transaction is needed.
+func (sc *SQLiteConnector) SampleRows(ctx context.Context, schema, tableName string, limit int) (*t.QueryResult, error) {
+	if sc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s.%s LIMIT %d", quoteIdentifier(schemaName(schema)), quoteIdentifier(tableName), limit)
+	sc.LogQuery(query, nil)
+	rows, err := sc.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error sampling rows: %v", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("error reading result columns: %v", err)
+	}
+
+	result := &t.QueryResult{Statement: query, Columns: columns}
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("error scanning sampled rows: %v", err)
+		}
+		row := make([]string, len(columns))
+		for i, v := range values {
+			switch val := v.(type) {
+			case nil:
+				row[i] = "NULL"
+			case []byte:
+				// Blobs are hex-encoded as PostgreSQL prints bytea, keeping the CSV text
+				row[i] = `\x` + hex.EncodeToString(val)
+			default:
+				row[i] = fmt.Sprint(val)
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading sampled rows: %v", err)
+	}
+
+	return result, nil
+}
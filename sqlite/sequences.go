@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetSequences returns the AUTOINCREMENT counters of the specified attached
+// database, which SQLite keeps in sqlite_sequence, one per table, named after it
+func (sc *SQLiteConnector) GetSequences(schema string) ([]t.Sequence, error) {
+	if sc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = schemaName(schema)
+
+	// sqlite_sequence is only created with the first AUTOINCREMENT table
+	rows, err := sc.Query(sc.db, `SELECT 1 FROM `+quoteIdentifier(schema)+`.sqlite_master WHERE type = 'table' AND name = 'sqlite_sequence'`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying sequences: %v", err)
+	}
+	exists := rows.Next()
+	rows.Close()
+	if !exists {
+		return nil, nil
+	}
+
+	rows, err = sc.Query(sc.db, `SELECT name, seq FROM `+quoteIdentifier(schema)+`.sqlite_sequence ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying sequences: %v", err)
+	}
+	defer rows.Close()
+
+	var sequences []t.Sequence
+	for rows.Next() {
+		seq := t.Sequence{Schema: schema, DataType: "INTEGER", Increment: 1, Min: "1", Max: "9223372036854775807"}
+		var current int64
+		if err := rows.Scan(&seq.Name, &current); err != nil {
+			return nil, fmt.Errorf("error scanning sequence results: %v", err)
+		}
+		seq.Current = sql.NullInt64{Int64: current, Valid: true}
+		sequences = append(sequences, seq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading sequences: %v", err)
+	}
+	rows.Close()
+
+	// The counter fills the INTEGER PRIMARY KEY of its table
+	for i := range sequences {
+		columns, err := sc.getColumns(schema, sequences[i].Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, col := range columns {
+			if col.IsPrimaryKey {
+				sequences[i].OwnedBy = sequences[i].Name + "." + col.Name
+				break
+			}
+		}
+	}
+	return sequences, nil
+}
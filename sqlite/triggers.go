@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// createTrigger matches the CREATE TRIGGER statement SQLite keeps, capturing
+// the timing and the event
+var createTrigger = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:TEMP\s+|TEMPORARY\s+)?TRIGGER\s+.*?\s+(BEFORE\s+|AFTER\s+|INSTEAD\s+OF\s+)?(DELETE|INSERT|UPDATE)\b.*?\s+ON\s+`)
+
+// getTriggers returns the triggers of a table. SQLite triggers run their body
+// for each row, and fire before the change unless declared otherwise.
+func (sc *SQLiteConnector) getTriggers(schema, tableName string) ([]t.Trigger, error) {
+	query := `
+		SELECT
+			name,
+			sql
+		FROM
+			` + quoteIdentifier(schema) + `.sqlite_master
+		WHERE
+			type = 'trigger'
+			AND tbl_name = ?
+		ORDER BY
+			name
+	`
+
+	rows, err := sc.Query(sc.db, query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying triggers: %v", err)
+	}
+	defer rows.Close()
+
+	var triggers []t.Trigger
+	for rows.Next() {
+		trigger := t.Trigger{Timing: "BEFORE", ForEach: "ROW", Enabled: true}
+		if err := rows.Scan(&trigger.Name, &trigger.Definition); err != nil {
+			return nil, fmt.Errorf("error scanning trigger results: %v", err)
+		}
+		if match := createTrigger.FindStringSubmatch(trigger.Definition); match != nil {
+			if timing := strings.Join(strings.Fields(match[1]), " "); timing != "" {
+				trigger.Timing = strings.ToUpper(timing)
+			}
+			trigger.Events = []string{strings.ToUpper(match[2])}
+		}
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers, rows.Err()
+}
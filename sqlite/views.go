@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViews returns the views in the specified attached database, with their
+// columns and SELECT statements
+func (sc *SQLiteConnector) GetViews(schema string) ([]t.View, error) {
+	if sc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema = schemaName(schema)
+
+	query := `
+		SELECT
+			name,
+			sql
+		FROM
+			` + quoteIdentifier(schema) + `.sqlite_master
+		WHERE
+			type = 'view'
+		ORDER BY
+			name
+	`
+
+	rows, err := sc.Query(sc.db, query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+	defer rows.Close()
+
+	var views []t.View
+	for rows.Next() {
+		view := t.View{Schema: schema}
+		if err := rows.Scan(&view.Name, &view.Definition); err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		// SQLite keeps the CREATE VIEW statement
+		view.Definition = sqlutil.ViewQuery(view.Definition)
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading views: %v", err)
+	}
+	rows.Close()
+
+	for i := range views {
+		if views[i].Columns, err = sc.getColumns(schema, views[i].Name); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
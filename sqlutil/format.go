@@ -0,0 +1,64 @@
+package sqlutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dedent removes blank leading and trailing lines, trailing spaces and the
+// indentation common to all lines of a query embedded in source code
+func Dedent(query string) string {
+	lines := strings.Split(strings.Trim(query, "\n"), "\n")
+
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// FormatLogged formats a logged query with its arguments listed in a leading comment
+func FormatLogged(query string, args []any) string {
+	var sb strings.Builder
+	for i, arg := range args {
+		if i == 0 {
+			sb.WriteString("--")
+		} else {
+			sb.WriteString(",")
+		}
+		sb.WriteString(fmt.Sprintf(" $%d = %s", i+1, formatArg(arg)))
+	}
+	if len(args) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(Dedent(query))
+	sb.WriteString(";\n")
+	return sb.String()
+}
+
+// formatArg formats a query argument as a SQL literal
+func formatArg(arg any) string {
+	switch v := arg.(type) {
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case nil:
+		return "NULL"
+	default:
+		return fmt.Sprint(v)
+	}
+}
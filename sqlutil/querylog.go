@@ -0,0 +1,50 @@
+package sqlutil
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// QueryLog passes the catalog queries of a connector to the function set with
+// SetQueryLog. Connectors embed it to implement types.QueryLogger. Queries may
+// be logged from several goroutines, so the function is called by one at a time.
+type QueryLog struct {
+	mu  sync.Mutex
+	log func(query string, args []any) // Receives catalog queries, if set
+}
+
+// SetQueryLog sets a function that receives every catalog query before it runs.
+// A nil function disables logging.
+func (l *QueryLog) SetQueryLog(log func(query string, args []any)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.log = log
+}
+
+// Logging reports whether queries are logged, so that costly log entries can be skipped
+func (l *QueryLog) Logging() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.log != nil
+}
+
+// LogQuery passes a query to the query log, if any
+func (l *QueryLog) LogQuery(query string, args []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.log != nil {
+		l.log(query, args)
+	}
+}
+
+// Query runs a catalog query returning rows, recording it in the query log
+func (l *QueryLog) Query(db *sql.DB, query string, args ...any) (*sql.Rows, error) {
+	l.LogQuery(query, args)
+	return db.Query(query, args...)
+}
+
+// QueryRow runs a catalog query returning one row, recording it in the query log
+func (l *QueryLog) QueryRow(db *sql.DB, query string, args ...any) *sql.Row {
+	l.LogQuery(query, args)
+	return db.QueryRow(query, args...)
+}
@@ -0,0 +1,32 @@
+package sqlutil
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestQueryLogConcurrent(t *testing.T) {
+	var l QueryLog
+	var queries []string
+	l.SetQueryLog(func(query string, args []any) {
+		queries = append(queries, query)
+	})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if l.Logging() {
+					l.LogQuery("SELECT 1", nil)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(queries) != 800 {
+		t.Errorf("logged %d queries, want 800", len(queries))
+	}
+}
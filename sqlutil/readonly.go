@@ -0,0 +1,53 @@
+package sqlutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// readOnlyKeywords lists the statement keywords accepted by the read-only guard
+var readOnlyKeywords = map[string]bool{
+	"SELECT":  true,
+	"WITH":    true,
+	"SHOW":    true,
+	"EXPLAIN": true,
+	"VALUES":  true,
+	"TABLE":   true,
+}
+
+// FirstKeyword returns the first keyword of a statement in upper case,
+// skipping leading whitespace, comments and opening parentheses
+func FirstKeyword(stmt string) string {
+	s := stmt
+	for {
+		s = strings.TrimLeftFunc(s, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '('
+		})
+
+		switch {
+		case strings.HasPrefix(s, "--"):
+			end := strings.IndexByte(s, '\n')
+			if end < 0 {
+				return ""
+			}
+			s = s[end+1:]
+		case strings.HasPrefix(s, "/*"):
+			s = s[blockCommentEnd(s):]
+		default:
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return !unicode.IsLetter(r)
+			})
+			if end < 0 {
+				end = len(s)
+			}
+			return strings.ToUpper(s[:end])
+		}
+	}
+}
+
+// IsReadOnly reports whether a statement starts with a keyword that does not modify data.
+// This is a first line of defence only: connectors must still run queries in a
+// read-only transaction, since e.g. a WITH clause can wrap a data-modifying statement.
+func IsReadOnly(stmt string) bool {
+	return readOnlyKeywords[FirstKeyword(stmt)]
+}
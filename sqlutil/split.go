@@ -0,0 +1,132 @@
+package sqlutil
+
+import (
+	"strings"
+)
+
+// SplitStatements splits a script into individual statements separated by semicolons.
+// Semicolons inside string literals, quoted identifiers, dollar-quoted bodies and
+// comments are ignored. Empty statements are dropped and the terminating semicolon
+// is not included in the result.
+func SplitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	flush := func() {
+		stmt := strings.TrimSpace(current.String())
+		if stmt != "" && !isCommentOnly(stmt) {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	i := 0
+	for i < len(script) {
+		c := script[i]
+
+		switch {
+		case c == '\'' || c == '"':
+			// String literal or quoted identifier, doubled quotes are escapes
+			end := i + 1
+			for end < len(script) {
+				if script[end] == c {
+					if end+1 < len(script) && script[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			end = min(end+1, len(script))
+			current.WriteString(script[i:end])
+			i = end
+
+		case c == '-' && i+1 < len(script) && script[i+1] == '-':
+			// Line comment
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script)
+			} else {
+				end += i
+			}
+			current.WriteString(script[i:end])
+			i = end
+
+		case c == '/' && i+1 < len(script) && script[i+1] == '*':
+			// Block comment, PostgreSQL allows nesting
+			end := i + blockCommentEnd(script[i:])
+			current.WriteString(script[i:end])
+			i = end
+
+		case c == '$':
+			// Dollar-quoted string such as $$...$$ or $body$...$body$
+			if tag := dollarTag(script[i:]); tag != "" {
+				end := strings.Index(script[i+len(tag):], tag)
+				if end < 0 {
+					end = len(script)
+				} else {
+					end += i + 2*len(tag)
+				}
+				current.WriteString(script[i:end])
+				i = end
+				continue
+			}
+			current.WriteByte(c)
+			i++
+
+		case c == ';':
+			flush()
+			i++
+
+		default:
+			current.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+
+	return statements
+}
+
+// dollarTag returns the opening dollar-quote tag at the start of s, or an empty string
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		if c == '$' {
+			return s[:j+1]
+		}
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !(isDigit && j > 1) {
+			return ""
+		}
+	}
+	return ""
+}
+
+// blockCommentEnd returns the length of the (possibly nested) block comment at the start of s
+func blockCommentEnd(s string) int {
+	depth := 0
+	end := 0
+	for end < len(s) {
+		if strings.HasPrefix(s[end:], "/*") {
+			depth++
+			end += 2
+		} else if strings.HasPrefix(s[end:], "*/") {
+			depth--
+			end += 2
+			if depth == 0 {
+				break
+			}
+		} else {
+			end++
+		}
+	}
+	return end
+}
+
+// isCommentOnly reports whether a statement consists only of comments
+func isCommentOnly(stmt string) bool {
+	return FirstKeyword(stmt) == ""
+}
@@ -0,0 +1,39 @@
+package sqlutil
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"single without semicolon", "SELECT 1", []string{"SELECT 1"}},
+		{"several", "SELECT 1;\n SELECT 2 ;", []string{"SELECT 1", "SELECT 2"}},
+		{"empty statements", ";; SELECT 1;;", []string{"SELECT 1"}},
+		{"string literal", "SELECT 'a;b'; SELECT 2", []string{"SELECT 'a;b'", "SELECT 2"}},
+		{"doubled quote", "SELECT 'it''s;'; SELECT 2", []string{"SELECT 'it''s;'", "SELECT 2"}},
+		{"quoted identifier", `SELECT "a;b" FROM t`, []string{`SELECT "a;b" FROM t`}},
+		{"line comment", "SELECT 1 -- one; two\n; SELECT 2", []string{"SELECT 1 -- one; two", "SELECT 2"}},
+		{"block comment", "SELECT /* ; */ 1", []string{"SELECT /* ; */ 1"}},
+		{"nested block comment", "SELECT /* a /* ; */ b; */ 1", []string{"SELECT /* a /* ; */ b; */ 1"}},
+		{"comment only", "-- nothing here;\n/* nor; here */", nil},
+		{"dollar quoted", "CREATE FUNCTION f() AS $$ BEGIN; END $$; SELECT 2",
+			[]string{"CREATE FUNCTION f() AS $$ BEGIN; END $$", "SELECT 2"}},
+		{"tagged dollar quote", "DO $body$ SELECT $$;$$; $body$",
+			[]string{"DO $body$ SELECT $$;$$; $body$"}},
+		{"positional parameter", "SELECT $1; SELECT $2", []string{"SELECT $1", "SELECT $2"}},
+		{"unterminated string", "SELECT 'a; b", []string{"SELECT 'a; b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.script); !slices.Equal(got, tt.want) {
+				t.Errorf("SplitStatements(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package sqlutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind classifies SQL tokens
+type TokenKind int
+
+// Token kinds
+const (
+	Word             TokenKind = iota // Keyword or bare identifier
+	QuotedIdentifier                  // "Quoted" identifier, Text is unquoted
+	Literal                           // String, dollar-quoted or numeric literal
+	Symbol                            // Punctuation or operator
+)
+
+// Token is a lexical element of a SQL statement
+type Token struct {
+	Kind TokenKind
+	Text string
+	Pos  int // Byte offset of the token in the statement
+}
+
+// Is reports whether the token is the given keyword, ignoring case
+func (tok Token) Is(keyword string) bool {
+	return tok.Kind == Word && strings.EqualFold(tok.Text, keyword)
+}
+
+// IsSymbol reports whether the token is the given punctuation or operator
+func (tok Token) IsSymbol(symbol string) bool {
+	return tok.Kind == Symbol && tok.Text == symbol
+}
+
+// IsName reports whether the token can be an identifier
+func (tok Token) IsName() bool {
+	return tok.Kind == Word || tok.Kind == QuotedIdentifier
+}
+
+// Name returns the identifier the token refers to. Bare identifiers fold to lower case.
+func (tok Token) Name() string {
+	if tok.Kind == Word {
+		return strings.ToLower(tok.Text)
+	}
+	return tok.Text
+}
+
+// multiCharSymbols lists the operators that are kept together as one token
+var multiCharSymbols = []string{"::", "<=", ">=", "<>", "!=", "||", "->>", "->"}
+
+// Tokenize splits a statement into tokens. Whitespace and comments are dropped.
+func Tokenize(stmt string) []Token {
+	var tokens []Token
+
+	i := 0
+	for i < len(stmt) {
+		c := stmt[i]
+		r, size := utf8.DecodeRuneInString(stmt[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case c == '-' && strings.HasPrefix(stmt[i:], "--"):
+			end := strings.IndexByte(stmt[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+
+		case c == '/' && strings.HasPrefix(stmt[i:], "/*"):
+			i += blockCommentEnd(stmt[i:])
+
+		case c == '\'' || c == '"':
+			// Doubled quotes are escapes
+			var sb strings.Builder
+			end := i + 1
+			for end < len(stmt) {
+				if stmt[end] == c {
+					if end+1 < len(stmt) && stmt[end+1] == c {
+						sb.WriteByte(c)
+						end += 2
+						continue
+					}
+					break
+				}
+				sb.WriteByte(stmt[end])
+				end++
+			}
+			if c == '"' {
+				tokens = append(tokens, Token{Kind: QuotedIdentifier, Text: sb.String(), Pos: i})
+			} else {
+				tokens = append(tokens, Token{Kind: Literal, Text: stmt[i:min(end+1, len(stmt))], Pos: i})
+			}
+			i = min(end+1, len(stmt))
+
+		case c == '$' && dollarTag(stmt[i:]) != "":
+			tag := dollarTag(stmt[i:])
+			end := strings.Index(stmt[i+len(tag):], tag)
+			if end < 0 {
+				end = len(stmt)
+			} else {
+				end += i + 2*len(tag)
+			}
+			tokens = append(tokens, Token{Kind: Literal, Text: stmt[i:end], Pos: i})
+			i = end
+
+		case unicode.IsLetter(r) || c == '_':
+			end := i + size
+			for end < len(stmt) {
+				r, size := utf8.DecodeRuneInString(stmt[end:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$' {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, Token{Kind: Word, Text: stmt[i:end], Pos: i})
+			i = end
+
+		case unicode.IsDigit(r):
+			end := i + 1
+			for end < len(stmt) && (unicode.IsDigit(rune(stmt[end])) || stmt[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, Token{Kind: Literal, Text: stmt[i:end], Pos: i})
+			i = end
+
+		default:
+			text := string(r)
+			for _, symbol := range multiCharSymbols {
+				if strings.HasPrefix(stmt[i:], symbol) {
+					text = symbol
+					break
+				}
+			}
+			tokens = append(tokens, Token{Kind: Symbol, Text: text, Pos: i})
+			i += len(text)
+		}
+	}
+
+	return tokens
+}
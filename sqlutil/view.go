@@ -0,0 +1,30 @@
+package sqlutil
+
+import (
+	"strings"
+)
+
+// ViewQuery returns the query of a CREATE VIEW statement, as databases that
+// keep views as DDL report them: everything after the AS that follows the view
+// name, column list and options. Other statements are returned as they are.
+func ViewQuery(ddl string) string {
+	if FirstKeyword(ddl) != "CREATE" {
+		return strings.TrimSpace(ddl)
+	}
+
+	view, depth := false, 0
+	for _, tok := range Tokenize(ddl) {
+		switch {
+		case tok.Is("VIEW"):
+			view = true
+		case tok.IsSymbol("("):
+			depth++
+		case tok.IsSymbol(")"):
+			depth--
+		case view && depth == 0 && tok.Is("AS"):
+			query := strings.TrimSpace(ddl[tok.Pos+len(tok.Text):])
+			return strings.TrimSpace(strings.TrimSuffix(query, ";"))
+		}
+	}
+	return strings.TrimSpace(ddl)
+}
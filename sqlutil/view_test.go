@@ -0,0 +1,31 @@
+package sqlutil
+
+import (
+	"testing"
+)
+
+func TestViewQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+		want string
+	}{
+		{"plain", "CREATE VIEW v AS SELECT 1;", "SELECT 1"},
+		{"lower case", "create or replace view s.v as\nselect a from t", "select a from t"},
+		{"column list", "CREATE VIEW v (a, b) AS SELECT x AS a, y AS b FROM t", "SELECT x AS a, y AS b FROM t"},
+		{"options", "CREATE VIEW [dbo].[v] WITH SCHEMABINDING AS SELECT id FROM dbo.t", "SELECT id FROM dbo.t"},
+		{"properties", "CREATE VIEW v COMMENT 'as built' TBLPROPERTIES ('a'='b') AS SELECT 1", "SELECT 1"},
+		{"leading comment", "-- totals\nCREATE VIEW v AS SELECT 1", "SELECT 1"},
+		{"temporary", "CREATE TEMP VIEW v AS SELECT 1", "SELECT 1"},
+		{"query only", "  SELECT 1 ", "SELECT 1"},
+		{"no AS", "CREATE VIEW v", "CREATE VIEW v"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ViewQuery(tt.ddl); got != tt.want {
+				t.Errorf("ViewQuery(%q) = %q, want %q", tt.ddl, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// serviceName identifies db-reader in the tracing backend
+const serviceName = "db-reader"
+
+// Setup sends the spans recorded through the global OpenTelemetry tracer
+// provider to an exporter:
+//
+//	otlp    an OpenTelemetry collector over OTLP/HTTP, at endpoint or, if
+//	        empty, the one of the OTEL_EXPORTER_OTLP_* environment variables
+//	stdout  w, as indented JSON, to check what is traced
+//
+// It also makes the W3C trace context of incoming requests the parent of
+// their spans. The returned function flushes the pending spans and stops the
+// exporter. Without Setup, spans are not recorded and cost next to nothing.
+func Setup(exporter, endpoint string, w io.Writer) (func(context.Context) error, error) {
+	var spans sdktrace.SpanExporter
+	var err error
+	switch exporter {
+	case "otlp":
+		var opts []otlptracehttp.Option
+		if endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		spans, err = otlptracehttp.New(context.Background(), opts...)
+	case "stdout":
+		spans, err = stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown trace exporter '%s', expected otlp or stdout", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s trace exporter: %v", exporter, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spans),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
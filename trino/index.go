@@ -0,0 +1,358 @@
+package trino
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+	"github.com/trinodb/trino-go-client/trino"
+)
+
+// TrinoConnector implements the DatabaseConnector interface for Trino. The
+// server federates several catalogs, each backed by a data source such as Hive
+// or PostgreSQL; the database is the catalog browsed, and catalog queries read
+// its information_schema.
+type TrinoConnector struct {
+	sqlutil.QueryLog
+
+	db      *sql.DB
+	catalog string // Catalog connected to, which qualifies the catalog views
+}
+
+// Connect establishes a connection to a Trino coordinator. Passwords are only
+// sent over HTTPS, which is used when one is given. Session settings are passed
+// as session properties, e.g. query_max_run_time=10m.
+func (tc *TrinoConnector) Connect(params t.ConnectionParams) error {
+	if params.Database == "" {
+		return fmt.Errorf("a Trino catalog is required")
+	}
+
+	server := &url.URL{Scheme: "http", Host: net.JoinHostPort(params.Host, params.Port)}
+	if params.Password != "" {
+		server.Scheme = "https"
+		server.User = url.UserPassword(params.User, params.Password)
+	} else {
+		server.User = url.User(params.User)
+	}
+
+	cfg := &trino.Config{
+		ServerURI:         server.String(),
+		Source:            "db-reader",
+		Catalog:           params.Database,
+		SessionProperties: params.Settings,
+	}
+	dsn, err := cfg.FormatDSN()
+	if err != nil {
+		return fmt.Errorf("invalid connection settings: %v", err)
+	}
+
+	tc.db, err = sql.Open("trino", dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	// Test the connection. The driver only reaches the server when running a
+	// statement, so a ping would always succeed.
+	var one int
+	if err := tc.db.QueryRow("SELECT 1").Scan(&one); err != nil {
+		tc.db.Close()
+		tc.db = nil
+		return fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	tc.catalog = params.Database
+	return nil
+}
+
+// Disconnect closes the database connection
+func (tc *TrinoConnector) Disconnect() error {
+	if tc.db != nil {
+		err := tc.db.Close()
+		tc.db = nil
+		if err != nil {
+			return fmt.Errorf("error closing database connection: %v", err)
+		}
+	}
+	return nil
+}
+
+// view returns the qualified name of an information_schema view of a catalog
+func view(catalog, name string) string {
+	return quoteIdentifier(catalog) + ".information_schema." + name
+}
+
+// GetCatalogs returns the catalogs configured on the server
+func (tc *TrinoConnector) GetCatalogs() ([]string, error) {
+	if tc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := tc.Query(tc.db, `
+		SELECT catalog_name
+		FROM system.metadata.catalogs
+		WHERE catalog_name <> 'system'
+		ORDER BY catalog_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying catalogs: %v", err)
+	}
+	defer rows.Close()
+
+	var catalogs []string
+	for rows.Next() {
+		var catalog string
+		if err := rows.Scan(&catalog); err != nil {
+			return nil, fmt.Errorf("error scanning catalog results: %v", err)
+		}
+		catalogs = append(catalogs, catalog)
+	}
+
+	return catalogs, rows.Err()
+}
+
+// GetCatalogSchemas returns the schemas of a catalog
+func (tc *TrinoConnector) GetCatalogSchemas(catalog string) ([]string, error) {
+	if tc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := tc.Query(tc.db, `
+		SELECT schema_name
+		FROM `+view(catalog, "schemata")+`
+		WHERE schema_name <> 'information_schema'
+		ORDER BY schema_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying schemas: %v", err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			return nil, fmt.Errorf("error scanning schema results: %v", err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, rows.Err()
+}
+
+// GetSchemas returns the schemas of the connected catalog
+func (tc *TrinoConnector) GetSchemas() ([]string, error) {
+	return tc.GetCatalogSchemas(tc.catalog)
+}
+
+// resolve splits a schema name qualified with its catalog, e.g. hive.sales,
+// so tables of other catalogs can be browsed on the same connection. Names
+// without a catalog are in the connected one.
+func (tc *TrinoConnector) resolve(schema string) (catalog, name string) {
+	if catalog, name, ok := strings.Cut(schema, "."); ok {
+		return catalog, name
+	}
+	return tc.catalog, schema
+}
+
+// GetTables returns a list of tables in the specified schema
+func (tc *TrinoConnector) GetTables(schema string) ([]string, error) {
+	if tc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	catalog, schemaName := tc.resolve(schema)
+
+	query := `
+		SELECT
+			table_name
+		FROM
+			` + view(catalog, "tables") + `
+		WHERE
+			table_schema = ?
+			AND table_type = 'BASE TABLE'
+		ORDER BY
+			table_name
+	`
+
+	rows, err := tc.Query(tc.db, query, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning table results: %v", err)
+		}
+		tables = append(tables, tableName)
+	}
+
+	return tables, rows.Err()
+}
+
+// GetTableStructure returns the structure of the specified table. Trino does
+// not report keys or indexes, so only the columns and comments are filled in;
+// the catalog of the table is given as a table property.
+func (tc *TrinoConnector) GetTableStructure(schema, tableName string) (*t.Table, error) {
+	if tc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	catalog, schemaName := tc.resolve(schema)
+
+	// Check if the table exists
+	var count int
+	err := tc.QueryRow(tc.db, `
+		SELECT COUNT(*)
+		FROM `+view(catalog, "tables")+`
+		WHERE table_schema = ? AND table_name = ?
+	`, schemaName, tableName).Scan(&count)
+	if err != nil {
+		return nil, fmt.Errorf("error checking table existence: %v", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("table '%s.%s' does not exist", schema, tableName)
+	}
+
+	table := &t.Table{
+		Name:       tableName,
+		Schema:     schema,
+		Properties: []t.Property{{Name: "Catalog", Value: catalog}},
+	}
+	if table.Comment, err = tc.getTableComment(catalog, schemaName, tableName); err != nil {
+		return nil, err
+	}
+	if table.Columns, err = tc.getColumns(catalog, schemaName, tableName); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// getTableComment returns the comment of a table, which the system metadata
+// tables hold for every catalog
+func (tc *TrinoConnector) getTableComment(catalog, schema, tableName string) (string, error) {
+	rows, err := tc.Query(tc.db, `
+		SELECT comment
+		FROM system.metadata.table_comments
+		WHERE catalog_name = ? AND schema_name = ? AND table_name = ?
+	`, catalog, schema, tableName)
+	if err != nil {
+		return "", fmt.Errorf("error querying table comment: %v", err)
+	}
+	defer rows.Close()
+
+	var comment sql.NullString
+	if rows.Next() {
+		if err := rows.Scan(&comment); err != nil {
+			return "", fmt.Errorf("error scanning table comment: %v", err)
+		}
+	}
+	return comment.String, rows.Err()
+}
+
+// getColumns returns the columns of a table with their comments
+func (tc *TrinoConnector) getColumns(catalog, schema, tableName string) ([]t.Column, error) {
+	query := `
+		SELECT
+			column_name,
+			data_type,
+			is_nullable,
+			column_default
+		FROM
+			` + view(catalog, "columns") + `
+		WHERE
+			table_schema = ? AND table_name = ?
+		ORDER BY
+			ordinal_position
+	`
+
+	rows, err := tc.Query(tc.db, query, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []t.Column
+	for rows.Next() {
+		var col t.Column
+		var isNullable string
+
+		if err := rows.Scan(&col.Name, &col.Type, &isNullable, &col.DefaultValue); err != nil {
+			return nil, fmt.Errorf("error scanning column results: %v", err)
+		}
+		col.Nullable = isNullable == "YES"
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	comments, err := tc.getColumnComments(catalog, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range columns {
+		columns[i].Comment = comments[columns[i].Name]
+	}
+
+	return columns, nil
+}
+
+// getColumnComments returns the comments of the columns of a table by column
+// name. Only SHOW COLUMNS reports them, and it cannot take parameters.
+func (tc *TrinoConnector) getColumnComments(catalog, schema, tableName string) (map[string]string, error) {
+	rows, err := tc.Query(tc.db, "SHOW COLUMNS FROM "+quoteQualified(catalog, schema, tableName))
+	if err != nil {
+		return nil, fmt.Errorf("error querying column comments: %v", err)
+	}
+	defer rows.Close()
+
+	// The result has the Column, Type, Extra and Comment columns
+	comments := make(map[string]string)
+	for rows.Next() {
+		var name, typ, extra, comment sql.NullString
+		if err := rows.Scan(&name, &typ, &extra, &comment); err != nil {
+			return nil, fmt.Errorf("error scanning column comments: %v", err)
+		}
+		if comment.String != "" {
+			comments[name.String] = comment.String
+		}
+	}
+
+	return comments, rows.Err()
+}
+
+// quoteIdentifier quotes a Trino identifier
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteQualified returns a quoted catalog and schema-qualified name for use in queries
+func quoteQualified(catalog, schema, name string) string {
+	return quoteIdentifier(catalog) + "." + quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// NewTrinoConnector creates a connector for Trino servers
+func NewTrinoConnector() t.DatabaseConnector {
+	return &TrinoConnector{}
+}
+
+// init registers the connector under its driver name, with how to connect
+func init() {
+	t.RegisterConnector(t.DriverTrino, NewTrinoConnector, t.ConnectorInfo{
+		Title:  "Trino",
+		Fields: t.ServerFields,
+		Labels: map[string]string{t.FieldDatabase: "Catalog"},
+		Defaults: func(p *t.ConnectionParams) {
+			// Hive and most other connectors name their default schema "default"
+			p.DefaultServer("8080", "trino")
+			p.Schema = cmp.Or(p.Schema, "default")
+		},
+	})
+}
@@ -0,0 +1,64 @@
+package trino
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// EstimateRowCount returns the row count in the table statistics, which the
+// summary row of SHOW STATS holds. Catalogs without statistics give -1.
+func (tc *TrinoConnector) EstimateRowCount(schema, tableName string) (int64, error) {
+	if tc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	catalog, schemaName := tc.resolve(schema)
+	rows, err := tc.Query(tc.db, "SHOW STATS FOR "+quoteQualified(catalog, schemaName, tableName))
+	if err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	defer rows.Close()
+
+	// The rows have the column_name, data_size, distinct_values_count,
+	// nulls_fraction, row_count, low_value and high_value columns
+	for rows.Next() {
+		var column, low, high sql.NullString
+		var size, distinct, nulls, count sql.NullFloat64
+		if err := rows.Scan(&column, &size, &distinct, &nulls, &count, &low, &high); err != nil {
+			return 0, fmt.Errorf("error querying row estimate: %v", err)
+		}
+		// The summary row has no column name
+		if column.Valid {
+			continue
+		}
+		if !count.Valid {
+			return -1, nil
+		}
+		return int64(count.Float64), nil
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error querying row estimate: %v", err)
+	}
+	return -1, nil
+}
+
+// CountRows returns the exact number of rows in a table
+func (tc *TrinoConnector) CountRows(ctx context.Context, schema, tableName string) (int64, error) {
+	if tc.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	catalog, schemaName := tc.resolve(schema)
+	query := "SELECT COUNT(*) FROM " + quoteQualified(catalog, schemaName, tableName)
+	tc.LogQuery(query, nil)
+
+	var count int64
+	if err := tc.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("row count cancelled")
+		}
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
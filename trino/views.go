@@ -0,0 +1,60 @@
+package trino
+
+import (
+	"database/sql"
+	"fmt"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// GetViews returns the views of the schema, with their columns and SELECT
+// statements. Materialized views are not listed in information_schema.views.
+func (tc *TrinoConnector) GetViews(schema string) ([]t.View, error) {
+	if tc.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	catalog, schemaName := tc.resolve(schema)
+
+	query := `
+		SELECT
+			table_name,
+			view_definition
+		FROM
+			` + view(catalog, "views") + `
+		WHERE
+			table_schema = ?
+		ORDER BY
+			table_name
+	`
+
+	rows, err := tc.Query(tc.db, query, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("error querying views: %v", err)
+	}
+	defer rows.Close()
+
+	var views []t.View
+	for rows.Next() {
+		view := t.View{Schema: schema}
+		var definition sql.NullString
+		if err := rows.Scan(&view.Name, &definition); err != nil {
+			return nil, fmt.Errorf("error scanning view results: %v", err)
+		}
+		view.Definition = definition.String
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading views: %v", err)
+	}
+	rows.Close()
+
+	for i := range views {
+		if views[i].Comment, err = tc.getTableComment(catalog, schemaName, views[i].Name); err != nil {
+			return nil, err
+		}
+		if views[i].Columns, err = tc.getColumns(catalog, schemaName, views[i].Name); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// Connection parameters a driver may use, as listed in ConnectorInfo.Fields
+const (
+	FieldHost        = "host"
+	FieldPort        = "port"
+	FieldUser        = "user"
+	FieldPassword    = "password"
+	FieldSecret      = "secret" // User and password from a secret store
+	FieldDatabase    = "database"
+	FieldSchema      = "schema"
+	FieldAccount     = "account"
+	FieldWarehouse   = "warehouse"
+	FieldRole        = "role"
+	FieldCredentials = "credentials"
+	FieldTimeouts    = "timeouts" // Statement and lock timeouts
+	FieldSettings    = "settings" // Session settings
+)
+
+// ServerFields are the fields of drivers connecting to a database server
+var ServerFields = []string{FieldHost, FieldPort, FieldUser, FieldPassword, FieldSecret, FieldDatabase, FieldSchema,
+	FieldTimeouts, FieldSettings}
+
+// ConnectorInfo describes how to connect with a driver, for the connection
+// dialogs and the defaults of the connection parameters
+type ConnectorInfo struct {
+	Title  string            // Shown in the connection dialog, e.g. "PostgreSQL"
+	Fields []string          // Connection parameters the driver uses
+	Labels map[string]string // Labels of the fields named otherwise by the driver, e.g. "Project" for FieldDatabase
+	Hint   string            // Placeholder of the database field, e.g. "Snapshot .json file"
+
+	// File drivers read a database file picked instead of connecting to a server.
+	// The picker offers the files with the given extensions, all if empty.
+	File       bool
+	Extensions []string
+
+	// AddFile combines a picked file with the database given so far, for drivers
+	// reading several files. The picked file replaces the database if nil.
+	AddFile func(database, path string) string
+
+	// Defaults fills in the parameters left empty, if set
+	Defaults func(p *ConnectionParams)
+}
+
+// Uses reports whether the driver uses a connection parameter
+func (info ConnectorInfo) Uses(field string) bool {
+	return slices.Contains(info.Fields, field)
+}
+
+// Label returns the label of a field, the given default unless the driver names it otherwise
+func (info ConnectorInfo) Label(field, label string) string {
+	if custom, ok := info.Labels[field]; ok {
+		return custom
+	}
+	return label
+}
+
+// registeredConnector is a connector factory with the description of its driver
+type registeredConnector struct {
+	factory DatabaseConnectorFactory
+	info    ConnectorInfo
+}
+
+// connectors holds the registered connectors by driver name
+var (
+	connectorsMu sync.RWMutex
+	connectors   = make(map[string]registeredConnector)
+)
+
+// RegisterConnector makes a connector available under a driver name, described
+// by info. Connector packages register themselves when imported. It panics if the
+// name is taken.
+func RegisterConnector(name string, factory DatabaseConnectorFactory, info ConnectorInfo) {
+	connectorsMu.Lock()
+	defer connectorsMu.Unlock()
+
+	if factory == nil {
+		panic("types: RegisterConnector factory is nil")
+	}
+	if _, dup := connectors[name]; dup {
+		panic("types: RegisterConnector called twice for driver " + name)
+	}
+	if info.Title == "" {
+		info.Title = name
+	}
+	connectors[name] = registeredConnector{factory: factory, info: info}
+}
+
+// NewConnector creates a connector for a registered driver. An empty driver is PostgreSQL.
+func NewConnector(driver string) (DatabaseConnector, error) {
+	if driver == "" {
+		driver = DriverPostgres
+	}
+
+	connectorsMu.RLock()
+	registered, ok := connectors[driver]
+	connectorsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown database driver '%s'", driver)
+	}
+	return registered.factory(), nil
+}
+
+// Connector returns the description of a registered driver. An empty driver is
+// PostgreSQL. Unknown drivers use the server fields under their name.
+func Connector(driver string) ConnectorInfo {
+	if driver == "" {
+		driver = DriverPostgres
+	}
+
+	connectorsMu.RLock()
+	registered, ok := connectors[driver]
+	connectorsMu.RUnlock()
+	if !ok {
+		return ConnectorInfo{Title: driver, Fields: ServerFields}
+	}
+	return registered.info
+}
+
+// Drivers returns the names of the registered drivers, sorted
+func Drivers() []string {
+	connectorsMu.RLock()
+	defer connectorsMu.RUnlock()
+
+	names := make([]string, 0, len(connectors))
+	for name := range connectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
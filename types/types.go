@@ -1,17 +1,83 @@
 package types
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
+	"errors"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Database drivers
+const (
+	DriverPostgres   = "postgres"
+	DriverMySQL      = "mysql"
+	DriverMSSQL      = "sqlserver"
+	DriverOracle     = "oracle" // The database name is the service name
+	DriverSQLite     = "sqlite" // The database name is the path of the database file
+	DriverDuckDB     = "duckdb" // The database name is a database file or data files separated by semicolons
+	DriverPgDump     = "pgdump" // The database name is a pg_dump --schema-only file or a directory of DDL scripts
+	DriverClickHouse = "clickhouse"
+	DriverSnowflake  = "snowflake" // Connects to the account, the host is only needed for private links
+	DriverBigQuery   = "bigquery"  // The database name is the project and schemas are its datasets
+	DriverODBC       = "odbc"      // The database name is a data source name or connection string
+	DriverMongoDB    = "mongodb"   // Collections are tables, the host may be a whole connection string
+	DriverDb2        = "db2"
+	DriverTrino      = "trino"    // The database name is the catalog, among the ones the server federates
+	DriverHive       = "hive"     // HiveServer2 or the Spark Thrift Server, whose databases are the schemas
+	DriverSnapshot   = "snapshot" // The database name is a schema snapshot file
+	DriverDemo       = "demo"     // Built-in sample schema, needs no database
 )
 
 // ConnectionParams contains parameters needed to connect to a database
 type ConnectionParams struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Database string
-	Schema   string
+	Driver   string `json:"driver,omitempty"` // One of the database drivers, PostgreSQL if empty
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	Database string `json:"database"`
+	Schema   string `json:"schema"`
+
+	// Snowflake account identifier, e.g. "myorg-myaccount", and the warehouse and role of the session
+	Account   string `json:"account,omitempty"`
+	Warehouse string `json:"warehouse,omitempty"`
+	Role      string `json:"role,omitempty"`
+
+	// BigQuery service account key file. Application default credentials are used if empty.
+	CredentialsFile string `json:"credentials_file,omitempty"`
+
+	// Reference to the user and password in Vault or AWS Secrets Manager, e.g.
+	// "vault:secret/data/db#password" or "aws:prod/db", fetched when connecting
+	Secret string `json:"secret,omitempty"`
+
+	// PostgreSQL through pgbouncer in transaction pooling mode: "on" or "off", detected when connecting if empty
+	PgBouncer string `json:"pgbouncer,omitempty"`
+
+	// Session limits applied when connecting, e.g. "30s" or "5min". Empty keeps the server default.
+	// PostgreSQL rejects them through pgbouncer, where they cannot bound the catalog queries.
+	StatementTimeout string `json:"statement_timeout,omitempty"`
+	LockTimeout      string `json:"lock_timeout,omitempty"`
+
+	// Settings holds additional session parameters such as search_path or work_mem
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// ApplyDefaults fills in the parameters left empty with the defaults of the driver
+func (p *ConnectionParams) ApplyDefaults() {
+	if defaults := Connector(p.Driver).Defaults; defaults != nil {
+		defaults(p)
+	}
+}
+
+// DefaultServer fills in the host, port and user of a server left empty
+func (p *ConnectionParams) DefaultServer(port, user string) {
+	p.Host = cmp.Or(p.Host, "localhost")
+	p.Port = cmp.Or(p.Port, port)
+	p.User = cmp.Or(p.User, user)
 }
 
 // Column represents a database table column
@@ -22,22 +88,299 @@ type Column struct {
 	DefaultValue sql.NullString
 	IsPrimaryKey bool
 	ForeignKey   sql.NullString // Foreign key reference information
+	Comment      string
+}
+
+// ForeignKeyTarget splits the foreign key reference of the column, formatted as
+// "table (column)" with an optionally schema-qualified and quoted table name,
+// into the unqualified table name and the referenced column
+func (c Column) ForeignKeyTarget() (table, column string, ok bool) {
+	_, table, column, ok = c.foreignKeyReference()
+	return table, column, ok
+}
+
+// ForeignKeySchema returns the schema qualifying the table the foreign key of
+// the column references, or "" when the reference is not qualified
+func (c Column) ForeignKeySchema() string {
+	schema, _, _, _ := c.foreignKeyReference()
+	return schema
+}
+
+// foreignKeyReference splits the foreign key reference of the column into the
+// schema, if qualified, the table and the column, unquoting the names
+func (c Column) foreignKeyReference() (schema, table, column string, ok bool) {
+	if !c.ForeignKey.Valid {
+		return "", "", "", false
+	}
+
+	ref := c.ForeignKey.String
+	open := strings.LastIndex(ref, " (")
+	if open < 0 {
+		return "", "", "", false
+	}
+	table = ref[:open]
+	column = strings.TrimSuffix(ref[open+2:], ")")
+
+	schema, table = splitQualifiedName(table)
+	return schema, table, column, true
+}
+
+// splitQualifiedName splits an optionally schema-qualified and quoted name into
+// the schema, "" if not qualified, and the name, ignoring dots inside quotes
+func splitQualifiedName(name string) (schema, unqualified string) {
+	inQuotes := false
+	for i := len(name) - 1; i >= 0; i-- {
+		if name[i] == '"' {
+			inQuotes = !inQuotes
+		} else if name[i] == '.' && !inQuotes {
+			return unquoteName(name[:i]), unquoteName(name[i+1:])
+		}
+	}
+	return "", unquoteName(name)
+}
+
+// unquoteName removes the double quotes around a name, if any
+func unquoteName(name string) string {
+	if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+		return strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+	}
+	return name
 }
 
 // Index represents a database index
 type Index struct {
 	Name       string
-	Columns    []string
+	Columns    []string // Key columns
+	Include    []string // Non-key columns stored in the index with INCLUDE
 	Unique     bool
 	PrimaryKey bool
+	Method     string // Access method, e.g. "btree" or "gin"
+	Predicate  string // WHERE clause of a partial index
+	Expression bool   // Some index keys are expressions, which are not listed in Columns
+	Size       int64  // Size in bytes, 0 if unknown
+}
+
+// Constraint kinds
+const (
+	PrimaryKeyConstraint = "PRIMARY KEY"
+	ForeignKeyConstraint = "FOREIGN KEY"
+	UniqueConstraint     = "UNIQUE"
+	CheckConstraint      = "CHECK"
+	ExcludeConstraint    = "EXCLUDE"
+)
+
+// Constraint represents a table constraint
+type Constraint struct {
+	Name       string
+	Type       string // One of the constraint kinds, e.g. "FOREIGN KEY"
+	Columns    []string
+	Definition string // Constraint definition as SQL, e.g. "CHECK (price > 0)"
+}
+
+// ReferencedTable returns the schema, "" if not qualified, and the table a
+// foreign key constraint references, read from its definition
+func (c Constraint) ReferencedTable() (schema, table string, ok bool) {
+	if c.Type != ForeignKeyConstraint {
+		return "", "", false
+	}
+	_, ref, found := strings.Cut(strings.ToUpper(c.Definition), "REFERENCES ")
+	if !found {
+		return "", "", false
+	}
+	ref = strings.TrimSpace(c.Definition[len(c.Definition)-len(ref):])
+
+	// The table name ends at the opening parenthesis or space outside quotes
+	inQuotes, end := false, len(ref)
+	for i := 0; i < len(ref) && end == len(ref); i++ {
+		switch {
+		case ref[i] == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && (ref[i] == '(' || ref[i] == ' '):
+			end = i
+		}
+	}
+	ref = ref[:end]
+	if ref == "" {
+		return "", "", false
+	}
+
+	schema, table = splitQualifiedName(ref)
+	return schema, table, true
+}
+
+// Trigger represents a trigger firing on changes to a table
+type Trigger struct {
+	Name       string
+	Timing     string   // BEFORE, AFTER or INSTEAD OF
+	Events     []string // INSERT, UPDATE, DELETE or TRUNCATE, in that order
+	ForEach    string   // ROW or STATEMENT
+	Function   string   // Function the trigger calls, e.g. "audit_change()", "" if the body is inline
+	Enabled    bool
+	Definition string // CREATE TRIGGER statement
 }
 
 // Table represents a database table structure
 type Table struct {
-	Name    string
-	Schema  string
-	Columns []Column
-	Indexes []Index
+	Name        string
+	Schema      string
+	Columns     []Column
+	Indexes     []Index
+	Constraints []Constraint
+	Triggers    []Trigger
+	Comment     string
+	Properties  []Property // Database-specific metadata such as the storage engine, in display order
+}
+
+// View represents a view or materialized view
+type View struct {
+	Name         string
+	Schema       string
+	Columns      []Column // Column types are empty when only the definition is known
+	Definition   string   // SELECT statement of the view
+	Materialized bool
+	Comment      string
+}
+
+// Property is a named piece of database-specific table metadata
+type Property struct {
+	Name  string
+	Value string
+}
+
+// QueryResult holds the result set of a single statement run from the query editor
+type QueryResult struct {
+	Statement string
+	Columns   []string
+	Rows      [][]string
+	Truncated bool // True if more rows were available than were fetched
+}
+
+// PlanNode is a node of a query execution plan
+type PlanNode struct {
+	NodeType     string
+	RelationName string
+	Schema       string
+	Alias        string
+	IndexName    string
+	Filter       string // Condition applied to the rows the node reads, if any
+	StartupCost  float64
+	TotalCost    float64
+	PlanRows     float64
+	PlanWidth    int
+	TableRows    int64 // Estimated row count of the scanned relation, if any
+	Children     []*PlanNode
+}
+
+// MigrationStatus describes the schema version recorded by a migration tool
+type MigrationStatus struct {
+	Tool        string // Migration tool name, e.g. "Flyway"
+	Table       string // History table the status was read from
+	Version     string // Currently applied version
+	Description string // Description of the current version, if recorded
+	AppliedAt   string // When the current version was applied, if recorded
+	Applied     int    // Number of applied migrations, if the tool keeps a history
+	Dirty       bool   // The last migration failed or was interrupted
+}
+
+// TableSize is the storage used by a table, including its indexes and TOAST data
+type TableSize struct {
+	Name  string
+	Bytes int64
+	Rows  int64 // Estimated row count
+}
+
+// TableReferences counts the foreign keys pointing at a table
+type TableReferences struct {
+	Name       string
+	References int
+}
+
+// SchemaStats summarizes the objects, storage and foreign key graph of a schema
+type SchemaStats struct {
+	Schema    string
+	Tables    int
+	Views     int
+	Indexes   int
+	Functions int
+	Sequences int
+
+	TotalBytes    int64       // Storage used by all tables, indexes and materialized views
+	LargestTables []TableSize // Largest tables, biggest first
+
+	ForeignKeys     int               // Number of foreign key constraints
+	ConnectedTables int               // Tables referencing or referenced by another table
+	IsolatedTables  int               // Tables without any foreign key relationship
+	MostReferenced  []TableReferences // Tables with the most incoming foreign keys, most first
+}
+
+// Heat ratings of tables in a usage heatmap
+const (
+	HeatHot  = "hot"
+	HeatWarm = "warm"
+	HeatCold = "cold"
+)
+
+// TableUsage counts the scans and row changes of a table since statistics were reset
+type TableUsage struct {
+	Name       string
+	SeqScans   int64
+	IndexScans int64
+	Inserts    int64
+	Updates    int64
+	Deletes    int64
+	Rows       int64   // Estimated live rows
+	Bytes      int64   // Storage used by the table, its indexes and TOAST data
+	Score      float64 // Accesses relative to the busiest table on a logarithmic scale, from 0 to 1, set by analysis.RateUsage
+	Heat       string  // HeatHot, HeatWarm or HeatCold, set by analysis.RateUsage
+}
+
+// Accesses returns the scans and row changes of the table
+func (u TableUsage) Accesses() int64 {
+	return u.SeqScans + u.IndexScans + u.Inserts + u.Updates + u.Deletes
+}
+
+// SchemaUsage is the table usage of a schema over the statistics window
+type SchemaUsage struct {
+	Schema string
+	Since  time.Time    // Start of the statistics window, zero if unknown
+	Tables []TableUsage // By name, busiest first once rated
+}
+
+// Finding is an issue or suggestion reported by a schema analysis
+type Finding struct {
+	Check   string // Identifier of the check that produced the finding, e.g. "orphaned-sequence"
+	Object  string // Affected object, e.g. "orders_id_seq"
+	Message string // Human readable explanation
+	Bytes   int64  // Storage that could be reclaimed, if known
+}
+
+// APIToken is a token of the metadata API with the metadata it may read
+type APIToken struct {
+	Name    string   `json:"name"`              // Shown in logs, e.g. the team using the token
+	Token   string   `json:"token"`             // Secret sent as "Authorization: Bearer <token>", or as a Basic password
+	Schemas []string `json:"schemas,omitempty"` // Allowed schemas, all if empty
+	Tables  []string `json:"tables,omitempty"`  // Allowed table name patterns (path.Match syntax), all if empty
+}
+
+// AllowsSchema reports whether the token may read a schema
+func (tok *APIToken) AllowsSchema(schema string) bool {
+	return len(tok.Schemas) == 0 || slices.Contains(tok.Schemas, schema)
+}
+
+// AllowsTable reports whether the token may read a table of a schema
+func (tok *APIToken) AllowsTable(schema, table string) bool {
+	if !tok.AllowsSchema(schema) {
+		return false
+	}
+	if len(tok.Tables) == 0 {
+		return true
+	}
+	for _, pattern := range tok.Tables {
+		if ok, _ := path.Match(pattern, table); ok {
+			return true
+		}
+	}
+	return false
 }
 
 // DatabaseConnector defines the interface for database interactions
@@ -53,6 +396,201 @@ type DatabaseConnector interface {
 
 	// GetTableStructure returns the structure of the specified table
 	GetTableStructure(schema, tableName string) (*Table, error)
+
+	// GetViews returns the views and materialized views in the schema, which
+	// GetTables leaves out, with their columns and definitions, sorted by name.
+	// It returns ErrViewsNotSupported if the connector cannot list views.
+	GetViews(schema string) ([]View, error)
+}
+
+// ErrViewsNotSupported is returned by connectors that cannot list views
+var ErrViewsNotSupported = errors.New("listing views is not supported for this database")
+
+// QueryExecutor is implemented by connectors that can run ad-hoc queries
+type QueryExecutor interface {
+	// ExecuteQuery runs a single statement inside a read-only transaction,
+	// aborting it when the context is cancelled
+	ExecuteQuery(ctx context.Context, query string) (*QueryResult, error)
+}
+
+// CatalogQueryExecutor is implemented by connectors that can run parameterized read-only queries
+type CatalogQueryExecutor interface {
+	// ExecuteCatalogQuery runs a query with arguments inside a read-only transaction
+	ExecuteCatalogQuery(ctx context.Context, query string, args ...any) (*QueryResult, error)
+}
+
+// QueryExplainer is implemented by connectors that can show execution plans
+type QueryExplainer interface {
+	// ExplainQuery returns the estimated execution plan of a statement without running it
+	ExplainQuery(query string) (*PlanNode, error)
+}
+
+// RowCounter is implemented by connectors that can report table row counts
+type RowCounter interface {
+	// EstimateRowCount returns the statistics-based row estimate of a table,
+	// or -1 if no statistics are available
+	EstimateRowCount(schema, tableName string) (int64, error)
+
+	// CountRows returns the exact number of rows in a table
+	CountRows(ctx context.Context, schema, tableName string) (int64, error)
+}
+
+// TableSizeLister is implemented by connectors that can estimate the rows and
+// storage of all tables of a schema at once
+type TableSizeLister interface {
+	// GetTableSizes returns the estimated rows and size of every table in the
+	// schema. Rows is -1 when there is no estimate and Bytes 0 when unknown.
+	GetTableSizes(schema string) ([]TableSize, error)
+}
+
+// DDLProvider is implemented by connectors that can show the DDL of a table as
+// the database itself generates it
+type DDLProvider interface {
+	// GetTableDDL returns the statements creating a table and its indexes.
+	// Foreign keys given as ALTER TABLE statements come last, so scripts of
+	// several tables can add them once every table exists. ErrDDLNotSupported
+	// is returned when the database cannot show the DDL.
+	GetTableDDL(schema, tableName string) (string, error)
+}
+
+// ErrDDLNotSupported is returned by DDL providers for databases that cannot show
+// the DDL of their tables, e.g. Redshift through the PostgreSQL connector
+var ErrDDLNotSupported = errors.New("showing the DDL is not supported for this database")
+
+// RowSampler is implemented by connectors that can read example rows of a table
+type RowSampler interface {
+	// SampleRows returns up to limit rows of a table, read inside a read-only transaction
+	SampleRows(ctx context.Context, schema, tableName string, limit int) (*QueryResult, error)
+}
+
+// TableChecksummer is implemented by connectors that can fingerprint the contents of a table
+type TableChecksummer interface {
+	// ChecksumTable returns the row count of a table and a hash of its rows that
+	// does not depend on row order. Hashes are only comparable between databases
+	// of the same type and version.
+	ChecksumTable(ctx context.Context, schema, tableName string) (rows int64, hash string, err error)
+}
+
+// CommentEditor is implemented by connectors that can change table and column comments.
+// It is the only write operation, so callers must check that writes are enabled.
+type CommentEditor interface {
+	// SetTableComment replaces the comment of a table; an empty comment removes it
+	SetTableComment(schema, tableName, comment string) error
+
+	// SetColumnComment replaces the comment of a column; an empty comment removes it
+	SetColumnComment(schema, tableName, column, comment string) error
+}
+
+// ReplicationInspector is implemented by connectors that can tell primaries and replicas apart
+type ReplicationInspector interface {
+	// IsReplica reports whether the connected server is a read-only standby
+	IsReplica() (bool, error)
+}
+
+// SearchPathInspector is implemented by connectors with schema search path resolution
+type SearchPathInspector interface {
+	// SearchPath returns the schemas that unqualified names resolve against, in order
+	SearchPath() ([]string, error)
+}
+
+// SchemaLister is implemented by connectors that can list the schemas of the database
+type SchemaLister interface {
+	// GetSchemas returns the names of the schemas holding user tables
+	GetSchemas() ([]string, error)
+}
+
+// CatalogLister is implemented by connectors to engines federating several
+// catalogs, each holding its own schemas. The database names the catalog.
+type CatalogLister interface {
+	// GetCatalogs returns the names of the catalogs the server can query
+	GetCatalogs() ([]string, error)
+
+	// GetCatalogSchemas returns the names of the schemas in a catalog
+	GetCatalogSchemas(catalog string) ([]string, error)
+}
+
+// ExtensionInspector is implemented by connectors that know which objects belong to extensions
+type ExtensionInspector interface {
+	// GetExtensionTables returns the tables in the schema that are owned by an extension
+	GetExtensionTables(schema string) ([]string, error)
+}
+
+// QueryLogger is implemented by connectors that can report the catalog queries they run
+type QueryLogger interface {
+	// SetQueryLog sets a function called with every catalog query and its arguments.
+	// A nil function disables logging.
+	SetQueryLog(log func(query string, args []any))
+}
+
+// ViewInspector is implemented by connectors that can read view definitions
+type ViewInspector interface {
+	// GetViewDefinitions returns the SELECT statement of every view in the schema, by view name
+	GetViewDefinitions(schema string) (map[string]string, error)
+}
+
+// Sequence represents a sequence generating column values
+type Sequence struct {
+	Name      string
+	Schema    string
+	DataType  string
+	Current   sql.NullInt64 // Last value generated, invalid if none was or it cannot be read
+	Increment int64
+	Min       string // Bounds as the database reports them, as they may not fit an int64
+	Max       string
+	Cycle     bool   // Restarts from the other bound once one is reached
+	OwnedBy   string // "table.column" the sequence fills and is dropped with, "" if none
+}
+
+// Routine represents a stored function or procedure
+type Routine struct {
+	Name       string
+	Schema     string
+	Kind       string // FUNCTION, PROCEDURE, or a kind particular to the database such as AGGREGATE
+	Arguments  string // Argument list as declared, e.g. "id integer, OUT total numeric"
+	ReturnType string // "" for procedures
+	Language   string
+	Source     string // Body of the routine, "" if it cannot be read
+	Comment    string
+}
+
+// RoutineLister is implemented by connectors that can list the stored
+// functions and procedures of a schema
+type RoutineLister interface {
+	// GetRoutines returns the functions and procedures in the schema with
+	// their signatures and sources, sorted by name. Overloaded routines
+	// appear once per signature.
+	GetRoutines(schema string) ([]Routine, error)
+}
+
+// SequenceLister is implemented by connectors that can list the sequences of a schema
+type SequenceLister interface {
+	// GetSequences returns the sequences in the schema with their settings and
+	// owning columns, sorted by name
+	GetSequences(schema string) ([]Sequence, error)
+}
+
+// MigrationInspector is implemented by connectors that can read migration tool history tables
+type MigrationInspector interface {
+	// GetMigrationStatus returns the status of every migration tool detected in the schema
+	GetMigrationStatus(schema string) ([]MigrationStatus, error)
+}
+
+// StatsProvider is implemented by connectors that can summarize a schema
+type StatsProvider interface {
+	// GetSchemaStats returns object counts, sizes and foreign key statistics of a schema
+	GetSchemaStats(schema string) (*SchemaStats, error)
+}
+
+// UsageInspector is implemented by connectors that can read table access statistics
+type UsageInspector interface {
+	// GetTableUsage returns how much each table of a schema was used since statistics were reset
+	GetTableUsage(schema string) (*SchemaUsage, error)
+}
+
+// OrphanDetector is implemented by connectors that can find leftover objects
+type OrphanDetector interface {
+	// FindOrphanedObjects returns objects in the schema that are candidates for cleanup
+	FindOrphanedObjects(schema string) ([]Finding, error)
 }
 
 // DatabaseConnectorFactory is a function type that creates a specific DatabaseConnector
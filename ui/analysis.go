@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/analysis"
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/export"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// buildAnalysis creates the analysis tab listing cleanup candidates and lint findings
+func (di *DBInspector) buildAnalysis() fyne.CanvasObject {
+	di.analysisStatus = widget.NewLabel("Run the analysis to find cleanup candidates.")
+
+	runBtn := widget.NewButtonWithIcon("Run Analysis", theme.SearchIcon(), func() {
+		di.runAnalysis()
+	})
+
+	exportBtn := widget.NewButtonWithIcon("Export...", theme.DocumentSaveIcon(), func() {
+		di.showFindingsExportDialog()
+	})
+
+	di.findingList = widget.NewList(
+		func() int { return len(di.findings) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil,
+				widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				nil, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			finding := di.findings[id]
+			row := obj.(*fyne.Container)
+
+			message := finding.Message
+			if finding.Bytes > 0 {
+				message += fmt.Sprintf(" (%s)", export.FormatBytes(finding.Bytes))
+			}
+			row.Objects[0].(*widget.Label).SetText(message)
+			row.Objects[1].(*widget.Label).SetText(fmt.Sprintf("[%s] %s", finding.Check, finding.Object))
+		},
+	)
+
+	return container.NewBorder(
+		container.NewHBox(di.analysisStatus, layout.NewSpacer(), exportBtn, runBtn),
+		nil, nil, nil,
+		di.findingList,
+	)
+}
+
+// runAnalysis runs every analysis supported by the connector on the current schema
+func (di *DBInspector) runAnalysis() {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	var findings []t.Finding
+
+	if detector, ok := di.connector.(t.OrphanDetector); ok {
+		orphans, err := detector.FindOrphanedObjects(di.connParams.Schema)
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		findings = append(findings, orphans...)
+	}
+
+	tables, err := di.loadAllTables()
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+	findings = append(findings, analysis.RedundantIndexes(tables)...)
+
+	naming, err := analysis.CheckNaming(tables, di.config.NamingRules)
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+	findings = append(findings, naming...)
+
+	// Growth is only known from the sizes recorded by scheduled snapshots
+	if store, ok := di.snapshotStore(); ok {
+		snapshots, err := store.Load(config.SchemaKey(di.liveConnection().params))
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		findings = append(findings, analysis.ArchiveCandidates(snapshots)...)
+	}
+
+	di.findings = findings
+	di.findingList.Refresh()
+	di.analysisStatus.SetText(fmt.Sprintf("%d findings in schema %s", len(findings), di.connParams.Schema))
+}
+
+// showFindingsExportDialog saves the findings of the last analysis as a Markdown report
+func (di *DBInspector) showFindingsExportDialog() {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		di.auditLog(audit.ActionExport, "analysis report to "+writer.URI().String())
+		if err := export.WriteFindingsMarkdown(writer, di.connParams.Schema, di.findings); err != nil {
+			dialog.ShowError(fmt.Errorf("error writing report: %v", err), di.window)
+		}
+	}, di.window)
+	save.SetFileName(di.connParams.Schema + "-analysis.md")
+	save.Show()
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Orders the columns of the details view can be sorted in
+const (
+	sortOrdinal  = "Ordinal position"
+	sortName     = "Name"
+	sortType     = "Type"
+	sortNullable = "Nullability"
+)
+
+// sortColumns returns the columns in the given order. Columns comparing equal
+// keep their ordinal position, so sorting by type groups columns of a type in
+// table order, and sorting by nullability lists the NOT NULL columns first.
+func sortColumns(columns []t.Column, order string) []t.Column {
+	var compare func(a, b t.Column) int
+	switch order {
+	case sortName:
+		compare = func(a, b t.Column) int { return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) }
+	case sortType:
+		compare = func(a, b t.Column) int { return strings.Compare(strings.ToLower(a.Type), strings.ToLower(b.Type)) }
+	case sortNullable:
+		compare = func(a, b t.Column) int {
+			switch {
+			case a.Nullable == b.Nullable:
+				return 0
+			case b.Nullable:
+				return -1
+			default:
+				return 1
+			}
+		}
+	default:
+		return columns
+	}
+
+	sorted := slices.Clone(columns)
+	slices.SortStableFunc(sorted, compare)
+	return sorted
+}
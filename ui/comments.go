@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/audit"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// tableCommentTarget is the comment target choice for the table itself
+const tableCommentTarget = "(table)"
+
+// toggleWrites enables the features that modify the database after confirmation,
+// or disables them
+func (di *DBInspector) toggleWrites() {
+	if di.config.EnableWrites {
+		di.setWrites(false)
+		return
+	}
+
+	dialog.ShowConfirm("Enable write operations",
+		"Features such as editing comments will modify the connected databases.\nEnable write operations?",
+		func(ok bool) {
+			if ok {
+				di.setWrites(true)
+			}
+		}, di.window)
+}
+
+// setWrites saves and shows the write operations setting
+func (di *DBInspector) setWrites(enabled bool) {
+	di.config.EnableWrites = enabled
+	di.saveConfig()
+	di.showWritesSetting()
+}
+
+// showWritesSetting reflects the write operations setting in the menu
+func (di *DBInspector) showWritesSetting() {
+	di.writesMenuItem.Checked = di.config.EnableWrites
+	di.window.MainMenu().Refresh()
+}
+
+// showCommentEditor opens the editor of the table and column comments of the selected table
+func (di *DBInspector) showCommentEditor() {
+	if di.selectedTable == nil {
+		return
+	}
+
+	editor, ok := di.connector.(t.CommentEditor)
+	if !ok {
+		dialog.ShowError(fmt.Errorf("the current connector cannot edit comments"), di.window)
+		return
+	}
+	if !di.config.EnableWrites {
+		dialog.ShowError(fmt.Errorf("write operations are disabled, enable them in the Settings menu first"), di.window)
+		return
+	}
+
+	// Asking the server whether it is a replica may take a while
+	table := di.selectedTable
+	go func() {
+		if di.onReplica() {
+			dialog.ShowError(fmt.Errorf("connected to a read replica, make changes on the primary instead"), di.window)
+			return
+		}
+		di.editComments(editor, table)
+	}()
+}
+
+// editComments shows the dialog editing the comments of a table, and saves them in the background
+func (di *DBInspector) editComments(editor t.CommentEditor, table *t.Table) {
+	targets := []string{tableCommentTarget}
+	for _, col := range table.Columns {
+		targets = append(targets, col.Name)
+	}
+
+	commentEntry := widget.NewMultiLineEntry()
+	commentEntry.Wrapping = fyne.TextWrapWord
+	commentEntry.SetMinRowsVisible(4)
+	commentEntry.SetPlaceHolder("Empty to remove the comment")
+
+	targetSelect := widget.NewSelect(targets, func(target string) {
+		commentEntry.SetText(currentComment(table, target))
+	})
+	targetSelect.SetSelected(tableCommentTarget)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Comment on", targetSelect),
+		widget.NewFormItem("Comment", commentEntry),
+	)
+
+	dialog.ShowCustomConfirm(fmt.Sprintf("Edit comments of %s", table.Name), "Save", "Close",
+		container.NewPadded(form), func(save bool) {
+			if !save {
+				return
+			}
+
+			target, comment := targetSelect.Selected, commentEntry.Text
+			object := fmt.Sprintf("column %s.%s.%s", table.Schema, table.Name, target)
+			if target == tableCommentTarget {
+				object = fmt.Sprintf("table %s.%s", table.Schema, table.Name)
+			}
+			di.confirmProductionWrite("Commenting on "+object, func() {
+				di.auditLog(audit.ActionWrite, "comment on "+object)
+				go func() {
+					var err error
+					if target == tableCommentTarget {
+						err = editor.SetTableComment(table.Schema, table.Name, comment)
+					} else {
+						err = editor.SetColumnComment(table.Schema, table.Name, target, comment)
+					}
+					if err != nil {
+						dialog.ShowError(fmt.Errorf("error commenting on %s: %v", object, err), di.window)
+						return
+					}
+
+					// Show the stored comment, unless another table was selected meanwhile
+					di.forgetStructure(table.Name)
+					if di.selectedTable == table {
+						di.loadTableDetails(table.Name)
+					}
+				}()
+			})
+		}, di.window)
+}
+
+// onReplica reports whether the connection is to a read replica, as its profile
+// is tagged or as the server reports
+func (di *DBInspector) onReplica() bool {
+	if profile, ok := di.config.Profile(di.profileName); ok && profile.Replica {
+		return true
+	}
+	inspector, ok := di.connector.(t.ReplicationInspector)
+	if !ok {
+		return false
+	}
+	replica, err := inspector.IsReplica()
+	return err == nil && replica
+}
+
+// currentComment returns the comment of the table or of one of its columns
+func currentComment(table *t.Table, target string) string {
+	if target == tableCommentTarget {
+		return table.Comment
+	}
+	for _, col := range table.Columns {
+		if col.Name == target {
+			return col.Comment
+		}
+	}
+	return ""
+}
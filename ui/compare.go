@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/diff"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// snapshotOption prefixes the stored snapshots among the sources a table can be compared with
+const snapshotOption = "Snapshot of "
+
+// showTableMenu shows the context menu of a table of the table list
+func (di *DBInspector) showTableMenu(table string, pos fyne.Position) {
+	menu := fyne.NewMenu("",
+		fyne.NewMenuItem("Compare with...", func() {
+			di.showTableCompareDialog(table)
+		}),
+	)
+	widget.ShowPopUpMenuAtPosition(menu, di.window.Canvas(), pos)
+}
+
+// showTableCompareDialog asks for a saved profile or a snapshot of the schema,
+// then shows how the table differs there from the connected database
+func (di *DBInspector) showTableCompareDialog(table string) {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	options, profiles := di.profileChoices()
+	snapshots := make(map[string]string)
+	if store, ok := di.snapshotStore(); ok {
+		entries, err := store.Entries(config.SchemaKey(di.liveConnection().params))
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		// Newest first
+		for i := len(entries) - 1; i >= 0; i-- {
+			option := snapshotOption + entries[i].Taken.Local().Format("2006-01-02 15:04:05")
+			options = append(options, option)
+			snapshots[option] = entries[i].Path
+		}
+	}
+	if len(options) == 0 {
+		dialog.ShowInformation("Compare "+table,
+			"Save a connection profile or enable snapshots in the Settings menu to compare tables.", di.window)
+		return
+	}
+
+	sourceSelect := widget.NewSelect(options, nil)
+	sourceSelect.SetSelected(options[0])
+
+	items := []*widget.FormItem{{Text: "Compare with", Widget: sourceSelect}}
+	dialog.ShowForm("Compare "+table, "Compare", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		current, err := di.connector.GetTableStructure(di.connParams.Schema, table)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error loading table details: %v", err), di.window)
+			return
+		}
+
+		source := sourceSelect.Selected
+		var other *t.Table
+		if path, ok := snapshots[source]; ok {
+			other, err = snapshotTable(path, current)
+		} else {
+			other, err = di.profileTable(profiles[source], table)
+		}
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+
+		di.showTableDiff(current, source, other)
+	}, di.window)
+}
+
+// snapshotTable returns the version of a table in a stored snapshot, or nil if
+// the snapshot does not have it
+func snapshotTable(path string, table *t.Table) (*t.Table, error) {
+	snapshot, err := diff.ReadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *t.Table
+	for _, candidate := range snapshot.Tables {
+		if candidate.Name != table.Name {
+			continue
+		}
+		// Snapshots spanning several schemas may have the name more than once
+		if found == nil || candidate.Schema == table.Schema {
+			found = candidate
+		}
+	}
+	return found, nil
+}
+
+// profileTable connects to the database of a saved profile and returns the
+// table with the given name in its schema, or nil if there is none
+func (di *DBInspector) profileTable(name, table string) (*t.Table, error) {
+	profile, ok := di.config.Profile(name)
+	if !ok {
+		return nil, fmt.Errorf("profile '%s' not found", name)
+	}
+
+	params := profile.Params
+	password, err := di.config.ProfilePassword(profile, di.sealer)
+	if err != nil {
+		return nil, err
+	}
+	params.Password = password
+	if params, err = config.ExpandEnv(params); err != nil {
+		return nil, err
+	}
+	if params, err = config.ResolveSecret(context.Background(), params); err != nil {
+		return nil, err
+	}
+
+	connector, err := t.NewConnector(params.Driver)
+	if err != nil {
+		return nil, err
+	}
+	if err := connector.Connect(params); err != nil {
+		return nil, fmt.Errorf("error connecting to profile '%s': %v", name, err)
+	}
+	defer connector.Disconnect()
+
+	tables, err := connector.GetTables(params.Schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range tables {
+		if candidate == table {
+			return connector.GetTableStructure(params.Schema, table)
+		}
+	}
+	return nil, nil
+}
+
+// showTableDiff shows the differences of a table from its version in another
+// database or snapshot, which is nil when the table is missing there
+func (di *DBInspector) showTableDiff(table *t.Table, source string, other *t.Table) {
+	current := &diff.Schema{Name: di.connParams.Database, Tables: []*t.Table{table}}
+	compared := &diff.Schema{Name: source}
+	if other != nil {
+		compared.Tables = []*t.Table{other}
+	}
+	changes := diff.Compare(current, compared, diff.Ignore{})
+
+	var summary string
+	switch {
+	case other == nil:
+		summary = fmt.Sprintf("%s does not exist in %s.", table.Name, source)
+	case len(changes) == 0:
+		summary = fmt.Sprintf("%s is identical in %s.", table.Name, source)
+	default:
+		summary = fmt.Sprintf("%d differences from %s; - only here, + only there, ~ changed here -> there.", len(changes), source)
+	}
+
+	lines := make([]string, len(changes))
+	for i, change := range changes {
+		lines[i] = change.String()
+	}
+	text := widget.NewLabel(strings.Join(lines, "\n"))
+	text.TextStyle = fyne.TextStyle{Monospace: true}
+
+	content := container.NewBorder(widget.NewLabel(summary), nil, nil, nil, container.NewScroll(text))
+	d := dialog.NewCustom(fmt.Sprintf("%s compared with %s", table.Name, source), "Close", content, di.window)
+	d.Resize(fyne.NewSize(700, 400))
+	d.Show()
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/export"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// buildDDL creates the DDL tab, showing the statements creating the selected table
+func (di *DBInspector) buildDDL() fyne.CanvasObject {
+	di.ddlText = widget.NewTextGrid()
+	di.ddlSource = widget.NewLabel("Select a table to show its DDL.")
+
+	copyBtn := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		di.window.Clipboard().SetContent(di.ddlText.Text())
+	})
+
+	return container.NewBorder(
+		container.NewBorder(nil, nil, nil, copyBtn, di.ddlSource),
+		nil, nil, nil,
+		container.NewScroll(di.ddlText),
+	)
+}
+
+// showTableDDL shows the DDL of a table: the database's own definition when the
+// connector can generate it, otherwise one reconstructed from the table structure
+func (di *DBInspector) showTableDDL(table *t.Table) {
+	if provider, ok := di.connector.(t.DDLProvider); ok {
+		ddl, err := provider.GetTableDDL(table.Schema, table.Name)
+		if err == nil {
+			di.ddlSource.SetText(fmt.Sprintf("DDL of %s as generated by the database", table.Name))
+			di.ddlText.SetText(ddl)
+			return
+		}
+		di.ddlSource.SetText(fmt.Sprintf("DDL of %s reconstructed from its structure (%v)", table.Name, err))
+	} else {
+		di.ddlSource.SetText(fmt.Sprintf("DDL of %s reconstructed from its structure", table.Name))
+	}
+
+	var sb strings.Builder
+	if err := export.WriteDDL(&sb, []*t.Table{table}); err != nil {
+		di.ddlText.SetText(fmt.Sprintf("Error writing DDL: %v", err))
+		return
+	}
+	di.ddlText.SetText(sb.String())
+}
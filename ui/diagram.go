@@ -0,0 +1,350 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/diagram"
+	"github.com/carloberd/db-reader/export"
+	"github.com/carloberd/db-reader/filter"
+	"github.com/carloberd/db-reader/render"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Diagram scope modes
+const (
+	scopeAll        = "All tables"
+	scopeSelected   = "Selected tables"
+	scopeNeighbours = "Selected table and neighbours"
+)
+
+// tableNode is a box showing a table and its columns in the ER diagram.
+// Nodes can be dragged to arrange the diagram by hand.
+type tableNode struct {
+	widget.BaseWidget
+	table     *t.Table
+	onDragged func()
+	onDragEnd func()
+}
+
+// newTableNode creates a diagram node for a table
+func newTableNode(table *t.Table) *tableNode {
+	node := &tableNode{table: table}
+	node.ExtendBaseWidget(node)
+	return node
+}
+
+// CreateRenderer implements fyne.Widget
+func (n *tableNode) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(render.NewTableBox(n.table))
+}
+
+// box returns the area the node takes in the diagram
+func (n *tableNode) box() diagram.Box {
+	pos, size := n.Position(), n.Size()
+	return diagram.Box{X: pos.X, Y: pos.Y, Width: size.Width, Height: size.Height}
+}
+
+// Dragged implements fyne.Draggable, keeping the node inside the diagram area
+func (n *tableNode) Dragged(ev *fyne.DragEvent) {
+	pos := n.Position().Add(ev.Dragged)
+	n.Move(fyne.NewPos(fyne.Max(pos.X, 0), fyne.Max(pos.Y, 0)))
+	if n.onDragged != nil {
+		n.onDragged()
+	}
+}
+
+// DragEnd implements fyne.Draggable
+func (n *tableNode) DragEnd() {
+	if n.onDragEnd != nil {
+		n.onDragEnd()
+	}
+}
+
+// diagramEdge is a drawn foreign key line between two diagram nodes
+type diagramEdge struct {
+	line     *canvas.Line
+	from, to *tableNode
+}
+
+// buildDiagram creates the ER diagram tab with its scoping controls
+func (di *DBInspector) buildDiagram() fyne.CanvasObject {
+	di.diagramTableChecks = newCheckList(nil)
+	checksPanel := container.NewStack(di.diagramTableChecks)
+	checksPanel.Hide()
+
+	di.diagramScope = widget.NewSelect([]string{scopeAll, scopeSelected, scopeNeighbours}, func(mode string) {
+		if mode == scopeSelected {
+			checksPanel.Show()
+		} else {
+			checksPanel.Hide()
+		}
+	})
+	di.diagramScope.SetSelected(scopeAll)
+
+	di.diagramDepth = widget.NewSelect([]string{"1", "2", "3", "4", "5"}, nil)
+	di.diagramDepth.SetSelected("1")
+
+	di.diagramExclude = widget.NewEntry()
+	di.diagramExclude.SetPlaceHolder("Exclude, e.g. audit_*, tmp_*")
+
+	refreshBtn := widget.NewButtonWithIcon("Draw", theme.ViewRefreshIcon(), func() {
+		di.refreshDiagram()
+	})
+
+	resetBtn := widget.NewButton("Reset Layout", func() {
+		di.resetDiagramLayout()
+	})
+
+	exportBtn := widget.NewButtonWithIcon("Export Mermaid...", theme.DocumentSaveIcon(), func() {
+		di.showMermaidExportDialog()
+	})
+
+	controls := container.NewBorder(nil, nil,
+		container.NewHBox(di.diagramScope, widget.NewLabel("Levels:"), di.diagramDepth),
+		container.NewHBox(refreshBtn, resetBtn, exportBtn),
+		di.diagramExclude,
+	)
+
+	di.diagramCanvas = container.NewWithoutLayout()
+	di.diagramSize = canvas.NewRectangle(nil)
+
+	return container.NewBorder(controls, nil, checksPanel, nil,
+		container.NewScroll(container.NewStack(di.diagramSize, di.diagramCanvas)))
+}
+
+// updateDiagramTables refreshes the table choices of the diagram scope
+func (di *DBInspector) updateDiagramTables() {
+	di.diagramTableChecks.SetOptions(di.tables)
+}
+
+// currentDiagramScope builds the diagram scope from the controls
+func (di *DBInspector) currentDiagramScope() (diagram.Scope, error) {
+	scope := diagram.Scope{
+		Exclude: filter.ParseList(di.diagramExclude.Text),
+	}
+
+	switch di.diagramScope.Selected {
+	case scopeSelected:
+		scope.Tables = di.diagramTableChecks.Selected()
+		if len(scope.Tables) == 0 {
+			return scope, fmt.Errorf("no tables selected for the diagram")
+		}
+	case scopeNeighbours:
+		if di.selectedTable == nil {
+			return scope, fmt.Errorf("select a table in the table list first")
+		}
+		scope.Focus = di.selectedTable.Name
+		scope.Depth, _ = strconv.Atoi(di.diagramDepth.Selected)
+	}
+
+	return scope, nil
+}
+
+// loadDiagramTables loads the tables within the current diagram scope
+func (di *DBInspector) loadDiagramTables() ([]*t.Table, diagram.Scope, error) {
+	if di.connInfo == nil {
+		return nil, diagram.Scope{}, fmt.Errorf("not connected to database")
+	}
+
+	scope, err := di.currentDiagramScope()
+	if err != nil {
+		return nil, scope, err
+	}
+
+	// Only the selected tables are fetched, rather than the whole schema
+	var tables []*t.Table
+	if len(scope.Tables) > 0 {
+		tables, err = di.loadTables(scope.Tables)
+	} else {
+		tables, err = di.loadAllTables()
+	}
+	if err != nil {
+		return nil, scope, err
+	}
+
+	tables, err = scope.Apply(tables)
+	return tables, scope, err
+}
+
+// refreshDiagram draws the ER diagram of the tables within the current scope
+func (di *DBInspector) refreshDiagram() {
+	tables, scope, err := di.loadDiagramTables()
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+
+	di.diagramCanvas.RemoveAll()
+
+	key := config.SchemaKey(di.connParams)
+	nodes, edges := layoutDiagram(tables, scope.Focus, di.config.Layout(key))
+	di.diagramEdges = edges
+
+	for _, node := range nodes {
+		node.onDragged = di.updateDiagramEdges
+		node.onDragEnd = func() {
+			pos := node.Position()
+			di.config.SetPosition(key, node.table.Name, config.Position{X: pos.X, Y: pos.Y})
+			di.saveConfig()
+			di.updateDiagramSize()
+		}
+	}
+
+	// Lines go below the nodes
+	for _, e := range edges {
+		di.diagramCanvas.Add(e.line)
+	}
+	for _, table := range tables {
+		di.diagramCanvas.Add(nodes[table.Name])
+	}
+
+	di.updateDiagramEdges()
+	di.updateDiagramSize()
+}
+
+// layoutDiagram creates and positions the nodes and foreign key lines of a diagram
+func layoutDiagram(tables []*t.Table, focus string, positions map[string]config.Position) (map[string]*tableNode, []diagramEdge) {
+	nodes := make(map[string]*tableNode, len(tables))
+	sizes := make(map[string]diagram.Point, len(tables))
+	for _, table := range tables {
+		node := newTableNode(table)
+		size := node.MinSize()
+		nodes[table.Name], sizes[table.Name] = node, diagram.Point{X: size.Width, Y: size.Height}
+	}
+
+	for name, box := range diagram.Layout(tables, focus, sizes, positions, theme.Padding()) {
+		nodes[name].Move(fyne.NewPos(box.X, box.Y))
+		nodes[name].Resize(fyne.NewSize(box.Width, box.Height))
+	}
+
+	var edges []diagramEdge
+	for _, edge := range diagram.Edges(tables) {
+		from, to := nodes[edge.From], nodes[edge.To]
+		start, end := diagram.Endpoints(from.box(), to.box())
+		edges = append(edges, diagramEdge{line: render.NewEdgeLine(start, end), from: from, to: to})
+	}
+
+	return nodes, edges
+}
+
+// updateDiagramEdges moves the foreign key lines to follow their nodes
+func (di *DBInspector) updateDiagramEdges() {
+	for _, e := range di.diagramEdges {
+		start, end := diagram.Endpoints(e.from.box(), e.to.box())
+		render.MoveEdgeLine(e.line, start, end)
+		e.line.Refresh()
+	}
+}
+
+// updateDiagramSize grows the scrollable diagram area to fit all nodes
+func (di *DBInspector) updateDiagramSize() {
+	di.diagramSize.SetMinSize(diagramExtent(di.diagramCanvas.Objects))
+	di.diagramCanvas.Refresh()
+}
+
+// diagramExtent returns the size needed to show all nodes among the diagram objects
+func diagramExtent(objects []fyne.CanvasObject) fyne.Size {
+	var boxes []diagram.Box
+	for _, obj := range objects {
+		if node, ok := obj.(*tableNode); ok {
+			boxes = append(boxes, node.box())
+		}
+	}
+	extent := diagram.Extent(boxes)
+	return fyne.NewSize(extent.X, extent.Y)
+}
+
+// resetDiagramLayout forgets the manually arranged positions and redraws the diagram
+func (di *DBInspector) resetDiagramLayout() {
+	if di.connInfo == nil {
+		return
+	}
+
+	di.config.ResetLayout(config.SchemaKey(di.connParams))
+	di.saveConfig()
+	di.refreshDiagram()
+}
+
+// showMermaidExportDialog saves the diagram within the current scope as a Mermaid file
+func (di *DBInspector) showMermaidExportDialog() {
+	tables, _, err := di.loadDiagramTables()
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		di.auditLog(audit.ActionExport, fmt.Sprintf("Mermaid diagram of %d tables to %s", len(tables), writer.URI()))
+		if err := diagram.WriteMermaid(writer, tables); err != nil {
+			dialog.ShowError(fmt.Errorf("error writing diagram: %v", err), di.window)
+		}
+	}, di.window)
+	save.SetFileName(di.connParams.Schema + ".mmd")
+	save.Show()
+}
+
+// showDOTExportDialog saves the diagram within the current scope as a Graphviz DOT file
+func (di *DBInspector) showDOTExportDialog() {
+	tables, _, err := di.loadDiagramTables()
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+
+	clusterCheck := widget.NewCheck("Group tables by schema", nil)
+	groupEntry := widget.NewEntry()
+	groupEntry.SetPlaceHolder("auth=users|sessions,prefix")
+	items := []*widget.FormItem{
+		{Text: "", Widget: clusterCheck},
+		{Text: "Modules", Widget: groupEntry, HintText: "name=patterns, and prefix to group the other tables by name prefix"},
+	}
+
+	dialog.ShowForm("Export Diagram (DOT)", "Save...", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		grouping, err := export.ParseGrouping(groupEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		opts := diagram.DOTOptions{ClusterBySchema: clusterCheck.Checked, Grouping: grouping}
+
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			di.auditLog(audit.ActionExport, fmt.Sprintf("DOT diagram of %d tables to %s", len(tables), writer.URI()))
+			if err := diagram.WriteDOT(writer, tables, opts); err != nil {
+				dialog.ShowError(fmt.Errorf("error writing diagram: %v", err), di.window)
+			}
+		}, di.window)
+		save.SetFileName(di.connParams.Schema + ".dot")
+		save.Show()
+	}, di.window)
+}
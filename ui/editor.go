@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// maxColumnWidth caps the initial width of a result column
+const maxColumnWidth = 300
+
+// buildQueryEditor creates the SQL editor with its result tabs
+func (di *DBInspector) buildQueryEditor() fyne.CanvasObject {
+	di.queryInput = widget.NewMultiLineEntry()
+	di.queryInput.SetPlaceHolder("SELECT ... ; SELECT ...")
+	di.queryInput.TextStyle = fyne.TextStyle{Monospace: true}
+
+	di.runBtn = widget.NewButtonWithIcon("Run", theme.MediaPlayIcon(), func() {
+		if cancel := di.runningQuery(); cancel != nil {
+			cancel()
+			di.queryStatus.SetText("Cancelling...")
+			return
+		}
+		di.runQueries()
+	})
+
+	di.queryStatus = widget.NewLabel("")
+
+	explainBtn := widget.NewButtonWithIcon("Explain", theme.InfoIcon(), func() {
+		di.explainQueries()
+	})
+
+	di.savedQuerySelect = widget.NewSelect(nil, di.loadSavedQuery)
+	di.savedQuerySelect.PlaceHolder = "Saved queries"
+
+	saveBtn := widget.NewButtonWithIcon("Save...", theme.DocumentSaveIcon(), func() {
+		di.showSaveQueryDialog()
+	})
+
+	di.resultTabs = container.NewAppTabs()
+
+	editor := container.NewBorder(
+		nil,
+		container.NewHBox(di.savedQuerySelect, saveBtn, di.queryStatus, layout.NewSpacer(), explainBtn, di.runBtn),
+		nil, nil,
+		di.queryInput,
+	)
+
+	split := container.NewVSplit(editor, di.resultTabs)
+	split.SetOffset(0.35)
+
+	return split
+}
+
+// runQueries splits the editor content into statements and executes them in order
+func (di *DBInspector) runQueries() {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	executor, ok := di.connector.(t.QueryExecutor)
+	if !ok {
+		dialog.ShowError(fmt.Errorf("the current connector does not support queries"), di.window)
+		return
+	}
+
+	statements := sqlutil.SplitStatements(di.queryInput.Text)
+	if len(statements) == 0 {
+		return
+	}
+
+	// Refuse the whole script if any statement could modify data
+	for _, stmt := range statements {
+		if !sqlutil.IsReadOnly(stmt) {
+			dialog.ShowError(fmt.Errorf("only read-only statements are allowed: %s", summarizeStatement(stmt)), di.window)
+			return
+		}
+	}
+
+	di.confirmProduction("Running the script", func() {
+		di.resultTabs.SetItems(nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		di.cancelQueryMu.Lock()
+		di.cancelQuery = cancel
+		di.cancelQueryMu.Unlock()
+		di.runBtn.SetText("Cancel")
+		di.runBtn.SetIcon(theme.MediaStopIcon())
+
+		go di.executeStatements(ctx, executor, statements)
+	})
+}
+
+// executeStatements runs statements in order in the background, showing the
+// elapsed time until all of them have completed or the run is cancelled
+func (di *DBInspector) executeStatements(ctx context.Context, executor t.QueryExecutor, statements []string) {
+	start := time.Now()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	stopTicker := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-stopTicker:
+				return
+			case <-ticker.C:
+				di.queryStatus.SetText(fmt.Sprintf("Running... %s", formatElapsed(time.Since(start))))
+			}
+		}
+	}()
+
+	for i, stmt := range statements {
+		title := fmt.Sprintf("Result %d", i+1)
+
+		di.auditLog(audit.ActionQuery, stmt)
+		result, err := executor.ExecuteQuery(ctx, stmt)
+		if err != nil {
+			di.resultTabs.Append(container.NewTabItemWithIcon(title, theme.ErrorIcon(),
+				newStatementError(stmt, err)))
+			// Later statements usually depend on earlier ones, so stop here
+			break
+		}
+
+		di.resultTabs.Append(container.NewTabItem(title, newResultView(result)))
+	}
+
+	ticker.Stop()
+	close(stopTicker)
+
+	status := fmt.Sprintf("Finished in %s", formatElapsed(time.Since(start)))
+	if ctx.Err() != nil {
+		status = fmt.Sprintf("Cancelled after %s", formatElapsed(time.Since(start)))
+	}
+	di.queryStatus.SetText(status)
+
+	di.cancelQueryMu.Lock()
+	cancel := di.cancelQuery
+	di.cancelQuery = nil
+	di.cancelQueryMu.Unlock()
+	cancel()
+
+	di.runBtn.SetText("Run")
+	di.runBtn.SetIcon(theme.MediaPlayIcon())
+
+	if len(di.resultTabs.Items) > 0 {
+		di.resultTabs.SelectIndex(0)
+	}
+}
+
+// runningQuery returns the function cancelling the running editor queries, nil if none are running
+func (di *DBInspector) runningQuery() context.CancelFunc {
+	di.cancelQueryMu.Lock()
+	defer di.cancelQueryMu.Unlock()
+	return di.cancelQuery
+}
+
+// formatElapsed formats a duration for the query status line
+func formatElapsed(d time.Duration) string {
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// newResultView creates the content of a result tab
+func newResultView(result *t.QueryResult) fyne.CanvasObject {
+	summary := fmt.Sprintf("%d rows", len(result.Rows))
+	if result.Truncated {
+		summary = fmt.Sprintf("First %d rows (result truncated)", len(result.Rows))
+	}
+
+	header := container.NewVBox(
+		widget.NewLabelWithStyle(summarizeStatement(result.Statement), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}),
+		widget.NewLabel(summary),
+	)
+
+	return container.NewBorder(header, nil, nil, nil, newResultTable(result))
+}
+
+// newResultTable creates a table widget showing a query result set
+func newResultTable(result *t.QueryResult) *widget.Table {
+	table := widget.NewTable(
+		func() (int, int) { return len(result.Rows), len(result.Columns) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(result.Rows[id.Row][id.Col])
+		},
+	)
+
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	}
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		obj.(*widget.Label).SetText(result.Columns[id.Col])
+	}
+
+	// Size columns to fit their content, within limits
+	for col, name := range result.Columns {
+		width := widget.NewLabel(name).MinSize().Width
+		for _, row := range result.Rows {
+			width = fyne.Max(width, widget.NewLabel(row[col]).MinSize().Width)
+			if width >= maxColumnWidth {
+				width = maxColumnWidth
+				break
+			}
+		}
+		table.SetColumnWidth(col, width)
+	}
+
+	return table
+}
+
+// newStatementError creates the content of a result tab for a failed statement
+func newStatementError(stmt string, err error) fyne.CanvasObject {
+	message := widget.NewLabel(err.Error())
+	message.Wrapping = fyne.TextWrapWord
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(summarizeStatement(stmt), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}),
+		message,
+	)
+}
+
+// summarizeStatement returns the first line of a statement, shortened for display
+func summarizeStatement(stmt string) string {
+	line := strings.TrimSpace(strings.SplitN(stmt, "\n", 2)[0])
+	if len(line) > 80 {
+		line = line[:77] + "..."
+	}
+	return line
+}
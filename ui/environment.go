@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+
+	"github.com/carloberd/db-reader/config"
+)
+
+// environmentColors are the banner colors of the environment tags
+var environmentColors = map[string]color.Color{
+	config.EnvironmentDev:     color.NRGBA{R: 0x2e, G: 0x7d, B: 0x32, A: 0xff},
+	config.EnvironmentStaging: color.NRGBA{R: 0xef, G: 0x6c, B: 0x00, A: 0xff},
+	config.EnvironmentProd:    color.NRGBA{R: 0xc6, G: 0x28, B: 0x28, A: 0xff},
+}
+
+// noEnvironment is the environment choice of untagged profiles
+const noEnvironment = "(none)"
+
+// buildEnvironmentBanner creates the colored banner showing the environment of the connection
+func (di *DBInspector) buildEnvironmentBanner() fyne.CanvasObject {
+	di.environmentBg = canvas.NewRectangle(color.Transparent)
+	di.environmentText = canvas.NewText("", color.White)
+	di.environmentText.TextStyle = fyne.TextStyle{Bold: true}
+	di.environmentText.Alignment = fyne.TextAlignCenter
+
+	di.environmentBanner = container.NewStack(di.environmentBg, container.NewPadded(di.environmentText))
+	di.environmentBanner.Hide()
+	return di.environmentBanner
+}
+
+// showEnvironment colors the banner and window title for the environment of the connection
+func (di *DBInspector) showEnvironment() {
+	bg, ok := environmentColors[di.environment]
+	if !ok {
+		di.environmentBanner.Hide()
+		di.window.SetTitle(di.workspaceTitle())
+		return
+	}
+
+	label := strings.ToUpper(di.environment)
+	if di.environment == config.EnvironmentProd {
+		label = "PRODUCTION – data queries require confirmation"
+	}
+	di.environmentBg.FillColor = bg
+	di.environmentBg.Refresh()
+	di.environmentText.Text = label
+	di.environmentText.Refresh()
+	di.environmentBanner.Show()
+	di.window.SetTitle(fmt.Sprintf("[%s] %s", strings.ToUpper(di.environment), di.workspaceTitle()))
+}
+
+// confirmProduction runs a data query right away, or after confirmation when
+// connected to a prod-tagged profile
+func (di *DBInspector) confirmProduction(action string, run func()) {
+	di.confirmOnProduction(fmt.Sprintf("%s will query its data.", action), run)
+}
+
+// confirmProductionWrite runs a change to the database right away, or after
+// confirmation when connected to a prod-tagged profile
+func (di *DBInspector) confirmProductionWrite(action string, run func()) {
+	di.confirmOnProduction(fmt.Sprintf("%s will modify it.", action), run)
+}
+
+// confirmOnProduction runs a function right away, or after confirming what it
+// does when connected to a prod-tagged profile
+func (di *DBInspector) confirmOnProduction(consequence string, run func()) {
+	if di.environment != config.EnvironmentProd {
+		run()
+		return
+	}
+
+	message := fmt.Sprintf("You are connected to the production database %s.\n%s\nContinue?",
+		di.connParams.Database, consequence)
+	dialog.ShowConfirm("Production database", message, func(ok bool) {
+		if ok {
+			run()
+		}
+	}, di.window)
+}
+
+// environmentChoices returns the options of the environment select
+func environmentChoices() []string {
+	return append([]string{noEnvironment}, config.Environments...)
+}
+
+// profileChoices returns the options of the profile select, grouped by
+// environment, and the profile name of every option
+func (di *DBInspector) profileChoices() ([]string, map[string]string) {
+	order, groups := di.config.ProfilesByEnvironment()
+
+	var options []string
+	names := make(map[string]string)
+	for _, env := range order {
+		for _, profile := range groups[env] {
+			option := profile.Name
+			if env != "" {
+				option = fmt.Sprintf("[%s] %s", env, profile.Name)
+			}
+			options = append(options, option)
+			names[option] = profile.Name
+		}
+	}
+	return options, names
+}
@@ -0,0 +1,403 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/analysis"
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/export"
+	"github.com/carloberd/db-reader/filter"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// loadAllTables fetches the structure of every table shown in the table list
+func (di *DBInspector) loadAllTables() ([]*t.Table, error) {
+	return di.loadTables(di.tables)
+}
+
+// loadWorkers is the number of tables loaded at the same time for exports
+const loadWorkers = 4
+
+// loadTables fetches the structure of the given tables, several at a time
+func (di *DBInspector) loadTables(names []string) ([]*t.Table, error) {
+	connector, schema := di.connector, di.connParams.Schema
+	return export.LoadTables(func(name string) (*t.Table, error) {
+		return connector.GetTableStructure(schema, name)
+	}, names, loadWorkers)
+}
+
+// newTableChooser creates a checkbox list of the tables in the table list, all
+// checked, with a pattern entry to check matching tables. The returned function
+// yields the checked tables in list order.
+func (di *DBInspector) newTableChooser() (fyne.CanvasObject, func() []string) {
+	checks := newCheckList(di.tables)
+	checks.SetSelected(di.tables)
+
+	matchEntry := widget.NewEntry()
+	matchEntry.SetPlaceHolder("Pattern, e.g. order_*, invoice*")
+	matchBtn := widget.NewButton("Check Matching", func() {
+		selected, err := filter.Selection{Match: filter.ParseList(matchEntry.Text)}.Apply(di.tables)
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		checks.SetSelected(selected)
+	})
+
+	allBtn := widget.NewButton("All", func() { checks.SetSelected(di.tables) })
+	noneBtn := widget.NewButton("None", func() { checks.SetSelected(nil) })
+
+	list := container.NewGridWrap(fyne.NewSize(300, 250), checks)
+
+	chooser := container.NewBorder(
+		container.NewBorder(nil, nil, nil, container.NewHBox(matchBtn, allBtn, noneBtn), matchEntry),
+		nil, nil, nil,
+		list,
+	)
+
+	return chooser, checks.Selected
+}
+
+// showBaselineExportDialog asks for a migration style and folder, then writes a baseline migration
+func (di *DBInspector) showBaselineExportDialog() {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	var styles []string
+	for _, style := range export.MigrationStyles {
+		styles = append(styles, string(style))
+	}
+	styleSelect := widget.NewSelect(styles, nil)
+	styleSelect.SetSelected(styles[0])
+
+	chooser, selectedTables := di.newTableChooser()
+
+	items := []*widget.FormItem{
+		{Text: "Migration tool", Widget: styleSelect},
+		{Text: "Tables", Widget: chooser},
+	}
+
+	dialog.ShowForm("Export Baseline Migration", "Choose Folder...", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		names := selectedTables()
+		if len(names) == 0 {
+			dialog.ShowError(fmt.Errorf("no tables selected"), di.window)
+			return
+		}
+
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+			if dir == nil {
+				return
+			}
+
+			tables, err := di.loadTables(names)
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+
+			style := styleSelect.Selected
+			di.auditLog(audit.ActionExport, fmt.Sprintf("%s baseline of %d tables to %s", style, len(tables), dir.Path()))
+			files, err := di.writeSchemaFiles(dir.Path(), "baseline", tables, map[string]string{"style": style})
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+
+			dialog.ShowInformation("Baseline exported",
+				fmt.Sprintf("Wrote %d tables to:\n%s", len(tables), strings.Join(files, "\n")),
+				di.window)
+		}, di.window)
+	}, di.window)
+}
+
+// showHTMLReportDialog asks for the tables and a folder, then writes an HTML report of them
+func (di *DBInspector) showHTMLReportDialog() {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	chooser, selectedTables := di.newTableChooser()
+	items := []*widget.FormItem{{Text: "Tables", Widget: chooser}}
+
+	dialog.ShowForm("Export HTML Report", "Choose Folder...", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		names := selectedTables()
+		if len(names) == 0 {
+			dialog.ShowError(fmt.Errorf("no tables selected"), di.window)
+			return
+		}
+
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+			if dir == nil {
+				return
+			}
+
+			tables, err := di.loadTables(names)
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+
+			di.auditLog(audit.ActionExport, fmt.Sprintf("HTML report of %d tables to %s", len(tables), dir.Path()))
+			files, err := di.writeSchemaFiles(dir.Path(), "html", tables, nil)
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+
+			dialog.ShowInformation("HTML report exported",
+				fmt.Sprintf("Wrote %d pages to %s.\nOpen index.html in a browser to view the report.", len(files)-1, dir.Path()),
+				di.window)
+		}, di.window)
+	}, di.window)
+}
+
+// showStatsExportDialog saves the schema statistics as a Markdown report
+func (di *DBInspector) showStatsExportDialog() {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	provider, ok := di.connector.(t.StatsProvider)
+	if !ok {
+		dialog.ShowError(fmt.Errorf("the current connector does not support schema statistics"), di.window)
+		return
+	}
+
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		stats, err := provider.GetSchemaStats(di.connParams.Schema)
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+
+		di.auditLog(audit.ActionExport, "statistics report to "+writer.URI().String())
+		if err := export.WriteStatsMarkdown(writer, stats); err != nil {
+			dialog.ShowError(fmt.Errorf("error writing report: %v", err), di.window)
+			return
+		}
+
+		// Append the table usage heatmap, to guide archiving decisions
+		if inspector, ok := di.connector.(t.UsageInspector); ok {
+			usage, err := inspector.GetTableUsage(di.connParams.Schema)
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+			analysis.RateUsage(usage)
+
+			fmt.Fprintln(writer)
+			if err := export.WriteUsageMarkdown(writer, usage); err != nil {
+				dialog.ShowError(fmt.Errorf("error writing report: %v", err), di.window)
+			}
+		}
+	}, di.window)
+	save.SetFileName(di.connParams.Schema + "-stats.md")
+	save.Show()
+}
+
+// copyColumnsMarkdown copies the columns of the selected table to the clipboard as a Markdown table
+func (di *DBInspector) copyColumnsMarkdown() {
+	if di.selectedTable == nil {
+		return
+	}
+
+	var sb strings.Builder
+	if err := export.WriteColumnsMarkdown(&sb, di.selectedTable); err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+
+	di.auditLog(audit.ActionExport, fmt.Sprintf("columns of %s as Markdown to the clipboard", di.selectedTable.Name))
+	di.window.Clipboard().SetContent(sb.String())
+}
+
+// documentFormat is a file format of the table and schema documents
+type documentFormat struct {
+	name       string
+	extension  string
+	writeTable func(w io.Writer, table *t.Table) error
+	format     string // Registered export format of the schema documents
+}
+
+// documentFormats are the formats offered when saving table and schema documents
+var documentFormats = []documentFormat{
+	{"JSON", ".json", export.WriteTableJSON, "json"},
+	{"JSON Lines", ".jsonl", export.WriteTableJSONL, "jsonl"},
+	{"YAML", ".yaml", export.WriteTableYAML, "yaml"},
+	{"JSON Schema", ".schema.json", export.WriteTableJSONSchema, "jsonschema"},
+	{"OpenAPI", ".openapi.json", export.WriteTableOpenAPI, "openapi"},
+	{"GraphQL", ".graphql", export.WriteTableGraphQL, "graphql"},
+	{"Markdown", ".md", export.WriteTableMarkdown, "md"},
+	{"AsciiDoc", ".adoc", export.WriteTableAsciiDoc, "adoc"},
+	{"CSV", ".csv", export.WriteTableInventoryCSV, "csv"},
+	{"Excel", ".xlsx", export.WriteTableWorkbook, "xlsx"},
+	{"PDF", ".pdf", export.WriteTablePDF, "pdf"},
+}
+
+// writeSchema writes the tables of the connected schema in the registered
+// export format of the document format
+func (di *DBInspector) writeSchema(w io.Writer, format documentFormat, tables []*t.Table) error {
+	document, ok := export.LookupFormat(format.format)
+	if !ok {
+		return fmt.Errorf("unknown export format '%s'", format.format)
+	}
+	return document.Exporter.Export(w, di.exportSchema(tables), export.Options{Connector: di.connector})
+}
+
+// writeSchemaFiles writes the tables of the connected schema into a folder in
+// the registered export format of the given name
+func (di *DBInspector) writeSchemaFiles(dir, name string, tables []*t.Table, settings map[string]string) ([]string, error) {
+	format, ok := export.LookupFormat(name)
+	if !ok || format.Files == nil {
+		return nil, fmt.Errorf("unknown export format '%s'", name)
+	}
+	opts := export.Options{Connector: di.connector, Settings: settings}
+	return format.Files(dir, di.exportSchema(tables), opts)
+}
+
+// exportSchema returns the export schema of tables of the connected schema
+func (di *DBInspector) exportSchema(tables []*t.Table) *export.Schema {
+	return &export.Schema{Name: di.connParams.Schema, Driver: di.connParams.Driver, Tables: tables}
+}
+
+// newDocumentFormatSelect creates a select of the document formats with the
+// named one selected. The returned function yields the selected format.
+func newDocumentFormatSelect(selected string) (*widget.Select, func() documentFormat) {
+	var names []string
+	for _, format := range documentFormats {
+		names = append(names, format.name)
+	}
+	formatSelect := widget.NewSelect(names, nil)
+	formatSelect.SetSelected(selected)
+
+	return formatSelect, func() documentFormat {
+		return documentFormats[max(formatSelect.SelectedIndex(), 0)]
+	}
+}
+
+// showTableDocumentExportDialog asks for a format, then saves the structure of the selected table
+func (di *DBInspector) showTableDocumentExportDialog() {
+	if di.selectedTable == nil {
+		return
+	}
+	table := di.selectedTable
+
+	formatSelect, selectedFormat := newDocumentFormatSelect(documentFormats[0].name)
+	items := []*widget.FormItem{{Text: "Format", Widget: formatSelect}}
+
+	dialog.ShowForm("Save "+table.Name+" as", "Save...", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		format := selectedFormat()
+
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			di.auditLog(audit.ActionExport, fmt.Sprintf("%s as %s to %s", table.Name, format.name, writer.URI()))
+			if err := format.writeTable(writer, table); err != nil {
+				dialog.ShowError(err, di.window)
+			}
+		}, di.window)
+		save.SetFileName(table.Name + format.extension)
+		save.Show()
+	}, di.window)
+}
+
+// showSchemaDocumentExportDialog asks for a format, initially the selected one,
+// and the tables to include, then saves them as a schema document. Markdown
+// and AsciiDoc give the data dictionary, a section per table, CSV the column
+// inventory, Excel a workbook with a sheet per table and PDF a printable report.
+func (di *DBInspector) showSchemaDocumentExportDialog(selected string) {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	formatSelect, selectedFormat := newDocumentFormatSelect(selected)
+	chooser, selectedTables := di.newTableChooser()
+	items := []*widget.FormItem{
+		{Text: "Format", Widget: formatSelect},
+		{Text: "Tables", Widget: chooser},
+	}
+
+	dialog.ShowForm("Export Schema", "Save...", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		names := selectedTables()
+		if len(names) == 0 {
+			dialog.ShowError(fmt.Errorf("no tables selected"), di.window)
+			return
+		}
+		format := selectedFormat()
+
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			tables, err := di.loadTables(names)
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+
+			di.auditLog(audit.ActionExport, fmt.Sprintf("%s of %d tables to %s", format.name, len(tables), writer.URI()))
+			if err := di.writeSchema(writer, format, tables); err != nil {
+				dialog.ShowError(err, di.window)
+			}
+		}, di.window)
+		save.SetFileName(di.connParams.Schema + format.extension)
+		save.Show()
+	}, di.window)
+}
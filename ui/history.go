@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/diff"
+	"github.com/carloberd/db-reader/history"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// showColumnMenu shows the context menu of a column row of the table details
+func (di *DBInspector) showColumnMenu(row int, pos fyne.Position) {
+	column, ok := di.columnAtRow(row)
+	if !ok {
+		return
+	}
+
+	menu := fyne.NewMenu("",
+		fyne.NewMenuItem(fmt.Sprintf("History of %s...", column), func() {
+			di.showColumnHistory(column)
+		}),
+	)
+	widget.ShowPopUpMenuAtPosition(menu, di.window.Canvas(), pos)
+}
+
+// columnAtRow returns the column listed on a row of the table details
+func (di *DBInspector) columnAtRow(row int) (string, bool) {
+	if di.selectedTable == nil {
+		return "", false
+	}
+
+	// Columns, as filtered, follow the section title, the header and the separator line
+	columns := di.filteredColumns(di.selectedTable)
+	for i, line := range di.tableDetails.Lines() {
+		if strings.HasPrefix(line, "COLUMNS") {
+			n := row - (i + 3)
+			if n < 0 || n >= len(columns) {
+				return "", false
+			}
+			return columns[n].Name, true
+		}
+	}
+	return "", false
+}
+
+// snapshotStore returns the store of scheduled snapshots, or false if they are disabled
+func (di *DBInspector) snapshotStore() (history.Store, bool) {
+	settings := di.config.Snapshots
+	if settings == nil {
+		return history.Store{}, false
+	}
+
+	dir := settings.Directory
+	if dir == "" {
+		dir = filepath.Join(filepath.Dir(di.configPath), "snapshots")
+	}
+	return history.Store{Dir: dir}, true
+}
+
+// stopSnapshots stops taking scheduled snapshots of the current connection
+func (di *DBInspector) stopSnapshots() {
+	if di.snapshotStop != nil {
+		close(di.snapshotStop)
+		di.snapshotStop = nil
+	}
+}
+
+// scheduleSnapshots takes a snapshot of the connected schema whenever the latest
+// one is older than the configured interval, checking again every interval
+func (di *DBInspector) scheduleSnapshots() {
+	di.stopSnapshots()
+
+	// Snapshots being viewed are not live databases to take snapshots of
+	store, ok := di.snapshotStore()
+	if !ok || di.connInfo == nil || di.connInfo.Driver == t.DriverSnapshot {
+		return
+	}
+	interval, err := time.ParseDuration(di.config.Snapshots.Interval)
+	if err != nil || interval <= 0 {
+		dialog.ShowError(fmt.Errorf("invalid snapshot interval '%s'", di.config.Snapshots.Interval), di.window)
+		return
+	}
+
+	stop := make(chan struct{})
+	di.snapshotStop = stop
+	connector, params := di.connector, di.connParams
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			err := takeScheduledSnapshot(store, connector, params, interval)
+			select {
+			case <-stop:
+				// The connection was closed meanwhile, which explains any error
+				return
+			default:
+			}
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("scheduled snapshot failed: %v", err), di.window)
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// takeScheduledSnapshot stores a snapshot of a schema if one is due
+func takeScheduledSnapshot(store history.Store, connector t.DatabaseConnector, params t.ConnectionParams, interval time.Duration) error {
+	key := config.SchemaKey(params)
+	due, err := store.Due(key, interval)
+	if err != nil || !due {
+		return err
+	}
+
+	names, err := connector.GetTables(params.Schema)
+	if err != nil {
+		return err
+	}
+	schema := &diff.Schema{Name: key}
+	for _, name := range names {
+		table, err := connector.GetTableStructure(params.Schema, name)
+		if err != nil {
+			return err
+		}
+		schema.Tables = append(schema.Tables, table)
+	}
+	// Sizes let the archive advisor follow how the tables grow
+	if err := schema.RecordStats(connector, params.Schema); err != nil {
+		return err
+	}
+
+	return store.Save(key, schema)
+}
+
+// showColumnHistory shows the changes of a column across the stored snapshots
+func (di *DBInspector) showColumnHistory(column string) {
+	store, ok := di.snapshotStore()
+	if !ok {
+		dialog.ShowInformation("Column history",
+			"Column history is built from scheduled snapshots, enable them in the Settings menu first.", di.window)
+		return
+	}
+
+	snapshots, err := store.Load(config.SchemaKey(di.liveConnection().params))
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+
+	table := di.selectedTable.Name
+	events := history.ColumnHistory(snapshots, table, column)
+	if len(events) == 0 {
+		dialog.ShowInformation("Column history",
+			fmt.Sprintf("%s.%s is not in any snapshot yet.", table, column), di.window)
+		return
+	}
+
+	lines := make([]string, len(events))
+	for i, event := range events {
+		lines[i] = event.String()
+	}
+	text := widget.NewLabel(strings.Join(lines, "\n"))
+	text.TextStyle = fyne.TextStyle{Monospace: true}
+
+	content := container.NewBorder(
+		widget.NewLabel(fmt.Sprintf("%d snapshots, from %s", len(snapshots), snapshots[0].Taken.Local().Format("2006-01-02"))),
+		nil, nil, nil,
+		container.NewScroll(text),
+	)
+	d := dialog.NewCustom(fmt.Sprintf("History of %s.%s", table, column), "Close", content, di.window)
+	d.Resize(fyne.NewSize(600, 400))
+	d.Show()
+}
+
+// showSnapshotSettings lets the user enable scheduled snapshots and set their interval
+func (di *DBInspector) showSnapshotSettings() {
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetPlaceHolder("e.g. 24h, empty to disable")
+	dirEntry := widget.NewEntry()
+	dirEntry.SetPlaceHolder("Next to the config file")
+	if settings := di.config.Snapshots; settings != nil {
+		intervalEntry.SetText(settings.Interval)
+		dirEntry.SetText(settings.Directory)
+	}
+
+	form := widget.NewForm(
+		widget.NewFormItem("Interval", intervalEntry),
+		widget.NewFormItem("Directory", dirEntry),
+	)
+
+	dialog.ShowCustomConfirm("Schema Snapshots", "Save", "Cancel", form, func(save bool) {
+		if !save {
+			return
+		}
+
+		interval := strings.TrimSpace(intervalEntry.Text)
+		if interval == "" {
+			di.config.Snapshots = nil
+		} else {
+			if d, err := time.ParseDuration(interval); err != nil || d <= 0 {
+				dialog.ShowError(fmt.Errorf("invalid snapshot interval '%s'", interval), di.window)
+				return
+			}
+			di.config.Snapshots = &config.SnapshotSettings{Interval: interval, Directory: strings.TrimSpace(dirEntry.Text)}
+		}
+		di.saveConfig()
+		di.scheduleSnapshots()
+	}, di.window)
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/importer"
+	"github.com/carloberd/db-reader/secrets"
+)
+
+// showImportDialog imports connection profiles from another database tool
+func (di *DBInspector) showImportDialog() {
+	pathEntry := widget.NewEntry()
+	pathEntry.SetPlaceHolder("Connection file")
+
+	var sources []string
+	for _, source := range importer.Sources {
+		sources = append(sources, string(source))
+	}
+	sourceSelect := widget.NewSelect(sources, func(source string) {
+		pathEntry.SetText(importer.DefaultPath(importer.Source(source)))
+	})
+	sourceSelect.SetSelected(sources[0])
+
+	browseBtn := widget.NewButton("Browse...", func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			reader.Close()
+			pathEntry.SetText(reader.URI().Path())
+		}, di.window)
+	})
+
+	items := []*widget.FormItem{
+		{Text: "Import from", Widget: sourceSelect},
+		{Text: "File", Widget: container.NewBorder(nil, nil, nil, browseBtn, pathEntry)},
+	}
+
+	dialog.ShowForm("Import Connections", "Import", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		profiles, err := importer.Import(importer.Source(sourceSelect.Selected), pathEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		if len(profiles) == 0 {
+			dialog.ShowInformation("Import Connections", "No PostgreSQL connections found.", di.window)
+			return
+		}
+
+		di.importProfiles(profiles)
+	}, di.window)
+}
+
+// importProfiles adds imported profiles, leaving existing profiles with the same name untouched
+func (di *DBInspector) importProfiles(profiles []config.Profile) {
+	// Ask for the master passphrase once up front rather than for every password
+	if di.sealer == nil && !secrets.KeyringAvailable() {
+		for _, profile := range profiles {
+			if profile.Params.Password != "" {
+				di.promptPassphrase(func() {
+					di.importProfiles(profiles)
+				})
+				return
+			}
+		}
+	}
+
+	imported, skipped := 0, 0
+	for _, profile := range profiles {
+		if _, exists := di.config.Profile(profile.Name); exists {
+			skipped++
+			continue
+		}
+
+		if err := di.config.StorePassword(&profile, profile.Params.Password, di.sealer); err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		di.config.SetProfile(profile)
+		imported++
+	}
+	di.saveConfig()
+
+	message := fmt.Sprintf("Imported %d connections.", imported)
+	if skipped > 0 {
+		message += fmt.Sprintf("\nSkipped %d connections whose profile name already exists.", skipped)
+	}
+	dialog.ShowInformation("Import Connections", message, di.window)
+}
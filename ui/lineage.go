@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/lineage"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// buildLineage creates the tab mapping view columns back to base table columns
+func (di *DBInspector) buildLineage() fyne.CanvasObject {
+	di.lineageDetails = widget.NewTextGrid()
+
+	di.lineageViews = widget.NewSelect(nil, func(view string) {
+		di.lineageDetails.SetText(di.formatViewLineage(view))
+	})
+	di.lineageViews.PlaceHolder = "Select a view"
+
+	return container.NewBorder(di.lineageViews, nil, nil, nil, container.NewScroll(di.lineageDetails))
+}
+
+// refreshLineage loads the view definitions of the current schema
+func (di *DBInspector) refreshLineage() {
+	di.viewLineage = nil
+	di.lineageViews.ClearSelected()
+	di.lineageDetails.SetText("")
+
+	inspector, ok := di.connector.(t.ViewInspector)
+	if !ok {
+		di.lineageViews.SetOptions(nil)
+		di.lineageDetails.SetText("View lineage is not supported for this database.")
+		return
+	}
+
+	definitions, err := inspector.GetViewDefinitions(di.connParams.Schema)
+	if err != nil {
+		di.lineageViews.SetOptions(nil)
+		di.lineageDetails.SetText(fmt.Sprintf("Error loading views: %v", err))
+		return
+	}
+
+	di.viewLineage = make(map[string][]lineage.ColumnLineage, len(definitions))
+	views := make([]string, 0, len(definitions))
+	for view, definition := range definitions {
+		di.viewLineage[view] = lineage.ViewColumns(definition)
+		views = append(views, view)
+	}
+	sort.Strings(views)
+
+	di.lineageViews.SetOptions(views)
+}
+
+// formatViewLineage formats the column lineage of a view
+func (di *DBInspector) formatViewLineage(view string) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("View: %s\n\n", view))
+	sb.WriteString(fmt.Sprintf("%-30s %-50s\n", "Column", "Derived from"))
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+
+	for _, col := range di.viewLineage[view] {
+		sb.WriteString(fmt.Sprintf("%-30s %-50s\n", col.Column, describeSources(col)))
+	}
+
+	sb.WriteString("\nLineage is derived by parsing the view definition and may be incomplete.\n")
+	return sb.String()
+}
+
+// describeSources formats the base table columns a view column derives from
+func describeSources(col lineage.ColumnLineage) string {
+	if len(col.Sources) == 0 {
+		return "(unknown)"
+	}
+
+	sources := make([]string, len(col.Sources))
+	for i, src := range col.Sources {
+		sources[i] = src.String()
+	}
+
+	description := strings.Join(sources, ", ")
+	if col.Expression {
+		description += " (expression)"
+	}
+	return description
+}
+
+// formatDerivedViews formats the view columns that derive from a table, if any
+func (di *DBInspector) formatDerivedViews(table string) string {
+	var lines []string
+	for view, columns := range di.viewLineage {
+		for _, col := range columns {
+			for _, src := range col.Sources {
+				if src.Table == table {
+					lines = append(lines, fmt.Sprintf("%-40s %-30s\n", view+"."+col.Column, src.Column))
+				}
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	sort.Strings(lines)
+
+	var sb strings.Builder
+	sb.WriteString("\nDERIVED VIEW COLUMNS:\n")
+	sb.WriteString(fmt.Sprintf("%-40s %-30s\n", "View column", "Derived from"))
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+	sb.WriteString(strings.Join(lines, ""))
+	return sb.String()
+}
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// detailsView shows the table details as a list of monospace lines. Only the
+// visible lines get widgets, so tables with thousands of columns stay responsive.
+type detailsView struct {
+	widget.List
+	lines          []string
+	widest         string // Longest line, which sets the width of the rows
+	onSecondaryTap func(row int, pos fyne.Position)
+}
+
+// newDetailsView creates the table details view, with a context menu callback for rows
+func newDetailsView(onSecondaryTap func(row int, pos fyne.Position)) *detailsView {
+	v := &detailsView{onSecondaryTap: onSecondaryTap}
+	v.Length = func() int { return len(v.lines) }
+	v.CreateItem = func() fyne.CanvasObject {
+		line := &detailsLine{view: v}
+		line.TextStyle = fyne.TextStyle{Monospace: true}
+		line.Text = v.widest
+		line.ExtendBaseWidget(line)
+		return line
+	}
+	v.UpdateItem = func(id widget.ListItemID, obj fyne.CanvasObject) {
+		line := obj.(*detailsLine)
+		line.row = id
+		line.SetText(v.lines[id])
+	}
+	v.OnSelected = func(id widget.ListItemID) { v.Unselect(id) }
+	v.HideSeparators = true
+	v.ExtendBaseWidget(v)
+	return v
+}
+
+// SetText replaces the details, splitting them into lines
+func (v *detailsView) SetText(text string) {
+	v.lines = strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	v.widest = ""
+	for _, line := range v.lines {
+		if len(line) > len(v.widest) {
+			v.widest = line
+		}
+	}
+	v.Refresh()
+	v.ScrollToTop()
+}
+
+// Lines returns the lines of the details
+func (v *detailsView) Lines() []string {
+	return v.lines
+}
+
+// detailsLine is a row of the details view that knows its line number
+type detailsLine struct {
+	widget.Label
+	view *detailsView
+	row  int
+}
+
+// TappedSecondary opens the context menu of the line
+func (l *detailsLine) TappedSecondary(e *fyne.PointEvent) {
+	if l.view.onSecondaryTap != nil {
+		l.view.onSecondaryTap(l.row, e.AbsolutePosition)
+	}
+}
+
+// checkList is a list of checkboxes that only creates widgets for the visible
+// options, for choosing among thousands of tables
+type checkList struct {
+	widget.List
+	options  []string
+	selected map[string]bool
+}
+
+// newCheckList creates a check list of the options, none checked
+func newCheckList(options []string) *checkList {
+	c := &checkList{options: options, selected: make(map[string]bool)}
+	c.Length = func() int { return len(c.options) }
+	c.CreateItem = func() fyne.CanvasObject { return widget.NewCheck("Table name", nil) }
+	c.UpdateItem = func(id widget.ListItemID, obj fyne.CanvasObject) {
+		check := obj.(*widget.Check)
+		option := c.options[id]
+		check.OnChanged = nil
+		check.Text = option
+		check.SetChecked(c.selected[option])
+		check.OnChanged = func(checked bool) {
+			if checked {
+				c.selected[option] = true
+			} else {
+				delete(c.selected, option)
+			}
+		}
+	}
+	c.OnSelected = func(id widget.ListItemID) { c.Unselect(id) }
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetOptions replaces the options, clearing the checks
+func (c *checkList) SetOptions(options []string) {
+	c.options = options
+	c.selected = make(map[string]bool)
+	c.Refresh()
+}
+
+// SetSelected checks exactly the given options
+func (c *checkList) SetSelected(names []string) {
+	c.selected = make(map[string]bool, len(names))
+	for _, name := range names {
+		c.selected[name] = true
+	}
+	c.Refresh()
+}
+
+// Selected returns the checked options in list order
+func (c *checkList) Selected() []string {
+	var names []string
+	for _, option := range c.options {
+		if c.selected[option] {
+			names = append(names, option)
+		}
+	}
+	return names
+}
+
+// tableNameLabel is the name of a table in the table list, with a context menu
+type tableNameLabel struct {
+	widget.Label
+	table          string
+	onSecondaryTap func(table string, pos fyne.Position)
+}
+
+// newTableNameLabel creates a table name label calling onSecondaryTap on right click
+func newTableNameLabel(onSecondaryTap func(table string, pos fyne.Position)) *tableNameLabel {
+	l := &tableNameLabel{onSecondaryTap: onSecondaryTap}
+	l.Text = "Table name"
+	l.ExtendBaseWidget(l)
+	return l
+}
+
+// TappedSecondary opens the context menu of the table
+func (l *tableNameLabel) TappedSecondary(e *fyne.PointEvent) {
+	if l.table != "" && l.onSecondaryTap != nil {
+		l.onSecondaryTap(l.table, e.AbsolutePosition)
+	}
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// setupMenu creates the main menu of the window
+func (di *DBInspector) setupMenu() {
+	fileMenu := fyne.NewMenu("File",
+		fyne.NewMenuItem("New Connection...", di.showConnectionDialog),
+		fyne.NewMenuItem("Open Demo Database", di.openDemo),
+		fyne.NewMenuItem("Import Connections...", di.showImportDialog),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Open Workspace...", di.showOpenWorkspaceDialog),
+		fyne.NewMenuItem("Save Workspace As...", di.showSaveWorkspaceDialog),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Export Baseline Migration...", di.showBaselineExportDialog),
+		fyne.NewMenuItem("Export Statistics Report...", di.showStatsExportDialog),
+		fyne.NewMenuItem("Export Diagram (Mermaid)...", di.showMermaidExportDialog),
+		fyne.NewMenuItem("Export Diagram (DOT)...", di.showDOTExportDialog),
+		fyne.NewMenuItem("Export Schema (JSON, JSON Lines, YAML, JSON Schema)...", func() { di.showSchemaDocumentExportDialog("JSON") }),
+		fyne.NewMenuItem("Export Data Dictionary (Markdown, AsciiDoc)...", func() { di.showSchemaDocumentExportDialog("Markdown") }),
+		fyne.NewMenuItem("Export API Scaffold (OpenAPI)...", func() { di.showSchemaDocumentExportDialog("OpenAPI") }),
+		fyne.NewMenuItem("Export GraphQL Schema...", func() { di.showSchemaDocumentExportDialog("GraphQL") }),
+		fyne.NewMenuItem("Export Column Inventory (CSV)...", func() { di.showSchemaDocumentExportDialog("CSV") }),
+		fyne.NewMenuItem("Export Workbook (Excel)...", func() { di.showSchemaDocumentExportDialog("Excel") }),
+		fyne.NewMenuItem("Export HTML Report...", di.showHTMLReportDialog),
+		fyne.NewMenuItem("Export PDF Report...", func() { di.showSchemaDocumentExportDialog("PDF") }),
+	)
+
+	viewMenu := fyne.NewMenu("View",
+		fyne.NewMenuItem("Switch Schema...", di.showSchemaSwitcher),
+		fyne.NewMenuItem("Switch Catalog...", di.showCatalogSwitcher),
+		fyne.NewMenuItem("Refresh Tables", di.refreshTableList),
+		fyne.NewMenuItem("View Snapshot...", di.showSnapshotBrowser),
+		fyne.NewMenuItem("Introspection SQL...", di.showQueryLog),
+	)
+
+	di.writesMenuItem = fyne.NewMenuItem("Enable Write Operations", di.toggleWrites)
+	di.prefetchMenuItem = fyne.NewMenuItem("Prefetch Table Structures", di.togglePrefetch)
+	settingsMenu := fyne.NewMenu("Settings",
+		di.writesMenuItem,
+		di.prefetchMenuItem,
+		fyne.NewMenuItem("Schema Snapshots...", di.showSnapshotSettings),
+	)
+
+	di.window.SetMainMenu(fyne.NewMainMenu(fileMenu, viewMenu, settingsMenu))
+}
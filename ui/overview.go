@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/analysis"
+	"github.com/carloberd/db-reader/render"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// buildOverview creates the database overview shown before a table is selected
+func (di *DBInspector) buildOverview() fyne.CanvasObject {
+	di.overview = widget.NewRichTextFromMarkdown("Not connected.")
+	di.overview.Wrapping = fyne.TextWrapWord
+
+	di.dashboard = container.NewVBox()
+
+	return container.NewScroll(container.NewVBox(di.overview, di.dashboard))
+}
+
+// refreshOverview rebuilds the overview for the current connection
+func (di *DBInspector) refreshOverview() {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", di.connParams.Database))
+	sb.WriteString(fmt.Sprintf("* **Server:** %s:%s\n", di.connParams.Host, di.connParams.Port))
+	if role := di.serverRoleLabel.Text; role != "" {
+		sb.WriteString(fmt.Sprintf("* **Role:** %s\n", strings.ToLower(role)))
+	}
+	sb.WriteString(fmt.Sprintf("* **Schema:** %s (%d tables)\n", di.connParams.Schema, len(di.tables)))
+
+	sb.WriteString("\n## Migrations\n\n")
+	sb.WriteString(di.formatMigrationStatus())
+
+	di.overview.ParseMarkdown(sb.String())
+
+	di.refreshDashboard()
+}
+
+// refreshDashboard shows the statistics dashboard and table usage heatmap of the current schema
+func (di *DBInspector) refreshDashboard() {
+	di.dashboard.RemoveAll()
+
+	if provider, ok := di.connector.(t.StatsProvider); ok {
+		stats, err := provider.GetSchemaStats(di.connParams.Schema)
+		if err != nil {
+			di.dashboard.Add(widget.NewLabel(fmt.Sprintf("Error loading schema statistics: %v", err)))
+		} else {
+			di.dashboard.Add(widget.NewLabelWithStyle("Statistics", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			di.dashboard.Add(render.NewStatsDashboard(stats))
+		}
+	}
+
+	if inspector, ok := di.connector.(t.UsageInspector); ok {
+		usage, err := inspector.GetTableUsage(di.connParams.Schema)
+		if err != nil {
+			di.dashboard.Add(widget.NewLabel(fmt.Sprintf("Error loading table usage: %v", err)))
+			return
+		}
+		analysis.RateUsage(usage)
+		di.dashboard.Add(widget.NewLabelWithStyle("Table usage", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		di.dashboard.Add(render.NewUsageHeatmap(usage))
+	}
+}
+
+// formatMigrationStatus describes the migration tools detected in the current schema
+func (di *DBInspector) formatMigrationStatus() string {
+	inspector, ok := di.connector.(t.MigrationInspector)
+	if !ok {
+		return "Migration history is not supported for this database.\n"
+	}
+
+	statuses, err := inspector.GetMigrationStatus(di.connParams.Schema)
+	if err != nil {
+		return fmt.Sprintf("Error reading migration history: %v\n", err)
+	}
+	if len(statuses) == 0 {
+		return "No migration tool history table found.\n"
+	}
+
+	var sb strings.Builder
+	for _, status := range statuses {
+		version := status.Version
+		if version == "" {
+			version = "none applied"
+		}
+
+		sb.WriteString(fmt.Sprintf("* **%s** (%s): version **%s**", status.Tool, status.Table, version))
+		if status.Description != "" {
+			sb.WriteString(fmt.Sprintf(" – %s", status.Description))
+		}
+		if status.AppliedAt != "" {
+			sb.WriteString(fmt.Sprintf(", applied %s", status.AppliedAt))
+		}
+		if status.Applied > 0 {
+			sb.WriteString(fmt.Sprintf(", %d migrations applied", status.Applied))
+		}
+		if status.Dirty {
+			sb.WriteString(" – **DIRTY: last migration failed or did not complete**")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
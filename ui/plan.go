@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/analysis"
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// largeTableRows is the estimated row count above which a sequential scan is highlighted
+const largeTableRows = 100000
+
+// explainQueries shows the execution plan of every statement in the editor
+func (di *DBInspector) explainQueries() {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	explainer, ok := di.connector.(t.QueryExplainer)
+	if !ok {
+		dialog.ShowError(fmt.Errorf("the current connector does not support execution plans"), di.window)
+		return
+	}
+
+	statements := sqlutil.SplitStatements(di.queryInput.Text)
+	if len(statements) == 0 {
+		return
+	}
+
+	for _, stmt := range statements {
+		if !sqlutil.IsReadOnly(stmt) {
+			dialog.ShowError(fmt.Errorf("only read-only statements are allowed: %s", summarizeStatement(stmt)), di.window)
+			return
+		}
+	}
+
+	di.resultTabs.SetItems(nil)
+
+	for i, stmt := range statements {
+		title := fmt.Sprintf("Plan %d", i+1)
+
+		di.auditLog(audit.ActionQuery, "EXPLAIN "+stmt)
+		plan, err := explainer.ExplainQuery(stmt)
+		if err != nil {
+			di.resultTabs.Append(container.NewTabItemWithIcon(title, theme.ErrorIcon(),
+				newStatementError(stmt, err)))
+			break
+		}
+
+		// Hints are a bonus, a table that cannot be loaded only drops them
+		hints, err := analysis.IndexHints(plan, largeTableRows, di.connector.GetTableStructure)
+		if err != nil {
+			hints = []t.Finding{{Message: fmt.Sprintf("Index hints unavailable: %v", err)}}
+		}
+
+		di.resultTabs.Append(container.NewTabItem(title, newPlanView(stmt, plan, hints)))
+	}
+
+	di.resultTabs.SelectIndex(0)
+}
+
+// newPlanView creates a collapsible tree of plan nodes with relative cost and row bars,
+// headed by the index hints for the plan
+func newPlanView(stmt string, plan *t.PlanNode, hints []t.Finding) fyne.CanvasObject {
+	// Nodes are addressed by their path from the root, e.g. "0.1.0"
+	nodes := make(map[string]*t.PlanNode)
+	var maxRows float64
+	var index func(id string, node *t.PlanNode)
+	index = func(id string, node *t.PlanNode) {
+		nodes[id] = node
+		maxRows = max(maxRows, node.PlanRows)
+		for i, child := range node.Children {
+			index(id+"."+strconv.Itoa(i), child)
+		}
+	}
+	index("0", plan)
+
+	tree := widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID {
+			if id == "" {
+				return []widget.TreeNodeID{"0"}
+			}
+			var children []widget.TreeNodeID
+			for i := range nodes[id].Children {
+				children = append(children, id+"."+strconv.Itoa(i))
+			}
+			return children
+		},
+		func(id widget.TreeNodeID) bool {
+			return id == "" || len(nodes[id].Children) > 0
+		},
+		func(branch bool) fyne.CanvasObject {
+			costBar := widget.NewProgressBar()
+			rowsBar := widget.NewProgressBar()
+			bars := container.NewGridWithColumns(2, costBar, rowsBar)
+			return container.NewBorder(nil, nil,
+				container.NewHBox(widget.NewIcon(nil), widget.NewLabel("")),
+				nil, bars)
+		},
+		func(id widget.TreeNodeID, branch bool, obj fyne.CanvasObject) {
+			node := nodes[id]
+			row := obj.(*fyne.Container)
+			left := row.Objects[1].(*fyne.Container)
+			icon := left.Objects[0].(*widget.Icon)
+			label := left.Objects[1].(*widget.Label)
+			bars := row.Objects[0].(*fyne.Container)
+			costBar := bars.Objects[0].(*widget.ProgressBar)
+			rowsBar := bars.Objects[1].(*widget.ProgressBar)
+
+			label.SetText(describePlanNode(node))
+			if isLargeSeqScan(node) {
+				icon.SetResource(theme.WarningIcon())
+				label.Importance = widget.DangerImportance
+			} else {
+				icon.SetResource(nil)
+				label.Importance = widget.MediumImportance
+			}
+			label.Refresh()
+
+			costBar.TextFormatter = func() string {
+				return fmt.Sprintf("cost %.2f", node.TotalCost)
+			}
+			costBar.SetValue(ratio(node.TotalCost, plan.TotalCost))
+
+			rowsBar.TextFormatter = func() string {
+				return fmt.Sprintf("%.0f rows", node.PlanRows)
+			}
+			rowsBar.SetValue(ratio(node.PlanRows, maxRows))
+		},
+	)
+	tree.OpenAllBranches()
+
+	header := container.NewVBox(
+		widget.NewLabelWithStyle(summarizeStatement(stmt), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}),
+		widget.NewLabel(fmt.Sprintf("Total cost %.2f, %.0f rows estimated", plan.TotalCost, plan.PlanRows)),
+	)
+	for _, hint := range hints {
+		label := widget.NewLabel(hint.Message)
+		label.Wrapping = fyne.TextWrapWord
+		label.Importance = widget.WarningImportance
+		header.Add(container.NewBorder(nil, nil, widget.NewIcon(theme.WarningIcon()), nil, label))
+	}
+
+	return container.NewBorder(header, nil, nil, nil, tree)
+}
+
+// describePlanNode returns a one-line description of a plan node
+func describePlanNode(node *t.PlanNode) string {
+	var sb strings.Builder
+	sb.WriteString(node.NodeType)
+
+	if node.IndexName != "" {
+		sb.WriteString(" using " + node.IndexName)
+	}
+	if node.RelationName != "" {
+		relation := node.RelationName
+		if node.Schema != "" {
+			relation = node.Schema + "." + relation
+		}
+		sb.WriteString(" on " + relation)
+		if node.Alias != "" && node.Alias != node.RelationName {
+			sb.WriteString(" " + node.Alias)
+		}
+	}
+	if isLargeSeqScan(node) {
+		sb.WriteString(fmt.Sprintf(" (table has ~%d rows)", node.TableRows))
+	}
+
+	return sb.String()
+}
+
+// isLargeSeqScan reports whether a node is a sequential scan on a large table
+func isLargeSeqScan(node *t.PlanNode) bool {
+	return node.NodeType == "Seq Scan" && node.TableRows >= largeTableRows
+}
+
+// ratio returns value/total clamped to the 0..1 range used by progress bars
+func ratio(value, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return min(value/total, 1)
+}
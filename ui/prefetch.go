@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"slices"
+	"time"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// prefetchWorkers is the number of table structures loaded at once in the
+// background, kept low so the catalog queries do not weigh on the server
+const prefetchWorkers = 2
+
+// prefetchPause spaces the table structures each worker loads
+const prefetchPause = 200 * time.Millisecond
+
+// togglePrefetch enables or disables loading the table structures in the background
+func (di *DBInspector) togglePrefetch() {
+	di.config.PrefetchStructures = !di.config.PrefetchStructures
+	di.saveConfig()
+	di.showPrefetchSetting()
+	di.prefetchStructures()
+}
+
+// showPrefetchSetting reflects the prefetch setting in the menu
+func (di *DBInspector) showPrefetchSetting() {
+	di.prefetchMenuItem.Checked = di.config.PrefetchStructures
+	di.window.MainMenu().Refresh()
+}
+
+// stopPrefetch stops loading table structures in the background and forgets
+// the ones already loaded
+func (di *DBInspector) stopPrefetch() {
+	if di.prefetchStop != nil {
+		close(di.prefetchStop)
+		di.prefetchStop = nil
+	}
+	di.structuresMu.Lock()
+	di.structures = nil
+	di.structuresMu.Unlock()
+}
+
+// prefetched is a table structure loaded in the background, with the catalog
+// queries that loaded it
+type prefetched struct {
+	table   *t.Table
+	queries []string
+}
+
+// prefetchStructures loads the structure of every table of the schema in the
+// background, if enabled, so selecting a table runs no catalog queries. A few
+// tables are loaded at a time with pauses in between, which spreads the load
+// on the server over the first minutes of the connection. Each worker opens a
+// connection of its own, so its queries stay out of the introspection SQL of
+// the current screen until the table they load is selected.
+func (di *DBInspector) prefetchStructures() {
+	di.stopPrefetch()
+	if !di.config.PrefetchStructures || di.connector == nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	di.prefetchStop = stop
+	params, names := di.connParams, slices.Clone(di.allTables)
+	structures := make(map[string]prefetched, len(names))
+	di.structuresMu.Lock()
+	di.structures = structures
+	di.structuresMu.Unlock()
+
+	queue := make(chan string)
+	go func() {
+		defer close(queue)
+		for _, name := range names {
+			select {
+			case queue <- name:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	for range prefetchWorkers {
+		go func() {
+			// Tables are loaded when selected if the worker cannot connect
+			connector, err := t.NewConnector(params.Driver)
+			if err != nil {
+				return
+			}
+			var queries []string
+			if logger, ok := connector.(t.QueryLogger); ok {
+				logger.SetQueryLog(func(query string, args []any) {
+					queries = append(queries, sqlutil.FormatLogged(query, args))
+				})
+			}
+			if err := connector.Connect(params); err != nil {
+				return
+			}
+			defer connector.Disconnect()
+
+			for name := range queue {
+				// A table that fails to load is loaded again when selected
+				queries = nil
+				if table, err := connector.GetTableStructure(params.Schema, name); err == nil {
+					di.structuresMu.Lock()
+					structures[name] = prefetched{table, queries}
+					di.structuresMu.Unlock()
+				}
+
+				select {
+				case <-stop:
+					return
+				case <-time.After(prefetchPause):
+				}
+			}
+		}()
+	}
+}
+
+// tableStructure returns the structure of a table of the connected schema,
+// prefetched if it already was. The queries that prefetched it are added to
+// the introspection SQL.
+func (di *DBInspector) tableStructure(name string) (*t.Table, error) {
+	di.structuresMu.Lock()
+	structure, ok := di.structures[name]
+	di.structuresMu.Unlock()
+	if ok {
+		di.logQueries(structure.queries)
+		return structure.table, nil
+	}
+	return di.connector.GetTableStructure(di.connParams.Schema, name)
+}
+
+// forgetStructure drops the prefetched structure of a table after changing it
+func (di *DBInspector) forgetStructure(name string) {
+	di.structuresMu.Lock()
+	delete(di.structures, name)
+	di.structuresMu.Unlock()
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/carloberd/db-reader/registry"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// formatRegistryEntry shows the descriptions and tags the data catalog of the
+// config has for a table, next to the comments of the database
+func (di *DBInspector) formatRegistryEntry(table *t.Table) string {
+	source, err := registry.New(di.config.Registry)
+	if err != nil {
+		return fmt.Sprintf("\nCATALOG:\nError: %v\n", err)
+	}
+	if source == nil {
+		return ""
+	}
+
+	entry, err := source.Lookup(context.Background(), di.connParams.Database, table.Schema, table.Name)
+	if err != nil {
+		return fmt.Sprintf("\nCATALOG:\nError: %v\n", err)
+	}
+	if entry == nil {
+		return "\nCATALOG:\n(not in the catalog)\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\nCATALOG (%s):\n", strings.ToUpper(entry.Source)))
+	if entry.Description != "" {
+		sb.WriteString(fmt.Sprintf("Description: %s\n", entry.Description))
+	}
+	if len(entry.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf("Tags: %s\n", strings.Join(entry.Tags, ", ")))
+	}
+
+	result := &t.QueryResult{Columns: []string{"Column", "Comment", "Description", "Tags"}}
+	for _, col := range table.Columns {
+		doc, ok := entry.Columns[col.Name]
+		if !ok {
+			continue
+		}
+		result.Rows = append(result.Rows, []string{col.Name, col.Comment, doc.Description, strings.Join(doc.Tags, ", ")})
+	}
+	if len(result.Rows) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(formatResultText(result))
+	}
+
+	return sb.String()
+}
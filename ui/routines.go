@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// buildRoutines creates the tab showing the signature and source of the
+// functions and procedures of the schema
+func (di *DBInspector) buildRoutines() fyne.CanvasObject {
+	di.routineDetails = widget.NewTextGrid()
+
+	di.routineSelect = widget.NewSelect(nil, func(signature string) {
+		for _, routine := range di.routines {
+			if routineSignature(routine) == signature {
+				di.routineDetails.SetText(formatRoutine(routine))
+				return
+			}
+		}
+	})
+	di.routineSelect.PlaceHolder = "Select a function or procedure"
+
+	return container.NewBorder(di.routineSelect, nil, nil, nil, container.NewScroll(di.routineDetails))
+}
+
+// refreshRoutines loads the functions and procedures of the current schema
+func (di *DBInspector) refreshRoutines() {
+	di.routines = nil
+	di.routineSelect.ClearSelected()
+	di.routineDetails.SetText("")
+
+	lister, ok := di.connector.(t.RoutineLister)
+	if !ok {
+		di.routineSelect.SetOptions(nil)
+		di.routineDetails.SetText("Listing functions and procedures is not supported for this database.")
+		return
+	}
+
+	routines, err := lister.GetRoutines(di.connParams.Schema)
+	if err != nil {
+		di.routineSelect.SetOptions(nil)
+		di.routineDetails.SetText(fmt.Sprintf("Error loading functions and procedures: %v", err))
+		return
+	}
+
+	di.routines = routines
+	signatures := make([]string, len(routines))
+	for i, routine := range routines {
+		signatures[i] = routineSignature(routine)
+	}
+	di.routineSelect.SetOptions(signatures)
+	if len(routines) == 0 {
+		di.routineDetails.SetText("The schema has no functions or procedures.")
+	}
+}
+
+// routineSignature names a routine with its arguments, which tells overloads apart
+func routineSignature(routine t.Routine) string {
+	return routine.Name + "(" + routine.Arguments + ")"
+}
+
+// formatRoutine formats the signature and source of a routine
+func formatRoutine(routine t.Routine) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s: %s.%s\n", strings.ToUpper(routine.Kind), routine.Schema, routineSignature(routine)))
+	if routine.ReturnType != "" {
+		sb.WriteString(fmt.Sprintf("Returns: %s\n", routine.ReturnType))
+	}
+	sb.WriteString(fmt.Sprintf("Language: %s\n", routine.Language))
+	if routine.Comment != "" {
+		sb.WriteString(fmt.Sprintf("Comment: %s\n", routine.Comment))
+	}
+
+	sb.WriteString("\nSOURCE:\n")
+	if routine.Source == "" {
+		sb.WriteString("(not available)\n")
+	} else {
+		sb.WriteString(strings.Trim(routine.Source, "\n") + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/audit"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// exactCountWarningRows is the estimated row count above which an exact count asks for confirmation
+const exactCountWarningRows = 10000000
+
+// buildRowCountBar creates the row count header of the details view
+func (di *DBInspector) buildRowCountBar() fyne.CanvasObject {
+	di.rowCountLabel = widget.NewLabel("")
+
+	di.countBtn = widget.NewButtonWithIcon("Exact count", theme.SearchIcon(), func() {
+		di.confirmExactCount()
+	})
+	di.countBtn.Hide()
+
+	return container.NewHBox(di.rowCountLabel, di.countBtn)
+}
+
+// showRowEstimate displays the estimated row count of the selected table
+func (di *DBInspector) showRowEstimate(table *t.Table) {
+	counter, ok := di.connector.(t.RowCounter)
+	if !ok {
+		di.rowCountLabel.SetText("")
+		di.countBtn.Hide()
+		return
+	}
+
+	di.rowEstimate = -1
+	estimate, err := counter.EstimateRowCount(table.Schema, table.Name)
+	switch {
+	case err != nil:
+		di.rowCountLabel.SetText(fmt.Sprintf("Rows: unknown (%v)", err))
+	case estimate < 0:
+		di.rowCountLabel.SetText("Rows: no estimate (table not analyzed)")
+	default:
+		di.rowEstimate = estimate
+		di.rowCountLabel.SetText(fmt.Sprintf("Rows: ~%d (estimated)", estimate))
+	}
+
+	di.countBtn.Enable()
+	di.countBtn.Show()
+}
+
+// confirmExactCount runs an exact row count, asking first if the table is very large
+// or the database is production
+func (di *DBInspector) confirmExactCount() {
+	di.confirmProduction("Counting the rows", func() {
+		if di.rowEstimate < exactCountWarningRows {
+			di.countExactRows()
+			return
+		}
+
+		message := fmt.Sprintf("The table has about %d rows.\nCounting them exactly scans the whole table and may take a long time.\nContinue?", di.rowEstimate)
+		dialog.ShowConfirm("Exact row count", message, func(ok bool) {
+			if ok {
+				di.countExactRows()
+			}
+		}, di.window)
+	})
+}
+
+// countExactRows runs COUNT(*) on the selected table in the background
+func (di *DBInspector) countExactRows() {
+	counter, ok := di.connector.(t.RowCounter)
+	if !ok || di.selectedTable == nil {
+		return
+	}
+
+	table := di.selectedTable
+	di.countBtn.Disable()
+	di.rowCountLabel.SetText("Rows: counting...")
+
+	di.auditLog(audit.ActionQuery, fmt.Sprintf("exact row count of %s.%s", table.Schema, table.Name))
+	go func() {
+		count, err := counter.CountRows(context.Background(), table.Schema, table.Name)
+
+		// Ignore the result if another table was selected meanwhile
+		if di.selectedTable != table {
+			return
+		}
+
+		di.countBtn.Enable()
+		if err != nil {
+			di.rowCountLabel.SetText("Rows: count failed")
+			dialog.ShowError(err, di.window)
+			return
+		}
+		di.rowCountLabel.SetText(fmt.Sprintf("Rows: %d (exact)", count))
+	}()
+}
+
+// sizeBuckets name the size classes of the table list badges, by upper limit in bytes
+var sizeBuckets = []struct {
+	limit int64
+	name  string
+}{
+	{10 << 20, "S"},
+	{1 << 30, "M"},
+	{100 << 30, "L"},
+}
+
+// loadTableSizes fetches the row and size estimates of the tables of the schema
+// in the background, then shows them as badges in the table list
+func (di *DBInspector) loadTableSizes() {
+	di.tableSizes = nil
+	lister, ok := di.connector.(t.TableSizeLister)
+	if !ok {
+		return
+	}
+
+	connector, schema := di.connector, di.connParams.Schema
+	go func() {
+		sizes, err := lister.GetTableSizes(schema)
+		// The badges are only a hint, so the names stay bare if the estimates fail
+		if err != nil || di.connector != connector || di.connParams.Schema != schema {
+			return
+		}
+
+		bySize := make(map[string]t.TableSize, len(sizes))
+		for _, size := range sizes {
+			bySize[size.Name] = size
+		}
+		di.tableSizes = bySize
+		di.tableList.Refresh()
+	}()
+}
+
+// tableBadge returns the row estimate and size bucket of a table, e.g. "1.2M · L",
+// or "" before the estimates are loaded
+func (di *DBInspector) tableBadge(table string) string {
+	size, ok := di.tableSizes[table]
+	if !ok {
+		return ""
+	}
+
+	var parts []string
+	if size.Rows >= 0 {
+		parts = append(parts, compactCount(size.Rows))
+	}
+	if size.Bytes > 0 {
+		bucket := "XL"
+		for _, b := range sizeBuckets {
+			if size.Bytes < b.limit {
+				bucket = b.name
+				break
+			}
+		}
+		parts = append(parts, bucket)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// compactCount formats a count with a metric suffix and at most two significant
+// digits below ten, e.g. 950, 3.2K, 13K or 1.2M
+func compactCount(n int64) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d", n)
+	}
+	value := float64(n)
+	for _, suffix := range []string{"K", "M", "B"} {
+		value /= 1000
+		if value < 999.5 || suffix == "B" {
+			if value < 10 {
+				return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0") + suffix
+			}
+			return fmt.Sprintf("%.0f", value) + suffix
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// showSchemaSwitcher lets the user pick another schema of the connected database
+// and reconnects to it
+func (di *DBInspector) showSchemaSwitcher() {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	lister, ok := di.connector.(t.SchemaLister)
+	if !ok {
+		dialog.ShowError(fmt.Errorf("the current connector cannot list schemas"), di.window)
+		return
+	}
+	schemas, err := lister.GetSchemas()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading schemas: %v", err), di.window)
+		return
+	}
+
+	schemaSelect := widget.NewSelect(schemas, nil)
+	schemaSelect.SetSelected(di.connParams.Schema)
+
+	dialog.ShowForm("Switch Schema", "Switch", "Cancel", []*widget.FormItem{
+		{Text: "Schema", Widget: schemaSelect},
+	}, func(ok bool) {
+		if !ok || schemaSelect.Selected == "" || schemaSelect.Selected == di.connParams.Schema {
+			return
+		}
+		di.connInfo.Schema = schemaSelect.Selected
+		di.connect()
+	}, di.window)
+}
+
+// showCatalogSwitcher lets the user browse the catalogs of a federating server,
+// pick one of its schemas and reconnect to it
+func (di *DBInspector) showCatalogSwitcher() {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+
+	lister, ok := di.connector.(t.CatalogLister)
+	if !ok {
+		dialog.ShowError(fmt.Errorf("the current connector cannot list catalogs"), di.window)
+		return
+	}
+	catalogs, err := lister.GetCatalogs()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error loading catalogs: %v", err), di.window)
+		return
+	}
+
+	// The schemas are loaded for the selected catalog
+	schemaSelect := widget.NewSelect(nil, nil)
+	catalogSelect := widget.NewSelect(catalogs, func(catalog string) {
+		schemas, err := lister.GetCatalogSchemas(catalog)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error loading schemas: %v", err), di.window)
+			schemas = nil
+		}
+		schemaSelect.Options = schemas
+		schemaSelect.ClearSelected()
+		if catalog == di.connParams.Database {
+			schemaSelect.SetSelected(di.connParams.Schema)
+		}
+		schemaSelect.Refresh()
+	})
+	catalogSelect.SetSelected(di.connParams.Database)
+
+	dialog.ShowForm("Switch Catalog", "Switch", "Cancel", []*widget.FormItem{
+		{Text: "Catalog", Widget: catalogSelect},
+		{Text: "Schema", Widget: schemaSelect},
+	}, func(ok bool) {
+		if !ok || catalogSelect.Selected == "" || schemaSelect.Selected == "" {
+			return
+		}
+		if catalogSelect.Selected == di.connParams.Database && schemaSelect.Selected == di.connParams.Schema {
+			return
+		}
+		di.connInfo.Database = catalogSelect.Selected
+		di.connInfo.Schema = schemaSelect.Selected
+		di.connect()
+	}, di.window)
+}
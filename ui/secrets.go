@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/config"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// promptPassphrase asks for the master passphrase protecting profile passwords,
+// or for a new one if none is set up yet, then calls done once it is unlocked
+func (di *DBInspector) promptPassphrase(done func()) {
+	create := di.config.Encryption == nil
+
+	passEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Master passphrase", passEntry),
+	}
+	title := "Unlock Saved Passwords"
+	if create {
+		title = "Set Master Passphrase"
+		items = append(items, widget.NewFormItem("Confirm", confirmEntry))
+		items = append(items, widget.NewFormItem("", widget.NewLabel(
+			"The OS keyring is unavailable, so saved passwords\nare encrypted with this passphrase.")))
+	}
+
+	dialog.ShowForm(title, "OK", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		if create && passEntry.Text != confirmEntry.Text {
+			dialog.ShowError(fmt.Errorf("the passphrases do not match"), di.window)
+			di.promptPassphrase(done)
+			return
+		}
+		if passEntry.Text == "" {
+			dialog.ShowError(fmt.Errorf("the passphrase must not be empty"), di.window)
+			di.promptPassphrase(done)
+			return
+		}
+
+		sealer, err := di.config.UnlockSecrets(passEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			di.promptPassphrase(done)
+			return
+		}
+		di.sealer = sealer
+
+		if create {
+			di.saveConfig()
+		}
+		if done != nil {
+			done()
+		}
+	}, di.window)
+}
+
+// saveProfile stores a connection profile, keeping its password in the OS keyring
+// or encrypted with the master passphrase
+func (di *DBInspector) saveProfile(profile config.Profile, password string) {
+	err := di.config.StorePassword(&profile, password, di.sealer)
+	if errors.Is(err, config.ErrPassphraseRequired) {
+		di.promptPassphrase(func() {
+			di.saveProfile(profile, password)
+		})
+		return
+	}
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+
+	di.config.SetProfile(profile)
+	di.saveConfig()
+}
+
+// watchSecret switches to a new connection when the password in the secret store
+// of the connection is rotated, so new connections of the pool do not fail with
+// the old one. The new connection is opened in the background and handed to the
+// UI thread, which swaps the connector and keeps the tables and tabs shown.
+func (di *DBInspector) watchSecret() {
+	di.stopSecretWatch()
+	if di.connInfo == nil || di.connParams.Secret == "" {
+		return
+	}
+
+	stop := make(chan struct{})
+	di.secretStop = stop
+	go config.WatchSecret(di.connParams, config.SecretCheckInterval, stop, func(rotated t.ConnectionParams) {
+		connector, err := t.NewConnector(rotated.Driver)
+		if err == nil {
+			err = connector.Connect(rotated)
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error reconnecting with rotated credentials: %v", err), di.window)
+			return
+		}
+
+		// Dialog callbacks run on the UI thread
+		notice := dialog.NewInformation("Credentials rotated",
+			"The password of the connection was rotated in the secret store.\nThe connection now uses the new one.", di.window)
+		notice.SetOnClosed(func() {
+			di.swapConnector(stop, connector, rotated)
+		})
+		notice.Show()
+	})
+}
+
+// swapConnector replaces the connector with one connected with rotated
+// credentials, unless the connection watched by stop was closed meanwhile
+func (di *DBInspector) swapConnector(stop chan struct{}, connector t.DatabaseConnector, params t.ConnectionParams) {
+	if di.secretStop != stop {
+		connector.Disconnect()
+		return
+	}
+
+	// Background work holding the previous connector is restarted on the new one
+	di.stopSnapshots()
+	di.stopPrefetch()
+	previous := di.connector
+	di.connector, di.connParams = connector, params
+	di.startQueryLog()
+	previous.Disconnect()
+	di.prefetchStructures()
+	di.scheduleSnapshots()
+
+	di.statusLabel.SetText(di.statusLabel.Text + " (credentials rotated)")
+}
+
+// stopSecretWatch stops watching the secret of the current connection
+func (di *DBInspector) stopSecretWatch() {
+	if di.secretStop != nil {
+		close(di.secretStop)
+		di.secretStop = nil
+	}
+}
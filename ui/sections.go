@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// customSectionTimeout bounds the run time of a custom section query
+const customSectionTimeout = 10 * time.Second
+
+// maxSectionColumnWidth caps the width of a custom section column
+const maxSectionColumnWidth = 40
+
+// loadCustomSections runs the custom catalog queries of the config for a table in
+// the background, and adds their results to the table details once done, unless
+// another table was selected meanwhile
+func (di *DBInspector) loadCustomSections(table *t.Table, load int) {
+	executor, ok := di.connector.(t.CatalogQueryExecutor)
+	if !ok || len(di.config.CustomSections) == 0 {
+		return
+	}
+	sections := slices.Clone(di.config.CustomSections)
+
+	go func() {
+		text := formatCustomSections(executor, sections, table)
+
+		di.detailsMu.Lock()
+		current := load == di.detailsLoad
+		if current {
+			di.detailSections += text
+		}
+		di.detailsMu.Unlock()
+
+		if current {
+			di.tableDetails.SetText(di.formatTableDetails(table))
+		}
+	}()
+}
+
+// formatCustomSections runs custom catalog queries for a table and formats their results
+func formatCustomSections(executor t.CatalogQueryExecutor, sections []config.CustomSection, table *t.Table) string {
+	var sb strings.Builder
+	for _, section := range sections {
+		sb.WriteString(fmt.Sprintf("\n%s:\n", strings.ToUpper(section.Name)))
+
+		query, args := sectionQuery(section.Query, table)
+		ctx, cancel := context.WithTimeout(context.Background(), customSectionTimeout)
+		result, err := executor.ExecuteCatalogQuery(ctx, query, args...)
+		cancel()
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("Error: %v\n", err))
+			continue
+		}
+
+		sb.WriteString(formatResultText(result))
+	}
+
+	return sb.String()
+}
+
+// sectionQuery returns a custom section query with the arguments it uses. The
+// schema ($1) and table ($2) parameters are renumbered in order of use and only
+// the used ones passed, since the server must infer the type of every parameter.
+// Dollar signs inside literals, quoted identifiers and comments are left alone.
+func sectionQuery(query string, table *t.Table) (string, []any) {
+	values := map[string]any{"1": table.Schema, "2": table.Name}
+
+	var sb strings.Builder
+	var args []any
+	numbers := make(map[string]int)
+	last := 0
+	tokens := sqlutil.Tokenize(query)
+	for i := 0; i+1 < len(tokens); i++ {
+		dollar, digit := tokens[i], tokens[i+1]
+		value, ok := values[digit.Text]
+		if !dollar.IsSymbol("$") || digit.Kind != sqlutil.Literal || digit.Pos != dollar.Pos+1 || !ok {
+			continue
+		}
+
+		n, seen := numbers[digit.Text]
+		if !seen {
+			args = append(args, value)
+			n = len(args)
+			numbers[digit.Text] = n
+		}
+		sb.WriteString(query[last:dollar.Pos])
+		sb.WriteString(fmt.Sprintf("$%d", n))
+		last = digit.Pos + len(digit.Text)
+	}
+	sb.WriteString(query[last:])
+
+	return sb.String(), args
+}
+
+// formatResultText formats a query result as fixed-width text
+func formatResultText(result *t.QueryResult) string {
+	if len(result.Rows) == 0 {
+		return "(no rows)\n"
+	}
+
+	widths := make([]int, len(result.Columns))
+	for i, col := range result.Columns {
+		widths[i] = utf8.RuneCountInString(col)
+	}
+	for _, row := range result.Rows {
+		for i, value := range row {
+			widths[i] = min(max(widths[i], utf8.RuneCountInString(value)), maxSectionColumnWidth)
+		}
+	}
+
+	var sb strings.Builder
+	writeRow := func(values []string) {
+		for i, value := range values {
+			if runes := []rune(value); len(runes) > widths[i] {
+				value = string(runes[:widths[i]-1]) + "…"
+			}
+			sb.WriteString(fmt.Sprintf("%-*s ", widths[i], value))
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(result.Columns)
+	total := len(widths)
+	for _, w := range widths {
+		total += w
+	}
+	sb.WriteString(strings.Repeat("-", total) + "\n")
+	for _, row := range result.Rows {
+		writeRow(row)
+	}
+
+	return sb.String()
+}
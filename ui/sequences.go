@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// buildSequences creates the tab listing the sequences of the schema
+func (di *DBInspector) buildSequences() fyne.CanvasObject {
+	di.sequenceDetails = widget.NewTextGrid()
+	return container.NewScroll(di.sequenceDetails)
+}
+
+// refreshSequences loads the sequences of the current schema
+func (di *DBInspector) refreshSequences() {
+	lister, ok := di.connector.(t.SequenceLister)
+	if !ok {
+		di.sequenceDetails.SetText("Listing sequences is not supported for this database.")
+		return
+	}
+
+	sequences, err := lister.GetSequences(di.connParams.Schema)
+	if err != nil {
+		di.sequenceDetails.SetText(fmt.Sprintf("Error loading sequences: %v", err))
+		return
+	}
+	if len(sequences) == 0 {
+		di.sequenceDetails.SetText("The schema has no sequences.")
+		return
+	}
+	di.sequenceDetails.SetText(formatSequences(sequences))
+}
+
+// formatSequences formats the sequences with their settings and owning columns
+func formatSequences(sequences []t.Sequence) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-30s %-10s %-12s %-10s %-22s %-6s %s\n", "Name", "Type", "Current", "Increment", "Range", "Cycle", "Owned by"))
+	sb.WriteString(strings.Repeat("-", 110) + "\n")
+	for _, seq := range sequences {
+		current := "-"
+		if seq.Current.Valid {
+			current = fmt.Sprint(seq.Current.Int64)
+		}
+		sb.WriteString(fmt.Sprintf("%-30s %-10s %-12s %-10d %-22s %-6t %s\n",
+			seq.Name, seq.DataType, current, seq.Increment, seq.Min+".."+seq.Max, seq.Cycle, seq.OwnedBy))
+	}
+	return sb.String()
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/config"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// snapshotColor is the banner color while viewing a snapshot
+var snapshotColor = color.NRGBA{R: 0x15, G: 0x65, B: 0xc0, A: 0xff}
+
+// liveConnection is the connection left to view one of its snapshots
+type liveConnection struct {
+	params      t.ConnectionParams // As entered, to reconnect with
+	connected   t.ConnectionParams // As connected, keying the snapshots
+	profileName string
+	environment string
+}
+
+// snapshotTaker is implemented by the snapshot connector
+type snapshotTaker interface {
+	Taken() time.Time
+}
+
+// buildSnapshotBanner creates the banner telling that a snapshot is shown instead of a live database
+func (di *DBInspector) buildSnapshotBanner() fyne.CanvasObject {
+	di.snapshotText = canvas.NewText("", color.White)
+	di.snapshotText.TextStyle = fyne.TextStyle{Bold: true}
+
+	di.backToLiveBtn = widget.NewButtonWithIcon("Back to Live", theme.NavigateBackIcon(), di.backToLive)
+
+	di.snapshotBanner = container.NewStack(
+		canvas.NewRectangle(snapshotColor),
+		container.NewPadded(container.NewHBox(layout.NewSpacer(), di.snapshotText, layout.NewSpacer(), di.backToLiveBtn)),
+	)
+	di.snapshotBanner.Hide()
+	return di.snapshotBanner
+}
+
+// showSnapshotBanner shows the banner when the connection is a snapshot, and
+// forgets the live connection once another database is connected
+func (di *DBInspector) showSnapshotBanner() {
+	taker, ok := di.connector.(snapshotTaker)
+	if !ok {
+		di.liveConn = nil
+		di.snapshotBanner.Hide()
+		return
+	}
+
+	di.snapshotText.Text = fmt.Sprintf("Viewing snapshot from %s", taker.Taken().Local().Format("2006-01-02 15:04"))
+	di.snapshotText.Refresh()
+	if di.liveConn != nil {
+		di.backToLiveBtn.Show()
+	} else {
+		di.backToLiveBtn.Hide()
+	}
+	di.snapshotBanner.Show()
+}
+
+// showSnapshotBrowser lists the stored snapshots of the connected schema and
+// opens the chosen one in place of the live database
+func (di *DBInspector) showSnapshotBrowser() {
+	if di.connInfo == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+	store, ok := di.snapshotStore()
+	if !ok {
+		dialog.ShowInformation("Snapshots",
+			"Snapshots are taken on a schedule, enable them in the Settings menu first.", di.window)
+		return
+	}
+
+	// While viewing a snapshot, the others of the live schema remain available
+	live := di.liveConnection()
+	entries, err := store.Entries(config.SchemaKey(live.connected))
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+	if len(entries) == 0 {
+		dialog.ShowInformation("Snapshots", "No snapshot of this schema has been taken yet.", di.window)
+		return
+	}
+
+	// Newest first
+	options := make([]string, len(entries))
+	paths := make(map[string]string, len(entries))
+	for i, entry := range entries {
+		option := entry.Taken.Local().Format("2006-01-02 15:04:05")
+		options[len(entries)-1-i] = option
+		paths[option] = entry.Path
+	}
+	snapshotSelect := widget.NewSelect(options, nil)
+	snapshotSelect.SetSelected(options[0])
+
+	items := []*widget.FormItem{{Text: "Taken", Widget: snapshotSelect}}
+	dialog.ShowForm("View Snapshot", "Open", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		di.liveConn = &live
+		di.connInfo = &t.ConnectionParams{Driver: t.DriverSnapshot, Database: paths[snapshotSelect.Selected], Schema: live.connected.Schema}
+		di.environment = ""
+		di.profileName = ""
+		di.connect()
+	}, di.window)
+}
+
+// liveConnection returns the live connection, which is the current one unless a snapshot is shown
+func (di *DBInspector) liveConnection() liveConnection {
+	if di.liveConn != nil {
+		return *di.liveConn
+	}
+	return liveConnection{params: *di.connInfo, connected: di.connParams, profileName: di.profileName, environment: di.environment}
+}
+
+// backToLive reconnects to the database whose snapshot is shown
+func (di *DBInspector) backToLive() {
+	if di.liveConn == nil {
+		return
+	}
+
+	live := *di.liveConn
+	di.liveConn = nil
+	di.connInfo = &live.params
+	di.profileName = live.profileName
+	di.environment = live.environment
+	di.connect()
+}
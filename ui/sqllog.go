@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/sqlutil"
+	t "github.com/carloberd/db-reader/types"
+)
+
+// startQueryLog makes the connector record its catalog queries for the introspection SQL view
+func (di *DBInspector) startQueryLog() {
+	if logger, ok := di.connector.(t.QueryLogger); ok {
+		logger.SetQueryLog(func(query string, args []any) {
+			di.queryLogMu.Lock()
+			defer di.queryLogMu.Unlock()
+			di.queryLog = append(di.queryLog, sqlutil.FormatLogged(query, args))
+		})
+	}
+}
+
+// logQueries adds catalog queries run by another connection to the introspection SQL
+func (di *DBInspector) logQueries(queries []string) {
+	di.queryLogMu.Lock()
+	defer di.queryLogMu.Unlock()
+	di.queryLog = append(di.queryLog, queries...)
+}
+
+// clearQueryLog forgets the recorded catalog queries, when switching to a new screen
+func (di *DBInspector) clearQueryLog() {
+	di.queryLogMu.Lock()
+	defer di.queryLogMu.Unlock()
+	di.queryLog = nil
+}
+
+// showQueryLog opens a window listing the catalog queries run for the current screen
+func (di *DBInspector) showQueryLog() {
+	text := widget.NewMultiLineEntry()
+	text.TextStyle = fyne.TextStyle{Monospace: true}
+
+	refresh := func() {
+		di.queryLogMu.Lock()
+		defer di.queryLogMu.Unlock()
+		if len(di.queryLog) == 0 {
+			text.SetText("-- No catalog queries recorded for the current screen")
+			return
+		}
+		text.SetText(strings.Join(di.queryLog, "\n"))
+	}
+	refresh()
+
+	w := di.app.NewWindow("Introspection SQL")
+	copyBtn := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(text.Text)
+	})
+	refreshBtn := widget.NewButtonWithIcon("Refresh", theme.ViewRefreshIcon(), refresh)
+
+	w.SetContent(container.NewBorder(nil, container.NewHBox(refreshBtn, copyBtn), nil, nil, text))
+	w.Resize(fyne.NewSize(700, 500))
+	w.Show()
+}
@@ -1,49 +1,137 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
-	"github.com/carloberd/db-reader/postgresql"
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/config"
+	"github.com/carloberd/db-reader/filter"
+	"github.com/carloberd/db-reader/lineage"
+	"github.com/carloberd/db-reader/secrets"
 	t "github.com/carloberd/db-reader/types"
 )
 
 // DBInspector is the main application structure
 type DBInspector struct {
-	app       fyne.App
-	window    fyne.Window
-	connector t.DatabaseConnector
-	connInfo  *t.ConnectionParams
+	app         fyne.App
+	window      fyne.Window
+	connector   t.DatabaseConnector
+	connInfo    *t.ConnectionParams // Parameters as entered or saved, with references to the environment and secret stores
+	connParams  t.ConnectionParams  // Parameters of the current connection, with the references resolved
+	profileName string              // Name of the profile used for the current connection, if any
+	environment string              // Environment tag of the current connection, if any
+	liveConn    *liveConnection     // Connection to return to while viewing one of its snapshots
+
+	// Persistent settings
+	config     *config.Config
+	configPath string
 
 	// Main widgets
-	tableList    *widget.List
-	statusLabel  *widget.Label
-	tableDetails *widget.TextGrid
+	tableList          *widget.List
+	systemTablesCheck  *widget.Check
+	statusLabel        *widget.Label
+	serverRoleLabel    *widget.Label
+	environmentBanner  *fyne.Container
+	environmentBg      *canvas.Rectangle
+	environmentText    *canvas.Text
+	snapshotBanner     *fyne.Container
+	snapshotText       *canvas.Text
+	backToLiveBtn      *widget.Button
+	detailTabs         *container.AppTabs
+	structureTab       *container.TabItem
+	ddlTab             *container.TabItem
+	ddlText            *widget.TextGrid
+	ddlSource          *widget.Label
+	overview           *widget.RichText
+	dashboard          *fyne.Container
+	tableDetails       *detailsView
+	columnFilter       *widget.Entry
+	columnSort         *widget.Select
+	rowCountLabel      *widget.Label
+	countBtn           *widget.Button
+	queryInput         *widget.Entry
+	queryStatus        *widget.Label
+	runBtn             *widget.Button
+	resultTabs         *container.AppTabs
+	analysisStatus     *widget.Label
+	findingList        *widget.List
+	diagramScope       *widget.Select
+	diagramDepth       *widget.Select
+	diagramExclude     *widget.Entry
+	diagramTableChecks *checkList
+	diagramCanvas      *fyne.Container
+	diagramSize        *canvas.Rectangle
+	diagramEdges       []diagramEdge
+	viewSelect         *widget.Select
+	viewDetails        *widget.TextGrid
+	sequenceDetails    *widget.TextGrid
+	routineSelect      *widget.Select
+	routineDetails     *widget.TextGrid
+	lineageViews       *widget.Select
+	lineageDetails     *widget.TextGrid
+	favoriteCheck      *widget.Check
+	noteInput          *widget.Entry
+	savedQuerySelect   *widget.Select
+	writesMenuItem     *fyne.MenuItem
+	prefetchMenuItem   *fyne.MenuItem
 
 	// Data
-	tables        []string
-	selectedTable *t.Table
+	allTables       []string // All tables in the schema, before filtering
+	extensionTables []string // Tables owned by extensions
+	tables          []string // Tables shown in the list
+	selectedTable   *t.Table
+	findings        []t.Finding
+	views           []t.View                           // Views of the schema, shown in the views tab
+	routines        []t.Routine                        // Functions and procedures of the schema, shown in the routines tab
+	viewLineage     map[string][]lineage.ColumnLineage // Column lineage by view name
+	rowEstimate     int64                              // Estimated rows of the selected table, -1 if unknown
+	tableSizes      map[string]t.TableSize             // Row and size estimates shown as badges in the table list
+	detailSections  string                             // Catalog, derived view and custom sections of the selected table
+	detailsLoad     int                                // Incremented on each table selection, so late custom sections are dropped
+	detailsMu       sync.Mutex                         // Guards detailSections and detailsLoad
+	cancelQuery     context.CancelFunc                 // Set while editor queries are running
+	cancelQueryMu   sync.Mutex
+	queryLog        []string // Catalog queries run for the current screen
+	queryLogMu      sync.Mutex
+	audit           *audit.Logger         // Nil unless auditing is enabled
+	sealer          *secrets.Sealer       // Decrypts saved passwords once the master passphrase is entered
+	snapshotStop    chan struct{}         // Closed to stop the scheduled snapshots of the connection
+	secretStop      chan struct{}         // Closed to stop watching the secret of the connection for rotation
+	prefetchStop    chan struct{}         // Closed to stop loading table structures in the background
+	structures      map[string]prefetched // Table structures loaded in the background, by name
+	structuresMu    sync.Mutex
 }
 
+// windowTitle is the title of the main window
+const windowTitle = "Database Inspector"
+
 // NewDBInspector creates a new database inspector
 func NewDBInspector(a fyne.App) *DBInspector {
-	w := a.NewWindow("PostgreSQL Database Inspector")
+	w := a.NewWindow(windowTitle)
 
 	inspector := &DBInspector{
-		app:         a,
-		window:      w,
-		statusLabel: widget.NewLabel("Not connected"),
-		connector:   postgresql.NewPostgresConnector(),
+		app:             a,
+		window:          w,
+		statusLabel:     widget.NewLabel("Not connected"),
+		serverRoleLabel: widget.NewLabelWithStyle("", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
 	}
 
 	inspector.setupUI()
+	inspector.setupMenu()
+	inspector.loadConfig()
 
 	return inspector
 }
@@ -58,9 +146,18 @@ func (di *DBInspector) setupUI() {
 	// Table list (initially empty)
 	di.tableList = widget.NewList(
 		func() int { return len(di.tables) },
-		func() fyne.CanvasObject { return widget.NewLabel("Table name") },
+		func() fyne.CanvasObject {
+			// The name, with the row estimate and size bucket as a badge on the right
+			badge := widget.NewLabel("")
+			badge.Importance = widget.LowImportance
+			return container.NewBorder(nil, nil, nil, badge, newTableNameLabel(di.showTableMenu))
+		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
-			obj.(*widget.Label).SetText(di.tables[id])
+			item := obj.(*fyne.Container)
+			name := item.Objects[0].(*tableNameLabel)
+			name.table = di.tables[id]
+			name.SetText(di.tableLabel(di.tables[id]))
+			item.Objects[1].(*widget.Label).SetText(di.tableBadge(di.tables[id]))
 		},
 	)
 
@@ -71,8 +168,73 @@ func (di *DBInspector) setupUI() {
 		}
 	}
 
-	// Table details area
-	di.tableDetails = widget.NewTextGrid()
+	// Toggle for extension-owned and migration tool tables
+	di.systemTablesCheck = widget.NewCheck("Show extension and tool tables", func(checked bool) {
+		di.config.ShowSystemTables = checked
+		di.saveConfig()
+		di.applyTableFilter()
+	})
+
+	// Table details area, with a context menu on columns
+	di.tableDetails = newDetailsView(di.showColumnMenu)
+
+	copyMarkdownBtn := widget.NewButtonWithIcon("Copy as Markdown", theme.ContentCopyIcon(), func() {
+		di.copyColumnsMarkdown()
+	})
+
+	saveAsBtn := widget.NewButtonWithIcon("Save as...", theme.DocumentSaveIcon(), func() {
+		di.showTableDocumentExportDialog()
+	})
+
+	editCommentsBtn := widget.NewButtonWithIcon("Edit Comments", theme.DocumentCreateIcon(), func() {
+		di.showCommentEditor()
+	})
+
+	// Filters the columns of wide tables by name, type or comment
+	di.columnFilter = widget.NewEntry()
+	di.columnFilter.SetPlaceHolder("Filter columns by name, type or comment")
+	di.columnFilter.OnChanged = func(string) {
+		if di.selectedTable != nil {
+			di.tableDetails.SetText(di.formatTableDetails(di.selectedTable))
+		}
+	}
+
+	// The sort order is kept while browsing tables, until the application is closed
+	di.columnSort = widget.NewSelect([]string{sortOrdinal, sortName, sortType, sortNullable}, func(string) {
+		if di.selectedTable != nil {
+			di.tableDetails.SetText(di.formatTableDetails(di.selectedTable))
+		}
+	})
+	di.columnSort.SetSelected(sortOrdinal)
+
+	di.structureTab = container.NewTabItem("Structure", container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, nil, container.NewHBox(editCommentsBtn, copyMarkdownBtn, saveAsBtn), di.buildRowCountBar()),
+			container.NewBorder(nil, nil, nil, container.NewHBox(widget.NewLabel("Sort by"), di.columnSort), di.columnFilter),
+		),
+		di.buildNotes(), nil, nil,
+		container.NewHScroll(di.tableDetails),
+	))
+	di.ddlTab = container.NewTabItem("DDL", di.buildDDL())
+	di.detailTabs = container.NewAppTabs(
+		container.NewTabItem("Overview", di.buildOverview()),
+		di.structureTab,
+		di.ddlTab,
+		container.NewTabItem("Query", di.buildQueryEditor()),
+		container.NewTabItem("Analysis", di.buildAnalysis()),
+		container.NewTabItem("Diagram", di.buildDiagram()),
+		container.NewTabItem("Views", di.buildViews()),
+		container.NewTabItem("Sequences", di.buildSequences()),
+		container.NewTabItem("Routines", di.buildRoutines()),
+		container.NewTabItem("Lineage", di.buildLineage()),
+	)
+
+	// The DDL may take several catalog queries, so it is only loaded when shown
+	di.detailTabs.OnSelected = func(tab *container.TabItem) {
+		if tab == di.ddlTab && di.selectedTable != nil {
+			di.showTableDDL(di.selectedTable)
+		}
+	}
 
 	// Main layout
 	split := container.NewHSplit(
@@ -81,23 +243,23 @@ func (di *DBInspector) setupUI() {
 				widget.NewLabel("Available tables:"),
 				widget.NewSeparator(),
 			),
-			nil, nil, nil,
+			di.systemTablesCheck, nil, nil,
 			di.tableList,
 		),
-		container.NewBorder(
-			nil, nil, nil, nil,
-			container.NewScroll(di.tableDetails),
-		),
+		di.detailTabs,
 	)
 	split.SetOffset(0.3) // 30% left, 70% right
 
 	// Overall layout
 	content := container.NewBorder(
 		container.NewVBox(
+			di.buildEnvironmentBanner(),
+			di.buildSnapshotBanner(),
 			container.NewHBox(
 				newConnBtn,
 				layout.NewSpacer(),
 				di.statusLabel,
+				di.serverRoleLabel,
 			),
 			widget.NewSeparator(),
 		),
@@ -123,69 +285,262 @@ func (di *DBInspector) showConnectionDialog() {
 
 	passEntry := widget.NewPasswordEntry()
 
+	secretEntry := widget.NewEntry()
+	secretEntry.SetPlaceHolder("vault:path#field or aws:secret-id (optional)")
+
 	dbEntry := widget.NewEntry()
 
 	schemaEntry := widget.NewEntry()
-	schemaEntry.SetText("public")
+
+	accountEntry := widget.NewEntry()
+	accountEntry.SetPlaceHolder("e.g. myorg-myaccount")
+	warehouseEntry := widget.NewEntry()
+	roleEntry := widget.NewEntry()
+
+	credentialsEntry := widget.NewEntry()
+	credentialsEntry.SetPlaceHolder("Service account key file (application default credentials)")
+	credentialsBtn := widget.NewButton("Browse...", func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			reader.Close()
+			credentialsEntry.SetText(reader.URI().Path())
+		}, di.window)
+	})
+
+	stmtTimeoutEntry := widget.NewEntry()
+	stmtTimeoutEntry.SetPlaceHolder("e.g. 30s (server default)")
+
+	lockTimeoutEntry := widget.NewEntry()
+	lockTimeoutEntry.SetPlaceHolder("e.g. 5s (server default)")
+
+	settingsEntry := widget.NewMultiLineEntry()
+	settingsEntry.SetPlaceHolder("search_path=app,public\nwork_mem=64MB")
+	settingsEntry.SetMinRowsVisible(3)
+
+	profileNameEntry := widget.NewEntry()
+	profileNameEntry.SetPlaceHolder("Leave empty to not save")
+
+	primaryEntry := widget.NewEntry()
+	primaryEntry.SetPlaceHolder("Primary host:port the replica follows")
+	primaryEntry.Disable()
+	replicaCheck := widget.NewCheck("Profile points at a read replica", func(replica bool) {
+		if replica {
+			primaryEntry.Enable()
+		} else {
+			primaryEntry.Disable()
+		}
+	})
+
+	environmentSelect := widget.NewSelect(environmentChoices(), nil)
+	environmentSelect.SetSelected(noEnvironment)
+
+	// File databases are picked instead of giving a server, and some drivers
+	// read several files picked one after the other
+	var driverSelect *widget.Select
+	browseBtn := widget.NewButton("Browse...", func() {
+		info := t.Connector(driverOf(driverSelect.Selected))
+		picker := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			reader.Close()
+			path := reader.URI().Path()
+			if info.AddFile != nil {
+				path = info.AddFile(dbEntry.Text, path)
+			}
+			dbEntry.SetText(path)
+		}, di.window)
+		if len(info.Extensions) > 0 {
+			picker.SetFilter(storage.NewExtensionFileFilter(info.Extensions))
+		}
+		picker.Show()
+	})
+
+	// The fields are enabled for the drivers using them, and BigQuery and Trino
+	// name some of them after their own concepts
+	var form *widget.Form
+	dbItem := &widget.FormItem{Text: "Database", Widget: container.NewBorder(nil, nil, nil, browseBtn, dbEntry)}
+	schemaItem := &widget.FormItem{Text: "Schema", Widget: schemaEntry}
+	fields := map[string][]fyne.Disableable{
+		t.FieldHost:        {hostEntry},
+		t.FieldPort:        {portEntry},
+		t.FieldUser:        {userEntry},
+		t.FieldPassword:    {passEntry},
+		t.FieldSecret:      {secretEntry},
+		t.FieldDatabase:    {dbEntry, browseBtn},
+		t.FieldSchema:      {schemaEntry},
+		t.FieldAccount:     {accountEntry},
+		t.FieldWarehouse:   {warehouseEntry},
+		t.FieldRole:        {roleEntry},
+		t.FieldCredentials: {credentialsEntry, credentialsBtn},
+		t.FieldTimeouts:    {stmtTimeoutEntry, lockTimeoutEntry},
+		t.FieldSettings:    {settingsEntry},
+	}
+
+	// The placeholders show the defaults of the selected driver
+	driverSelect = widget.NewSelect(driverNames(), func(name string) {
+		driver := driverOf(name)
+		info := t.Connector(driver)
+		defaults := t.ConnectionParams{Driver: driver, Database: "the database"}
+		defaults.ApplyDefaults()
+		hostEntry.SetPlaceHolder(defaults.Host)
+		portEntry.SetPlaceHolder(defaults.Port)
+		userEntry.SetPlaceHolder(defaults.User)
+		schemaEntry.SetPlaceHolder(defaults.Schema)
+		dbEntry.SetPlaceHolder(info.Hint)
+
+		for field, widgets := range fields {
+			for _, w := range widgets {
+				if info.Uses(field) {
+					w.Enable()
+				} else {
+					w.Disable()
+				}
+			}
+		}
+		if info.File {
+			browseBtn.Show()
+		} else {
+			browseBtn.Hide()
+		}
+
+		dbItem.Text = info.Label(t.FieldDatabase, "Database")
+		schemaItem.Text = info.Label(t.FieldSchema, "Schema")
+		if form != nil {
+			form.Refresh()
+		}
+	})
+	driverSelect.SetSelected(driverName(t.DriverPostgres))
+
+	fillFields := func(params *t.ConnectionParams) {
+		driverSelect.SetSelected(driverName(params.Driver))
+		hostEntry.SetText(params.Host)
+		portEntry.SetText(params.Port)
+		userEntry.SetText(params.User)
+		passEntry.SetText(params.Password)
+		secretEntry.SetText(params.Secret)
+		if params.Secret != "" {
+			// The password comes from the secret store and is not kept with the profile
+			passEntry.SetText("")
+		}
+		dbEntry.SetText(params.Database)
+		schemaEntry.SetText(params.Schema)
+		accountEntry.SetText(params.Account)
+		warehouseEntry.SetText(params.Warehouse)
+		roleEntry.SetText(params.Role)
+		credentialsEntry.SetText(params.CredentialsFile)
+		stmtTimeoutEntry.SetText(params.StatementTimeout)
+		lockTimeoutEntry.SetText(params.LockTimeout)
+		settingsEntry.SetText(formatSettings(params.Settings))
+	}
 
 	// Populate fields if there's already a connection
 	if di.connInfo != nil {
-		hostEntry.SetText(di.connInfo.Host)
-		portEntry.SetText(di.connInfo.Port)
-		userEntry.SetText(di.connInfo.User)
-		passEntry.SetText(di.connInfo.Password)
-		dbEntry.SetText(di.connInfo.Database)
-		schemaEntry.SetText(di.connInfo.Schema)
+		fillFields(di.connInfo)
 	}
 
+	// Saved profiles fill in the fields when selected. They are listed by environment.
+	profileOptions, profileNames := di.profileChoices()
+	profileSelect := widget.NewSelect(profileOptions, func(option string) {
+		if profile, ok := di.config.Profile(profileNames[option]); ok {
+			params := profile.Params
+			password, err := di.config.ProfilePassword(profile, di.sealer)
+			if err != nil {
+				dialog.ShowError(err, di.window)
+			}
+			params.Password = password
+			fillFields(&params)
+			profileNameEntry.SetText(profile.Name)
+			replicaCheck.SetChecked(profile.Replica)
+			primaryEntry.SetText(profile.Primary)
+			if profile.Environment != "" {
+				environmentSelect.SetSelected(profile.Environment)
+			} else {
+				environmentSelect.SetSelected(noEnvironment)
+			}
+		}
+	})
+	profileSelect.PlaceHolder = "(no profile)"
+
 	// Create the form
-	form := &widget.Form{
+	form = &widget.Form{
 		Items: []*widget.FormItem{
+			{Text: "Profile", Widget: profileSelect},
+			{Text: "Database type", Widget: driverSelect},
 			{Text: "Host", Widget: hostEntry},
 			{Text: "Port", Widget: portEntry},
 			{Text: "User", Widget: userEntry},
 			{Text: "Password", Widget: passEntry},
-			{Text: "Database", Widget: dbEntry},
-			{Text: "Schema", Widget: schemaEntry},
+			{Text: "Secret", Widget: secretEntry},
+			dbItem,
+			schemaItem,
+			{Text: "Account", Widget: accountEntry},
+			{Text: "Warehouse", Widget: warehouseEntry},
+			{Text: "Role", Widget: roleEntry},
+			{Text: "Credentials", Widget: container.NewBorder(nil, nil, nil, credentialsBtn, credentialsEntry)},
+			{Text: "Statement timeout", Widget: stmtTimeoutEntry},
+			{Text: "Lock timeout", Widget: lockTimeoutEntry},
+			{Text: "Session settings", Widget: settingsEntry},
+			{Text: "Save as profile", Widget: profileNameEntry},
+			{Text: "Environment", Widget: environmentSelect},
+			{Text: "", Widget: replicaCheck},
+			{Text: "Primary", Widget: primaryEntry},
 		},
 		OnSubmit: func() {
 			// Collect connection parameters
-			host := hostEntry.Text
-			if host == "" {
-				host = "localhost"
-			}
-
-			port := portEntry.Text
-			if port == "" {
-				port = "5432"
-			}
-
-			user := userEntry.Text
-			if user == "" {
-				user = "postgres"
-			}
-
 			password := passEntry.Text
 			database := dbEntry.Text
-			schema := schemaEntry.Text
-			if schema == "" {
-				schema = "public"
-			}
 
 			// Verify database name is provided
-			if database == "" {
+			if database == "" && t.Connector(driverOf(driverSelect.Selected)).Uses(t.FieldDatabase) {
 				dialog.ShowError(fmt.Errorf("database name is required"), di.window)
 				return
 			}
 
+			settings, err := parseSettings(settingsEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, di.window)
+				return
+			}
+
 			// Store parameters
 			di.connInfo = &t.ConnectionParams{
-				Host:     host,
-				Port:     port,
-				User:     user,
-				Password: password,
-				Database: database,
-				Schema:   schema,
+				Driver:           driverOf(driverSelect.Selected),
+				Host:             hostEntry.Text,
+				Port:             portEntry.Text,
+				User:             userEntry.Text,
+				Password:         password,
+				Database:         database,
+				Schema:           schemaEntry.Text,
+				Account:          strings.TrimSpace(accountEntry.Text),
+				Warehouse:        strings.TrimSpace(warehouseEntry.Text),
+				Role:             strings.TrimSpace(roleEntry.Text),
+				CredentialsFile:  strings.TrimSpace(credentialsEntry.Text),
+				Secret:           strings.TrimSpace(secretEntry.Text),
+				StatementTimeout: strings.TrimSpace(stmtTimeoutEntry.Text),
+				LockTimeout:      strings.TrimSpace(lockTimeoutEntry.Text),
+				Settings:         settings,
+			}
+			di.connInfo.ApplyDefaults()
+
+			// The environment colors the window and guards data queries
+			di.environment = environmentSelect.Selected
+			if di.environment == noEnvironment {
+				di.environment = ""
+			}
+
+			// Save the profile if a name was given
+			di.profileName = strings.TrimSpace(profileNameEntry.Text)
+			if di.profileName != "" {
+				di.saveProfile(config.Profile{
+					Name:        di.profileName,
+					Params:      *di.connInfo,
+					Replica:     replicaCheck.Checked,
+					Primary:     strings.TrimSpace(primaryEntry.Text),
+					Environment: di.environment,
+				}, password)
 			}
 
 			// Attempt connection
@@ -197,18 +552,147 @@ func (di *DBInspector) showConnectionDialog() {
 	dialog.ShowCustom("Connect to Database", "Cancel", form, di.window)
 }
 
+// openDemo connects to the built-in demo database
+func (di *DBInspector) openDemo() {
+	di.connInfo = &t.ConnectionParams{Driver: t.DriverDemo}
+	di.connInfo.ApplyDefaults()
+	di.environment = ""
+	di.profileName = ""
+	di.connect()
+}
+
+// parseSettings parses session settings given as one key=value pair per line
+func parseSettings(text string) (map[string]string, error) {
+	settings := make(map[string]string)
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid session setting '%s', expected key=value", line)
+		}
+		settings[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	if len(settings) == 0 {
+		return nil, nil
+	}
+	return settings, nil
+}
+
+// formatSettings formats session settings as one key=value pair per line
+func formatSettings(settings map[string]string) string {
+	lines := make([]string, 0, len(settings))
+	for key, value := range settings {
+		lines = append(lines, key+"="+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// loadConfig reads the persistent settings, falling back to an empty configuration
+func (di *DBInspector) loadConfig() {
+	di.config = &config.Config{}
+
+	path, err := config.DefaultPath()
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+	di.configPath = path
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		return
+	}
+	di.config = cfg
+	di.applyWorkspace()
+}
+
+// saveConfig writes the persistent settings
+func (di *DBInspector) saveConfig() {
+	if di.configPath == "" {
+		return
+	}
+
+	if err := di.config.Save(di.configPath); err != nil {
+		dialog.ShowError(err, di.window)
+	}
+}
+
+// driverNames returns the database types of the registered connectors, sorted
+func driverNames() []string {
+	drivers := t.Drivers()
+	names := make([]string, 0, len(drivers))
+	for _, driver := range drivers {
+		names = append(names, driverName(driver))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// driverName returns the database type shown for a driver
+func driverName(driver string) string {
+	return t.Connector(driver).Title
+}
+
+// driverOf returns the driver of a database type shown in the connection dialog
+func driverOf(name string) string {
+	for _, driver := range t.Drivers() {
+		if driverName(driver) == name {
+			return driver
+		}
+	}
+	return t.DriverPostgres
+}
+
 // connect establishes a database connection
 func (di *DBInspector) connect() {
 	// Close existing connection, if any
+	di.stopSnapshots()
+	di.stopSecretWatch()
+	di.stopPrefetch()
 	if di.connector != nil {
 		di.connector.Disconnect()
 	}
 
 	// Update status
 	di.statusLabel.SetText("Connecting...")
+	di.showEnvironment()
+	di.clearQueryLog()
+
+	// Profiles may take secrets from the environment, e.g. ${PROD_DB_PASS}
+	params, err := config.ExpandEnv(*di.connInfo)
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		di.statusLabel.SetText("Connection error")
+		return
+	}
+	// and from Vault or AWS Secrets Manager
+	if params, err = config.ResolveSecret(context.Background(), params); err != nil {
+		dialog.ShowError(err, di.window)
+		di.statusLabel.SetText("Connection error")
+		return
+	}
+	di.connParams = params
+
+	// Use a connector for the database type of the connection
+	connector, err := t.NewConnector(params.Driver)
+	if err != nil {
+		dialog.ShowError(err, di.window)
+		di.statusLabel.SetText("Connection error")
+		return
+	}
+	di.connector = connector
+	di.startQueryLog()
 
 	// Connect to database
-	err := di.connector.Connect(*di.connInfo)
+	err = di.connector.Connect(params)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("connection error: %v", err), di.window)
 		di.statusLabel.SetText("Connection error")
@@ -216,30 +700,139 @@ func (di *DBInspector) connect() {
 	}
 
 	// Connection successful
-	di.statusLabel.SetText(fmt.Sprintf("Connected to %s", di.connInfo.Database))
+	di.showSnapshotBanner()
+	di.auditLog(audit.ActionConnect, "schema "+di.connParams.Schema)
+	status := fmt.Sprintf("Connected to %s", di.connParams.Database)
+	if inspector, ok := di.connector.(t.SearchPathInspector); ok {
+		if path, err := inspector.SearchPath(); err == nil {
+			status += fmt.Sprintf(" (search_path: %s)", strings.Join(path, ", "))
+		}
+	}
+	di.statusLabel.SetText(status)
+
+	// Show whether we are on a primary or a replica
+	di.checkServerRole()
 
 	// Load table list
 	di.loadTableList()
+
+	di.refreshOverview()
+	di.refreshViews()
+	di.refreshSequences()
+	di.refreshRoutines()
+	di.refreshLineage()
+	di.detailTabs.SelectIndex(0)
+
+	di.scheduleSnapshots()
+	di.watchSecret()
 }
 
-// loadTableList fetches and displays the list of tables
+// checkServerRole displays whether the server is a primary or a replica, and warns
+// when connected to a primary while a replica profile exists for the same database
+func (di *DBInspector) checkServerRole() {
+	inspector, ok := di.connector.(t.ReplicationInspector)
+	if !ok {
+		di.serverRoleLabel.Hide()
+		return
+	}
+
+	replica, err := inspector.IsReplica()
+	if err != nil {
+		di.serverRoleLabel.SetText("UNKNOWN ROLE")
+		di.serverRoleLabel.Importance = widget.MediumImportance
+		di.serverRoleLabel.Show()
+		return
+	}
+
+	if replica {
+		di.serverRoleLabel.SetText("REPLICA")
+		di.serverRoleLabel.Importance = widget.SuccessImportance
+		di.serverRoleLabel.Show()
+		return
+	}
+
+	di.serverRoleLabel.SetText("PRIMARY")
+	di.serverRoleLabel.Importance = widget.WarningImportance
+	di.serverRoleLabel.Show()
+
+	var names, unmatched []string
+	for _, profile := range di.config.ReplicaProfiles(di.connParams) {
+		if profile.Name == di.profileName {
+			continue
+		}
+		names = append(names, profile.Name)
+		if profile.Primary == "" {
+			unmatched = append(unmatched, profile.Name)
+		}
+	}
+	if len(names) > 0 {
+		message := fmt.Sprintf("This server is a primary, but a replica profile exists for %s: %s.\nConsider using the replica for inspection.",
+			di.connParams.Database, strings.Join(names, ", "))
+		if len(unmatched) > 0 {
+			message += fmt.Sprintf("\n\n%s has no primary set, so it was matched on the database name alone.\nSet its primary host:port in the connection dialog.",
+				strings.Join(unmatched, ", "))
+		}
+		dialog.ShowInformation("Connected to a primary", message, di.window)
+	}
+}
+
+// loadTableList fetches and displays the list of tables, and prefetches
+// their structures again
 func (di *DBInspector) loadTableList() {
 	// Get tables from database
 	var err error
-	di.tables, err = di.connector.GetTables(di.connInfo.Schema)
+	di.allTables, err = di.connector.GetTables(di.connParams.Schema)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("error loading tables: %v", err), di.window)
 		return
 	}
 
+	// Find extension-owned tables so they can be hidden
+	di.extensionTables = nil
+	if inspector, ok := di.connector.(t.ExtensionInspector); ok {
+		di.extensionTables, err = inspector.GetExtensionTables(di.connParams.Schema)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error loading extension tables: %v", err), di.window)
+		}
+	}
+
+	di.applyTableFilter()
+	di.loadTableSizes()
+	di.prefetchStructures()
+}
+
+// refreshTableList loads the list of tables again, for tables created or
+// changed since connecting
+func (di *DBInspector) refreshTableList() {
+	if di.connector == nil {
+		dialog.ShowError(fmt.Errorf("not connected to database"), di.window)
+		return
+	}
+	di.clearQueryLog()
+	di.loadTableList()
+}
+
+// applyTableFilter updates the table list according to the system tables toggle
+func (di *DBInspector) applyTableFilter() {
+	if di.config.ShowSystemTables {
+		di.tables = di.allTables
+	} else {
+		di.tables = filter.Tables(di.allTables, di.extensionTables)
+	}
+	di.sortFavorites()
+
 	// Update the list widget
+	di.tableList.UnselectAll()
 	di.tableList.Refresh()
+	di.updateDiagramTables()
 }
 
 // loadTableDetails loads and displays details of the selected table
 func (di *DBInspector) loadTableDetails(tableName string) {
+	di.clearQueryLog()
+
 	// Get table structure from database
-	table, err := di.connector.GetTableStructure(di.connInfo.Schema, tableName)
+	table, err := di.tableStructure(tableName)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("error loading table details: %v", err), di.window)
 		return
@@ -247,25 +840,52 @@ func (di *DBInspector) loadTableDetails(tableName string) {
 
 	di.selectedTable = table
 
+	// The sections below the structure may query the database or the data
+	// catalog, so they are kept while the columns are filtered
+	di.detailsMu.Lock()
+	di.detailsLoad++
+	load := di.detailsLoad
+	di.detailSections = di.formatRegistryEntry(table) + di.formatDerivedViews(table.Name)
+	di.detailsMu.Unlock()
+	di.loadCustomSections(table, load)
+
 	// Format table details
 	details := di.formatTableDetails(table)
 
-	// Update the TextGrid
+	// Update the details view
 	di.tableDetails.SetText(details)
+
+	// Show the row estimate in the header
+	di.showRowEstimate(table)
+	di.showTableNotes(table.Name)
+
+	di.detailTabs.Select(di.structureTab)
 }
 
 // formatTableDetails formats table structure as a string
 func (di *DBInspector) formatTableDetails(table *t.Table) string {
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf("Table: %s.%s\n\n", table.Schema, table.Name))
+	sb.WriteString(fmt.Sprintf("Table: %s.%s\n", table.Schema, table.Name))
+	if table.Comment != "" {
+		sb.WriteString(fmt.Sprintf("Comment: %s\n", table.Comment))
+	}
+	for _, prop := range table.Properties {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", prop.Name, prop.Value))
+	}
+	sb.WriteString("\n")
 
-	sb.WriteString("COLUMNS:\n")
+	columns := di.filteredColumns(table)
+	if len(columns) < len(table.Columns) {
+		sb.WriteString(fmt.Sprintf("COLUMNS (%d of %d matching '%s'):\n", len(columns), len(table.Columns), di.columnFilter.Text))
+	} else {
+		sb.WriteString("COLUMNS:\n")
+	}
 	sb.WriteString(fmt.Sprintf("%-20s %-25s %-10s %-25s %-10s %-25s\n",
 		"Name", "Type", "Nullable", "Default", "PrimaryKey", "Foreign Key"))
 	sb.WriteString(strings.Repeat("-", 115) + "\n")
 
-	for _, col := range table.Columns {
+	for _, col := range columns {
 		defaultVal := "NULL"
 		if col.DefaultValue.Valid {
 			defaultVal = col.DefaultValue.String
@@ -287,14 +907,50 @@ func (di *DBInspector) formatTableDetails(table *t.Table) string {
 
 		for _, idx := range table.Indexes {
 			columns := strings.Join(idx.Columns, ", ")
+			if len(idx.Include) > 0 {
+				columns += " INCLUDE " + strings.Join(idx.Include, ", ")
+			}
 			sb.WriteString(fmt.Sprintf("%-30s %-40s %-10t %-10t\n",
 				idx.Name, columns, idx.Unique, idx.PrimaryKey))
 		}
 	}
 
+	if len(table.Triggers) > 0 {
+		sb.WriteString("\nTRIGGERS:\n")
+		sb.WriteString(fmt.Sprintf("%-30s %-10s %-25s %-10s %-30s %-10s\n", "Name", "Timing", "Events", "For Each", "Function", "Enabled"))
+		sb.WriteString(strings.Repeat("-", 120) + "\n")
+
+		for _, trigger := range table.Triggers {
+			sb.WriteString(fmt.Sprintf("%-30s %-10s %-25s %-10s %-30s %-10t\n",
+				trigger.Name, trigger.Timing, strings.Join(trigger.Events, " OR "), trigger.ForEach, trigger.Function, trigger.Enabled))
+		}
+	}
+
+	di.detailsMu.Lock()
+	sb.WriteString(di.detailSections)
+	di.detailsMu.Unlock()
+
 	return sb.String()
 }
 
+// filteredColumns returns the columns of a table whose name, type or comment
+// contains the text of the column filter, ignoring case, in the chosen sort order
+func (di *DBInspector) filteredColumns(table *t.Table) []t.Column {
+	text := strings.ToLower(strings.TrimSpace(di.columnFilter.Text))
+	if text == "" {
+		return sortColumns(table.Columns, di.columnSort.Selected)
+	}
+
+	var columns []t.Column
+	for _, col := range table.Columns {
+		if strings.Contains(strings.ToLower(col.Name), text) || strings.Contains(strings.ToLower(col.Type), text) ||
+			strings.Contains(strings.ToLower(col.Comment), text) {
+			columns = append(columns, col)
+		}
+	}
+	return sortColumns(columns, di.columnSort.Selected)
+}
+
 // Show displays the application window
 func (di *DBInspector) Show() error {
 	di.window.ShowAndRun()
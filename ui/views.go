@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// buildViews creates the tab listing the views of the schema with their columns and definitions
+func (di *DBInspector) buildViews() fyne.CanvasObject {
+	di.viewDetails = widget.NewTextGrid()
+
+	di.viewSelect = widget.NewSelect(nil, func(name string) {
+		for _, view := range di.views {
+			if view.Name == name {
+				di.viewDetails.SetText(formatView(view))
+				return
+			}
+		}
+	})
+	di.viewSelect.PlaceHolder = "Select a view"
+
+	return container.NewBorder(di.viewSelect, nil, nil, nil, container.NewScroll(di.viewDetails))
+}
+
+// refreshViews loads the views of the current schema
+func (di *DBInspector) refreshViews() {
+	di.views = nil
+	di.viewSelect.ClearSelected()
+	di.viewDetails.SetText("")
+
+	views, err := di.connector.GetViews(di.connParams.Schema)
+	if errors.Is(err, t.ErrViewsNotSupported) {
+		di.viewSelect.SetOptions(nil)
+		di.viewDetails.SetText("Listing views is not supported for this database.")
+		return
+	}
+	if err != nil {
+		di.viewSelect.SetOptions(nil)
+		di.viewDetails.SetText(fmt.Sprintf("Error loading views: %v", err))
+		return
+	}
+
+	di.views = views
+	names := make([]string, len(views))
+	for i, view := range views {
+		names[i] = view.Name
+	}
+	di.viewSelect.SetOptions(names)
+	if len(views) == 0 {
+		di.viewDetails.SetText("The schema has no views.")
+	}
+}
+
+// formatView formats the columns and definition of a view
+func formatView(view t.View) string {
+	var sb strings.Builder
+
+	kind := "View"
+	if view.Materialized {
+		kind = "Materialized view"
+	}
+	sb.WriteString(fmt.Sprintf("%s: %s.%s\n", kind, view.Schema, view.Name))
+	if view.Comment != "" {
+		sb.WriteString(fmt.Sprintf("Comment: %s\n", view.Comment))
+	}
+
+	sb.WriteString("\nCOLUMNS:\n")
+	sb.WriteString(fmt.Sprintf("%-30s %-25s %-10s %s\n", "Name", "Type", "Nullable", "Comment"))
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	for _, col := range view.Columns {
+		sb.WriteString(fmt.Sprintf("%-30s %-25s %-10t %s\n", col.Name, col.Type, col.Nullable, col.Comment))
+	}
+
+	sb.WriteString("\nDEFINITION:\n")
+	sb.WriteString(strings.TrimSpace(view.Definition) + "\n")
+	return sb.String()
+}
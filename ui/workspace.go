@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/carloberd/db-reader/audit"
+	"github.com/carloberd/db-reader/config"
+)
+
+// favoritePrefix marks starred tables in the table list
+const favoritePrefix = "★ "
+
+// buildNotes creates the favorite toggle and note editor of the selected table
+func (di *DBInspector) buildNotes() fyne.CanvasObject {
+	di.favoriteCheck = widget.NewCheck("Favorite", func(checked bool) {
+		if di.selectedTable == nil || di.connInfo == nil {
+			return
+		}
+		key := config.SchemaKey(di.connParams)
+		if di.config.IsFavorite(key, di.selectedTable.Name) == checked {
+			return
+		}
+		di.config.SetFavorite(key, di.selectedTable.Name, checked)
+		di.saveConfig()
+		di.sortFavorites()
+		di.tableList.UnselectAll()
+		di.tableList.Refresh()
+	})
+	di.favoriteCheck.Disable()
+
+	di.noteInput = widget.NewMultiLineEntry()
+	di.noteInput.SetPlaceHolder("Notes about this table")
+	di.noteInput.Wrapping = fyne.TextWrapWord
+	di.noteInput.SetMinRowsVisible(3)
+
+	saveBtn := widget.NewButtonWithIcon("Save Note", theme.DocumentSaveIcon(), func() {
+		if di.selectedTable == nil || di.connInfo == nil {
+			return
+		}
+		di.config.SetNote(config.SchemaKey(di.connParams), di.selectedTable.Name, di.noteInput.Text)
+		di.saveConfig()
+	})
+
+	return container.NewBorder(nil, nil, nil, container.NewVBox(di.favoriteCheck, saveBtn), di.noteInput)
+}
+
+// showTableNotes displays the favorite state and note of a table
+func (di *DBInspector) showTableNotes(table string) {
+	key := config.SchemaKey(di.connParams)
+	di.favoriteCheck.Enable()
+	di.favoriteCheck.SetChecked(di.config.IsFavorite(key, table))
+	di.noteInput.SetText(di.config.Note(key, table))
+}
+
+// sortFavorites moves starred tables to the top of the table list
+func (di *DBInspector) sortFavorites() {
+	if di.connInfo == nil {
+		return
+	}
+
+	key := config.SchemaKey(di.connParams)
+	sorted := make([]string, len(di.tables))
+	copy(sorted, di.tables)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return di.config.IsFavorite(key, sorted[i]) && !di.config.IsFavorite(key, sorted[j])
+	})
+	di.tables = sorted
+}
+
+// tableLabel returns the table list label of a table
+func (di *DBInspector) tableLabel(table string) string {
+	if di.connInfo != nil && di.config.IsFavorite(config.SchemaKey(di.connParams), table) {
+		return favoritePrefix + table
+	}
+	return table
+}
+
+// showSaveQueryDialog stores the editor content as a named query
+func (di *DBInspector) showSaveQueryDialog() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(di.savedQuerySelect.Selected)
+
+	dialog.ShowForm("Save Query", "Save", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+	}, func(ok bool) {
+		if !ok || nameEntry.Text == "" {
+			return
+		}
+		di.config.SetSavedQuery(config.SavedQuery{Name: nameEntry.Text, SQL: di.queryInput.Text})
+		di.saveConfig()
+		di.savedQuerySelect.SetOptions(di.config.SavedQueryNames())
+		di.savedQuerySelect.SetSelected(nameEntry.Text)
+	}, di.window)
+}
+
+// loadSavedQuery puts a saved query into the editor
+func (di *DBInspector) loadSavedQuery(name string) {
+	if query, ok := di.config.SavedQuery(name); ok {
+		di.queryInput.SetText(query.SQL)
+	}
+}
+
+// showOpenWorkspaceDialog switches to a workspace file
+func (di *DBInspector) showOpenWorkspaceDialog() {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		reader.Close()
+
+		path := reader.URI().Path()
+		cfg, err := config.Load(path)
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+
+		di.configPath = path
+		di.config = cfg
+		di.applyWorkspace()
+	}, di.window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	open.Show()
+}
+
+// showSaveWorkspaceDialog saves the workspace to a new file and switches to it
+func (di *DBInspector) showSaveWorkspaceDialog() {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		writer.Close()
+
+		path := writer.URI().Path()
+		if err := di.config.Save(path); err != nil {
+			dialog.ShowError(err, di.window)
+			return
+		}
+
+		di.configPath = path
+		di.applyWorkspace()
+	}, di.window)
+	save.SetFileName("workspace.json")
+	save.Show()
+}
+
+// openAuditLog starts audit logging as configured by the workspace
+func (di *DBInspector) openAuditLog() {
+	di.audit.Close()
+	logger, err := audit.Open(di.config.Audit)
+	if err != nil {
+		dialog.ShowError(err, di.window)
+	}
+	di.audit = logger
+}
+
+// auditLog records an action on the current connection in the audit log
+func (di *DBInspector) auditLog(action, detail string) {
+	var database string
+	if di.connInfo != nil {
+		database = audit.Target(di.connParams)
+	}
+	di.audit.Log(database, action, detail)
+}
+
+// workspaceTitle returns the window title naming the workspace, unless it is the default configuration
+func (di *DBInspector) workspaceTitle() string {
+	if path, err := config.DefaultPath(); err == nil && path != di.configPath {
+		return fmt.Sprintf("%s - %s", windowTitle, filepath.Base(di.configPath))
+	}
+	return windowTitle
+}
+
+// applyWorkspace refreshes the UI after the workspace has been loaded or switched
+func (di *DBInspector) applyWorkspace() {
+	di.showEnvironment()
+	di.openAuditLog()
+
+	// Encrypted passwords need the passphrase of this workspace
+	di.sealer = nil
+	if di.config.Encryption != nil {
+		di.promptPassphrase(nil)
+	}
+
+	di.systemTablesCheck.SetChecked(di.config.ShowSystemTables)
+	di.showWritesSetting()
+	di.showPrefetchSetting()
+	if !di.config.PrefetchStructures {
+		di.stopPrefetch()
+	}
+	di.scheduleSnapshots()
+	di.savedQuerySelect.SetOptions(di.config.SavedQueryNames())
+	di.applyTableFilter()
+
+	if di.selectedTable != nil {
+		di.showTableNotes(di.selectedTable.Name)
+	}
+}
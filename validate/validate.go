@@ -0,0 +1,237 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	t "github.com/carloberd/db-reader/types"
+)
+
+// Expectation declares the tables, columns and indexes a database must have.
+// Other objects in the database are allowed.
+type Expectation struct {
+	Tables map[string]TableExpectation `json:"tables"` // By name, schema-qualified or in the default schema
+}
+
+// TableExpectation declares the required columns and indexes of a table
+type TableExpectation struct {
+	Columns map[string]ColumnExpectation `json:"columns,omitempty"`
+	Indexes []IndexExpectation           `json:"indexes,omitempty"`
+}
+
+// ColumnExpectation declares a required column. Unset fields are not checked.
+type ColumnExpectation struct {
+	Type     string `json:"type,omitempty"`
+	Nullable *bool  `json:"nullable,omitempty"`
+}
+
+// IndexExpectation declares a required index, found by name or, without a name,
+// by its columns. Unique requires a unique index; a non-unique expectation
+// is also met by a unique index.
+type IndexExpectation struct {
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+	Unique  bool     `json:"unique,omitempty"`
+}
+
+// Violation is an expectation the database does not meet
+type Violation struct {
+	Table   string
+	Message string
+}
+
+// String describes the violation on a single line
+func (v Violation) String() string {
+	return v.Table + ": " + v.Message
+}
+
+// Load reads an expectation file
+func Load(path string) (*Expectation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading expectation file: %v", err)
+	}
+
+	var exp Expectation
+	if err := json.Unmarshal(data, &exp); err != nil {
+		return nil, fmt.Errorf("error parsing expectation file: %v", err)
+	}
+	return &exp, nil
+}
+
+// Check validates the database against the expectations. Tables without a
+// schema qualifier are looked up in the default schema.
+func Check(connector t.DatabaseConnector, defaultSchema string, exp *Expectation) ([]Violation, error) {
+	names := make([]string, 0, len(exp.Tables))
+	for name := range exp.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	existing := make(map[string][]string)
+	var violations []Violation
+	for _, name := range names {
+		schema, tableName := defaultSchema, name
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			schema, tableName = name[:dot], name[dot+1:]
+		}
+
+		if _, ok := existing[schema]; !ok {
+			tables, err := connector.GetTables(schema)
+			if err != nil {
+				return nil, err
+			}
+			existing[schema] = tables
+		}
+		if !slices.Contains(existing[schema], tableName) {
+			violations = append(violations, Violation{Table: name, Message: "table is missing"})
+			continue
+		}
+
+		table, err := connector.GetTableStructure(schema, tableName)
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range checkTable(table, exp.Tables[name]) {
+			violations = append(violations, Violation{Table: name, Message: message})
+		}
+	}
+
+	return violations, nil
+}
+
+// checkTable returns the unmet expectations of a table
+func checkTable(table *t.Table, exp TableExpectation) []string {
+	var messages []string
+
+	columns := make(map[string]t.Column, len(table.Columns))
+	for _, col := range table.Columns {
+		columns[col.Name] = col
+	}
+
+	names := make([]string, 0, len(exp.Columns))
+	for name := range exp.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		want := exp.Columns[name]
+		col, ok := columns[name]
+		if !ok {
+			messages = append(messages, fmt.Sprintf("column %s is missing", name))
+			continue
+		}
+		if want.Type != "" && !SameType(want.Type, col.Type) {
+			messages = append(messages, fmt.Sprintf("column %s has type %s, expected %s", name, col.Type, want.Type))
+		}
+		if want.Nullable != nil && *want.Nullable != col.Nullable {
+			if col.Nullable {
+				messages = append(messages, fmt.Sprintf("column %s is nullable, expected NOT NULL", name))
+			} else {
+				messages = append(messages, fmt.Sprintf("column %s is NOT NULL, expected nullable", name))
+			}
+		}
+	}
+
+	for _, want := range exp.Indexes {
+		if !hasIndex(table, want) {
+			messages = append(messages, fmt.Sprintf("%s is missing", describeIndex(want)))
+		}
+	}
+
+	return messages
+}
+
+// hasIndex reports whether a table has an index meeting the expectation
+func hasIndex(table *t.Table, want IndexExpectation) bool {
+	for _, idx := range table.Indexes {
+		if want.Name != "" && idx.Name != want.Name {
+			continue
+		}
+		if len(want.Columns) > 0 && !slices.Equal(idx.Columns, want.Columns) {
+			continue
+		}
+		if want.Unique && !idx.Unique {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// describeIndex names an expected index for violation messages
+func describeIndex(want IndexExpectation) string {
+	desc := "index"
+	if want.Unique {
+		desc = "unique index"
+	}
+	if want.Name != "" {
+		desc += " " + want.Name
+	}
+	if len(want.Columns) > 0 {
+		desc += " on (" + strings.Join(want.Columns, ", ") + ")"
+	}
+	return desc
+}
+
+// typeAliases maps alternative spellings of common types to the names the
+// connector reports
+var typeAliases = map[string]string{
+	"int":               "integer",
+	"int4":              "integer",
+	"int8":              "bigint",
+	"int2":              "smallint",
+	"serial":            "integer",
+	"bigserial":         "bigint",
+	"bool":              "boolean",
+	"float8":            "double",
+	"float4":            "real",
+	"timestamptz":       "timestamp with time zone",
+	"timestamp":         "timestamp without time zone",
+	"time":              "time without time zone",
+	"timetz":            "time with time zone",
+	"character varying": "varchar",
+	"double precision":  "double",
+}
+
+// SameType reports whether two type names denote the same type, ignoring case,
+// spacing and the usual aliases, e.g. "int8" and "bigint"
+func SameType(a, b string) bool {
+	return normalizeType(a) == normalizeType(b)
+}
+
+// normalizeType brings a type name into a canonical form, so that expectations
+// can use the usual aliases, e.g. "int8" for "bigint"
+func normalizeType(typ string) string {
+	typ = strings.Join(strings.Fields(strings.ToLower(typ)), " ")
+
+	// Keep modifiers such as the length of varchar(255), which may also come
+	// before the rest of the name, as in "timestamp(3) with time zone"
+	base, modifier := typ, ""
+	if open := strings.Index(typ, "("); open >= 0 {
+		end := strings.Index(typ[open:], ")")
+		if end < 0 {
+			end = len(typ) - open - 1
+		}
+		base = strings.Join(strings.Fields(typ[:open]+" "+typ[open+end+1:]), " ")
+		modifier = typ[open : open+end+1]
+	}
+	base, array := strings.CutSuffix(base, "[]")
+	base = strings.TrimSpace(base)
+	if alias, ok := typeAliases[base]; ok {
+		base = alias
+	}
+	base = strings.Replace(base, "character varying", "varchar", 1)
+	base = strings.Replace(base, "character", "char", 1)
+	base = strings.Replace(base, "double precision", "double", 1)
+
+	if array {
+		base += "[]"
+	}
+	return base + strings.ReplaceAll(modifier, " ", "")
+}